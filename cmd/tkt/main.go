@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -13,6 +14,11 @@ import (
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		// Ctrl-Cで中断した場合はスタックトレースを表示しない
+		if errors.Is(err, context.Canceled) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(130)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		if st := errors.StackTraces(err); len(st) > 0 {
 			fmt.Fprintf(os.Stderr, "Stack trace:\n%s\n", st)
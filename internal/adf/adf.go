@@ -203,6 +203,13 @@ func (a *Translator) walk() {
 }
 
 func (a *Translator) visit(n *Node, depth int) {
+	// Rendering nothing would silently drop the node when the description is pushed back,
+	// so leave a placeholder that can be detected.
+	if !IsSupportedNode(n.NodeType) {
+		a.buf.WriteString(unsupportedPlaceholder(n))
+		return
+	}
+
 	a.buf.WriteString(a.tsl.Open(n, depth))
 
 	for _, child := range n.Content {
@@ -39,3 +39,107 @@ func TestADFReplaceAll(t *testing.T) {
 	assert.False(t, strings.Contains(string(dump), "Prefix:"))
 	assert.True(t, strings.Contains(string(dump), "Replaced:"))
 }
+
+func TestMediaResolver(t *testing.T) {
+	doc := &ADF{
+		Version: 1,
+		DocType: "doc",
+		Content: []*Node{
+			{
+				NodeType: NodeType("mediaSingle"),
+				Content: []*Node{
+					{NodeType: NodeMedia, Attributes: map[string]any{"id": "abc", "type": "file", "alt": "screen shot.png"}},
+				},
+			},
+		},
+	}
+
+	resolve := func(attrs map[string]any) (string, bool) {
+		if attrs["alt"] == "screen shot.png" {
+			return "assets/PRJ-1/screen%20shot.png", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name string
+		tr   TagOpenerCloser
+		want string
+	}{
+		{name: "resolver未設定", tr: NewMarkdownTranslator(), want: "[attachment]"},
+		{name: "解決できる", tr: NewJiraMarkdownTranslator(WithMediaResolver(resolve)), want: "![screen shot.png](assets/PRJ-1/screen%20shot.png)"},
+		{name: "解決できない", tr: NewJiraMarkdownTranslator(WithMediaResolver(func(map[string]any) (string, bool) { return "", false })), want: "[attachment]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, NewTranslator(doc, tt.tr).Translate(), tt.want)
+		})
+	}
+}
+
+func TestUnsupportedNodes(t *testing.T) {
+	paragraph := func(text string) *Node {
+		return &Node{NodeType: NodeParagraph, Content: []*Node{{NodeType: ChildNodeText, NodeValue: NodeValue{Text: text}}}}
+	}
+	tests := []struct {
+		name string
+		node *Node
+		want string
+	}{
+		{
+			name: "expand",
+			node: &Node{
+				NodeType:   NodeType("expand"),
+				Attributes: map[string]any{"title": "Details"},
+				Content:    []*Node{paragraph("Hidden text"), paragraph("More <text>")},
+			},
+			want: "<!-- tkt:unsupported-adf type=expand -->\nDetails\nHidden text\nMore ❬text❭\n\n",
+		},
+		{
+			name: "decision list",
+			node: &Node{
+				NodeType: NodeType("decisionList"),
+				Content: []*Node{
+					{NodeType: NodeType("decisionItem"), Content: []*Node{{NodeType: ChildNodeText, NodeValue: NodeValue{Text: "Use Go"}}}},
+					{NodeType: NodeType("decisionItem"), Content: []*Node{{NodeType: ChildNodeText, NodeValue: NodeValue{Text: "Ship it"}}}},
+				},
+			},
+			want: "<!-- tkt:unsupported-adf type=decisionList -->\nUse Go\nShip it\n\n",
+		},
+		{
+			name: "no text",
+			node: &Node{NodeType: NodeType("rule")},
+			want: "<!-- tkt:unsupported-adf type=rule -->\n\n",
+		},
+		{
+			name: "inline status",
+			node: &Node{
+				NodeType: NodeParagraph,
+				Content: []*Node{
+					{NodeType: ChildNodeText, NodeValue: NodeValue{Text: "State:"}},
+					{NodeType: NodeType("status"), Attributes: map[string]any{"text": "DONE"}},
+				},
+			},
+			want: "State:<!-- tkt:unsupported-adf type=status -->DONE\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &ADF{Version: 1, DocType: "doc", Content: []*Node{tt.node}}
+			got := NewTranslator(doc, NewJiraMarkdownTranslator()).Translate()
+			assert.Equal(t, tt.want, got)
+			assert.True(t, HasUnsupportedPlaceholder(got))
+		})
+	}
+
+	doc := &ADF{Version: 1, DocType: "doc", Content: []*Node{
+		paragraph("supported"),
+		{NodeType: NodeType("expand"), Content: []*Node{{NodeType: NodeType("expand")}}},
+		{NodeType: NodeType("layoutSection")},
+		{NodeType: NodeType("expand")},
+		{NodeType: NodeType("mediaSingle"), Content: []*Node{{NodeType: NodeMedia}}},
+	}}
+	assert.Equal(t, map[NodeType]int{"expand": 2, "layoutSection": 1}, UnsupportedNodes(doc))
+}
@@ -25,7 +25,8 @@ type JiraMarkdownTranslator struct {
 }
 
 // NewJiraMarkdownTranslator constructs jira markdown translator.
-func NewJiraMarkdownTranslator() *JiraMarkdownTranslator {
+// Additional options are applied after the default jira hooks.
+func NewJiraMarkdownTranslator(opts ...MarkdownTranslatorOption) *JiraMarkdownTranslator {
 	openHooks := nodeTypeHook{
 		NodePanel: nodePanelOpenHook,
 	}
@@ -35,10 +36,10 @@ func NewJiraMarkdownTranslator() *JiraMarkdownTranslator {
 	}
 
 	return &JiraMarkdownTranslator{
-		MarkdownTranslator: NewMarkdownTranslator(
+		MarkdownTranslator: NewMarkdownTranslator(append([]MarkdownTranslatorOption{
 			WithMarkdownOpenHooks(openHooks),
 			WithMarkdownCloseHooks(closeHooks),
-		),
+		}, opts...)...),
 	}
 }
 
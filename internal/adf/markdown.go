@@ -22,10 +22,15 @@ type MarkdownTranslator struct {
 		depthU  int
 		counter map[int]int // each level starts with same numeric counter at the moment.
 	}
-	openHooks  nodeTypeHook
-	closeHooks nodeTypeHook
+	openHooks     nodeTypeHook
+	closeHooks    nodeTypeHook
+	mediaResolver MediaResolver
 }
 
+// MediaResolver returns the link target for a media node from its attributes.
+// It returns false when the media cannot be resolved, in which case a placeholder is written instead.
+type MediaResolver func(attrs map[string]any) (string, bool)
+
 // MarkdownTranslatorOption is a functional option for MarkdownTranslator.
 type MarkdownTranslatorOption func(*MarkdownTranslator)
 
@@ -65,6 +70,13 @@ func WithMarkdownCloseHooks(hooks nodeTypeHook) MarkdownTranslatorOption {
 	}
 }
 
+// WithMediaResolver sets a resolver that turns media nodes into markdown image links.
+func WithMediaResolver(resolve MediaResolver) MarkdownTranslatorOption {
+	return func(tr *MarkdownTranslator) {
+		tr.mediaResolver = resolve
+	}
+}
+
 // Open implements TagOpener interface.
 //
 //nolint:gocyclo
@@ -100,7 +112,7 @@ func (tr *MarkdownTranslator) Open(n Connector, _ int) string {
 		case NodeTable:
 			tag.WriteString("\n")
 		case NodeMedia:
-			tag.WriteString("\n[attachment]")
+			tag.WriteString("\n" + tr.media(attrs))
 		case NodeBulletList:
 			tr.list.depthU++
 			tr.list.ul[tr.list.depthU] = true
@@ -238,6 +250,20 @@ func (tr *MarkdownTranslator) Close(n Connector) string {
 	return tag.String()
 }
 
+// media returns an image link for a media node, or a placeholder when it cannot be resolved.
+func (tr *MarkdownTranslator) media(a any) string {
+	attrs, _ := a.(map[string]any)
+	if tr.mediaResolver == nil || attrs == nil {
+		return "[attachment]"
+	}
+	target, ok := tr.mediaResolver(attrs)
+	if !ok {
+		return "[attachment]"
+	}
+	alt, _ := attrs["alt"].(string)
+	return fmt.Sprintf("![%s](%s)", alt, target)
+}
+
 func (tr *MarkdownTranslator) setOpenTagAttributes(a any) string {
 	if a == nil {
 		return ""
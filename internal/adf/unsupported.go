@@ -0,0 +1,126 @@
+package adf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnsupportedPlaceholderPrefix starts the placeholder written in place of a node the translator does not understand.
+// Pushing a description that still contains the placeholder would replace the original node remotely,
+// so callers should refuse to update such descriptions.
+const UnsupportedPlaceholderPrefix = "<!-- tkt:unsupported-adf"
+
+// HasUnsupportedPlaceholder reports whether s contains a placeholder for an unsupported node.
+func HasUnsupportedPlaceholder(s string) bool {
+	return strings.Contains(s, UnsupportedPlaceholderPrefix)
+}
+
+// wrapperNodes are nodes without their own markup whose content is rendered as-is.
+var wrapperNodes = []NodeType{"doc", "mediaSingle", "mediaGroup"}
+
+// inlineNodes are nodes that appear inside a paragraph. Unsupported ones get an inline placeholder.
+var inlineNodes = []NodeType{
+	ChildNodeText, InlineNodeCard, InlineNodeEmoji, InlineNodeMention, InlineNodeHardBreak,
+	"status", "date", "placeholder", "inlineExtension", "mediaInline",
+}
+
+// IsSupportedNode reports whether the translator renders the node type.
+func IsSupportedNode(nt NodeType) bool {
+	switch nt {
+	case InlineNodeCard, InlineNodeEmoji, InlineNodeMention, InlineNodeHardBreak:
+		return true
+	}
+	return GetADFNodeType(nt) != NodeTypeUnknown || contains(wrapperNodes, nt)
+}
+
+func isInlineNode(nt NodeType) bool {
+	return contains(inlineNodes, nt)
+}
+
+func contains(types []NodeType, nt NodeType) bool {
+	for _, t := range types {
+		if t == nt {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsupportedNodes counts the nodes of the document the translator does not understand, by type.
+// Nodes inside an unsupported node are not counted, matching the single placeholder written for it.
+func UnsupportedNodes(doc *ADF) map[NodeType]int {
+	counts := make(map[NodeType]int)
+	if doc == nil {
+		return counts
+	}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if !IsSupportedNode(n.NodeType) {
+			counts[n.NodeType]++
+			return
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	for _, n := range doc.Content {
+		walk(n)
+	}
+	return counts
+}
+
+// unsupportedPlaceholder renders an unsupported node as a marked placeholder followed by its text.
+func unsupportedPlaceholder(n *Node) string {
+	marker := fmt.Sprintf("%s type=%s -->", UnsupportedPlaceholderPrefix, n.NodeType)
+	lines := plainText(n)
+	if isInlineNode(n.NodeType) {
+		return marker + strings.Join(lines, " ")
+	}
+	if len(lines) == 0 {
+		return marker + "\n\n"
+	}
+	return marker + "\n" + strings.Join(lines, "\n") + "\n\n"
+}
+
+// plainText extracts the text of a node on a best-effort basis, one line per block.
+func plainText(n *Node) []string {
+	var (
+		lines []string
+		cur   strings.Builder
+	)
+	flush := func() {
+		if line := sanitize(cur.String()); line != "" {
+			lines = append(lines, line)
+		}
+		cur.Reset()
+	}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		switch n.NodeType {
+		case ChildNodeText:
+			cur.WriteString(n.Text)
+			return
+		case InlineNodeHardBreak:
+			flush()
+			return
+		}
+		if attrs, ok := n.Attributes.(map[string]any); ok {
+			// e.g. the title of an expand, or the text of a status or mention
+			if title, ok := attrs["title"].(string); ok && title != "" {
+				cur.WriteString(title)
+				flush()
+			} else if text, ok := attrs["text"].(string); ok {
+				cur.WriteString(text)
+			}
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+		if !isInlineNode(n.NodeType) {
+			flush()
+		}
+	}
+	walk(n)
+	flush()
+	return lines
+}
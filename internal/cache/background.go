@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"time"
 
 	"github.com/qawatake/tkt/internal/config"
@@ -11,86 +12,112 @@ import (
 
 // StartBackgroundUpdate starts a background goroutine to update the cache
 // This is the same logic as fetch command but runs in background without UI feedback
-func StartBackgroundUpdate() {
+// The returned channel receives the result once the update finishes, so callers can reload the cache.
+// Cancelling ctx aborts the requests in flight.
+func StartBackgroundUpdate(ctx context.Context) <-chan error {
+	done := make(chan error, 1)
 	go func() {
-		err := performBackgroundUpdate()
+		err := performBackgroundUpdate(ctx)
 		if err != nil {
-			verbose.Printf("Background cache update failed: %v\n", err)
+			verbose.Printf(verbose.Cache, "Background cache update failed: %v\n", err)
 		} else {
-			verbose.Printf("Background cache update completed successfully\n")
+			verbose.Printf(verbose.Cache, "Background cache update completed successfully\n")
 		}
+		done <- err
+		close(done)
 	}()
+	return done
 }
 
 // performBackgroundUpdate performs the cache update logic from fetch command
-func performBackgroundUpdate() error {
+func performBackgroundUpdate(ctx context.Context) error {
 	// 1. Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		verbose.Printf("Background cache update: Failed to load config: %v\n", err)
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to load config: %v\n", err)
 		return err
 	}
 
-	verbose.Printf("Background cache update: Starting...\n")
+	verbose.Printf(verbose.Cache, "Background cache update: Starting...\n")
 
 	// 2. Create JIRA client
 	jiraClient, err := jira.NewClient(cfg)
 	if err != nil {
-		verbose.Printf("Background cache update: Failed to create JIRA client: %v\n", err)
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to create JIRA client: %v\n", err)
 		return err
 	}
 
 	// 3. Determine if this should be incremental or full fetch
 	var tickets []*ticket.Ticket
 	startTime := time.Now()
+	fetchMode := config.FetchModeFull
+	expandedJQL := jiraClient.BaseJQL()
 
 	lastFetch, fetchErr := config.GetLastFetchTime()
 	if fetchErr != nil {
-		verbose.Printf("Background cache update: Failed to get last fetch time: %v\n", fetchErr)
-		verbose.Printf("Background cache update: Performing full fetch\n")
-		tickets, err = jiraClient.FetchIssues()
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to get last fetch time: %v\n", fetchErr)
+		verbose.Printf(verbose.Cache, "Background cache update: Performing full fetch\n")
+		tickets, err = jiraClient.FetchIssues(ctx)
 	} else if lastFetch.IsZero() {
-		verbose.Printf("Background cache update: First fetch, performing full fetch\n")
-		tickets, err = jiraClient.FetchIssues()
+		verbose.Printf(verbose.Cache, "Background cache update: First fetch, performing full fetch\n")
+		tickets, err = jiraClient.FetchIssues(ctx)
 	} else {
-		verbose.Printf("Background cache update: Last fetch time: %s\n", lastFetch.Format(time.RFC3339))
-		verbose.Printf("Background cache update: Performing incremental fetch\n")
-		tickets, err = jiraClient.FetchIssuesIncremental(lastFetch)
+		verbose.Printf(verbose.Cache, "Background cache update: Last fetch time: %s\n", lastFetch.Format(time.RFC3339))
+		verbose.Printf(verbose.Cache, "Background cache update: Performing incremental fetch\n")
+		fetchMode = config.FetchModeIncremental
+		expandedJQL = jiraClient.IncrementalJQL(lastFetch)
+		tickets, err = jiraClient.FetchIssuesIncremental(ctx, lastFetch)
 	}
 
 	if err != nil {
-		verbose.Printf("Background cache update: Failed to fetch tickets: %v\n", err)
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to fetch tickets: %v\n", err)
 		return err
 	}
 
-	verbose.Printf("Background cache update: Fetched %d tickets\n", len(tickets))
+	verbose.Printf(verbose.Cache, "Background cache update: Fetched %d tickets\n", len(tickets))
 
 	// 4. Ensure cache directory exists
 	cacheDir, err := config.EnsureCacheDir()
 	if err != nil {
-		verbose.Printf("Background cache update: Failed to ensure cache directory: %v\n", err)
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to ensure cache directory: %v\n", err)
 		return err
 	}
 
 	// 5. Save tickets to cache
 	savedCount := 0
 	for _, ticket := range tickets {
-		savedCachePath, err := ticket.SaveToFile(cacheDir)
+		savedCachePath, err := ticket.SaveToCache(cacheDir)
 		if err != nil {
-			verbose.Printf("Background cache update: Failed to save ticket %s: %v\n", ticket.Key, err)
+			verbose.Printf(verbose.Cache, "Background cache update: Failed to save ticket %s: %v\n", ticket.Key, err)
 		} else {
-			verbose.Printf("Background cache update: Saved %s -> %s\n", ticket.Key, savedCachePath)
+			verbose.Printf(verbose.Cache, "Background cache update: Saved %s -> %s\n", ticket.Key, savedCachePath)
 			savedCount++
 		}
 	}
 
 	// 6. Save last fetch time
 	if saveErr := config.SaveLastFetchTime(startTime); saveErr != nil {
-		verbose.Printf("Background cache update: Failed to save last fetch time: %v\n", saveErr)
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to save last fetch time: %v\n", saveErr)
 	} else {
-		verbose.Printf("Background cache update: Saved last fetch time: %s\n", startTime.Format(time.RFC3339))
+		verbose.Printf(verbose.Cache, "Background cache update: Saved last fetch time: %s\n", startTime.Format(time.RFC3339))
 	}
 
-	verbose.Printf("Background cache update: Completed successfully, saved %d tickets\n", savedCount)
+	// 7. Save cache metadata
+	meta := config.CacheMetadata{
+		Server:      cfg.Server,
+		ProjectKey:  cfg.Project.Key,
+		JQL:         cfg.JQL,
+		ExpandedJQL: string(expandedJQL),
+		FetchMode:   fetchMode,
+		FetchedAt:   startTime,
+	}
+	if saveErr := config.SaveCacheMetadata(cacheDir, meta); saveErr != nil {
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to save cache metadata: %v\n", saveErr)
+	}
+	if saveErr := config.SaveWorkspaceMetadata(meta); saveErr != nil {
+		verbose.Printf(verbose.Cache, "Background cache update: Failed to save workspace metadata: %v\n", saveErr)
+	}
+
+	verbose.Printf(verbose.Cache, "Background cache update: Completed successfully, saved %d tickets\n", savedCount)
 	return nil
 }
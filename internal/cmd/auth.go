@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/spf13/cobra"
+)
+
+var authServer string
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "APIトークンに関する操作を行います",
+	Long:  `APIトークンに関する操作を行います。`,
+}
+
+var authSetTokenCmd = &cobra.Command{
+	Use:   "set-token",
+	Short: "APIトークンをOSのキーリングに保存します",
+	Long: `APIトークンをOSのキーリング (macOSのキーチェーン、LinuxのSecret Service、Windowsの資格情報マネージャー) に保存します。
+トークンはサーバーごとに保存し、環境変数 JIRA_API_TOKEN より優先して使います。
+標準入力がパイプの場合は標準入力から読み込み、それ以外は入力を求めます (入力した内容は表示されません)。
+--server を省略した場合は設定ファイルの server に保存します。`,
+	Example: `  tkt auth set-token
+  op read op://Private/jira/token | tkt auth set-token
+  tkt auth set-token --server https://other.atlassian.net`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		server, err := authTargetServer()
+		if err != nil {
+			return err
+		}
+
+		token, err := readAPIToken()
+		if err != nil {
+			return err
+		}
+		if err := config.SaveKeyringToken(server, token); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s のAPIトークンをキーリングに保存しました\n", server)
+		return nil
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "APIトークンの取得元を表示します",
+	Long: `設定ファイルのサーバーと extra_sources のサイトごとに、APIトークンをどこから取得するかを表示します。
+トークンそのものは表示しません。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		configs := []*config.Config{cfg}
+		for _, source := range cfg.ExtraSources {
+			configs = append(configs, source.Config(cfg))
+		}
+		for _, c := range configs {
+			fmt.Printf("%s (ログイン: %s)\n", c.Server, c.Login)
+			if _, err := config.KeyringToken(c.Server); err != nil {
+				fmt.Printf("  ⚠️  %v\n", err)
+			}
+			token, source, err := c.APIToken()
+			switch {
+			case err != nil:
+				fmt.Printf("  ❌ %v\n", err)
+			case token == "":
+				fmt.Printf("  ❌ APIトークンが見つかりません (tkt auth set-token で保存できます)\n")
+			default:
+				fmt.Printf("  ✅ %s\n", source)
+			}
+		}
+		return nil
+	},
+}
+
+// authTargetServer は --server、なければ設定ファイルの server を返します
+func authTargetServer() (string, error) {
+	if authServer != "" {
+		return strings.TrimRight(authServer, "/"), nil
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("設定ファイルの読み込みに失敗しました (--server でサーバーを指定できます): %v", err)
+	}
+	if cfg.Server == "" {
+		return "", fmt.Errorf("設定ファイルにserverが設定されていません。--server でサーバーを指定してください")
+	}
+	return strings.TrimRight(cfg.Server, "/"), nil
+}
+
+// readAPIToken は標準入力がパイプの場合はそこから、それ以外は入力欄からAPIトークンを読み込みます
+func readAPIToken() (string, error) {
+	var token string
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("標準入力からのAPIトークンの読み込みに失敗しました: %v", err)
+		}
+		token = line
+	} else {
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("APIトークン").
+					Description("https://id.atlassian.com/manage-profile/security/api-tokens で発行できます").
+					EchoMode(huh.EchoModePassword).
+					Value(&token),
+			),
+		).WithTheme(huh.ThemeBase())
+		if err := form.Run(); err != nil {
+			return "", fmt.Errorf("APIトークンの入力がキャンセルされました: %v", err)
+		}
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("APIトークンが空です")
+	}
+	return token, nil
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authSetTokenCmd)
+	authCmd.AddCommand(authStatusCmd)
+
+	authSetTokenCmd.Flags().StringVar(&authServer, "server", "", "トークンを保存するサーバーのURL (省略時は設定ファイルの server)")
+}
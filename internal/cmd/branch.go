@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	currentJSON bool
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch [TICKET-KEY]",
+	Short: "チケットのgitブランチを作成してチェックアウトします",
+	Long: `チケットのgitブランチを作成してチェックアウトします。
+ブランチ名は設定ファイルの branch.template (デフォルト: {{.Key}}-{{slug .Title}}) から生成します。
+TICKET-KEY を省略した場合は、キャッシュにあるチケットから検索して選択します。
+同名のブランチが既にある場合は、そのブランチをチェックアウトします。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		var t *ticket.Ticket
+		if len(args) > 0 {
+			t, err = findTicket(cfg, args[0])
+		} else {
+			t, err = pickCachedTicket()
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := branchName(cfg.BranchTemplate(), t)
+		if err != nil {
+			return err
+		}
+
+		repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+		if err != nil {
+			return fmt.Errorf("gitリポジトリを開けませんでした: %v", err)
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return fmt.Errorf("ワークツリーの取得に失敗しました: %v", err)
+		}
+
+		ref := plumbing.NewBranchReferenceName(name)
+		_, err = repo.Reference(ref, true)
+		exists := err == nil
+		if err := wt.Checkout(&git.CheckoutOptions{Branch: ref, Create: !exists, Keep: true}); err != nil {
+			return fmt.Errorf("ブランチ %s のチェックアウトに失敗しました: %v", name, err)
+		}
+
+		if exists {
+			fmt.Printf("既存のブランチ %s に切り替えました\n", name)
+		} else {
+			fmt.Printf("✅ ブランチ %s を作成して切り替えました\n", name)
+		}
+		return nil
+	},
+}
+
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "現在のgitブランチ名からチケットのキーを表示します",
+	Long: `現在のgitブランチ名からチケットのキーを検出して表示します。
+--json を指定すると、キーの代わりにチケットのフロントマターをJSON形式で出力します。
+キーが見つからない場合は終了コード1で終了します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+		if err != nil {
+			return fmt.Errorf("gitリポジトリを開けませんでした: %v", err)
+		}
+		head, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("HEADの取得に失敗しました: %v", err)
+		}
+		if !head.Name().IsBranch() {
+			return fmt.Errorf("ブランチがチェックアウトされていません (detached HEAD)")
+		}
+
+		branch := head.Name().Short()
+		key := extractTicketKey(branch, cfg.Project.Key)
+		if key == "" {
+			return fmt.Errorf("ブランチ名 %s からチケットのキーが見つかりません", branch)
+		}
+
+		if !currentJSON {
+			fmt.Println(key)
+			return nil
+		}
+
+		dto := ticketDTO{Key: key}
+		if t, err := findTicket(cfg, key); err == nil {
+			dto = newTicketDTO(t, newParentTitleIndex(nil))
+		} else {
+			fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+		}
+		b, err := json.Marshal(dto)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	},
+}
+
+// pickCachedTicket はキャッシュにあるキー付きのチケットから1件を選択させます
+func pickCachedTicket() (*ticket.Ticket, error) {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	tickets, err := loadTickets(cacheDir, false)
+	if err != nil {
+		return nil, fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
+	}
+	if len(tickets) == 0 {
+		return nil, fmt.Errorf("キャッシュにチケットがありません。'tkt fetch' を実行するか、TICKET-KEYを指定してください")
+	}
+	t, err := pickTicket(tickets, cacheDir, nil)
+	if err != nil {
+		return nil, err
+	}
+	if t.Key == "" {
+		return nil, fmt.Errorf("キーのないチケットからはブランチを作成できません")
+	}
+	return t, nil
+}
+
+var (
+	branchDashRe = regexp.MustCompile(`-{2,}`)
+	genericKeyRe = regexp.MustCompile(`(?:^|[^A-Za-z0-9])([A-Za-z][A-Za-z0-9_]*-[0-9]+)`)
+)
+
+// branchName はテンプレートからチケットのブランチ名を生成します
+func branchName(tmpl string, t *ticket.Ticket) (string, error) {
+	tp, err := template.New("branch").Funcs(template.FuncMap{"slug": ticket.Slug}).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("branch.template の解析に失敗しました: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tp.Execute(&buf, t); err != nil {
+		return "", fmt.Errorf("ブランチ名の生成に失敗しました: %v", err)
+	}
+
+	// スラッグが空の場合などに残る余分なハイフンを取り除く
+	name := strings.Join(strings.Fields(buf.String()), "-")
+	name = branchDashRe.ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-/")
+	if name == "" {
+		return "", fmt.Errorf("ブランチ名が空になりました。branch.template を確認してください")
+	}
+	if err := plumbing.NewBranchReferenceName(name).Validate(); err != nil {
+		return "", fmt.Errorf("ブランチ名 %s は使用できません: %v", name, err)
+	}
+	return name, nil
+}
+
+// extractTicketKey はブランチ名からチケットのキーを取り出します
+// プロジェクトキーが分かる場合はそのプロジェクトのキーを優先します。
+func extractTicketKey(branch, projectKey string) string {
+	if projectKey != "" {
+		re := regexp.MustCompile(`(?i)(?:^|[^A-Za-z0-9])(` + regexp.QuoteMeta(projectKey) + `-[0-9]+)`)
+		if m := re.FindStringSubmatch(branch); m != nil {
+			return strings.ToUpper(m[1])
+		}
+	}
+	if m := genericKeyRe.FindStringSubmatch(branch); m != nil {
+		return strings.ToUpper(m[1])
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+	rootCmd.AddCommand(currentCmd)
+
+	currentCmd.Flags().BoolVar(&currentJSON, "json", false, "チケットのフロントマターをJSON形式で出力")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		ticket   *ticket.Ticket
+		want     string
+	}{
+		{
+			name:     "デフォルトのテンプレート",
+			template: config.DefaultBranchTemplate,
+			ticket:   &ticket.Ticket{Key: "PRJ-123", Title: "Fix login: handle nil pointer!"},
+			want:     "PRJ-123-fix-login-handle-nil-pointer",
+		},
+		{
+			name:     "日本語のタイトルはキーのみ",
+			template: config.DefaultBranchTemplate,
+			ticket:   &ticket.Ticket{Key: "PRJ-123", Title: "ログイン画面の改善"},
+			want:     "PRJ-123",
+		},
+		{
+			name:     "英語と日本語の混在",
+			template: config.DefaultBranchTemplate,
+			ticket:   &ticket.Ticket{Key: "PRJ-1", Title: "API のタイムアウト対応"},
+			want:     "PRJ-1-api",
+		},
+		{
+			name:     "プレフィックス付きのテンプレート",
+			template: "feature/{{.Key}}",
+			ticket:   &ticket.Ticket{Key: "PRJ-1", Title: "title"},
+			want:     "feature/PRJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := branchName(tt.template, tt.ticket)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExtractTicketKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		branch     string
+		projectKey string
+		want       string
+	}{
+		{name: "先頭にキー", branch: "PRJ-123-fix-login", projectKey: "PRJ", want: "PRJ-123"},
+		{name: "プレフィックス付き", branch: "feature/prj-45-add-api", projectKey: "PRJ", want: "PRJ-45"},
+		{name: "プロジェクトキーを優先", branch: "fix-2-PRJ-9", projectKey: "PRJ", want: "PRJ-9"},
+		{name: "プロジェクトキーが未設定", branch: "ABC-7-foo", projectKey: "", want: "ABC-7"},
+		{name: "キーがない", branch: "main", projectKey: "PRJ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractTicketKey(tt.branch, tt.projectKey))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheInfoJSON bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "キャッシュに関する操作を行います",
+	Long:  `キャッシュに関する操作を行います。`,
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "キャッシュの取得元の情報を表示します",
+	Long: `キャッシュの取得元の情報を表示します。
+最後のフェッチで使用したサーバー・プロジェクト・JQL・フェッチモード・取得時刻・チケット数を表示し、
+現在の設定のJQLと異なる場合は警告します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		cacheDir, err := config.CacheDir(cfg)
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		meta, err := config.ReadCacheMetadata(cacheDir)
+		if err != nil {
+			return err
+		}
+
+		if cacheInfoJSON {
+			b, err := json.MarshalIndent(struct {
+				CacheDir string                `json:"cache_dir"`
+				Metadata *config.CacheMetadata `json:"metadata"`
+				Stale    bool                  `json:"stale"`
+			}{
+				CacheDir: cacheDir,
+				Metadata: meta,
+				Stale:    meta != nil && !meta.MatchesConfig(cfg),
+			}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		fmt.Printf("キャッシュディレクトリ: %s\n", cacheDir)
+		if meta == nil {
+			fmt.Println("キャッシュのメタデータがありません。'tkt fetch' を実行してください")
+			return nil
+		}
+		fmt.Printf("サーバー:           %s\n", meta.Server)
+		fmt.Printf("プロジェクト:       %s\n", meta.ProjectKey)
+		fmt.Printf("JQL:                %s\n", meta.JQL)
+		fmt.Printf("実行したJQL:        %s\n", meta.ExpandedJQL)
+		fmt.Printf("フェッチモード:     %s\n", meta.FetchMode)
+		fmt.Printf("取得時刻:           %s\n", meta.FetchedAt.Local().Format(time.DateTime))
+		fmt.Printf("チケット数:         %d\n", meta.TicketCount)
+		if staleness, err := checkCacheStaleness(cfg); err == nil && staleness.Stale() {
+			fmt.Printf("⚠️  キャッシュが古くなっています (push.max_cache_age: %s)\n", staleness.MaxAge)
+		}
+
+		warnCacheMetadataMismatch(cfg)
+		return nil
+	},
+}
+
+// warnCacheMetadataMismatch は現在の設定がキャッシュ取得時の設定と異なる場合に警告を表示します
+func warnCacheMetadataMismatch(cfg *config.Config) {
+	changes := cacheMetadataMismatch(cfg)
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  警告: 現在の設定はキャッシュ取得時の設定と異なります\n")
+	for _, change := range changes {
+		fmt.Fprintf(os.Stderr, "   %s\n", change)
+	}
+	fmt.Fprintf(os.Stderr, "   'tkt fetch --clean' でキャッシュを取得し直してください\n")
+}
+
+// cacheMetadataMismatch はこのワークスペースで最後にフェッチしたときの設定から変わった項目を返します
+// キャッシュディレクトリはサーバーとJQLごとに分かれるため、ワークスペースごとに保存した前回のフェッチの設定と比較します。
+// (ワークスペースの記録がない場合はキャッシュディレクトリのメタデータと比較します)
+func cacheMetadataMismatch(cfg *config.Config) []string {
+	meta, err := config.ReadWorkspaceMetadata()
+	if err != nil {
+		return nil
+	}
+	if meta == nil {
+		cacheDir, err := config.CacheDir(cfg)
+		if err != nil {
+			return nil
+		}
+		if meta, err = config.ReadCacheMetadata(cacheDir); err != nil || meta == nil {
+			return nil
+		}
+	}
+	if meta.MatchesConfig(cfg) {
+		return nil
+	}
+	var changes []string
+	if meta.Server != cfg.Server {
+		changes = append(changes, fmt.Sprintf("サーバー: %s (キャッシュ) → %s (現在)", meta.Server, cfg.Server))
+	}
+	if meta.ProjectKey != cfg.Project.Key {
+		changes = append(changes, fmt.Sprintf("プロジェクト: %s (キャッシュ) → %s (現在)", meta.ProjectKey, cfg.Project.Key))
+	}
+	if meta.JQL != cfg.JQL {
+		changes = append(changes, fmt.Sprintf("JQL: %s (キャッシュ) → %s (現在)", meta.JQL, cfg.JQL))
+	}
+	return changes
+}
+
+// cacheStaleness はキャッシュの古さを表します
+type cacheStaleness struct {
+	LastFetch time.Time
+	MaxAge    time.Duration
+}
+
+// Stale はキャッシュが許容する古さを超えているかを返します
+// 一度もフェッチしていない場合も古いとみなします。
+func (s cacheStaleness) Stale() bool {
+	return s.LastFetch.IsZero() || time.Since(s.LastFetch) > s.MaxAge
+}
+
+// String はキャッシュの古さを表示用の文字列で返します
+func (s cacheStaleness) String() string {
+	if s.LastFetch.IsZero() {
+		return "未フェッチ"
+	}
+	return fmt.Sprintf("%s前 (%s)", time.Since(s.LastFetch).Truncate(time.Minute), s.LastFetch.Local().Format(time.DateTime))
+}
+
+// checkCacheStaleness は最終フェッチ時刻とpush.max_cache_ageからキャッシュの古さを求めます
+func checkCacheStaleness(cfg *config.Config) (cacheStaleness, error) {
+	maxAge, err := cfg.PushMaxCacheAge()
+	if err != nil {
+		return cacheStaleness{}, err
+	}
+	lastFetch, err := config.GetLastFetchTime()
+	if err != nil {
+		return cacheStaleness{}, err
+	}
+	return cacheStaleness{LastFetch: lastFetch, MaxAge: maxAge}, nil
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheInfoCmd)
+	rootCmd.AddCommand(cacheCmd)
+
+	cacheInfoCmd.Flags().BoolVar(&cacheInfoJSON, "json", false, "JSON形式で出力")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheMetadataMismatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	cfg := &config.Config{Server: "https://company.atlassian.net", JQL: "project = PRJ"}
+	cfg.Project.Key = "PRJ"
+
+	// まだフェッチしていない場合は警告しない
+	assert.Empty(t, cacheMetadataMismatch(cfg))
+
+	assert.NoError(t, config.SaveWorkspaceMetadata(config.CacheMetadata{Server: cfg.Server, ProjectKey: "PRJ", JQL: cfg.JQL}))
+	assert.Empty(t, cacheMetadataMismatch(cfg))
+
+	// JQLを変えるとキャッシュディレクトリも変わるが、ワークスペースの前回のフェッチと比較して警告する
+	changed := *cfg
+	changed.JQL = "project = PRJ AND sprint in openSprints()"
+	changed.Project.Key = "NEW"
+	assert.Equal(t, []string{
+		"プロジェクト: PRJ (キャッシュ) → NEW (現在)",
+		"JQL: project = PRJ (キャッシュ) → project = PRJ AND sprint in openSprints() (現在)",
+	}, cacheMetadataMismatch(&changed))
+}
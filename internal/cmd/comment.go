@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commentMessage string
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment TICKET-KEY",
+	Short: "JIRAチケットにコメントを追加します",
+	Long: `JIRAチケットにコメントを追加します。
+-m でコメントを指定しない場合はエディタ (環境変数VISUAL、EDITORの順、未設定の場合はvim) を開きます。
+コメントはMarkdownで記述でき、JIRA記法に変換して送信します。`,
+	Example: `  tkt comment PRJ-123 -m "レビューお願いします"
+  tkt comment PRJ-123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := args[0]
+		if !utils.IsValidJIRAKey(key) {
+			return fmt.Errorf("無効なJIRAキーです: %s", key)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		body := strings.TrimSpace(commentMessage)
+		if !cmd.Flags().Changed("message") {
+			body, err = openEditor()
+			if err != nil {
+				return err
+			}
+		}
+		if body == "" {
+			return fmt.Errorf("コメントが空です")
+		}
+
+		commentID, err := ui.WithSpinnerValue("コメントを追加中...", func() (string, error) {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return "", fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return jiraClient.AddComment(key, body)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ %s にコメントを追加しました (ID: %s)\n", key, commentID)
+		fmt.Printf("   %s/browse/%s?focusedCommentId=%s\n", cfg.Server, key, commentID)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commentCmd)
+
+	commentCmd.Flags().StringVarP(&commentMessage, "message", "m", "", "コメントの本文 (Markdown)")
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "設定ファイルに関する操作を行います",
+	Long:  `設定ファイルに関する操作を行います。`,
+}
+
+var configRefreshTypesCmd = &cobra.Command{
+	Use:   "refresh-types",
+	Short: "設定ファイルのissue.typesをJIRAの最新の状態に更新します",
+	Long: `設定ファイルのissue.typesをJIRAの最新の状態に更新します。
+Issue Typeの名前が管理者によって変更された場合に実行してください。
+フェッチ済みのチケットはtype_id (Issue TypeのID) でIssue Typeを特定するため、
+チケットのファイルのtypeが古い名前のままでもpushできます。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		types, err := ui.WithSpinnerValue("Issue Types一覧を取得中...", func() ([]config.IssueType, error) {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return jiraClient.FetchIssueTypes()
+		})
+		if err != nil {
+			return err
+		}
+
+		changes := issueTypeChanges(cfg.Issue.Types, types)
+		if err := config.SaveIssueTypes(types); err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			fmt.Println("issue.typesに変更はありません")
+			return nil
+		}
+		fmt.Println("issue.typesを更新しました:")
+		for _, change := range changes {
+			fmt.Printf("  %s\n", change)
+		}
+		return nil
+	},
+}
+
+// issueTypeChanges は更新前後のissue.typesの違い (名前の変更・追加・削除) を表示用に返します
+func issueTypeChanges(before, after []config.IssueType) []string {
+	old := make(map[string]config.IssueType, len(before))
+	for _, it := range before {
+		old[it.ID] = it
+	}
+	var changes []string
+	seen := make(map[string]bool, len(after))
+	for _, it := range after {
+		seen[it.ID] = true
+		prev, ok := old[it.ID]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("追加: %s (ID: %s)", it.Name, it.ID))
+		case prev.Name != it.Name:
+			changes = append(changes, fmt.Sprintf("名前の変更: %s → %s (ID: %s)", prev.Name, it.Name, it.ID))
+		}
+	}
+	for _, it := range before {
+		if !seen[it.ID] {
+			changes = append(changes, fmt.Sprintf("削除: %s (ID: %s)", it.Name, it.ID))
+		}
+	}
+	return changes
+}
+
+func init() {
+	configCmd.AddCommand(configRefreshTypesCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -86,19 +87,33 @@ func runCreate() error {
 		return fmt.Errorf("基本情報の入力がキャンセルされました: %v", err)
 	}
 
+	// JIRAクライアントはスプリントとエピックの選択で共有する
+	var jiraClient *jira.Client
+	getClient := func() (*jira.Client, error) {
+		if jiraClient != nil {
+			return jiraClient, nil
+		}
+		c, err := jira.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		jiraClient = c
+		return c, nil
+	}
+
 	// 3. スプリント選択
 	var selectedSprintName string
 
-	if cfg.Board.ID != 0 {
+	if boardIDs := cfg.BoardIDs(); len(boardIDs) > 0 {
 		// JIRAクライアントを作成
-		jiraClient, err := jira.NewClient(cfg)
+		jiraClient, err := getClient()
 		if err != nil {
-			return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			return err
 		}
 
 		// アクティブと未来のスプリントを取得
 		sprints, err := ui.WithSpinnerValue("スプリント情報を取得中...", func() ([]jira.Sprint, error) {
-			return jiraClient.GetActiveAndFutureSprints(cfg.Board.ID)
+			return jiraClient.GetActiveAndFutureSprintsOfBoards(boardIDs)
 		})
 		if err != nil {
 			fmt.Printf("⚠️  スプリント情報の取得に失敗しました: %v\n", err)
@@ -110,8 +125,14 @@ func runCreate() error {
 				return stateOrder[sprints[i].State] < stateOrder[sprints[j].State]
 			})
 
+			// 同じ名前のスプリントが複数のボードにある場合はボード名を表示する
+			boardNames := map[int]string{}
+			if hasDuplicateSprintNames(sprints) {
+				boardNames = jiraClient.FetchBoardNames(boardIDs)
+			}
+
 			// スプリント選択オプションを準備
-			sprintSelectorOptions := make([]ui.SelectorOption, len(sprints)+1)
+			sprintSelectorOptions := make([]ui.SelectorOption, len(sprints)+2)
 
 			// "スプリントに追加しない"オプションを先頭に追加
 			sprintSelectorOptions[0] = ui.SelectorOption{
@@ -119,6 +140,12 @@ func runCreate() error {
 				Description: "スプリントを指定せずにチケットを作成",
 				Value:       "",
 			}
+			// push時点のアクティブなスプリントに追加するオプション
+			sprintSelectorOptions[1] = ui.SelectorOption{
+				Title:       jira.SprintActive,
+				Description: "push時点のアクティブなスプリントに追加 (push後に実際のスプリント名に置き換えます)",
+				Value:       jira.SprintActive,
+			}
 
 			for i, sprint := range sprints {
 				statusEmoji := ""
@@ -129,8 +156,12 @@ func runCreate() error {
 					statusEmoji = "🔵 "
 				}
 
-				sprintSelectorOptions[i+1] = ui.SelectorOption{
-					Title:       fmt.Sprintf("%s%s (%s)", statusEmoji, sprint.Name, sprint.State),
+				title := fmt.Sprintf("%s%s (%s)", statusEmoji, sprint.Name, sprint.State)
+				if name, ok := boardNames[sprint.BoardID]; ok {
+					title += fmt.Sprintf(" [%s]", name)
+				}
+				sprintSelectorOptions[i+2] = ui.SelectorOption{
+					Title:       title,
 					Description: fmt.Sprintf("ID: %d | 開始: %s | 終了: %s", sprint.ID, sprint.StartDate, sprint.EndDate),
 					Value:       sprint.Name,
 				}
@@ -148,6 +179,28 @@ func runCreate() error {
 		fmt.Println("\n⚠️  ボード設定が見つかりません。スプリント選択はスキップします。")
 	}
 
+	// エピック選択 (エピックを親にできる標準のIssue Typeのみ)
+	var selectedEpicKey string
+	if cfg.HierarchyLevel(selectedType) == config.HierarchyStandard {
+		jiraClient, err := getClient()
+		if err != nil {
+			return err
+		}
+		epics, err := ui.WithSpinnerValue("エピックを取得中...", func() ([]*ticket.Ticket, error) {
+			return jiraClient.FetchEpics(context.Background())
+		})
+		if err != nil {
+			fmt.Printf("⚠️  エピックの取得に失敗しました: %v\n", err)
+			fmt.Println("エピックを選択せずに作成を続行します...")
+		} else if len(epics) > 0 {
+			selectedEpicKey, err = selectRemoteEpic(epics)
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				fmt.Println("エピックを選択せずに作成を続行します...")
+			}
+		}
+	}
+
 	// 4. ボディをvimエディタで入力
 	fmt.Println("\n📝 ボディを編集します (vimエディタが開きます)...")
 	body, err := openEditor()
@@ -164,8 +217,11 @@ func runCreate() error {
 		Key:        "", // リモートが採番するため空文字列
 		Title:      title,
 		Type:       selectedType,
+		ParentKey:  selectedEpicKey,
 		Body:       body,
 		SprintName: selectedSprintName,
+		// strict_ticket_detection ではkeyのない下書きをマーカーでチケットとして認識する
+		Marker: cfg.StrictTicketDetection,
 	}
 
 	// 6. ローカルファイルとして保存
@@ -177,22 +233,93 @@ func runCreate() error {
 		return fmt.Errorf("ローカルファイルの保存に失敗しました: %v", err)
 	}
 
+	// 選択したIssue Typeの作成時の必須項目を、入力欄としてフロントマターに追加する
+	var missing []jira.RequiredField
+	if jiraClient, err := getClient(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	} else if missing, err = draftRequiredFields(jiraClient, newTicket); err != nil {
+		fmt.Printf("⚠️  必須項目の取得に失敗しました: %v\n", err)
+	} else if err := addRequiredPlaceholders(filePath, missing); err != nil {
+		return err
+	}
+
 	fmt.Println("\n✅ ローカルチケットが作成されました！")
 	fmt.Printf("   タイトル: %s\n", newTicket.Title)
 	fmt.Printf("   タイプ: %s\n", newTicket.Type)
 	if selectedSprintName != "" {
 		fmt.Printf("   スプリント: %s\n", selectedSprintName)
 	}
+	if selectedEpicKey != "" {
+		fmt.Printf("   エピック: %s\n", selectedEpicKey)
+	}
 	fmt.Printf("   ファイル: %s\n", filePath)
+	if len(missing) > 0 {
+		labels := make([]string, 0, len(missing))
+		for _, f := range missing {
+			labels = append(labels, f.Label())
+		}
+		fmt.Printf("   必須項目: %s (pushの前に入力してください)\n", strings.Join(labels, ", "))
+	}
 	fmt.Printf("   次のステップ: 'tkt push' でJIRAに同期してキーを取得\n")
 
 	return nil
 }
 
-// openEditor はvimエディタを開いてユーザーに入力させます
+// draftRequiredFields は下書きのIssue Typeの作成時の必須項目のうち、未入力のものを返します
+func draftRequiredFields(jiraClient *jira.Client, t *ticket.Ticket) ([]jira.RequiredField, error) {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	required, err := ui.WithSpinnerValue("必須項目を確認中...", func() ([]jira.RequiredField, error) {
+		return jiraClient.RequiredFields(context.Background(), cacheDir, t.Type)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return missingRequiredFields(t, required), nil
+}
+
+// requiredPlaceholderLines は未入力の必須項目をフロントマターに追加する行にします
+// tktで設定できる項目は空の値を、本文とtktで設定できない項目はコメントを追加します。
+func requiredPlaceholderLines(missing []jira.RequiredField) []string {
+	var lines []string
+	seen := make(map[string]bool)
+	for _, f := range missing {
+		switch {
+		case f.FrontmatterKey == "":
+			lines = append(lines, fmt.Sprintf("# 必須: %s はtktから設定できません (JIRAで作成するか、JIRAで既定値を設定してください)", f.Label()))
+		case f.FrontmatterKey == "body":
+			lines = append(lines, fmt.Sprintf("# 必須: %s は本文に入力してください", f.Name))
+		case !seen[f.FrontmatterKey]:
+			seen[f.FrontmatterKey] = true
+			lines = append(lines, fmt.Sprintf("%s: # 必須: %s", f.FrontmatterKey, f.Name))
+		}
+	}
+	return lines
+}
+
+// addRequiredPlaceholders は下書きのファイルのフロントマターに未入力の必須項目の入力欄を追加します
+func addRequiredPlaceholders(filePath string, missing []jira.RequiredField) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("下書きの読み込みに失敗しました: %v", err)
+	}
+	updated := ticket.InsertFrontmatterLines(string(content), requiredPlaceholderLines(missing))
+	if err := os.WriteFile(filePath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("下書きへの必須項目の追加に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// openEditor はエディタを開いてユーザーに入力させます
+// 環境変数VISUALまたはEDITORが設定されている場合はそのエディタを、それ以外はvimを使用します。
 func openEditor() (string, error) {
 	// 一時ファイルを作成
-	tmpFile, err := os.CreateTemp("", "tkt-create-*.md")
+	tmpFile, err := os.CreateTemp("", "tkt-*.md")
 	if err != nil {
 		return "", fmt.Errorf("一時ファイルの作成に失敗しました: %v", err)
 	}
@@ -209,14 +336,15 @@ func openEditor() (string, error) {
 
 	tmpFile.Close()
 
-	// vimエディタを起動 (insertモードで開始)
-	cmd := exec.Command("vim", "+startinsert", tmpFile.Name())
+	// エディタを起動
+	name, args := editorCommand()
+	cmd := exec.Command(name, append(args, tmpFile.Name())...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("vimエディタの実行に失敗しました: %v", err)
+		return "", fmt.Errorf("エディタ (%s) の実行に失敗しました: %v", name, err)
 	}
 
 	// ファイルの変更を確認
@@ -240,3 +368,34 @@ func openEditor() (string, error) {
 
 	return body, nil
 }
+
+// editorCommand は起動するエディタのコマンドと引数を返します
+// VISUALもEDITORも未設定の場合はvimをinsertモードで起動します。
+func editorCommand() (string, []string) {
+	if fields := configuredEditor(); len(fields) > 0 {
+		return fields[0], fields[1:]
+	}
+	return "vim", []string{"+startinsert"}
+}
+
+// configuredEditor は環境変数VISUAL、EDITORの順に設定されているエディタのコマンドを返します
+func configuredEditor() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// hasDuplicateSprintNames は同じ名前のスプリントが複数あるかを返します
+func hasDuplicateSprintNames(sprints []jira.Sprint) bool {
+	seen := make(map[string]bool, len(sprints))
+	for _, sprint := range sprints {
+		if seen[sprint.Name] {
+			return true
+		}
+		seen[sprint.Name] = true
+	}
+	return false
+}
@@ -16,14 +16,18 @@ import (
 )
 
 var (
-	diffDir    string
-	diffFormat string
+	diffDir     string
+	diffFormat  string
+	diffContext int
+	diffFull    bool
 )
 
 var diffCmd = &cobra.Command{
 	Use:   "diff",
 	Short: "ローカルとリモートにあるJIRAチケットの差分を表示します。",
-	Long:  `ローカルで編集したJIRAチケットとリモートにあるJIRAチケットの差分を表示します。`,
+	Long: `ローカルで編集したJIRAチケットとリモートにあるJIRAチケットの差分を表示します。
+--context で差分の前後に表示する行数 (デフォルトは設定ファイルの diff.context、なければ3行) を、
+--full で変更されたチケットのファイル全体を表示します。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 1. 設定ファイルを読み込む
 		cfg, err := config.LoadConfig()
@@ -39,7 +43,12 @@ var diffCmd = &cobra.Command{
 			diffDir = cfg.Directory
 		}
 
-		verbose.Printf("ローカルとリモートのJIRAチケットの差分を表示します（ディレクトリ: %s, フォーマット: %s）\n", diffDir, diffFormat)
+		contextLines, err := diffContextLines(cmd, cfg, diffContext, diffFull)
+		if err != nil {
+			return err
+		}
+
+		verbose.Printf(verbose.Diff, "ローカルとリモートのJIRAチケットの差分を表示します（ディレクトリ: %s, フォーマット: %s）\n", diffDir, diffFormat)
 
 		// 2. キャッシュディレクトリを確保
 		cacheDir, err := config.EnsureCacheDir()
@@ -47,22 +56,55 @@ var diffCmd = &cobra.Command{
 			return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
 		}
 
+		// 3. 手動で編集されたキャッシュを警告
+		warnTamperedCache(cacheDir)
+
 		// 4. ローカルとキャッシュの差分を検出
-		verbose.Printf("ローカルディレクトリ %s とキャッシュの差分を検出中...\n", diffDir)
-		diffs, err := ticket.CompareDirs(diffDir, cacheDir)
+		verbose.Printf(verbose.Diff, "ローカルディレクトリ %s とキャッシュの差分を検出中...\n", diffDir)
+		diffs, err := ticket.CompareDirs(diffDir, cacheDir, append(compareOptions(cfg), ticket.WithContextLines(contextLines))...)
 		if err != nil {
 			return fmt.Errorf("差分の検出に失敗しました: %v", err)
 		}
 
 		// 5. 差分を表示
 		if diffFormat == "json" {
-			return displayDiffsAsJSON(diffs)
+			return displayDiffsAsJSON(diffs, contextLines)
 		} else {
 			return displayDiffsAsText(diffs)
 		}
 	},
 }
 
+// warnTamperedCache は最終フェッチより後に手動で編集されたキャッシュのファイルがあれば警告します
+// キャッシュは差分の基準なので、編集されていると差分が正しく表示されません。
+func warnTamperedCache(cacheDir string) {
+	tampered := tamperedCacheFiles(cacheDir)
+	if len(tampered) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "⚠️  キャッシュのファイルが手動で編集されています (差分が正しくない可能性があります):")
+	for _, path := range tampered {
+		fmt.Fprintf(os.Stderr, "   %s\n", path)
+	}
+	fmt.Fprintln(os.Stderr, "   'tkt fetch --clean' でキャッシュを取得し直してください")
+}
+
+// tamperedCacheFiles は最終フェッチより後に手動で編集されたキャッシュのファイルを返します
+// 調べられない場合は詳細ログに出力して何も返しません。
+func tamperedCacheFiles(cacheDir string) []string {
+	lastFetch, err := config.ReadLastFetchTime(cacheDir)
+	if err != nil {
+		verbose.Printf(verbose.Diff, "警告: %v\n", err)
+		return nil
+	}
+	tampered, err := ticket.TamperedCacheFiles(cacheDir, lastFetch)
+	if err != nil {
+		verbose.Printf(verbose.Diff, "警告: %v\n", err)
+		return nil
+	}
+	return tampered
+}
+
 // displayDiffsAsText はテキスト形式で差分を表示します
 func displayDiffsAsText(diffs []ticket.DiffResult) error {
 	changedCount := 0
@@ -82,6 +124,7 @@ func displayDiffsAsText(diffs []ticket.DiffResult) error {
 			} else {
 				output.WriteString(fmt.Sprintf("\n\n[変更] %s (%s)\n", diff.Key, diff.FilePath))
 			}
+			output.WriteString(diff.Header() + "\n")
 			if diff.DiffText != "" {
 				output.WriteString("差分:\n")
 				output.WriteString(diff.DiffText)
@@ -96,19 +139,35 @@ func displayDiffsAsText(diffs []ticket.DiffResult) error {
 		output.WriteString(fmt.Sprintf("\n\n[変更なし] %d件のチケットには変更がありません\n", unchangedCount))
 	}
 
+	// 無視した項目の変更も見えなくならないように一覧で示す
+	for _, diff := range diffs {
+		if len(diff.IgnoredFields) > 0 {
+			output.WriteString(fmt.Sprintf("\n⚠️  %s: diff.ignore_fields により無視した変更があります: %s", diff.Key, strings.Join(diff.IgnoredFields, ", ")))
+		}
+		if len(diff.ReadonlyEdits) > 0 {
+			output.WriteString("\n⚠️  " + readonlyEditMessage(diff))
+		}
+	}
+
 	output.WriteString(fmt.Sprintf("\n概要: %d件変更, %d件変更なし\n", changedCount, unchangedCount))
 
 	return displayWithPager(output.String())
 }
 
 // displayDiffsAsJSON はJSON形式で差分を表示します
-func displayDiffsAsJSON(diffs []ticket.DiffResult) error {
+// context には差分の前後に表示した行数 (--full の場合は "full") を出力します。
+func displayDiffsAsJSON(diffs []ticket.DiffResult, contextLines int) error {
+	var context interface{} = contextLines
+	if contextLines == ticket.FullContext {
+		context = "full"
+	}
 	output := map[string]interface{}{
 		"summary": map[string]int{
 			"changed":   0,
 			"unchanged": 0,
 		},
-		"diffs": diffs,
+		"context": context,
+		"diffs":   diffs,
 	}
 
 	// 統計を計算
@@ -171,4 +230,6 @@ func init() {
 	// フラグの設定
 	diffCmd.Flags().StringVarP(&diffDir, "dir", "d", "", "比較対象のローカルディレクトリ")
 	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "出力フォーマット (text|json)")
+	diffCmd.Flags().IntVar(&diffContext, "context", ticket.DefaultContextLines, "差分の前後に表示する行数")
+	diffCmd.Flags().BoolVar(&diffFull, "full", false, "変更されたチケットのファイル全体を表示する")
 }
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStdout は fn の実行中に標準出力に書かれた内容を返します
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+// setupDirectoryWorkspace は directory の設定値を dir にしたワークスペースを作成します
+// キャッシュには PRJ-1 から PRJ-3 があり、ワークスペースでは PRJ-1 の変更、PRJ-2 の削除マーク、下書き TMP-1 があります。
+func setupDirectoryWorkspace(t *testing.T, workDir, dir string) *config.Config {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(filepath.Join(workDir, "tkt.yml"), []byte("server: https://company.atlassian.net\nproject:\n  key: PRJ\ndirectory: "+dir+"\n"), 0644))
+	cfg, err := config.LoadConfig()
+	assert.NoError(t, err)
+	cacheDir, err := config.EnsureCacheDir()
+	assert.NoError(t, err)
+	for _, c := range []*ticket.Ticket{
+		{Key: "PRJ-1", Title: "変更するチケット", Type: "task", Body: "本文\n"},
+		{Key: "PRJ-2", Title: "削除するチケット", Type: "task", Body: "本文\n"},
+		{Key: "PRJ-3", Title: "削除するチケット", Type: "task", Body: "本文\n"},
+	} {
+		_, err := c.SaveToCache(cacheDir)
+		assert.NoError(t, err)
+	}
+
+	ticketsDir := filepath.Join(workDir, "tickets")
+	assert.NoError(t, os.MkdirAll(ticketsDir, 0755))
+	for _, l := range []*ticket.Ticket{
+		{Key: "PRJ-1", Title: "変更したチケット", Type: "task", Body: "本文\n"},
+		{Key: "PRJ-2", Title: "削除するチケット", Type: "task", Body: "本文\n"},
+		{Key: "PRJ-3", Title: "削除するチケット", Type: "task", Body: "本文\n"},
+	} {
+		_, err := l.SaveToFile(ticketsDir)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, os.Rename(filepath.Join(ticketsDir, "PRJ-2.md"), filepath.Join(ticketsDir, ".PRJ-2.md")))
+	draft := "---\ntitle: 下書き\ntype: task\ntkt: true\n---\n本文\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(ticketsDir, "TMP-1.md"), []byte(draft), 0644))
+	return cfg
+}
+
+// TestDirectoryVariants は directory を ./tickets, tickets/, 絶対パスのどれで書いても
+// diff, push, rm が同じファイルを対象にすることを確かめます
+func TestDirectoryVariants(t *testing.T) {
+	t.Setenv("PAGER", "cat")
+
+	type result struct {
+		diffs       []string // diffの結果 (変更の種類とワークスペースからの相対パス)
+		pushTargets []string // pushの対象 (ワークスペースからの相対パス)
+		removed     []string // rmの後のワークスペースのファイル
+	}
+	run := func(t *testing.T, dir func(workDir string) string) result {
+		t.Setenv("HOME", t.TempDir())
+		workDir := t.TempDir()
+		t.Chdir(workDir)
+		// macOSなどでTempDirがシンボリックリンクの場合に備えて、実際の作業ディレクトリを使う
+		workDir, err := os.Getwd()
+		assert.NoError(t, err)
+		cfg := setupDirectoryWorkspace(t, workDir, dir(workDir))
+		rel := func(path string) string {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(workDir, path)
+			}
+			r, err := filepath.Rel(workDir, path)
+			assert.NoError(t, err)
+			return filepath.ToSlash(r)
+		}
+		var res result
+
+		// diff
+		diffDir, diffFormat = "", "json"
+		defer func() { diffDir, diffFormat = "", "text" }()
+		out := captureStdout(t, func() {
+			assert.NoError(t, diffCmd.RunE(diffCmd, nil))
+		})
+		var diffOutput struct {
+			Diffs []ticket.DiffResult `json:"diffs"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(out), &diffOutput), out)
+		for _, d := range diffOutput.Diffs {
+			if d.HasDiff {
+				res.diffs = append(res.diffs, string(d.Change)+" "+rel(d.FilePath))
+			}
+		}
+		sort.Strings(res.diffs)
+
+		// push: キー、下書きのファイル名、パスのどれで指定しても同じファイルを対象にする
+		cacheDir, err := config.EnsureCacheDir()
+		assert.NoError(t, err)
+		diffs, err := ticket.CompareDirs(cfg.Directory, cacheDir, compareOptions(cfg)...)
+		assert.NoError(t, err)
+		targets, err := resolvePushTargets(cfg.Directory, []string{"PRJ-1", "TMP-1", filepath.Join("tickets", ".PRJ-2.md")})
+		assert.NoError(t, err)
+		for _, d := range filterPushTargets(diffs, targets) {
+			res.pushTargets = append(res.pushTargets, rel(d.FilePath))
+		}
+		sort.Strings(res.pushTargets)
+
+		// rm
+		assert.NoError(t, runDirectRM(cfg, []string{"PRJ-3"}))
+		entries, err := os.ReadDir(filepath.Join(workDir, "tickets"))
+		assert.NoError(t, err)
+		for _, e := range entries {
+			res.removed = append(res.removed, e.Name())
+		}
+		return res
+	}
+
+	want := result{
+		diffs:       []string{"create tickets/TMP-1.md", "delete tickets/.PRJ-2.md", "update tickets/PRJ-1.md"},
+		pushTargets: []string{"tickets/.PRJ-2.md", "tickets/PRJ-1.md", "tickets/TMP-1.md"},
+		removed:     []string{".PRJ-2.md", ".PRJ-3.md", "PRJ-1.md", "TMP-1.md"},
+	}
+	tests := []struct {
+		name string
+		dir  func(workDir string) string
+	}{
+		{name: "tickets", dir: func(string) string { return "tickets" }},
+		{name: "./tickets", dir: func(string) string { return "./tickets" }},
+		{name: "tickets/", dir: func(string) string { return "tickets/" }},
+		{name: "絶対パス", dir: func(workDir string) string { return filepath.Join(workDir, "tickets") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, want, run(t, tt.dir))
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [TICKET-KEY]",
+	Short: "チケットをエディタで編集します",
+	Long: `ワークスペースのチケットのファイルをエディタ (環境変数VISUAL、EDITORの順、未設定の場合はvim) で開きます。
+TICKET-KEYを省略した場合は、ワークスペースのチケットから対話的に選択します。
+ワークスペースにファイルがなくキャッシュにある場合は、キャッシュからコピーしてから開きます。
+削除マークが付いたチケットは開けません。
+
+エディタの終了後、push と同じ方法でこのファイルの差分を表示し、そのままpushするかを確認します。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+		if cfg.Directory == "" {
+			return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+		}
+
+		var t *ticket.Ticket
+		if len(args) > 0 {
+			key := ticket.CanonicalKey(args[0])
+			if !utils.IsValidJIRAKey(key) {
+				return fmt.Errorf("無効なチケットキーです: %s", args[0])
+			}
+			t, err = loadWorkspaceTicket(cfg, key)
+		} else {
+			t, err = pickWorkspaceTicket(cfg)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := editFile(t.FilePath); err != nil {
+			return err
+		}
+
+		return pushEditedFile(cfg, t.FilePath)
+	},
+}
+
+// pickWorkspaceTicket はワークスペースのチケットから1件を選択させます
+// ワークスペースにチケットがない場合はキャッシュのチケットから選択し、ワークスペースにコピーします。
+func pickWorkspaceTicket(cfg *config.Config) (*ticket.Ticket, error) {
+	tickets, err := loadTickets(cfg.Directory, cfg.StrictTicketDetection)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
+	}
+	if len(tickets) == 0 {
+		t, err := pickCachedTicket()
+		if err != nil {
+			return nil, err
+		}
+		return loadWorkspaceTicket(cfg, t.Key)
+	}
+	return pickTicket(tickets, cfg.Directory, nil)
+}
+
+// editFile はファイルをエディタで開き、エディタが終了するまで待ちます
+func editFile(path string) error {
+	name, args := "vim", []string(nil)
+	if fields := configuredEditor(); len(fields) > 0 {
+		name, args = fields[0], fields[1:]
+	}
+	c := exec.Command(name, append(args, path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("エディタ (%s) の実行に失敗しました: %v", name, err)
+	}
+	return nil
+}
+
+// pushEditedFile は編集したファイルの差分を push と同じ方法で検出し、確認のうえpushします
+func pushEditedFile(cfg *config.Config, path string) error {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+
+	// まずはJIRAにアクセスせずに差分があるかを確認する
+	d, err := diffForFile(cfg, cacheDir, path)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		fmt.Println("差分はありません")
+		return nil
+	}
+
+	// push と同様に最新のチケットをキャッシュに取得してから差分を検出し直す
+	type editResult struct {
+		diff       *ticket.DiffResult
+		jiraClient *jira.Client
+	}
+	result, err := ui.WithSpinnerValue("差分を検出中...", func() (editResult, error) {
+		jiraClient, err := jira.NewClient(cfg)
+		if err != nil {
+			return editResult{}, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		jiraClient.SetSprintCacheDir(cacheDir)
+		if d.Key != "" && d.Change == ticket.ChangeUpdate {
+			remoteTicket, err := jiraClient.FetchIssue(d.Key)
+			if err != nil {
+				return editResult{}, err
+			}
+			if _, err := remoteTicket.SaveToCache(cacheDir); err != nil {
+				return editResult{}, err
+			}
+		}
+		d, err := diffForFile(cfg, cacheDir, path)
+		if err != nil {
+			return editResult{}, err
+		}
+		return editResult{diff: d, jiraClient: jiraClient}, nil
+	})
+	if err != nil {
+		return err
+	}
+	if result.diff == nil {
+		fmt.Println("差分はありません")
+		return nil
+	}
+	if err := checkUnsupportedADF([]ticket.DiffResult{*result.diff}); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== ファイル: %s ===\n", result.diff.FilePath)
+	fmt.Println(result.diff.Header())
+	fmt.Printf("差分:\n%s\n", result.diff.DiffText)
+	if !utils.PromptForConfirmation("このファイルをpushしますか？") {
+		fmt.Println("pushをスキップしました。あとで 'tkt push' でpushできます")
+		return nil
+	}
+
+	var stats pushStats
+	err = ui.WithSpinner("変更を適用中...", func() error {
+		stats, err = applyPush(result.jiraClient, newPushOptions(cfg), []ticket.DiffResult{*result.diff})
+		return err
+	})
+	printPushNotices(stats)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ pushしました: %d 件作成, %d 件更新\n", stats.created, stats.updated)
+	return nil
+}
+
+// diffForFile はワークスペースの差分のうち、指定したファイルの差分を返します。差分がない場合は nil を返します。
+func diffForFile(cfg *config.Config, cacheDir, path string) (*ticket.DiffResult, error) {
+	diffs, err := ticket.CompareDirs(cfg.Directory, cacheDir, compareOptions(cfg)...)
+	if err != nil {
+		return nil, fmt.Errorf("差分の検出に失敗しました: %v", err)
+	}
+	for _, d := range diffs {
+		if filepath.Clean(d.FilePath) == filepath.Clean(path) && d.HasDiff {
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfiguredEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		visual string
+		editor string
+		want   []string
+	}{
+		{name: "VISUALを優先する", visual: "code --wait", editor: "nano", want: []string{"code", "--wait"}},
+		{name: "VISUALが未設定の場合はEDITOR", editor: "nano", want: []string{"nano"}},
+		{name: "どちらも未設定", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			assert.Equal(t, tt.want, configuredEditor())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envJSON bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "tktが使用するパスや設定値を表示します",
+	Long: `tktが使用するパスや設定値をKEY=VALUE形式で表示します。
+拡張機能やシェルスクリプトから 'eval $(tkt env)' のように利用できます。
+ネットワークアクセスを行わないため、認証情報が未設定でも動作します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		vars, err := tktEnv(cfg)
+		if err != nil {
+			return err
+		}
+
+		if envJSON {
+			m := make(map[string]string, len(vars))
+			for _, v := range vars {
+				m[v.key] = v.value
+			}
+			b, err := json.MarshalIndent(m, "", "  ")
+			if err != nil {
+				return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		for _, v := range vars {
+			fmt.Printf("%s=%s\n", v.key, shellQuote(v.value))
+		}
+		return nil
+	},
+}
+
+type envVar struct {
+	key   string
+	value string
+}
+
+// tktEnv は表示する環境変数の一覧を組み立てます
+func tktEnv(cfg *config.Config) ([]envVar, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("作業ディレクトリの取得に失敗しました: %v", err)
+	}
+
+	directory := ""
+	if cfg.Directory != "" {
+		directory = cfg.Directory
+		if !filepath.IsAbs(directory) {
+			directory = filepath.Join(root, directory)
+		}
+	}
+
+	cacheDir, err := config.CacheDir(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+
+	lastFetch := ""
+	lastFetchTime, err := config.ReadLastFetchTime(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	if !lastFetchTime.IsZero() {
+		lastFetch = lastFetchTime.Format(time.RFC3339)
+	}
+
+	return []envVar{
+		{key: "TKT_ROOT", value: root},
+		{key: "TKT_DIRECTORY", value: directory},
+		{key: "TKT_CACHE_DIR", value: cacheDir},
+		{key: "TKT_SERVER", value: cfg.Server},
+		{key: "TKT_PROJECT_KEY", value: cfg.Project.Key},
+		{key: "TKT_LAST_FETCH", value: lastFetch},
+	}, nil
+}
+
+// shellQuote はevalで安全に評価できるようにシングルクォートで値を囲みます
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().BoolVar(&envJSON, "json", false, "JSON形式で出力")
+}
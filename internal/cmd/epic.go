@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var epicCmd = &cobra.Command{
+	Use:   "epic",
+	Short: "エピックに関する操作を行います",
+	Long:  `エピックに関する操作を行います。`,
+}
+
+var epicListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "プロジェクトのエピックを一覧表示します",
+	Long: `プロジェクトのエピックのキー、ステータス、名前を更新日時の新しい順に表示します。
+設定のJQLに関係なく、JIRAからプロジェクトの全てのエピックを取得します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		epics, err := ui.WithSpinnerValue("エピックを取得中...", func() ([]*ticket.Ticket, error) {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return jiraClient.FetchEpics(cmd.Context())
+		})
+		if err != nil {
+			return fmt.Errorf("エピックの取得に失敗しました: %v", err)
+		}
+		return printEpics(os.Stdout, epics)
+	},
+}
+
+func printEpics(w io.Writer, epics []*ticket.Ticket) error {
+	if len(epics) == 0 {
+		fmt.Fprintln(w, "エピックはありません")
+		return nil
+	}
+	rows := [][]string{{"KEY", "STATUS", "NAME"}}
+	for _, e := range epics {
+		rows = append(rows, []string{e.Key, e.Status, e.Title})
+	}
+	return writeTable(w, rows)
+}
+
+// selectRemoteEpic はJIRAから取得したエピックを選択させ、選択されたキーを返します
+// エピックに追加しない場合は空文字を返します。
+func selectRemoteEpic(epics []*ticket.Ticket) (string, error) {
+	options := make([]ui.SelectorOption, 0, len(epics)+1)
+	options = append(options, ui.SelectorOption{
+		Title:       "エピックに追加しない",
+		Description: "親のエピックを指定せずにチケットを作成",
+		Value:       "",
+	})
+	for _, e := range epics {
+		options = append(options, ui.SelectorOption{
+			Title:       fmt.Sprintf("%s %s", e.Key, e.Title),
+			Description: e.Status,
+			Value:       e.Key,
+		})
+	}
+
+	selected, err := ui.Select("🗂  エピックを選択してください:", options)
+	if err != nil {
+		return "", fmt.Errorf("エピックの選択がキャンセルされました: %v", err)
+	}
+	if selected == nil {
+		return "", nil
+	}
+	return selected.(string), nil
+}
+
+func init() {
+	rootCmd.AddCommand(epicCmd)
+	epicCmd.AddCommand(epicListCmd)
+}
@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/qawatake/tkt/internal/adf"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/jira"
 	"github.com/qawatake/tkt/internal/ticket"
@@ -13,8 +21,9 @@ import (
 )
 
 var (
-	outputDir  string
-	cleanFetch bool
+	outputDir           string
+	cleanFetch          bool
+	fetchWithAttachment bool
 )
 
 var fetchCmd = &cobra.Command{
@@ -37,97 +46,331 @@ var fetchCmd = &cobra.Command{
 		}
 
 		// 設定情報をデバッグ表示
-		verbose.Printf("JIRA Server: %s\n", cfg.Server)
-		verbose.Printf("Project Key: %s\n", cfg.Project.Key)
-		verbose.Printf("Auth Type: %s\n", cfg.AuthType)
+		verbose.Printf(verbose.General, "JIRA Server: %s\n", cfg.Server)
+		verbose.Printf(verbose.General, "Project Key: %s\n", cfg.Project.Key)
+		verbose.Printf(verbose.General, "Auth Type: %s\n", cfg.AuthType)
 		if cfg.JQL != "" {
-			verbose.Printf("Custom JQL: %s\n", cfg.JQL)
+			verbose.Printf(verbose.General, "Custom JQL: %s\n", cfg.JQL)
+		}
+
+		// 増分フェッチの場合、キャッシュ取得時と設定が変わっていないか確認
+		if !cleanFetch {
+			warnCacheMetadataMismatch(cfg)
 		}
 
 		// チケット取得処理を一括実行
 		savedCount, err := ui.WithSpinnerValue("チケット取得中...", func() (int, error) {
-			// 2. JIRAに接続
-			jiraClient, err := jira.NewClient(cfg)
-			if err != nil {
-				return 0, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
-			}
+			return fetchToCache(cmd.Context(), cfg, cleanFetch, fetchAttachmentDir())
+		})
+		if err != nil {
+			return err
+		}
 
-			// 3. チケットを取得（増分または全件）
-			var tickets []*ticket.Ticket
-			startTime := time.Now()
-
-			if cleanFetch {
-				verbose.Printf("クリーンフェッチモードで実行します\n")
-				tickets, err = jiraClient.FetchIssues()
-			} else {
-				lastFetch, fetchErr := config.GetLastFetchTime()
-				if fetchErr != nil {
-					verbose.Printf("最終フェッチ時刻の取得に失敗しました: %v\n", fetchErr)
-					verbose.Printf("初回フェッチとして全件取得します\n")
-					tickets, err = jiraClient.FetchIssues()
-				} else if lastFetch.IsZero() {
-					verbose.Printf("初回フェッチのため全件取得します\n")
-					tickets, err = jiraClient.FetchIssues()
-				} else {
-					verbose.Printf("最終フェッチ時刻: %s\n", lastFetch.Format(time.RFC3339))
-					verbose.Printf("増分フェッチモードで実行します\n")
-					tickets, err = jiraClient.FetchIssuesIncremental(lastFetch)
-				}
-			}
+		verbose.Printf(verbose.General, "\n%d 件のチケットを保存しました\n", savedCount)
+		return nil
+	},
+}
 
-			if err != nil {
-				return 0, fmt.Errorf("チケットの取得に失敗しました: %v", err)
-			}
+// fetchAttachmentDir は--with-attachmentsが指定されていれば添付ファイルを保存するディレクトリを返します
+func fetchAttachmentDir() string {
+	if !fetchWithAttachment {
+		return ""
+	}
+	return outputDir
+}
 
-			verbose.Printf("%d 件のチケットを取得しました\n", len(tickets))
-
-			// 5. キャッシュディレクトリを確保
-			var cacheDir string
-			if cleanFetch {
-				// クリーンフェッチの場合は既存ファイルを削除
-				cacheDir, err = config.ClearCacheDir()
-				if err != nil {
-					return 0, fmt.Errorf("キャッシュディレクトリのクリアに失敗しました: %v", err)
-				}
-			} else {
-				// 通常の増分フェッチの場合は既存ファイルを保持
-				cacheDir, err = config.EnsureCacheDir()
-				if err != nil {
-					return 0, fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
-				}
-			}
+// fetchToCache はJIRAからチケットを取得してキャッシュに保存し、保存した件数を返します
+// clean が false の場合は前回のフェッチ以降に更新されたチケットのみを取得します。
+// attachmentDir が空でなければ添付ファイルを attachmentDir/assets/<KEY>/ に保存します。
+// ctx がキャンセルされた場合は取得途中のチケットを保存せずに errFetchCancelled を返します。
+func fetchToCache(ctx context.Context, cfg *config.Config, clean bool, attachmentDir string) (int, error) {
+	// 2. JIRAに接続
+	jiraClient, err := jira.NewClient(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+	if attachmentDir != "" {
+		jiraClient.EnableAttachments()
+	}
 
-			// チケットを処理
-			savedCount := 0
-			for _, ticket := range tickets {
-				// JIRAのイシューからTicketを作成
+	// 3. チケットを取得（増分または全件）
+	var tickets []*ticket.Ticket
+	startTime := time.Now()
+	fetchMode := config.FetchModeFull
+	expandedJQL := jiraClient.BaseJQL()
 
-				// キャッシュディレクトリに保存
-				savedCachePath, err := ticket.SaveToFile(cacheDir)
-				if err != nil {
-					verbose.Printf("警告: チケット %s のキャッシュ保存に失敗しました: %v\n", ticket.Key, err)
-				}
+	if clean {
+		verbose.Printf(verbose.General, "クリーンフェッチモードで実行します\n")
+		tickets, err = jiraClient.FetchIssues(ctx)
+	} else {
+		lastFetch, fetchErr := config.GetLastFetchTime()
+		if fetchErr != nil {
+			verbose.Printf(verbose.General, "最終フェッチ時刻の取得に失敗しました: %v\n", fetchErr)
+			verbose.Printf(verbose.General, "初回フェッチとして全件取得します\n")
+			tickets, err = jiraClient.FetchIssues(ctx)
+		} else if lastFetch.IsZero() {
+			verbose.Printf(verbose.General, "初回フェッチのため全件取得します\n")
+			tickets, err = jiraClient.FetchIssues(ctx)
+		} else {
+			verbose.Printf(verbose.General, "最終フェッチ時刻: %s\n", lastFetch.Format(time.RFC3339))
+			verbose.Printf(verbose.General, "増分フェッチモードで実行します\n")
+			fetchMode = config.FetchModeIncremental
+			expandedJQL = jiraClient.IncrementalJQL(lastFetch)
+			tickets, err = jiraClient.FetchIssuesIncremental(ctx, lastFetch)
+		}
+	}
 
-				verbose.Printf("保存: %s -> %s\n", ticket.Key, savedCachePath)
-				savedCount++
-			}
+	if ctx.Err() != nil {
+		return 0, errFetchCancelled
+	}
+	if err != nil {
+		return 0, fmt.Errorf("チケットの取得に失敗しました: %v", err)
+	}
 
-			// 6. 最終フェッチ時刻を保存
-			if saveErr := config.SaveLastFetchTime(startTime); saveErr != nil {
-				verbose.Printf("警告: 最終フェッチ時刻の保存に失敗しました: %v\n", saveErr)
-			} else {
-				verbose.Printf("最終フェッチ時刻を保存しました: %s\n", startTime.Format(time.RFC3339))
-			}
+	verbose.Printf(verbose.General, "%d 件のチケットを取得しました\n", len(tickets))
+	if summary := unsupportedADFSummary(jiraClient.UnsupportedADFCounts()); summary != "" {
+		verbose.Printf(verbose.General, "警告: 未対応のADFのノードを本文の目印 (%s ...) に置き換えました: %s\n", adf.UnsupportedPlaceholderPrefix, summary)
+	}
 
-			return savedCount, nil
-		})
+	// 5. キャッシュディレクトリを確保
+	var cacheDir string
+	if clean {
+		// クリーンフェッチの場合は一時ディレクトリに書き込み、最後に既存のキャッシュと入れ替える
+		// (途中で失敗しても既存のキャッシュは残る。入れ替えた後は一時ディレクトリは存在しない)
+		cacheDir, err = config.CreateCacheStagingDir()
 		if err != nil {
-			return err
+			return 0, fmt.Errorf("キャッシュの一時ディレクトリの作成に失敗しました: %v", err)
+		}
+		defer os.RemoveAll(cacheDir)
+	} else {
+		// 通常の増分フェッチの場合は既存ファイルを保持
+		cacheDir, err = config.EnsureCacheDir()
+		if err != nil {
+			return 0, fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+		}
+	}
+
+	// キーの大文字小文字の違いで重複したキャッシュをまとめる
+	duplicates, err := ticket.MergeDuplicateCacheFiles(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range duplicates {
+		for _, removed := range d.Removed {
+			verbose.Printf(verbose.Cache, "重複したキャッシュをまとめました: %s -> %s\n", removed, d.Kept)
+		}
+	}
+
+	// チケットを処理 (ネットワークファイルシステムでも遅くならないよう、書き込みをまとめる)
+	writer := ticket.NewCacheWriter(cacheDir, cfg.DurableWrites())
+	savedCount := 0
+	for _, ticket := range tickets {
+		// キャッシュディレクトリに保存
+		savedCachePath, err := writer.Add(ticket)
+		if err != nil {
+			verbose.Printf(verbose.Cache, "警告: チケット %s のキャッシュ保存に失敗しました: %v\n", ticket.Key, err)
+		}
+
+		verbose.Printf(verbose.Cache, "保存: %s -> %s\n", ticket.Key, savedCachePath)
+		savedCount++
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+
+	// 添付ファイルをダウンロード (取得済みのファイルはスキップ)
+	if attachmentDir != "" {
+		downloaded, err := jiraClient.DownloadAttachments(attachmentDir, tickets)
+		if err != nil {
+			return 0, fmt.Errorf("添付ファイルの取得に失敗しました: %v", err)
 		}
+		verbose.Printf(verbose.General, "%d 件の添付ファイルを保存しました\n", downloaded)
+	}
+
+	// 6. キャッシュのメタデータを保存
+	meta := config.CacheMetadata{
+		Server:      cfg.Server,
+		ProjectKey:  cfg.Project.Key,
+		JQL:         cfg.JQL,
+		ExpandedJQL: string(expandedJQL),
+		FetchMode:   fetchMode,
+		FetchedAt:   startTime,
+	}
+	if saveErr := config.SaveCacheMetadata(cacheDir, meta); saveErr != nil {
+		verbose.Printf(verbose.Cache, "警告: キャッシュメタデータの保存に失敗しました: %v\n", saveErr)
+	}
+	if saveErr := config.SaveWorkspaceMetadata(meta); saveErr != nil {
+		verbose.Printf(verbose.Cache, "警告: ワークスペースのメタデータの保存に失敗しました: %v\n", saveErr)
+	}
+
+	// JQLの対象外の親チケットのタイトルを取得しておく (一覧での親チケットの表示に使用)
+	if parentErr := cacheParentTitles(ctx, jiraClient, cacheDir, tickets); parentErr != nil {
+		verbose.Printf(verbose.General, "警告: 親チケットのタイトルの取得に失敗しました: %v\n", parentErr)
+	}
+
+	// 7. スプリントの一覧も有効期間が切れていれば取得し直す
+	if _, sprintErr := jiraClient.CachedSprints(ctx, cacheDir); sprintErr != nil {
+		verbose.Printf(verbose.Cache, "警告: スプリントのキャッシュの更新に失敗しました: %v\n", sprintErr)
+	}
 
-		verbose.Printf("\n%d 件のチケットを保存しました\n", savedCount)
+	// クリーンフェッチの場合は書き込みが終わったキャッシュを既存のキャッシュと入れ替える
+	if clean {
+		if _, err := config.ReplaceCacheDir(cacheDir); err != nil {
+			return 0, err
+		}
+	}
+
+	// 8. 最終フェッチ時刻を保存
+	if saveErr := config.SaveLastFetchTime(startTime); saveErr != nil {
+		verbose.Printf(verbose.Cache, "警告: 最終フェッチ時刻の保存に失敗しました: %v\n", saveErr)
+	} else {
+		verbose.Printf(verbose.Cache, "最終フェッチ時刻を保存しました: %s\n", startTime.Format(time.RFC3339))
+	}
+
+	// 9. extra_sources のチケットを読み取り専用で取得する
+	// 別のサイトの失敗でメインのサイトのフェッチを失敗にしないよう、警告にとどめる
+	if err := fetchExtraSources(ctx, cfg, clean); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return 0, err
+		}
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+	}
+
+	return savedCount, nil
+}
+
+// fetchExtraSources は extra_sources のチケットをキャッシュの .sources/ 以下にサイトごとに取得します
+func fetchExtraSources(ctx context.Context, cfg *config.Config, clean bool) error {
+	if len(cfg.ExtraSources) == 0 {
 		return nil
-	},
+	}
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+	}
+	if err := config.PruneExtraSourceCacheDirs(cacheDir, cfg.ExtraSources); err != nil {
+		return err
+	}
+	var errs []error
+	for _, src := range cfg.ExtraSources {
+		dir := config.ExtraSourceCacheDir(cacheDir, src)
+		n, err := fetchExtraSource(ctx, cfg, src, dir, clean)
+		if ctx.Err() != nil {
+			return errFetchCancelled
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("追加ソース %s (%s) のチケットの取得に失敗しました: %v", src.Prefix(), src.Server, err))
+			continue
+		}
+		verbose.Printf(verbose.General, "追加ソース %s から %d 件のチケットを保存しました: %s\n", src.Prefix(), n, dir)
+	}
+	return errors.Join(errs...)
+}
+
+// fetchExtraSource は1つの追加ソースのチケットを dir に取得し、保存した件数を返します
+// 最終フェッチ時刻は追加ソースごとに dir に記録し、増分フェッチに使います。
+func fetchExtraSource(ctx context.Context, cfg *config.Config, src config.ExtraSource, dir string, clean bool) (int, error) {
+	jiraClient, err := jira.NewClient(src.Config(cfg))
+	if err != nil {
+		return 0, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+
+	startTime := time.Now()
+	var lastFetch time.Time
+	if !clean {
+		lastFetch, err = config.ReadLastFetchTime(dir)
+		if err != nil {
+			verbose.Printf(verbose.General, "追加ソース %s の最終フェッチ時刻の取得に失敗したため全件取得します: %v\n", src.Prefix(), err)
+		}
+	}
+	var tickets []*ticket.Ticket
+	if lastFetch.IsZero() {
+		tickets, err = jiraClient.FetchIssues(ctx)
+	} else {
+		tickets, err = jiraClient.FetchIssuesIncremental(ctx, lastFetch)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if lastFetch.IsZero() {
+		// 全件取得した場合は、JQLの範囲外になったチケットが残らないように作り直す
+		if err := os.RemoveAll(dir); err != nil {
+			return 0, fmt.Errorf("追加ソースのキャッシュの削除に失敗しました: %v", err)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("追加ソースのキャッシュディレクトリの作成に失敗しました: %v", err)
+	}
+	writer := ticket.NewCacheWriter(dir, cfg.DurableWrites())
+	for _, t := range tickets {
+		t.Source = src.Prefix()
+		if _, err := writer.Add(t); err != nil {
+			verbose.Printf(verbose.Cache, "警告: チケット %s のキャッシュ保存に失敗しました: %v\n", t.Key, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+	if err := config.WriteLastFetchTime(dir, startTime); err != nil {
+		return 0, err
+	}
+	return len(tickets), nil
+}
+
+// errFetchCancelled はCtrl-Cでフェッチを中断したことを表します
+var errFetchCancelled = &cancelledError{message: "フェッチを中断しました (取得途中のチケットは保存していません)"}
+
+// cacheParentTitles はキャッシュにない親チケットのタイトルをまとめて取得し、キャッシュとは別に保存します
+// すでにタイトルを保存した親チケットは取得し直しません。キャッシュに入った親チケットはキャッシュから引くので保存から外します。
+func cacheParentTitles(ctx context.Context, jiraClient *jira.Client, cacheDir string, tickets []*ticket.Ticket) error {
+	titles, err := config.ReadParentTitles(cacheDir)
+	if err != nil {
+		return err
+	}
+	inCache := func(key string) bool {
+		// キャッシュのファイル名は常に KEY.md
+		_, err := os.Stat(filepath.Join(cacheDir, key+".md"))
+		return err == nil
+	}
+	for key := range titles {
+		if inCache(key) {
+			delete(titles, key)
+		}
+	}
+
+	var missing []string
+	for _, t := range tickets {
+		key := ticket.CanonicalKey(t.ParentKey)
+		if key == "" || slices.Contains(missing, key) || inCache(key) {
+			continue
+		}
+		if _, ok := titles[key]; ok {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) > 0 {
+		verbose.Printf(verbose.General, "キャッシュにない親チケット %d 件のタイトルを取得します: %v\n", len(missing), missing)
+		parents, err := jiraClient.BulkFetchIssues(ctx, missing)
+		if err != nil {
+			return err
+		}
+		for _, p := range parents {
+			titles[ticket.CanonicalKey(p.Key)] = p.Title
+		}
+	}
+	return config.SaveParentTitles(cacheDir, titles)
+}
+
+// unsupportedADFSummary は未対応のADFのノードの種類ごとの数を「種類 件数」の形で並べます
+func unsupportedADFSummary(counts map[string]int) string {
+	types := slices.Sorted(maps.Keys(counts))
+	parts := make([]string, 0, len(types))
+	for _, nt := range types {
+		parts = append(parts, fmt.Sprintf("%s %d件", nt, counts[nt]))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func init() {
@@ -136,4 +379,5 @@ func init() {
 	// フラグの設定
 	fetchCmd.Flags().StringVarP(&outputDir, "output", "o", "", "出力ディレクトリ")
 	fetchCmd.Flags().BoolVarP(&cleanFetch, "clean", "c", false, "クリーンフェッチモード（増分フェッチのキャッシュを無視）")
+	fetchCmd.Flags().BoolVar(&fetchWithAttachment, "with-attachments", false, "添付ファイルを <directory>/assets/<KEY>/ にダウンロードし、本文の画像をリンクにする")
 }
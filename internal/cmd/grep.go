@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -16,11 +18,11 @@ import (
 	"github.com/charmbracelet/glamour/styles"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
-	tty "github.com/mattn/go-tty"
 	"github.com/muesli/termenv"
 	"github.com/qawatake/tkt/internal/cache"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
 	"github.com/qawatake/tkt/internal/pkg/utils"
 	"github.com/qawatake/tkt/internal/ticket"
 	"github.com/spf13/cobra"
@@ -28,6 +30,8 @@ import (
 
 var (
 	useWorkspace bool
+	grepNoTUI    bool
+	grepPinned   bool
 )
 
 var grepCmd = &cobra.Command{
@@ -39,9 +43,10 @@ var grepCmd = &cobra.Command{
 		defer derrors.Wrap(&err)
 
 		// Start background cache update
-		cache.StartBackgroundUpdate()
+		cacheUpdate := cache.StartBackgroundUpdate(cmd.Context())
 
 		var searchDir string
+		strict := false
 		if useWorkspace {
 			// ワークスペースディレクトリを使用
 			cfg, err := config.LoadConfig()
@@ -52,6 +57,9 @@ var grepCmd = &cobra.Command{
 				return fmt.Errorf("ワークスペースディレクトリが設定されていません")
 			}
 			searchDir = cfg.Directory
+			strict = cfg.StrictTicketDetection
+			// ワークスペースはバックグラウンドの更新で変わらないので再読み込みを促さない
+			cacheUpdate = nil
 		} else {
 			// デフォルトでキャッシュディレクトリを使用
 			cacheDir, err := config.EnsureCacheDir()
@@ -62,81 +70,193 @@ var grepCmd = &cobra.Command{
 		}
 
 		// マークダウンファイルを読み込み
-		tickets, err := loadTickets(searchDir)
+		tickets, err := loadTicketsWithExtraSources(searchDir, strict)
 		if err != nil {
 			return fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
 		}
 
+		// キャッシュからなくなったチケットのピン留めを解除する
+		if cacheDir, err := config.EnsureCacheDir(); err == nil {
+			pins, err := prunePins(cacheDir, os.Stderr)
+			if err != nil {
+				return err
+			}
+			if grepPinned {
+				tickets = filterPinned(tickets, pins)
+			}
+		}
+
 		if len(tickets) == 0 {
 			return fmt.Errorf("チケットが見つかりません")
 		}
-		tty, err := tty.Open()
-		if err != nil {
-			return err
-		}
-		defer tty.Close()
 
-		// Bubble Teaアプリを起動
-		model, err := newGrepModel(tickets, searchDir)
-		if err != nil {
-			return err
-		}
-		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(tty.Output()))
-		termenv.SetDefaultOutput(termenv.NewOutput(tty.Output()))
-		p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithOutput(tty.Output()), tea.WithMouseCellMotion())
-		_, err = p.Run()
-		if err != nil {
-			return err
-		}
+		return runGrep(os.Stdout, tickets, searchDir, cacheUpdate)
+	},
+}
 
-		// Ctrl+Cで終了した場合はexit code 1で終了
-		if model.cancelled {
-			os.Exit(1)
+// filterPinned はピン留めしたチケットのみを返します
+func filterPinned(tickets []*ticket.Ticket, pins []string) []*ticket.Ticket {
+	var filtered []*ticket.Ticket
+	for _, t := range tickets {
+		if slices.Contains(pins, t.Key) {
+			filtered = append(filtered, t)
 		}
+	}
+	return filtered
+}
 
-		t := model.Selected()
-		if t == nil {
-			return fmt.Errorf("チケットが選択されていません")
-		}
-		dto := ticketDTO{
-			Key:              t.Key,
-			ParentKey:        t.ParentKey,
-			Type:             t.Type,
-			Status:           t.Status,
-			Assignee:         t.Assignee,
-			Reporter:         t.Reporter,
-			CreatedAt:        t.CreatedAt.Format("2006-01-02"),
-			UpdatedAt:        t.UpdatedAt.Format("2006-01-02"),
-			OriginalEstimate: float64(t.OriginalEstimate),
-			URL:              t.URL,
-			Title:            t.Title,
-			FilePath:         t.FilePath,
-		}
-		// フロントマターをJSON形式で出力
-		b, err := json.Marshal(dto)
+// runGrep はチケットを選択し、選択したチケットのフロントマターをJSON形式で出力します
+// TTYが利用できない場合や --no-tui が指定された場合は、全チケットを1行1件のJSONで出力します。
+// cacheUpdateはバックグラウンドのキャッシュ更新の完了通知です (nilの場合は通知しません)。
+func runGrep(out io.Writer, tickets []*ticket.Ticket, searchDir string, cacheUpdate <-chan error) error {
+	if grepNoTUI {
+		return printTicketList(out, tickets)
+	}
+
+	t, err := pickTicket(tickets, searchDir, cacheUpdate)
+	if errors.Is(err, errNoTTY) {
+		fmt.Fprintln(os.Stderr, "TTYが利用できないため、非対話モード (--no-tui) でチケット一覧を出力します")
+		return printTicketList(out, tickets)
+	}
+	if err != nil {
+		return err
+	}
+
+	// フロントマターをJSON形式で出力
+	b, err := json.Marshal(newTicketDTO(t, newParentTitleIndex(tickets)))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(b))
+	return nil
+}
+
+// pickTicket はファジー検索のTUIでチケットを選択させ、選択されたチケットを返します
+// TTYが利用できない場合はerrNoTTYを返します。Ctrl+Cで終了した場合はexit code 1で終了します。
+func pickTicket(tickets []*ticket.Ticket, searchDir string, cacheUpdate <-chan error) (*ticket.Ticket, error) {
+	tty, err := openInteractiveTTY()
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+
+	// Bubble Teaアプリを起動
+	model, err := newGrepModel(tickets, searchDir)
+	if err != nil {
+		return nil, err
+	}
+	model.cacheUpdate = cacheUpdate
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(tty.Output()))
+	termenv.SetDefaultOutput(termenv.NewOutput(tty.Output()))
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithOutput(tty.Output()), tea.WithMouseCellMotion())
+	_, err = p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	// Ctrl+Cで終了した場合はexit code 1で終了
+	if model.cancelled {
+		os.Exit(1)
+	}
+
+	t := model.Selected()
+	if t == nil {
+		return nil, fmt.Errorf("チケットが選択されていません")
+	}
+	return t, nil
+}
+
+// printTicketList はチケットのフロントマターを1行1件のJSON形式で出力します
+func printTicketList(out io.Writer, tickets []*ticket.Ticket) error {
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].Key < tickets[j].Key
+	})
+	parents := newParentTitleIndex(tickets)
+	for _, t := range tickets {
+		b, err := json.Marshal(newTicketDTO(t, parents))
 		if err != nil {
 			return err
 		}
-		fmt.Println(string(b))
-		return nil
-	},
+		fmt.Fprintln(out, string(b))
+	}
+	return nil
+}
+
+// newTicketDTO はチケットをJSON出力用の形にします
+// parents が nil でない場合は親チケットのタイトルも含めます (別のサイトのチケットは含めません)。
+func newTicketDTO(t *ticket.Ticket, parents *parentTitleIndex) ticketDTO {
+	stats := t.BodyStats()
+	var parentTitle string
+	if t.ParentKey != "" && t.Source == "" {
+		parentTitle = parents.title(t.ParentKey)
+	}
+	return ticketDTO{
+		Key:               t.Key,
+		ParentKey:         t.ParentKey,
+		ParentTitle:       parentTitle,
+		Type:              t.Type,
+		Status:            t.Status,
+		Assignee:          t.Assignee,
+		Reporter:          t.Reporter,
+		Creator:           t.Creator,
+		Epic:              t.Epic,
+		Team:              t.Team,
+		CreatedAt:         t.CreatedAt.Format("2006-01-02"),
+		UpdatedAt:         t.UpdatedAt.Format("2006-01-02"),
+		OriginalEstimate:  float64(t.OriginalEstimate),
+		RemainingEstimate: float64(t.RemainingEstimate),
+		TimeSpent:         float64(t.TimeSpent),
+		URL:               t.URL,
+		Priority:          t.Priority,
+		Labels:            t.Labels,
+		Components:        t.Components,
+		FixVersions:       t.FixVersions,
+		References:        t.References,
+		Flagged:           t.IsFlagged(),
+		Source:            t.Source,
+		Title:             t.Title,
+		BodyChars:         stats.Chars,
+		BodyWords:         stats.Words,
+		FilePath:          t.FilePath,
+	}
 }
 
 type ticketDTO struct {
-	Key              string  `json:"key"`
-	ParentKey        string  `json:"parentKey"`
-	Type             string  `json:"type"`
-	Status           string  `json:"status"`
-	Assignee         string  `json:"assignee"`
-	Reporter         string  `json:"reporter"`
-	CreatedAt        string  `json:"created_at"`
-	UpdatedAt        string  `json:"updated_at"`
-	OriginalEstimate float64 `json:"original_estimate"`
-	URL              string  `json:"url"`
-	Title            string  `json:"title"`
-	FilePath         string  `json:"_file_path"`
+	Key               string             `json:"key"`
+	ParentKey         string             `json:"parentKey"`
+	ParentTitle       string             `json:"parentTitle,omitempty"`
+	Type              string             `json:"type"`
+	Status            string             `json:"status"`
+	Assignee          string             `json:"assignee"`
+	Reporter          string             `json:"reporter"`
+	Creator           string             `json:"creator"`
+	Epic              string             `json:"epic,omitempty"`
+	Team              string             `json:"team,omitempty"`
+	CreatedAt         string             `json:"created_at"`
+	UpdatedAt         string             `json:"updated_at"`
+	OriginalEstimate  float64            `json:"original_estimate"`
+	RemainingEstimate float64            `json:"remaining_estimate"`
+	TimeSpent         float64            `json:"time_spent"`
+	URL               string             `json:"url"`
+	Priority          string             `json:"priority"`
+	Labels            []string           `json:"labels"`
+	Components        []string           `json:"components"`
+	FixVersions       []string           `json:"fix_versions"`
+	References        []ticket.Reference `json:"references,omitempty"`
+	Flagged           bool               `json:"flagged"`
+	Source            string             `json:"source,omitempty"`
+	Title             string             `json:"title"`
+	BodyChars         int                `json:"body_chars"`
+	BodyWords         int                `json:"body_words"`
+	FilePath          string             `json:"_file_path"`
 }
 
+// flaggedIndicator はフラグ (Impediment) が付いているチケットに表示する印です
+const flaggedIndicator = "⛔"
+
+// pinnedIndicator はピン留めしたチケットに表示する印です
+const pinnedIndicator = "★"
+
 type grepModel struct {
 	input         textinput.Model
 	mdRenderer    *glamour.TermRenderer
@@ -146,8 +266,26 @@ type grepModel struct {
 	cursor        int
 	width         int
 	height        int
-	configDir     string // 設定されたディレクトリを保持
-	cancelled     bool   // Ctrl+Cで終了したかどうか
+	configDir     string            // 設定されたディレクトリを保持
+	cancelled     bool              // Ctrl+Cで終了したかどうか
+	sprints       []jira.Sprint     // キャッシュされたスプリントの一覧 (スプリントの状態と日付の表示に使用)
+	parents       *parentTitleIndex // 親チケットのタイトルの索引 (親チケットの表示に使用)
+	cacheUpdate   <-chan error      // バックグラウンドのキャッシュ更新の完了通知
+	cacheUpdated  bool              // キャッシュが更新され、再読み込みできるかどうか
+	pinned        map[string]bool   // ピン留めしたチケットのキー (一覧の先頭に表示する)
+	notice        string            // ステータス行に表示するメッセージ (ブラウザで開いた結果など)
+}
+
+// cacheUpdatedMsg はバックグラウンドのキャッシュ更新が完了したことを表します
+type cacheUpdatedMsg struct {
+	err error
+}
+
+// waitForCacheUpdate はキャッシュ更新の完了を待つコマンドです
+func waitForCacheUpdate(ch <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		return cacheUpdatedMsg{err: <-ch}
+	}
 }
 
 type ticketItem struct {
@@ -185,8 +323,38 @@ func newGrepModel(tickets []*ticket.Ticket, configDir string) (_ *grepModel, err
 		return nil, err
 	}
 
-	// ソート: 新規ファイル（JIRAキーなし）を最初に、その後はupdated_atの降順
+	pinned := loadPinnedKeys()
+	items := newTicketItems(tickets, pinned)
+
+	model := &grepModel{
+		pinned:        pinned,
+		input:         input,
+		mdRenderer:    mdRenderer,
+		tickets:       items,
+		filteredItems: items,
+		searchQuery:   "",
+		cursor:        0,
+		configDir:     configDir,
+		sprints:       loadCachedSprints(),
+		parents:       newParentTitleIndex(tickets),
+	}
+
+	// 初期状態で最初のファイルを確実に選択
+	if len(items) > 0 {
+		model.cursor = 0
+	}
+
+	return model, nil
+}
+
+// newTicketItems はチケットをピン留めしたもの、新規ファイル、updated_atの降順の順に並べて一覧の項目にします
+func newTicketItems(tickets []*ticket.Ticket, pinned map[string]bool) []ticketItem {
+	// ソート: ピン留めしたチケットを最初に、次に新規ファイル（JIRAキーなし）、その後はupdated_atの降順
 	sort.Slice(tickets, func(i, j int) bool {
+		if pinned[tickets[i].Key] != pinned[tickets[j].Key] {
+			return pinned[tickets[i].Key]
+		}
+
 		// 新規ファイル（JIRAキーが無効）かどうかをチェック
 		isNewI := !utils.IsValidJIRAKey(tickets[i].Key)
 		isNewJ := !utils.IsValidJIRAKey(tickets[j].Key)
@@ -210,39 +378,85 @@ func newGrepModel(tickets []*ticket.Ticket, configDir string) (_ *grepModel, err
 			continue
 		}
 
-		// 未pushファイルの場合はキーを「DRAFT」として表示
-		displayKey := t.Key
-		if !utils.IsValidJIRAKey(t.Key) {
-			displayKey = "DRAFT"
-		}
-
 		items = append(items, ticketItem{
-			key:     displayKey,
+			key:     displayTicketKey(t),
 			title:   t.Title,
 			content: t.Body, // フロントマターを除いた本文のみ
 			ticket:  t,      // 元のticketオブジェクトを保持
 		})
 	}
+	return items
+}
 
-	model := &grepModel{
-		input:         input,
-		mdRenderer:    mdRenderer,
-		tickets:       items,
-		filteredItems: items,
-		searchQuery:   "",
-		cursor:        0,
-		configDir:     configDir,
+// ticketCountLabel は絞り込み後の件数と全体の件数を表示用にします (例: 34/812 tickets)
+func ticketCountLabel(filtered, total int) string {
+	return fmt.Sprintf("%d/%d tickets", filtered, total)
+}
+
+// itemIdentity はチケットを再読み込みしても同じ項目を指すための識別子です
+// 未pushのチケットはキーがないのでファイルパスを使います。
+func itemIdentity(t *ticket.Ticket) string {
+	if t.Key != "" {
+		return displayTicketKey(t)
 	}
+	return t.FilePath
+}
 
-	// 初期状態で最初のファイルを確実に選択
-	if len(items) > 0 {
-		model.cursor = 0
+// reload はチケットを読み込み直し、現在の検索条件で絞り込み直します
+// カーソルは同じチケット (キーで照合) に残します。見つからない場合は先頭に戻します。
+func (m *grepModel) reload() error {
+	var current string
+	if t := m.Selected(); t != nil {
+		current = itemIdentity(t)
 	}
 
-	return model, nil
+	tickets, err := loadTicketsWithExtraSources(m.configDir, false)
+	if err != nil {
+		return err
+	}
+	m.tickets = newTicketItems(tickets, m.pinned)
+	m.sprints = loadCachedSprints()
+	m.parents = newParentTitleIndex(tickets)
+	m.filterItems()
+
+	m.cursor = 0
+	for i, item := range m.filteredItems {
+		if itemIdentity(item.ticket) == current {
+			m.cursor = i
+			break
+		}
+	}
+	m.cacheUpdated = false
+	return nil
+}
+
+// loadCachedSprints はキャッシュされたスプリントの一覧を読み込みます
+// grepはオフラインで動作するため、キャッシュが古くても取得し直しません。
+func loadCachedSprints() []jira.Sprint {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil
+	}
+	sprints, _, err := jira.ReadSprintCache(cacheDir)
+	if err != nil {
+		return nil
+	}
+	return sprints
+}
+
+// sprintLabel はスプリント名に状態と日付を付けて返します
+// キャッシュにないスプリントは名前だけを返します。
+func (m *grepModel) sprintLabel(name string) string {
+	if sprint := jira.LookupSprint(m.sprints, name); sprint != nil {
+		return sprint.Label()
+	}
+	return name
 }
 
 func (m *grepModel) Init() tea.Cmd {
+	if m.cacheUpdate != nil {
+		return tea.Batch(tea.ClearScreen, waitForCacheUpdate(m.cacheUpdate))
+	}
 	return tea.ClearScreen
 }
 
@@ -253,6 +467,11 @@ func (m *grepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case cacheUpdatedMsg:
+		// 失敗した場合はキャッシュが変わっていないので通知しない
+		m.cacheUpdated = msg.err == nil
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
@@ -262,6 +481,14 @@ func (m *grepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			return m, tea.Quit
 
+		case "ctrl+r":
+			// キャッシュを読み込み直す (読み込みに失敗した場合は今の一覧のまま)
+			_ = m.reload()
+
+		case "ctrl+o":
+			// 選択中のチケットをブラウザで開く (文字キーは検索語の入力に使うためctrlと組み合わせる)
+			m.notice = m.openSelected()
+
 		case "up", "ctrl+p":
 			if m.cursor > 0 {
 				m.cursor--
@@ -424,8 +651,16 @@ func (m *grepModel) View() string {
 		m.height = 24
 	}
 
-	// ヘッダー部分
-	header := m.input.View()
+	// ヘッダー部分 (検索欄と件数)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	status := mutedStyle.Render(ticketCountLabel(len(m.filteredItems), len(m.tickets)))
+	if m.cacheUpdated {
+		status += mutedStyle.Italic(true).Render("  cache updated — press ctrl+r to reload")
+	}
+	if m.notice != "" {
+		status += mutedStyle.Render("  " + m.notice)
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, m.input.View(), status)
 
 	if len(m.filteredItems) == 0 {
 		emptyMsg := lipgloss.NewStyle().
@@ -477,6 +712,16 @@ func (m *grepModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, body)
 }
 
+// keyColumnWidth は一覧のキーの列の幅を返します
+// 絞り込んでも列の幅が変わらないように、すべてのチケットのキーから決めます。
+func (m *grepModel) keyColumnWidth() int {
+	keys := make([]string, 0, len(m.tickets))
+	for _, item := range m.tickets {
+		keys = append(keys, item.key)
+	}
+	return keyColumnWidth(keys)
+}
+
 func (m *grepModel) renderLeftPane(width, height int) string {
 	var items []string
 
@@ -485,11 +730,12 @@ func (m *grepModel) renderLeftPane(width, height int) string {
 		start = m.cursor - height + 1
 	}
 
+	keyWidth := m.keyColumnWidth()
 	for i := start; i < start+height && i < len(m.filteredItems); i++ {
 		item := m.filteredItems[i]
 
-		// キーを固定幅で左詰めパディング（DRAFTやJIRAキーに対応）
-		keyPadded := fmt.Sprintf("%-8s", item.key)
+		// キーを表示幅で左詰めパディング（DRAFTや長いJIRAキーに対応）
+		keyPadded := padRight(item.key, keyWidth)
 		line := keyPadded
 
 		// タイトルがある場合は表示
@@ -497,6 +743,14 @@ func (m *grepModel) renderLeftPane(width, height int) string {
 			line = fmt.Sprintf("%s %s", keyPadded, item.title)
 		}
 
+		// フラグが付いているチケットには印を付ける
+		if item.ticket != nil && item.ticket.IsFlagged() {
+			line = flaggedIndicator + " " + line
+		}
+		if item.ticket != nil && m.pinned[item.ticket.Key] {
+			line = pinnedIndicator + " " + line
+		}
+
 		// 幅に合わせてトリミング
 		line = ansi.TruncateWc(line, width, "…")
 
@@ -526,14 +780,21 @@ func (m *grepModel) renderCenterPane(width, height int) string {
 		return strings.Join(items, "\n")
 	}
 
-	content := m.filteredItems[m.cursor].content
-	content, err := m.mdRenderer.Render(content)
+	item := m.filteredItems[m.cursor]
+	content, err := m.mdRenderer.Render(item.content)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		panic(err)
 	}
 	content = strings.TrimSpace(content)
-	return lipgloss.NewStyle().Width(width - 2).MaxWidth(width).Render(content)
+
+	// キー・タイトル・本文の文字数をヘッダーとして表示
+	header := lipgloss.NewStyle().Bold(true).Width(width - 2).MaxWidth(width).MaxHeight(1).
+		Render(fmt.Sprintf("%s %s", item.key, item.title))
+	chars := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).
+		Render(ticket.FormatChars(item.ticket.BodyStats().Chars))
+	body := lipgloss.NewStyle().Width(width - 2).MaxWidth(width).Render(content)
+	return lipgloss.JoinVertical(lipgloss.Left, header, chars, "", body)
 }
 
 func (m *grepModel) renderRightPane(width, height int) string {
@@ -578,6 +839,24 @@ func (m *grepModel) renderRightPane(width, height int) string {
 				valueStyle.Render(selectedTicket.Status)))
 		}
 
+		if selectedTicket.SprintName != "" {
+			items = append(items, fmt.Sprintf("%s: %s",
+				frontmatterStyle.Render("Sprint"),
+				valueStyle.Render(m.sprintLabel(selectedTicket.SprintName))))
+		}
+
+		if selectedTicket.IsFlagged() {
+			items = append(items, fmt.Sprintf("%s: %s",
+				frontmatterStyle.Render("Flagged"),
+				valueStyle.Render(flaggedIndicator+" Impediment")))
+		}
+
+		if selectedTicket.Priority != "" {
+			items = append(items, fmt.Sprintf("%s: %s",
+				frontmatterStyle.Render("Priority"),
+				valueStyle.Render(selectedTicket.Priority)))
+		}
+
 		if selectedTicket.Assignee != "" {
 			items = append(items, fmt.Sprintf("%s: %s",
 				frontmatterStyle.Render("Assignee"),
@@ -594,7 +873,7 @@ func (m *grepModel) renderRightPane(width, height int) string {
 		if selectedTicket.ParentKey != "" {
 			items = append(items, fmt.Sprintf("%s: %s",
 				frontmatterStyle.Render("Parent"),
-				valueStyle.Render(selectedTicket.ParentKey)))
+				valueStyle.Render(parentLabel(selectedTicket, m.parents))))
 		} else {
 			items = append(items, fmt.Sprintf("%s: %s",
 				frontmatterStyle.Render("Parent"),
@@ -612,6 +891,18 @@ func (m *grepModel) renderRightPane(width, height int) string {
 				valueStyle.Render("None")))
 		}
 
+		// 本文から参照しているConfluenceのページ (タイトルがなければURL)
+		if len(selectedTicket.References) > 0 {
+			items = append(items, frontmatterStyle.Render("References")+":")
+			for _, ref := range selectedTicket.References {
+				label := ref.Title
+				if label == "" {
+					label = ref.URL
+				}
+				items = append(items, "  "+valueStyle.Render(label))
+			}
+		}
+
 		items = append(items, "") // 区切り線
 
 		if !selectedTicket.CreatedAt.IsZero() {
@@ -625,6 +916,16 @@ func (m *grepModel) renderRightPane(width, height int) string {
 				frontmatterStyle.Render("Updated"),
 				valueStyle.Render(selectedTicket.UpdatedAt.Format("2006-01-02"))))
 		}
+
+		// extra_sources のチケットは別のサイトのものなので、取得元とURLを示す
+		if selectedTicket.Source != "" {
+			items = append(items, fmt.Sprintf("%s: %s",
+				frontmatterStyle.Render("Source"),
+				valueStyle.Render(selectedTicket.Source+" (read-only)")))
+			items = append(items, fmt.Sprintf("%s: %s",
+				frontmatterStyle.Render("URL"),
+				valueStyle.Render(selectedTicket.URL)))
+		}
 	} else {
 		items = append(items, lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
@@ -639,6 +940,18 @@ func (m *grepModel) renderRightPane(width, height int) string {
 	return strings.Join(items, "\n")
 }
 
+// openSelected は選択中のチケットのJIRAのページをブラウザで開き、ステータス行に表示するメッセージを返します
+func (m *grepModel) openSelected() string {
+	t := m.Selected()
+	if t == nil || t.URL == "" {
+		return "no URL to open"
+	}
+	if err := openBrowser(t.URL); err != nil {
+		return err.Error()
+	}
+	return "opened " + t.Key
+}
+
 func (m *grepModel) Selected() *ticket.Ticket {
 	if len(m.filteredItems) == 0 || m.cursor >= len(m.filteredItems) {
 		return nil
@@ -648,34 +961,58 @@ func (m *grepModel) Selected() *ticket.Ticket {
 	return m.filteredItems[m.cursor].ticket
 }
 
-func loadTickets(dir string) ([]*ticket.Ticket, error) {
+// displayTicketKey は一覧に表示するキーを返します。未pushのチケットは「DRAFT」と表示します。
+// extra_sources のチケットはサイトのプレフィックスを付けます (例: other:ABC-12)。
+func displayTicketKey(t *ticket.Ticket) string {
+	if !utils.IsValidJIRAKey(t.Key) {
+		return "DRAFT"
+	}
+	if t.Source != "" {
+		return t.Source + ":" + t.Key
+	}
+	return t.Key
+}
+
+// loadTickets はディレクトリのチケットを読み込みます
+// strict の場合はチケットの形式 (HasTicketSignature) を満たすファイルのみを読み込みます。
+func loadTickets(dir string, strict bool) ([]*ticket.Ticket, error) {
 	var tickets []*ticket.Ticket
 
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+	// ドットで始まるファイル（既に削除マークされたもの）と .tktignore に一致するファイルはスキップ
+	files, _, err := ticket.WalkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		t, err := ticket.FromFile(path)
 		if err != nil {
-			return err
+			// エラーは無視してスキップ
+			continue
 		}
-		if !d.IsDir() && strings.HasSuffix(path, ".md") {
-			// ドットで始まるファイル（既に削除マークされたもの）はスキップ
-			filename := filepath.Base(path)
-			if strings.HasPrefix(filename, ".") {
-				return nil
-			}
-
-			t, err := ticket.FromFile(path)
-			if err != nil {
-				// エラーは無視してスキップ
-				return nil
-			}
-			// 有効なチケット（keyまたはtitleが存在）のみを追加
-			if t.Key != "" || t.Title != "" {
-				tickets = append(tickets, t)
-			}
+		if strict && !t.HasTicketSignature() {
+			continue
 		}
-		return nil
-	})
+		// 有効なチケット（keyまたはtitleが存在）のみを追加
+		if t.Key != "" || t.Title != "" {
+			tickets = append(tickets, t)
+		}
+	}
 
-	return tickets, err
+	return tickets, nil
+}
+
+// loadTicketsWithExtraSources は loadTickets に加えて、dir がキャッシュディレクトリの場合は
+// extra_sources から取得した読み取り専用のチケット (.sources/ 以下) も読み込みます
+func loadTicketsWithExtraSources(dir string, strict bool) ([]*ticket.Ticket, error) {
+	tickets, err := loadTickets(dir, strict)
+	if err != nil {
+		return nil, err
+	}
+	extra, err := loadTickets(filepath.Join(dir, config.ExtraSourcesDir), false)
+	if err != nil {
+		return nil, err
+	}
+	return append(tickets, extra...), nil
 }
 
 func init() {
@@ -683,4 +1020,6 @@ func init() {
 
 	// フラグの設定
 	grepCmd.Flags().BoolVarP(&useWorkspace, "workspace", "w", false, "ワークスペースディレクトリを検索対象にする")
+	grepCmd.Flags().BoolVar(&grepNoTUI, "no-tui", false, "インタラクティブな画面を使わず、全チケットを1行1件のJSON形式で出力する")
+	grepCmd.Flags().BoolVar(&grepPinned, "pinned", false, "ピン留めしたチケットのみを表示する")
 }
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrepModelReload(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, key := range []string{"PRJ-1", "PRJ-2", "PRJ-3"} {
+		tk := &ticket.Ticket{Key: key, Title: "spike " + key, Type: "task", UpdatedAt: base.Add(time.Duration(i) * time.Hour)}
+		_, err := tk.SaveToFile(dir)
+		assert.NoError(t, err)
+	}
+
+	tickets, err := loadTickets(dir, false)
+	assert.NoError(t, err)
+	m, err := newGrepModel(tickets, dir)
+	assert.NoError(t, err)
+
+	// PRJ-2を選択した状態で絞り込む
+	m.searchQuery = "spike"
+	m.filterItems()
+	for i, item := range m.filteredItems {
+		if item.key == "PRJ-2" {
+			m.cursor = i
+		}
+	}
+	m.cacheUpdated = true
+
+	// バックグラウンドの更新で新しいチケットが増えても、同じチケットを選択したまま
+	added := &ticket.Ticket{Key: "PRJ-4", Title: "spike PRJ-4", Type: "task", UpdatedAt: base.Add(10 * time.Hour)}
+	_, err = added.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.reload())
+	assert.Equal(t, "PRJ-2", m.Selected().Key)
+	assert.Len(t, m.filteredItems, 4)
+	assert.False(t, m.cacheUpdated)
+	assert.Equal(t, "4/4 tickets", ticketCountLabel(len(m.filteredItems), len(m.tickets)))
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var historyLocal bool
+
+var historyCmd = &cobra.Command{
+	Use:   "history --local [TICKET-KEY]",
+	Short: "pushの履歴を表示します",
+	Long: `--local を指定すると、このワークスペースでpushした操作 (作成・更新・削除) の履歴を古い順に表示します。
+TICKET-KEYを指定した場合はそのチケットの履歴のみ表示します。
+
+履歴はキャッシュディレクトリの .history/history.jsonl に記録され、1MBを超えると history.jsonl.1 にローテーションします。
+更新・削除の前のキャッシュの内容も保存しているので、tkt undo でワークスペースに復元できます。`,
+	Example: `  tkt history --local
+  tkt history --local PRJ-123`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		if !historyLocal {
+			return fmt.Errorf("JIRAの変更履歴の表示には対応していません。--local でこのワークスペースのpushの履歴を表示できます")
+		}
+		var key string
+		if len(args) > 0 {
+			key = ticket.CanonicalKey(args[0])
+			if !utils.IsValidJIRAKey(key) {
+				return fmt.Errorf("無効なチケットキーです: %s", args[0])
+			}
+		}
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		entries, err := ticket.ReadHistory(cacheDir, key)
+		if err != nil {
+			return err
+		}
+		return printHistory(os.Stdout, entries)
+	},
+}
+
+func printHistory(w io.Writer, entries []ticket.HistoryEntry) error {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "pushの履歴はありません")
+		return nil
+	}
+	rows := [][]string{{"TIME", "KEY", "ACTION", "FIELDS"}}
+	for _, e := range entries {
+		rows = append(rows, []string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Key, changeTypeLabel(e.Action), orDash(strings.Join(e.ChangedFields, ", "))})
+	}
+	return writeTable(w, rows)
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().BoolVar(&historyLocal, "local", false, "このワークスペースでpushした操作の履歴を表示する")
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintHistory(t *testing.T) {
+	at := time.Date(2025, 6, 1, 10, 0, 0, 0, time.Local)
+	entries := []ticket.HistoryEntry{
+		{Time: at, Key: "PRJ-1", Action: ticket.ChangeCreate},
+		{Time: at.Add(time.Minute), Key: "PRJ-1", Action: ticket.ChangeUpdate, ChangedFields: []string{"title", "body"}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, printHistory(&buf, entries))
+	assert.Equal(t, "TIME                 KEY    ACTION  FIELDS\n"+
+		"2025-06-01 10:00:00  PRJ-1  作成    -\n"+
+		"2025-06-01 10:01:00  PRJ-1  更新    title, body\n", buf.String())
+}
+
+func TestLastUndoableEntry(t *testing.T) {
+	cacheDir := t.TempDir()
+	assert.NoError(t, ticket.AppendHistory(cacheDir, ticket.HistoryEntry{Key: "PRJ-1", Action: ticket.ChangeUpdate, ChangedFields: []string{"title"}}, []byte("1回目")))
+	assert.NoError(t, ticket.AppendHistory(cacheDir, ticket.HistoryEntry{Key: "PRJ-1", Action: ticket.ChangeUpdate, ChangedFields: []string{"body"}}, []byte("2回目")))
+	assert.NoError(t, ticket.AppendHistory(cacheDir, ticket.HistoryEntry{Key: "PRJ-2", Action: ticket.ChangeCreate}, nil))
+
+	entry, err := lastUndoableEntry(cacheDir, "PRJ-1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"body"}, entry.ChangedFields)
+	}
+
+	_, err = lastUndoableEntry(cacheDir, "PRJ-2")
+	assert.ErrorContains(t, err, "復元できるpushの履歴がありません")
+}
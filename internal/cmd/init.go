@@ -3,14 +3,19 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
 	"github.com/qawatake/tkt/internal/ui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -56,7 +61,6 @@ func runInit() error {
 	fmt.Println("=======================")
 
 	var serverURL, loginEmail string
-	var continueSetup bool
 
 	// 1. 基本設定フォーム
 	basicForm := huh.NewForm(
@@ -91,37 +95,26 @@ func runInit() error {
 	}
 
 	// 2. APIトークンの確認
-	apiToken := os.Getenv("JIRA_API_TOKEN")
-	if apiToken == "" {
-		fmt.Println("\n⚠️  JIRA_API_TOKEN環境変数が設定されていません。")
-		fmt.Println("   Atlassian API Token (https://id.atlassian.com/manage-profile/security/api-tokens) を取得して、")
-		fmt.Println("   環境変数 JIRA_API_TOKEN に設定してください。")
-
-		confirmForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewConfirm().
-					Title("続行しますか？").
-					Description("APIトークンなしでもセットアップを続行できますが、後で設定が必要です").
-					Value(&continueSetup),
-			),
-		).WithTheme(huh.ThemeBase())
-
-		err := confirmForm.Run()
-		if err != nil {
-			return fmt.Errorf("確認入力がキャンセルされました: %v", err)
-		}
-		if !continueSetup {
-			return fmt.Errorf("セットアップを中止しました")
-		}
-		apiToken = "dummy_token" // 一時的なダミートークン
+	cred, err := askInitCredential(serverURL)
+	if err != nil {
+		return err
+	}
+	if cred == nil {
+		// トークンなしで続行する場合はAPIを使う手順をすべて省略する
+		return writePlaceholderConfig(serverURL, loginEmail)
 	}
+	apiToken := cred.token
 
 	// 4. プロジェクト一覧を取得
 	projects, err := ui.WithSpinnerValue("プロジェクト一覧を取得中...", func() ([]JiraProject, error) {
 		return fetchProjects(serverURL, loginEmail, apiToken)
 	})
 	if err != nil {
-		return fmt.Errorf("プロジェクト一覧の取得に失敗しました: %v", err)
+		return fmt.Errorf("プロジェクト一覧の取得に失敗しました: %v", cred.describe(err, loginEmail))
+	}
+	// 確認できたトークンをキーリングに保存する
+	if err := cred.storeValidated(serverURL); err != nil {
+		return err
 	}
 
 	if len(projects) == 0 {
@@ -149,7 +142,7 @@ func runInit() error {
 		return fetchBoards(serverURL, loginEmail, apiToken, selectedProject.Key)
 	})
 	if err != nil {
-		return fmt.Errorf("ボード一覧の取得に失敗しました: %v", err)
+		return fmt.Errorf("ボード一覧の取得に失敗しました: %v", cred.describe(err, loginEmail))
 	}
 
 	var selectedBoard *JiraBoard
@@ -188,6 +181,24 @@ func runInit() error {
 
 	jqlInput = defaultJQL
 
+	// JQLは自由に入力するか、条件を選んで組み立てる
+	jqlMode, err := ui.Select("🔎 フェッチするチケットの条件 (JQL) の指定方法を選択してください:", []ui.SelectorOption{
+		{Title: "JQLを入力する", Description: fmt.Sprintf("デフォルト: %s", defaultJQL), Value: "input"},
+		{Title: "条件を選んで組み立てる", Description: "ステータス・担当者・更新日を選び、一致する件数を確認できます", Value: "build"},
+	})
+	if err != nil {
+		return fmt.Errorf("JQLの指定方法の選択がキャンセルされました: %v", err)
+	}
+	if jqlMode == "build" {
+		jqlInput, err = buildJQLInteractively([]string{selectedProject.Key}, func(jql string) (int, error) {
+			n, err := countIssues(serverURL, loginEmail, apiToken, jql)
+			return n, cred.describe(err, loginEmail)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	settingsForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -222,7 +233,15 @@ func runInit() error {
 		return fetchIssueTypes(serverURL, loginEmail, apiToken, selectedProject.ID)
 	})
 	if err != nil {
-		return fmt.Errorf("issue Types一覧の取得に失敗しました: %v", err)
+		return fmt.Errorf("issue Types一覧の取得に失敗しました: %v", cred.describe(err, loginEmail))
+	}
+
+	// 10. コンポーネント一覧を取得 (push時の検証用。取得できなくても続行)
+	components, err := ui.WithSpinnerValue("コンポーネント一覧を取得中...", func() ([]string, error) {
+		return fetchComponents(serverURL, loginEmail, apiToken, selectedProject.Key)
+	})
+	if err != nil {
+		fmt.Printf("⚠️  コンポーネント一覧の取得に失敗しました: %v\n", cred.describe(err, loginEmail))
 	}
 
 	// 11. 設定ファイルを作成
@@ -234,11 +253,14 @@ func runInit() error {
 		Timezone:  "Asia/Tokyo",
 		Directory: directoryInput,
 	}
+	cfg.TokenCommand = cred.tokenCommand
+	cfg.TokenFile = cred.tokenFile
 
 	// Project情報を設定
 	cfg.Project.Key = selectedProject.Key
 	cfg.Project.ID = selectedProject.ID
 	cfg.Project.Type = "software"
+	cfg.Project.Components = components
 
 	// Board情報を設定
 	cfg.Board.ID = selectedBoard.ID
@@ -259,22 +281,249 @@ func runInit() error {
 	}
 
 	// 12. 設定ファイルを保存 (tkt.ymlをカレントディレクトリに作成)
-	configFile := "tkt.yml"
+	if err := writeConfigFile(cfg); err != nil {
+		return err
+	}
+
+	fmt.Println("\n✅ 設定が完了しました！")
+	fmt.Printf("   設定ファイル: %s (カレントディレクトリ)\n", initConfigFile)
+	fmt.Printf("   プロジェクト: %s (%s)\n", selectedProject.Name, selectedProject.Key)
+	fmt.Printf("   ボード: %s (ID: %d)\n", selectedBoard.Name, selectedBoard.ID)
+	if cfg.TokenCommand == "" && cfg.TokenFile == "" && !cred.inKeyring && os.Getenv(config.APITokenEnv) == "" {
+		fmt.Printf("   ⚠️  tktを使う前に tkt auth set-token でAPIトークンを保存するか、環境変数 %s を設定してください\n", config.APITokenEnv)
+	}
+
+	return nil
+}
+
+// initConfigFile はinitで作成する設定ファイルです
+const initConfigFile = "tkt.yml"
+
+// defaultTokenFile はinitでAPIトークンを保存するファイルです
+const defaultTokenFile = "~/.config/tkt/token"
+
+// errInitUnauthorized はinit中のAPI呼び出しが認証エラーになったことを表します
+var errInitUnauthorized = errors.New("認証に失敗しました")
+
+// initCredential はinitで使用するAPIトークンとその取得元です
+type initCredential struct {
+	token  string
+	source string
+	// tokenCommand, tokenFile は設定ファイルに書き込むトークンの取得方法です
+	tokenCommand string
+	tokenFile    string
+	// saveToKeyring がtrueの場合、トークンを確認できたらキーリングに保存します
+	saveToKeyring bool
+	// inKeyring はトークンがキーリングに保存されていることを表します
+	inKeyring bool
+}
+
+// storeValidated はJIRAで確認できたトークンをキーリングに保存します
+// 環境変数のトークンの場合は、保存するかどうかを確認します。
+func (c *initCredential) storeValidated(serverURL string) error {
+	if c.inKeyring {
+		return nil
+	}
+	if !c.saveToKeyring {
+		if c.source != "環境変数 "+config.APITokenEnv || !utils.PromptForConfirmation("環境変数のAPIトークンをOSのキーリングに保存しますか？") {
+			return nil
+		}
+	}
+	if err := config.SaveKeyringToken(serverURL, c.token); err != nil {
+		return err
+	}
+	c.inKeyring = true
+	fmt.Println("🔐 APIトークンをキーリングに保存しました")
+	return nil
+}
+
+// describe は認証エラーの場合に、使用した認証情報をエラーに追加します
+func (c *initCredential) describe(err error, login string) error {
+	if !errors.Is(err, errInitUnauthorized) {
+		return err
+	}
+	return fmt.Errorf("%w\n使用した認証情報: %s (ログイン: %s)\nAPIトークンとログインメールアドレスの組み合わせを確認してください", err, c.source, login)
+}
+
+// askInitCredential はinitで使用するAPIトークンを決めます
+// キーリングに保存済みのトークンがなく、環境変数も設定されていない場合は入力を求めます。トークンなしで続行する場合はnilを返します。
+func askInitCredential(serverURL string) (*initCredential, error) {
+	if token, err := config.KeyringToken(serverURL); err == nil && token != "" {
+		return &initCredential{token: token, source: "キーリング", inKeyring: true}, nil
+	}
+	if token := os.Getenv(config.APITokenEnv); token != "" {
+		return &initCredential{token: token, source: "環境変数 " + config.APITokenEnv}, nil
+	}
+
+	fmt.Printf("\n⚠️  %s環境変数が設定されていません。\n", config.APITokenEnv)
+	fmt.Println("   Atlassian API Token (https://id.atlassian.com/manage-profile/security/api-tokens) を取得してください。")
+
+	choice, err := ui.Select("🔑 APIトークンを指定してください:", []ui.SelectorOption{
+		{Title: "APIトークンを入力する", Description: "入力した内容は表示されません", Value: "input"},
+		{Title: "トークンなしで続行する", Description: "JIRAへの問い合わせを省略し、設定ファイルの雛形だけを作成します", Value: "skip"},
+		{Title: "中止する", Description: "セットアップを中止します", Value: "abort"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("確認入力がキャンセルされました: %v", err)
+	}
+	switch choice.(string) {
+	case "skip":
+		return nil, nil
+	case "abort":
+		return nil, fmt.Errorf("セットアップを中止しました")
+	}
+
+	var token string
+	tokenForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("APIトークン").
+				EchoMode(huh.EchoModePassword).
+				Value(&token).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("APIトークンは必須です")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(huh.ThemeBase())
+	if err := tokenForm.Run(); err != nil {
+		return nil, fmt.Errorf("APIトークンの入力がキャンセルされました: %v", err)
+	}
+	cred := &initCredential{token: strings.TrimSpace(token), source: "入力したAPIトークン"}
+
+	// 次回以降のトークンの取得方法
+	choice, err = ui.Select("💾 次回以降のAPIトークンの取得方法を選択してください:", []ui.SelectorOption{
+		{Title: "OSのキーリングに保存する", Description: "JIRAで確認できたらキーリングに保存します (tkt auth set-token と同じ)", Value: "keyring"},
+		{Title: fmt.Sprintf("環境変数 %s", config.APITokenEnv), Description: "設定ファイルには何も書き込みません", Value: "env"},
+		{Title: "ファイルに保存する (token_file)", Description: fmt.Sprintf("%s にパーミッション600で保存します", defaultTokenFile), Value: "file"},
+		{Title: "コマンドで取得する (token_command)", Description: "パスワードマネージャーなどのコマンドを指定します", Value: "command"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("取得方法の選択がキャンセルされました: %v", err)
+	}
+	switch choice.(string) {
+	case "keyring":
+		cred.saveToKeyring = true
+	case "file":
+		path := config.ExpandHome(defaultTokenFile)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("トークンの保存先の作成に失敗しました: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(cred.token+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("トークンの保存に失敗しました: %v", err)
+		}
+		cred.tokenFile = defaultTokenFile
+	case "command":
+		var command string
+		commandForm := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title("token_command").
+					Description("APIトークンを標準出力に出力するコマンド (例: op read op://Private/jira/token)").
+					Value(&command),
+			),
+		).WithTheme(huh.ThemeBase())
+		if err := commandForm.Run(); err != nil {
+			return nil, fmt.Errorf("コマンドの入力がキャンセルされました: %v", err)
+		}
+		cred.tokenCommand = strings.TrimSpace(command)
+	}
+	return cred, nil
+}
+
+// writePlaceholderConfig はAPIトークンなしで続行した場合に、雛形の設定ファイルを作成します
+func writePlaceholderConfig(serverURL, loginEmail string) error {
+	const placeholderProjectKey = "YOUR_PROJECT_KEY"
+	cfg := &config.Config{
+		AuthType:  "basic",
+		Login:     loginEmail,
+		Server:    serverURL,
+		JQL:       fmt.Sprintf("project = %s", placeholderProjectKey),
+		Timezone:  "Asia/Tokyo",
+		Directory: "tickets",
+	}
+	cfg.Project.Key = placeholderProjectKey
+	cfg.Project.Type = "software"
+
+	if err := writeConfigFile(cfg); err != nil {
+		return err
+	}
+
+	fmt.Println("\n📝 設定ファイルの雛形を作成しました")
+	fmt.Printf("   設定ファイル: %s (カレントディレクトリ)\n", initConfigFile)
+	fmt.Println("\n次の手順で設定を完了してください:")
+	fmt.Printf("   1. APIトークンを取得して、tkt auth set-token で保存する (または環境変数 %s に設定する)\n", config.APITokenEnv)
+	fmt.Println("   2. tkt init を再実行して、プロジェクト・ボード・Issue Typeを設定する")
+	fmt.Printf("      (手動で設定する場合は %s の project, board, jql, issue.types を編集してください)\n", initConfigFile)
+	return nil
+}
+
+// writeConfigFile は設定ファイルをカレントディレクトリに書き込みます
+func writeConfigFile(cfg *config.Config) error {
 	data, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("設定ファイルのマーシャルに失敗しました: %v", err)
 	}
-
-	if err := os.WriteFile(configFile, data, 0644); err != nil {
+	if err := os.WriteFile(initConfigFile, data, 0644); err != nil {
 		return fmt.Errorf("設定ファイルの書き込みに失敗しました: %v", err)
 	}
+	return nil
+}
 
-	fmt.Println("\n✅ 設定が完了しました！")
-	fmt.Printf("   設定ファイル: %s (カレントディレクトリ)\n", configFile)
-	fmt.Printf("   プロジェクト: %s (%s)\n", selectedProject.Name, selectedProject.Key)
-	fmt.Printf("   ボード: %s (ID: %d)\n", selectedBoard.Name, selectedBoard.ID)
+// countIssues はJQLに一致するチケットの件数を返します
+// 件数のみを返すAPIがない場合 (Server/DC) は maxResults=0 の検索結果の total を使います。
+func countIssues(serverURL, email, apiToken, jql string) (int, error) {
+	body, err := json.Marshal(map[string]string{"jql": jql})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), "POST", serverURL+"/rest/api/3/search/approximate-count", strings.NewReader(string(body)))
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(email, apiToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
 
-	return nil
+	client := jira.DefaultHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		u := serverURL + "/rest/api/2/search?maxResults=0&fields=key&jql=" + url.QueryEscape(jql)
+		req, err := http.NewRequestWithContext(context.Background(), "GET", u, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.SetBasicAuth(email, apiToken)
+		req.Header.Set("Accept", "application/json")
+		resp, err = client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return 0, fmt.Errorf("%w: %s", errInitUnauthorized, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("JIRA API request failed: %s", resp.Status)
+	}
+
+	var result struct {
+		Count int `json:"count"`
+		Total int `json:"total"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
+	}
+	return max(result.Count, result.Total), nil
 }
 
 func fetchProjects(serverURL, email, apiToken string) ([]JiraProject, error) {
@@ -289,13 +538,16 @@ func fetchProjects(serverURL, email, apiToken string) ([]JiraProject, error) {
 	req.SetBasicAuth(email, apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
+	client := jira.DefaultHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w: %s", errInitUnauthorized, resp.Status)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("JIRA API request failed: %s", resp.Status)
 	}
@@ -319,13 +571,16 @@ func fetchBoards(serverURL, email, apiToken, projectKey string) ([]JiraBoard, er
 	req.SetBasicAuth(email, apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
+	client := jira.DefaultHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w: %s", errInitUnauthorized, resp.Status)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("JIRA API request failed: %s", resp.Status)
 	}
@@ -353,13 +608,16 @@ func fetchIssueTypes(serverURL, email, apiToken, projectID string) ([]JiraIssueT
 	req.SetBasicAuth(email, apiToken)
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
+	client := jira.DefaultHTTPClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w: %s", errInitUnauthorized, resp.Status)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("JIRA API request failed: %s", resp.Status)
 	}
@@ -371,3 +629,42 @@ func fetchIssueTypes(serverURL, email, apiToken, projectID string) ([]JiraIssueT
 
 	return issueTypes, nil
 }
+
+func fetchComponents(serverURL, email, apiToken, projectKey string) ([]string, error) {
+	url := serverURL + "/rest/api/3/project/" + projectKey + "/components"
+
+	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(email, apiToken)
+	req.Header.Set("Accept", "application/json")
+
+	client := jira.DefaultHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("%w: %s", errInitUnauthorized, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JIRA API request failed: %s", resp.Status)
+	}
+
+	var components []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&components); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var jqlWrite bool
+
+var jqlCmd = &cobra.Command{
+	Use:   "jql",
+	Short: "フェッチの対象のJQLを対話的に組み立てます",
+	Long: `プロジェクト、ステータスカテゴリ、担当者、更新日の範囲を選んでJQLを組み立てます。
+組み立てたJQLと一致するチケットの件数を確認してから、tkt.yml の jql に書き込めます。
+選択肢は設定ファイルとキャッシュから作るので、件数の確認以外ではJIRAに問い合わせません。`,
+	Example: `  tkt jql
+  tkt jql --write`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		projects := jqlProjectCandidates(cfg)
+		if len(projects) == 0 {
+			return fmt.Errorf("プロジェクトが見つかりません。tkt initで設定してください")
+		}
+
+		// 件数の確認に使う。JIRAクライアントを作れない場合は件数を表示せずに続ける
+		var count func(jql string) (int, error)
+		if jiraClient, err := jira.NewClient(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  件数を確認できません: %v\n", err)
+		} else {
+			count = func(jql string) (int, error) {
+				return jiraClient.CountIssues(cmd.Context(), jira.JQL(jql))
+			}
+		}
+
+		jql, err := buildJQLInteractively(projects, count)
+		if err != nil {
+			return err
+		}
+		fmt.Println(jql)
+
+		if jql == cfg.JQL {
+			fmt.Println("tkt.yml の jql と同じです")
+			return nil
+		}
+		if !jqlWrite && !utils.PromptForConfirmation("tkt.yml の jql をこのJQLに置き換えますか？") {
+			return nil
+		}
+		if err := config.SaveJQL(jql); err != nil {
+			return err
+		}
+		fmt.Println("✅ tkt.yml の jql を更新しました。tkt fetch --clean で取得し直してください")
+		return nil
+	},
+}
+
+// jqlConditions は対話的に組み立てるJQLの条件です。空の条件はJQLに含めません。
+type jqlConditions struct {
+	Project        string // プロジェクトのキー
+	StatusCategory string // open (完了以外), new, indeterminate, done
+	Assignee       string // me (自分), unassigned (未割り当て)
+	UpdatedWithin  string // JQLの相対日付 (例: 4w)
+}
+
+// String は条件をJQLにします
+func (c jqlConditions) String() string {
+	var clauses []string
+	if c.Project != "" {
+		clauses = append(clauses, "project = "+c.Project)
+	}
+	switch c.StatusCategory {
+	case "open":
+		clauses = append(clauses, "statusCategory != Done")
+	case "new":
+		clauses = append(clauses, `statusCategory = "To Do"`)
+	case "indeterminate":
+		clauses = append(clauses, `statusCategory = "In Progress"`)
+	case "done":
+		clauses = append(clauses, "statusCategory = Done")
+	}
+	switch c.Assignee {
+	case "me":
+		clauses = append(clauses, "assignee = currentUser()")
+	case "unassigned":
+		clauses = append(clauses, "assignee IS EMPTY")
+	}
+	if c.UpdatedWithin != "" {
+		clauses = append(clauses, "updated >= -"+c.UpdatedWithin)
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// jqlProjectCandidates はプロジェクトの候補を返します
+// 設定ファイルのプロジェクトを先頭に、キャッシュにあるチケットのキーのプロジェクトを続けます。
+func jqlProjectCandidates(cfg *config.Config) []string {
+	var projects []string
+	if cfg.Project.Key != "" {
+		projects = append(projects, cfg.Project.Key)
+	}
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return projects
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		verbose.Printf(verbose.Cache, "警告: キャッシュディレクトリの読み込みに失敗しました: %v\n", err)
+		return projects
+	}
+	var cached []string
+	for _, entry := range entries {
+		// キャッシュのファイル名は常に KEY.md
+		key, ok := strings.CutSuffix(entry.Name(), ".md")
+		if entry.IsDir() || !ok || !utils.IsValidJIRAKey(key) {
+			continue
+		}
+		project, _, _ := strings.Cut(key, "-")
+		if !slices.Contains(projects, project) && !slices.Contains(cached, project) {
+			cached = append(cached, project)
+		}
+	}
+	slices.Sort(cached)
+	return append(projects, cached...)
+}
+
+// buildJQLInteractively は条件を選んでJQLを組み立て、JQLと一致するチケットの件数を確認させます
+// projects が1つの場合はプロジェクトの選択を省略します。count が nil の場合は件数を表示しません。
+func buildJQLInteractively(projects []string, count func(jql string) (int, error)) (string, error) {
+	for {
+		conds, err := selectJQLConditions(projects)
+		if err != nil {
+			return "", err
+		}
+		jql := conds.String()
+
+		fmt.Printf("\nJQL: %s\n", jql)
+		if count != nil {
+			n, err := ui.WithSpinnerValue("一致するチケットを数えています...", func() (int, error) {
+				return count(jql)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  件数の取得に失敗しました: %v\n", err)
+			} else {
+				fmt.Printf("一致するチケット: %d 件\n", n)
+			}
+		}
+
+		choice, err := ui.Select("このJQLを使いますか？", []ui.SelectorOption{
+			{Title: "このJQLを使う", Value: "use"},
+			{Title: "条件を選び直す", Value: "retry"},
+		})
+		if err != nil {
+			return "", fmt.Errorf("JQLの確認がキャンセルされました: %v", err)
+		}
+		if choice == nil {
+			return "", fmt.Errorf("JQLが選択されていません")
+		}
+		if choice == "use" {
+			return jql, nil
+		}
+	}
+}
+
+// selectJQLConditions はJQLの条件をリストから選ばせます
+func selectJQLConditions(projects []string) (jqlConditions, error) {
+	var conds jqlConditions
+	selectValue := func(title string, options []ui.SelectorOption) (string, error) {
+		v, err := ui.Select(title, options)
+		if err != nil {
+			return "", fmt.Errorf("条件の選択がキャンセルされました: %v", err)
+		}
+		if v == nil {
+			return "", fmt.Errorf("条件が選択されていません")
+		}
+		return v.(string), nil
+	}
+
+	if len(projects) == 1 {
+		conds.Project = projects[0]
+	} else {
+		options := make([]ui.SelectorOption, 0, len(projects))
+		for _, p := range projects {
+			options = append(options, ui.SelectorOption{Title: p, Value: p})
+		}
+		project, err := selectValue("📋 プロジェクト", options)
+		if err != nil {
+			return conds, err
+		}
+		conds.Project = project
+	}
+
+	var err error
+	conds.StatusCategory, err = selectValue("📌 ステータス", []ui.SelectorOption{
+		{Title: "完了以外", Description: "statusCategory != Done", Value: "open"},
+		{Title: "すべて", Value: ""},
+		{Title: "未着手 (To Do)", Value: "new"},
+		{Title: "進行中 (In Progress)", Value: "indeterminate"},
+		{Title: "完了 (Done)", Value: "done"},
+	})
+	if err != nil {
+		return conds, err
+	}
+	conds.Assignee, err = selectValue("👤 担当者", []ui.SelectorOption{
+		{Title: "すべて", Value: ""},
+		{Title: "自分", Description: "assignee = currentUser()", Value: "me"},
+		{Title: "未割り当て", Description: "assignee IS EMPTY", Value: "unassigned"},
+	})
+	if err != nil {
+		return conds, err
+	}
+	conds.UpdatedWithin, err = selectValue("🕒 更新日", []ui.SelectorOption{
+		{Title: "すべて", Value: ""},
+		{Title: "1週間以内", Value: "1w"},
+		{Title: "4週間以内", Value: "4w"},
+		{Title: "3か月以内", Value: "13w"},
+		{Title: "半年以内", Value: "26w"},
+	})
+	if err != nil {
+		return conds, err
+	}
+	return conds, nil
+}
+
+func init() {
+	rootCmd.AddCommand(jqlCmd)
+
+	jqlCmd.Flags().BoolVar(&jqlWrite, "write", false, "確認せずに tkt.yml の jql を置き換える")
+}
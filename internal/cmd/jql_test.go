@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJQLConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		conds jqlConditions
+		want  string
+	}{
+		{
+			name:  "プロジェクトのみ",
+			conds: jqlConditions{Project: "PRJ"},
+			want:  "project = PRJ",
+		},
+		{
+			name:  "完了以外の自分のチケット",
+			conds: jqlConditions{Project: "PRJ", StatusCategory: "open", Assignee: "me"},
+			want:  "project = PRJ AND statusCategory != Done AND assignee = currentUser()",
+		},
+		{
+			name:  "すべての条件",
+			conds: jqlConditions{Project: "PRJ", StatusCategory: "indeterminate", Assignee: "unassigned", UpdatedWithin: "4w"},
+			want:  `project = PRJ AND statusCategory = "In Progress" AND assignee IS EMPTY AND updated >= -4w`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.conds.String())
+		})
+	}
+}
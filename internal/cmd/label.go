@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var labelPush bool
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "チケットのラベルをまとめて操作します",
+	Long:  `チケットのラベルをまとめて操作します。`,
+}
+
+var labelAddCmd = &cobra.Command{
+	Use:   "add LABEL TICKET-KEY...",
+	Short: "チケットにラベルを追加します",
+	Long: `ワークスペースのチケットのlabelsにラベルを追加します。ワークスペースにないチケットはキャッシュからコピーします。
+--push を指定すると、labels全体を置き換えずにラベルの追加だけをJIRAに送信するので、他の人が同時に編集したラベルは残ります。`,
+	Example: `  tkt label add backend PRJ-1 PRJ-2
+  tkt label add backend PRJ-1 --push`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+		return runLabelEdit(args[0], args[1:], true)
+	},
+}
+
+var labelRemoveCmd = &cobra.Command{
+	Use:   "remove LABEL TICKET-KEY...",
+	Short: "チケットからラベルを削除します",
+	Long: `ワークスペースのチケットのlabelsからラベルを削除します。ワークスペースにないチケットはキャッシュからコピーします。
+--push を指定すると、labels全体を置き換えずにラベルの削除だけをJIRAに送信するので、他の人が同時に編集したラベルは残ります。`,
+	Example: `  tkt label remove wontfix PRJ-1 PRJ-2 --push`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+		return runLabelEdit(args[0], args[1:], false)
+	},
+}
+
+var labelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "キャッシュのチケットで使われているラベルを件数とともに表示します",
+	Long: `キャッシュのチケットで使われているラベルを、使われている件数の多い順に表示します。
+表記ゆれ (backend と back-end など) を見つけてラベルを統一するのに使えます。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		tickets, err := loadTickets(cacheDir, false)
+		if err != nil {
+			return fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
+		}
+		return printLabelCounts(os.Stdout, countLabels(tickets))
+	},
+}
+
+// runLabelEdit はチケットのラベルを追加 (add が true) または削除し、--push の場合はJIRAにも反映します
+func runLabelEdit(label string, args []string, add bool) error {
+	if err := jira.ValidateLabel(label); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(args))
+	for _, arg := range args {
+		key := ticket.CanonicalKey(arg)
+		if !utils.IsValidJIRAKey(key) {
+			return fmt.Errorf("無効なチケットキーです: %s", arg)
+		}
+		keys = append(keys, key)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+	}
+
+	edit := func(labels []string) ([]string, bool) {
+		if add {
+			return addLabel(labels, label)
+		}
+		return removeLabel(labels, label)
+	}
+	sign := "-"
+	if add {
+		sign = "+"
+	}
+
+	for _, key := range keys {
+		t, err := loadWorkspaceTicket(cfg, key)
+		if err != nil {
+			return err
+		}
+		labels, changed := edit(t.Labels)
+		if !changed {
+			fmt.Printf("🏷️  %s: %s%s (変更なし)\n", key, sign, label)
+			continue
+		}
+		t.Labels = labels
+		if _, err := t.SaveToFile(filepath.Dir(t.FilePath), ticket.WithFilenameTemplate(filenameTemplate(cfg))); err != nil {
+			return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+		}
+		fmt.Printf("🏷️  %s: %s%s\n", key, sign, label)
+	}
+
+	if !labelPush {
+		fmt.Println("'tkt push' または --push でJIRAに反映できます")
+		return nil
+	}
+
+	err = ui.WithSpinner("ラベルを更新中...", func() error {
+		jiraClient, err := jira.NewClient(cfg)
+		if err != nil {
+			return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		for _, key := range keys {
+			var addLabels, removeLabels []string
+			if add {
+				addLabels = []string{label}
+			} else {
+				removeLabels = []string{label}
+			}
+			if err := jiraClient.UpdateLabels(key, addLabels, removeLabels); err != nil {
+				return fmt.Errorf("チケット %s のラベルの更新に失敗しました: %v", key, err)
+			}
+			// キャッシュにも同じ操作を反映し、pushしたラベルが差分にならないようにする
+			if err := updateCachedTicket(key, func(t *ticket.Ticket) {
+				t.Labels, _ = edit(t.Labels)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ %d 件のチケットのラベルをJIRAに反映しました\n", len(keys))
+	return nil
+}
+
+// addLabel はラベルを末尾に追加します。すでにある場合は変更しません。
+func addLabel(labels []string, label string) ([]string, bool) {
+	if slices.Contains(labels, label) {
+		return labels, false
+	}
+	return append(slices.Clone(labels), label), true
+}
+
+// removeLabel はラベルを削除します。ない場合は変更しません。
+func removeLabel(labels []string, label string) ([]string, bool) {
+	if !slices.Contains(labels, label) {
+		return labels, false
+	}
+	return slices.DeleteFunc(slices.Clone(labels), func(l string) bool { return l == label }), true
+}
+
+// labelCount はラベルと、そのラベルが付いているチケットの件数です
+type labelCount struct {
+	label string
+	count int
+}
+
+// countLabels はラベルごとのチケットの件数を、件数の多い順 (同じ件数はラベル名順) に返します
+func countLabels(tickets []*ticket.Ticket) []labelCount {
+	counts := make(map[string]int)
+	for _, t := range tickets {
+		for _, label := range t.Labels {
+			counts[label]++
+		}
+	}
+	result := make([]labelCount, 0, len(counts))
+	for label, count := range counts {
+		result = append(result, labelCount{label: label, count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].count != result[j].count {
+			return result[i].count > result[j].count
+		}
+		return result[i].label < result[j].label
+	})
+	return result
+}
+
+func printLabelCounts(w io.Writer, counts []labelCount) error {
+	if len(counts) == 0 {
+		fmt.Fprintln(w, "ラベルはありません")
+		return nil
+	}
+	rows := [][]string{{"LABEL", "TICKETS"}}
+	for _, c := range counts {
+		rows = append(rows, []string{c.label, strconv.Itoa(c.count)})
+	}
+	return writeTable(w, rows)
+}
+
+func init() {
+	labelCmd.AddCommand(labelAddCmd)
+	labelCmd.AddCommand(labelRemoveCmd)
+	labelCmd.AddCommand(labelListCmd)
+	rootCmd.AddCommand(labelCmd)
+
+	labelCmd.PersistentFlags().BoolVar(&labelPush, "push", false, "ラベルの変更をすぐにJIRAに反映する")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRemoveLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      []string
+		add         bool
+		label       string
+		want        []string
+		wantChanged bool
+	}{
+		{name: "追加", labels: []string{"ui"}, add: true, label: "backend", want: []string{"ui", "backend"}, wantChanged: true},
+		{name: "すでにある", labels: []string{"backend"}, add: true, label: "backend", want: []string{"backend"}},
+		{name: "削除", labels: []string{"ui", "backend", "api"}, label: "backend", want: []string{"ui", "api"}, wantChanged: true},
+		{name: "削除するラベルがない", labels: []string{"ui"}, label: "backend", want: []string{"ui"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]string{}, tt.labels...)
+			var got []string
+			var changed bool
+			if tt.add {
+				got, changed = addLabel(tt.labels, tt.label)
+			} else {
+				got, changed = removeLabel(tt.labels, tt.label)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, original, tt.labels, "元のスライスは変更しない")
+		})
+	}
+}
+
+func TestCountLabels(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{Key: "PRJ-1", Labels: []string{"backend", "api"}},
+		{Key: "PRJ-2", Labels: []string{"backend"}},
+		{Key: "PRJ-3", Labels: []string{"back-end", "api"}},
+		{Key: "PRJ-4"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, printLabelCounts(&buf, countLabels(tickets)))
+	assert.Equal(t, "LABEL     TICKETS\napi       2\nbackend   2\nback-end  1\n", buf.String())
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link TICKET-KEY RELATION TICKET-KEY",
+	Short: "JIRAチケット同士をリンクします",
+	Long: `JIRAチケット同士をリンクします。
+RELATIONにはリンクの説明 (blocks, is blocked by, relates to など) またはリンクの種類の名前 (Blocks など) を指定します。
+
+リンクした両方のチケットのキャッシュとワークスペースのlinksも更新します。
+フロントマターのlinksを編集してpushしてもリンクを追加・削除できます。`,
+	Example: `  tkt link PRJ-1 blocks PRJ-2
+  tkt link PRJ-1 is blocked by PRJ-2
+  tkt link PRJ-1 relates to PRJ-3`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		from, to := args[0], args[len(args)-1]
+		relation := strings.Join(args[1:len(args)-1], " ")
+		for _, key := range []string{from, to} {
+			if !utils.IsValidJIRAKey(key) {
+				return fmt.Errorf("無効なJIRAキーです: %s", key)
+			}
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		err = ui.WithSpinner("リンクを作成中...", func() error {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			if err := jiraClient.LinkIssues(from, relation, to); err != nil {
+				return err
+			}
+			// リンクした両方のチケットのlinksをリモートに合わせる
+			for _, key := range []string{from, to} {
+				if err := refreshLinks(cfg, jiraClient, key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("🔗 %s %s %s\n", from, relation, to)
+		return nil
+	},
+}
+
+// refreshLinks はリモートのチケットを取得してキャッシュに保存し、ワークスペースのlinksも同じにします
+// ワークスペースのlinksだけが古いままだと、次のpushでリンクを削除する差分になるためです。
+func refreshLinks(cfg *config.Config, jiraClient *jira.Client, key string) error {
+	remote, err := jiraClient.FetchIssue(key)
+	if err != nil {
+		return fmt.Errorf("チケット %s の取得に失敗しました: %v", key, err)
+	}
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	if _, err := remote.SaveToCache(cacheDir); err != nil {
+		return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+	}
+
+	if cfg.Directory == "" {
+		return nil
+	}
+	filePath, err := ticket.FindFile(cfg.Directory, key)
+	if err != nil || filePath == "" {
+		return nil
+	}
+	local, err := ticket.FromFile(filePath)
+	if err != nil {
+		return fmt.Errorf("チケット %s の読み込みに失敗しました: %v", key, err)
+	}
+	local.Links = remote.Links
+	if _, err := local.SaveToFile(cfg.Directory, ticket.WithFilenameTemplate(filenameTemplate(cfg))); err != nil {
+		return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// exitCodeLintFindings は指摘がある場合の終了コードです
+const exitCodeLintFindings = 1
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "pushで反映されない編集などを検査します",
+	Long: `ワークスペースのチケットを検査し、pushで反映されない編集を一覧にします。
+読み取り専用の項目 (created_at, creator, updated_at, reporter, time_spent, url) の編集と、
+下書きで未入力の作成時の必須項目、階層のルールに合わない親 (サブタスクの親は標準のチケット、標準のチケットの親はエピック) を検出します。
+tkt push の前に確認する内容と同じです。
+必須項目はキャッシュ (1日) がない場合のみ、親のチケットタイプはキャッシュにない場合のみJIRAから取得します。
+
+指摘がない場合は終了コード0、指摘がある場合は終了コード1で終了します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+		if cfg.Directory == "" {
+			return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+		}
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		diffs, err := ticket.CompareDirs(cfg.Directory, cacheDir, compareOptions(cfg)...)
+		if err != nil {
+			return fmt.Errorf("差分の検出に失敗しました: %v", err)
+		}
+
+		var findings []string
+		for _, diff := range readonlyEditDiffs(diffs) {
+			findings = append(findings, fmt.Sprintf("%s: %s", diff.FilePath, readonlyEditMessage(diff)))
+		}
+		// JIRAに接続できなくても、キャッシュで確認できる項目は検査する
+		drafts := draftDiffs(diffs)
+		var jiraClient *jira.Client
+		var clientErr error
+		if len(drafts) > 0 || slices.ContainsFunc(diffs, parentChanged) {
+			jiraClient, clientErr = jira.NewClient(cfg)
+		}
+		if len(drafts) > 0 {
+			if clientErr != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  必須項目の確認をスキップします: %v\n", clientErr)
+			} else {
+				missing, err := requiredFieldFindings(cmd.Context(), jiraClient, cacheDir, drafts)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "⚠️  必須項目の確認をスキップします: %v\n", err)
+				}
+				findings = append(findings, missing...)
+			}
+		}
+		if clientErr != nil {
+			jiraClient = nil
+		}
+		parents, err := parentFindings(cfg, jiraClient, cacheDir, diffs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  親のチケットタイプの確認をスキップします: %v\n", err)
+		}
+		findings = append(findings, parents...)
+
+		if len(findings) == 0 {
+			fmt.Println("✅ 指摘はありません")
+			return nil
+		}
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		os.Exit(exitCodeLintFindings)
+		return nil
+	},
+}
+
+// draftDiffs はJIRAに新規作成する下書きの差分を返します
+func draftDiffs(diffs []ticket.DiffResult) []ticket.DiffResult {
+	var drafts []ticket.DiffResult
+	for _, diff := range diffs {
+		if diff.Change == ticket.ChangeCreate {
+			drafts = append(drafts, diff)
+		}
+	}
+	return drafts
+}
+
+// missingRequiredFields は必須項目のうちチケットに入力されていないものを返します
+// tktから設定できない項目 (フロントマターのキーがない項目) は常に含めます。
+func missingRequiredFields(t *ticket.Ticket, required []jira.RequiredField) []jira.RequiredField {
+	var missing []jira.RequiredField
+	for _, f := range required {
+		if f.FrontmatterKey == "" || !t.HasValue(f.FrontmatterKey) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// requiredFieldFindings は下書きで未入力の作成時の必須項目を、下書きごとの指摘にして返します
+// 必須項目はIssue Typeごとにcreatemeta APIから取得し、キャッシュします。
+func requiredFieldFindings(ctx context.Context, jiraClient *jira.Client, cacheDir string, drafts []ticket.DiffResult) ([]string, error) {
+	var findings []string
+	for _, diff := range drafts {
+		t, err := ticket.FromFile(diff.FilePath)
+		if err != nil {
+			return findings, err
+		}
+		required, err := jiraClient.RequiredFields(ctx, cacheDir, t.Type)
+		if err != nil {
+			return findings, fmt.Errorf("%s の必須項目の取得に失敗しました: %w", t.Type, err)
+		}
+		missing := missingRequiredFields(t, required)
+		if len(missing) == 0 {
+			continue
+		}
+		labels := make([]string, 0, len(missing))
+		for _, f := range missing {
+			labels = append(labels, f.Label())
+		}
+		findings = append(findings, fmt.Sprintf("%s: 作成時の必須項目が未入力です: %s", diff.FilePath, strings.Join(labels, ", ")))
+	}
+	return findings, nil
+}
+
+// readonlyEditDiffs は読み取り専用の項目が編集されているチケットの差分を返します
+func readonlyEditDiffs(diffs []ticket.DiffResult) []ticket.DiffResult {
+	var edited []ticket.DiffResult
+	for _, diff := range diffs {
+		if len(diff.ReadonlyEdits) > 0 {
+			edited = append(edited, diff)
+		}
+	}
+	return edited
+}
+
+// readonlyEditMessage は読み取り専用の項目の編集を知らせるメッセージを返します
+func readonlyEditMessage(diff ticket.DiffResult) string {
+	return fmt.Sprintf("%s: 読み取り専用の項目の編集はpushで反映されません: %s", diff.Key, strings.Join(diff.ReadonlyEdits, ", "))
+}
+
+// reportReadonlyEdits はpushの前に読み取り専用の項目の編集を警告します
+// push.strict_readonly がtrueの場合は、警告の代わりにエラーを返してpushを中止します。
+func reportReadonlyEdits(cfg *config.Config, edited []ticket.DiffResult) error {
+	if len(edited) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(edited))
+	for _, diff := range edited {
+		messages = append(messages, readonlyEditMessage(diff))
+	}
+	if cfg.Push.StrictReadonly {
+		return fmt.Errorf("読み取り専用の項目が編集されています。元に戻してからpushしてください (push.strict_readonly)\n%s", strings.Join(messages, "\n"))
+	}
+	for _, m := range messages {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", m)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
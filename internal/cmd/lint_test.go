@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentFindings(t *testing.T) {
+	cacheDir, localDir := t.TempDir(), t.TempDir()
+	for _, parent := range []*ticket.Ticket{
+		{Key: "PRJ-100", Type: "Epic", Title: "決済基盤刷新"},
+		{Key: "PRJ-200", Type: "Story", Title: "決済画面"},
+	} {
+		_, err := parent.SaveToCache(cacheDir)
+		assert.NoError(t, err)
+	}
+	save := func(tk *ticket.Ticket) string {
+		path, err := tk.SaveToFile(localDir)
+		assert.NoError(t, err)
+		return path
+	}
+	underStory := save(&ticket.Ticket{Key: "PRJ-1", Type: "Story", Title: "ストーリーの下のストーリー", ParentKey: "PRJ-200"})
+	underEpic := save(&ticket.Ticket{Key: "PRJ-2", Type: "Story", Title: "エピックの下のストーリー", ParentKey: "PRJ-100"})
+	draft := save(&ticket.Ticket{Type: "Sub-task", Title: "エピックの下のサブタスク", ParentKey: "PRJ-100"})
+	unchanged := save(&ticket.Ticket{Key: "PRJ-3", Type: "Story", Title: "親を変えていない", ParentKey: "PRJ-200"})
+	unknown := save(&ticket.Ticket{Key: "PRJ-4", Type: "Story", Title: "キャッシュにない親", ParentKey: "OPS-1"})
+
+	cfg := &config.Config{}
+	cfg.Issue.Types = []config.IssueType{{Name: "Sub-task", Subtask: true}, {Name: "Story"}, {Name: "Epic"}}
+	diffs := []ticket.DiffResult{
+		{Key: "PRJ-1", FilePath: underStory, HasDiff: true, Change: ticket.ChangeUpdate, ChangedFields: []string{"parentKey"}},
+		{Key: "PRJ-2", FilePath: underEpic, HasDiff: true, Change: ticket.ChangeUpdate, ChangedFields: []string{"parentKey"}},
+		{FilePath: draft, HasDiff: true, Change: ticket.ChangeCreate},
+		{Key: "PRJ-3", FilePath: unchanged, HasDiff: true, Change: ticket.ChangeUpdate, ChangedFields: []string{"title"}},
+		// JIRAに接続できない場合、キャッシュにない親はスキップする
+		{Key: "PRJ-4", FilePath: unknown, HasDiff: true, Change: ticket.ChangeUpdate, ChangedFields: []string{"parentKey"}},
+	}
+
+	findings, err := parentFindings(cfg, nil, cacheDir, diffs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(localDir, "PRJ-1.md") + ": Story の親はエピックである必要があります (親: Story) (PRJ-200)",
+		draft + ": サブタスク (Sub-task) の親は標準のチケットである必要があります (親: Epic) (PRJ-100)",
+	}, findings)
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listCache    bool
+	listStatus   string
+	listType     string
+	listAssignee string
+	listSprint   string
+	listCreator  string
+	listSince    string
+	listSort     string
+	listFormat   string
+	listBy       string
+)
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "チケットを一覧表示します",
+	Long: `ワークスペースのチケットを表形式で一覧表示します。--cache を指定するとキャッシュのチケットを表示します。
+未pushのチケットのキーは DRAFT と表示します。
+
+--status, --type, --assignee, --sprint, --creator で絞り込めます (大文字と小文字は区別しません)。
+--created-since で作成日 (2025-06-01 のような日付、または 14d のような日数) 以降に作成されたチケットに絞り込めます。
+--sort で並び順 (updated: 更新日時の新しい順, key, status) を指定できます。
+--format json|tsv でスクリプト向けの形式で出力します (tsvにはヘッダー行を出力しません)。
+--by で項目 (status, assignee, sprint, team) の値ごとにまとめて表示します (table形式のみ)。`,
+	Example: `  tkt ls
+  tkt ls --status "In Progress" --assignee "山田 太郎"
+  tkt ls --cache --sprint "Sprint 42" --sort updated
+  tkt ls --cache --creator "automation" --created-since 14d
+  tkt ls --format tsv | cut -f1
+  tkt ls --cache --sprint "Sprint 42" --by team`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		var dir string
+		if listCache {
+			dir, err = config.EnsureCacheDir()
+			if err != nil {
+				return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+			}
+		} else {
+			if cfg.Directory == "" {
+				return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+			}
+			dir = cfg.Directory
+		}
+
+		tickets, err := loadTicketsWithExtraSources(dir, !listCache && cfg.StrictTicketDetection)
+		if err != nil {
+			return fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
+		}
+		var createdSince time.Time
+		if listSince != "" {
+			createdSince, err = parseSinceDate(listSince, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+		tickets = filterTickets(tickets, listFilter{
+			status:       listStatus,
+			typ:          listType,
+			assignee:     listAssignee,
+			sprint:       listSprint,
+			creator:      listCreator,
+			createdSince: createdSince,
+		})
+		if err := sortTickets(tickets, listSort); err != nil {
+			return err
+		}
+
+		if listBy != "" {
+			if listFormat != "table" {
+				return fmt.Errorf("--by は --format table の場合のみ指定できます")
+			}
+			groups, err := groupTickets(tickets, listBy)
+			if err != nil {
+				return err
+			}
+			return printTicketGroups(os.Stdout, groups)
+		}
+		return printTickets(os.Stdout, tickets, listFormat)
+	},
+}
+
+// listFilter は一覧の絞り込み条件です。空の条件は絞り込みません。
+type listFilter struct {
+	status, typ, assignee, sprint, creator string
+	// createdSince はこの日時以降に作成されたチケットに絞り込みます (作成日時のない下書きは含めません)
+	createdSince time.Time
+}
+
+// filterTickets は条件に一致するチケットのみを返します (大文字と小文字は区別しません)
+func filterTickets(tickets []*ticket.Ticket, f listFilter) []*ticket.Ticket {
+	match := func(want, got string) bool {
+		return want == "" || strings.EqualFold(want, got)
+	}
+	var filtered []*ticket.Ticket
+	for _, t := range tickets {
+		if !f.createdSince.IsZero() && (t.CreatedAt.IsZero() || t.CreatedAt.Before(f.createdSince)) {
+			continue
+		}
+		if match(f.status, t.Status) && match(f.typ, t.Type) && match(f.assignee, t.Assignee) && match(f.sprint, t.SprintName) && match(f.creator, t.Creator) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// parseSinceDate は --created-since の値 (2025-06-01 のような日付、または 14d のような日数) を日時にします
+// 日付はローカル時刻の0時、日数は now からさかのぼった日の0時とします。
+func parseSinceDate(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			y, m, d := now.AddDate(0, 0, -n).Date()
+			return time.Date(y, m, d, 0, 0, 0, 0, now.Location()), nil
+		}
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("無効な日付です: %s (2025-06-01 のような日付、または 14d のような日数を指定してください)", s)
+	}
+	return t, nil
+}
+
+// sortTickets はチケットを指定した順に並べます
+func sortTickets(tickets []*ticket.Ticket, by string) error {
+	var less func(a, b *ticket.Ticket) bool
+	switch by {
+	case "key":
+		less = func(a, b *ticket.Ticket) bool { return a.Key < b.Key }
+	case "updated":
+		less = func(a, b *ticket.Ticket) bool { return a.UpdatedAt.After(b.UpdatedAt) }
+	case "status":
+		less = func(a, b *ticket.Ticket) bool { return a.Status < b.Status }
+	default:
+		return fmt.Errorf("無効な並び順です: %s (updated, key, status のいずれかを指定してください)", by)
+	}
+	sort.SliceStable(tickets, func(i, j int) bool {
+		a, b := tickets[i], tickets[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		// 同じ順位の場合はキー、ファイルの順にする (未pushのチケットはキーが空)
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.FilePath < b.FilePath
+	})
+	return nil
+}
+
+// ticketGroup は --by でまとめたチケットです
+type ticketGroup struct {
+	name    string
+	tickets []*ticket.Ticket
+}
+
+// noGroupValue は --by の項目に値のないチケットのグループ名です
+const noGroupValue = "(未設定)"
+
+// groupTickets はチケットを項目 (status, assignee, sprint, team) の値ごとにまとめます
+// グループは名前順で、値のないチケットは最後にまとめます。グループ内の順序は変えません。
+func groupTickets(tickets []*ticket.Ticket, by string) ([]ticketGroup, error) {
+	var value func(t *ticket.Ticket) string
+	switch by {
+	case "status":
+		value = func(t *ticket.Ticket) string { return t.Status }
+	case "assignee":
+		value = func(t *ticket.Ticket) string { return t.Assignee }
+	case "sprint":
+		value = func(t *ticket.Ticket) string { return t.SprintName }
+	case "team":
+		value = func(t *ticket.Ticket) string { return t.Team }
+	default:
+		return nil, fmt.Errorf("無効なグループ化の項目です: %s (status, assignee, sprint, team のいずれかを指定してください)", by)
+	}
+
+	byName := make(map[string]*ticketGroup)
+	var names []string
+	for _, t := range tickets {
+		name := value(t)
+		group, ok := byName[name]
+		if !ok {
+			group = &ticketGroup{name: name}
+			byName[name] = group
+			names = append(names, name)
+		}
+		group.tickets = append(group.tickets, t)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// 値のないグループは最後にする
+		if (names[i] == "") != (names[j] == "") {
+			return names[j] == ""
+		}
+		return names[i] < names[j]
+	})
+	groups := make([]ticketGroup, 0, len(names))
+	for _, name := range names {
+		group := *byName[name]
+		if group.name == "" {
+			group.name = noGroupValue
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// printTicketGroups はグループごとに見出しとチケットの表を出力します
+func printTicketGroups(w io.Writer, groups []ticketGroup) error {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "チケットはありません")
+		return nil
+	}
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "[%s] %d件\n", group.name, len(group.tickets))
+		if err := printTickets(w, group.tickets, "table"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTickets はチケットの一覧を指定した形式 (table, json, tsv) で出力します
+func printTickets(w io.Writer, tickets []*ticket.Ticket, format string) error {
+	switch format {
+	case "table":
+		if len(tickets) == 0 {
+			fmt.Fprintln(w, "チケットはありません")
+			return nil
+		}
+		// 日本語のタイトルや担当者名でも列がそろうように、表示幅で詰める
+		rows := [][]string{{"KEY", "TYPE", "STATUS", "ASSIGNEE", "UPDATED", "TITLE"}}
+		for _, t := range tickets {
+			rows = append(rows, listRow(t))
+		}
+		return writeTable(w, rows)
+	case "tsv":
+		for _, t := range tickets {
+			fmt.Fprintln(w, strings.Join(listRow(t), "\t"))
+		}
+		return nil
+	case "json":
+		dtos := make([]ticketDTO, 0, len(tickets))
+		parents := newParentTitleIndex(tickets)
+		for _, t := range tickets {
+			dtos = append(dtos, newTicketDTO(t, parents))
+		}
+		b, err := json.MarshalIndent(dtos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+		}
+		fmt.Fprintln(w, string(b))
+		return nil
+	default:
+		return fmt.Errorf("無効な出力形式です: %s (table, json, tsv のいずれかを指定してください)", format)
+	}
+}
+
+// listRow は一覧の1行 (キー, タイプ, ステータス, 担当者, 更新日, タイトル) を返します
+// タブと改行は列を崩すので空白に置き換えます。
+func listRow(t *ticket.Ticket) []string {
+	updated := "-"
+	if !t.UpdatedAt.IsZero() {
+		updated = t.UpdatedAt.Local().Format("2006-01-02")
+	}
+	clean := strings.NewReplacer("\t", " ", "\n", " ").Replace
+	return []string{
+		displayTicketKey(t),
+		orDash(clean(t.Type)),
+		orDash(clean(t.Status)),
+		orDash(clean(t.Assignee)),
+		updated,
+		clean(t.Title),
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().BoolVar(&listCache, "cache", false, "キャッシュのチケットを一覧表示する")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "ステータスで絞り込む")
+	listCmd.Flags().StringVar(&listType, "type", "", "チケットタイプで絞り込む")
+	listCmd.Flags().StringVar(&listAssignee, "assignee", "", "担当者で絞り込む")
+	listCmd.Flags().StringVar(&listSprint, "sprint", "", "スプリント名で絞り込む")
+	listCmd.Flags().StringVar(&listCreator, "creator", "", "作成者で絞り込む")
+	listCmd.Flags().StringVar(&listSince, "created-since", "", "指定した日 (2025-06-01 または 14d) 以降に作成されたチケットに絞り込む")
+	listCmd.Flags().StringVar(&listSort, "sort", "key", "並び順 (updated, key, status)")
+	listCmd.Flags().StringVar(&listFormat, "format", "table", "出力形式 (table, json, tsv)")
+	listCmd.Flags().StringVar(&listBy, "by", "", "項目の値ごとにまとめて表示する (status, assignee, sprint, team)")
+}
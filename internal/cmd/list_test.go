@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTickets(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 12, 0, 0, 0, time.Local) }
+	newTickets := func() []*ticket.Ticket {
+		return []*ticket.Ticket{
+			{Key: "PRJ-2", Type: "task", Status: "To Do", Assignee: "山田", Creator: "automation", SprintName: "Sprint 1", Title: "二つ目", CreatedAt: day(2), UpdatedAt: day(3)},
+			{Key: "PRJ-1", Type: "bug", Status: "In Progress", Assignee: "佐藤", Creator: "佐藤", SprintName: "Sprint 2", Title: "一つ目", CreatedAt: day(1), UpdatedAt: day(5)},
+			{Key: "", Type: "task", Title: "下書き", FilePath: "TMP-1.md"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter listFilter
+		sort   string
+		format string
+		want   string
+	}{
+		{
+			name:   "表形式でキー順",
+			sort:   "key",
+			format: "table",
+			want: "KEY    TYPE  STATUS       ASSIGNEE  UPDATED     TITLE\n" +
+				"DRAFT  task  -            -         -           下書き\n" +
+				"PRJ-1  bug   In Progress  佐藤      2025-06-05  一つ目\n" +
+				"PRJ-2  task  To Do        山田      2025-06-03  二つ目\n",
+		},
+		{
+			name:   "ステータスで絞り込み (大文字と小文字を区別しない)",
+			filter: listFilter{status: "to do"},
+			sort:   "key",
+			format: "tsv",
+			want:   "PRJ-2\ttask\tTo Do\t山田\t2025-06-03\t二つ目\n",
+		},
+		{
+			name:   "更新日時の新しい順",
+			filter: listFilter{typ: "task", sprint: ""},
+			sort:   "updated",
+			format: "tsv",
+			want:   "PRJ-2\ttask\tTo Do\t山田\t2025-06-03\t二つ目\nDRAFT\ttask\t-\t-\t-\t下書き\n",
+		},
+		{
+			name:   "スプリントと担当者で絞り込み",
+			filter: listFilter{assignee: "佐藤", sprint: "sprint 2"},
+			sort:   "status",
+			format: "tsv",
+			want:   "PRJ-1\tbug\tIn Progress\t佐藤\t2025-06-05\t一つ目\n",
+		},
+		{
+			name:   "作成者で絞り込み",
+			filter: listFilter{creator: "Automation"},
+			sort:   "key",
+			format: "tsv",
+			want:   "PRJ-2\ttask\tTo Do\t山田\t2025-06-03\t二つ目\n",
+		},
+		{
+			name:   "作成日で絞り込み (作成日時のない下書きは含めない)",
+			filter: listFilter{createdSince: day(2)},
+			sort:   "key",
+			format: "tsv",
+			want:   "PRJ-2\ttask\tTo Do\t山田\t2025-06-03\t二つ目\n",
+		},
+		{
+			name:   "該当なし",
+			filter: listFilter{status: "Done"},
+			sort:   "key",
+			format: "table",
+			want:   "チケットはありません\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tickets := filterTickets(newTickets(), tt.filter)
+			assert.NoError(t, sortTickets(tickets, tt.sort))
+			var buf bytes.Buffer
+			assert.NoError(t, printTickets(&buf, tickets, tt.format))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	assert.Error(t, sortTickets(newTickets(), "title"))
+	assert.Error(t, printTickets(&bytes.Buffer{}, newTickets(), "csv"))
+}
+
+func TestGroupTickets(t *testing.T) {
+	tickets := []*ticket.Ticket{
+		{Key: "PRJ-1", Status: "To Do", Team: "Platform", Title: "一つ目"},
+		{Key: "PRJ-2", Status: "To Do", Title: "二つ目"},
+		{Key: "PRJ-3", Status: "Done", Team: "Mobile", Title: "三つ目"},
+		{Key: "PRJ-4", Status: "Done", Team: "Platform", Title: "四つ目"},
+	}
+	keys := func(groups []ticketGroup) map[string][]string {
+		m := make(map[string][]string)
+		for _, g := range groups {
+			for _, t := range g.tickets {
+				m[g.name] = append(m[g.name], t.Key)
+			}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name      string
+		by        string
+		wantNames []string
+		wantKeys  map[string][]string
+	}{
+		{
+			name:      "チームごと (チームのないチケットは最後)",
+			by:        "team",
+			wantNames: []string{"Mobile", "Platform", "(未設定)"},
+			wantKeys:  map[string][]string{"Mobile": {"PRJ-3"}, "Platform": {"PRJ-1", "PRJ-4"}, "(未設定)": {"PRJ-2"}},
+		},
+		{
+			name:      "ステータスごと",
+			by:        "status",
+			wantNames: []string{"Done", "To Do"},
+			wantKeys:  map[string][]string{"Done": {"PRJ-3", "PRJ-4"}, "To Do": {"PRJ-1", "PRJ-2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := groupTickets(tickets, tt.by)
+			assert.NoError(t, err)
+			var names []string
+			for _, g := range groups {
+				names = append(names, g.name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantKeys, keys(groups))
+		})
+	}
+
+	_, err := groupTickets(tickets, "title")
+	assert.Error(t, err)
+}
+
+func TestParseSinceDate(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "日付", in: "2025-06-01", want: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)},
+		{name: "日数", in: "14d", want: time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)},
+		{name: "今日", in: "0d", want: time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local)},
+		{name: "不正な値", in: "last week", wantErr: true},
+		{name: "負の日数", in: "-3d", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSinceDate(tt.in, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %v", got)
+		})
+	}
+}
+
+func TestLoadTicketsWithExtraSources(t *testing.T) {
+	cacheDir := t.TempDir()
+	_, err := (&ticket.Ticket{Key: "PRJ-1", Type: "task", Title: "自分のサイト"}).SaveToFile(cacheDir)
+	assert.NoError(t, err)
+	sourceDir := filepath.Join(cacheDir, config.ExtraSourcesDir, "other-0123abcd")
+	_, err = (&ticket.Ticket{Key: "ABC-12", Type: "task", Title: "別のサイト", Source: "other", URL: "https://other.atlassian.net/browse/ABC-12"}).SaveToFile(sourceDir)
+	assert.NoError(t, err)
+
+	tickets, err := loadTicketsWithExtraSources(cacheDir, false)
+	assert.NoError(t, err)
+	assert.NoError(t, sortTickets(tickets, "key"))
+	var buf bytes.Buffer
+	assert.NoError(t, printTickets(&buf, tickets, "tsv"))
+	assert.Equal(t, "other:ABC-12\ttask\t-\t-\t-\t別のサイト\nPRJ-1\ttask\t-\t-\t-\t自分のサイト\n", buf.String())
+
+	// ワークスペースと同じく、.sources/ は通常の読み込みでは対象外
+	tickets, err = loadTickets(cacheDir, false)
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 1)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logMessage string
+	logList    bool
+	logJSON    bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log TICKET-KEY [DURATION]",
+	Short: "JIRAチケットに作業ログを記録します",
+	Long: `JIRAチケットに作業ログを記録します。
+DURATIONには 90m, 1.5h, 1h30m のような作業時間を指定します (単位を省略した場合は時間)。
+開始日時は現在時刻で、設定ファイルのtimezoneで送信します。
+
+--list を指定すると、記録済みの作業ログを一覧表示します。`,
+	Example: `  tkt log PRJ-123 1.5h -m "ペアプロ"
+  tkt log PRJ-123 90m
+  tkt log PRJ-123 --list`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := args[0]
+		if !utils.IsValidJIRAKey(key) {
+			return fmt.Errorf("無効なJIRAキーです: %s", key)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		if logList {
+			if len(args) > 1 {
+				return fmt.Errorf("--list と作業時間は同時に指定できません")
+			}
+			return runLogList(cfg, key)
+		}
+
+		if len(args) < 2 {
+			return fmt.Errorf("作業時間を指定してください (例: tkt log %s 1.5h)", key)
+		}
+		spent, err := ticket.ParseHour(args[1])
+		if err != nil {
+			return err
+		}
+
+		worklogID, err := ui.WithSpinnerValue("作業ログを記録中...", func() (string, error) {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return "", fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return jiraClient.AddWorklog(key, spent.Duration(), strings.TrimSpace(logMessage))
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("⏱️  %s に %s の作業ログを記録しました (ID: %s)\n", key, formatWorklogDuration(spent), worklogID)
+		return nil
+	},
+}
+
+// runLogList はチケットの作業ログを表形式 (--jsonの場合はJSON) で表示します
+func runLogList(cfg *config.Config, key string) error {
+	worklogs, err := ui.WithSpinnerValue("作業ログを取得中...", func() ([]jira.Worklog, error) {
+		jiraClient, err := jira.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		return jiraClient.Worklogs(key)
+	})
+	if err != nil {
+		return err
+	}
+
+	if logJSON {
+		dtos := make([]worklogDTO, 0, len(worklogs))
+		for _, w := range worklogs {
+			dtos = append(dtos, newWorklogDTO(w))
+		}
+		b, err := json.MarshalIndent(dtos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if len(worklogs) == 0 {
+		fmt.Printf("%s に作業ログはありません\n", key)
+		return nil
+	}
+
+	var total ticket.Hour
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tAUTHOR\tTIME\tCOMMENT")
+	for _, worklog := range worklogs {
+		spent := ticket.NewHour(worklog.TimeSpent)
+		total += spent
+		comment := strings.ReplaceAll(worklog.Comment, "\n", " ")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", worklog.Started.Format("2006-01-02 15:04"), worklog.Author, formatWorklogDuration(spent), comment)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n合計: %s (%d 件)\n", formatWorklogDuration(total), len(worklogs))
+	return nil
+}
+
+// formatWorklogDuration は作業時間を 1h30m のような表記にします
+func formatWorklogDuration(h ticket.Hour) string {
+	d := h.Duration().Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+type worklogDTO struct {
+	ID               string `json:"id"`
+	Author           string `json:"author"`
+	Started          string `json:"started"`
+	TimeSpentSeconds int64  `json:"time_spent_seconds"`
+	Comment          string `json:"comment"`
+}
+
+func newWorklogDTO(w jira.Worklog) worklogDTO {
+	return worklogDTO{
+		ID:               w.ID,
+		Author:           w.Author,
+		Started:          w.Started.Format(time.RFC3339),
+		TimeSpentSeconds: int64(w.TimeSpent / time.Second),
+		Comment:          w.Comment,
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().StringVarP(&logMessage, "message", "m", "", "作業ログのコメント")
+	logCmd.Flags().BoolVar(&logList, "list", false, "記録済みの作業ログを一覧表示")
+	logCmd.Flags().BoolVar(&logJSON, "json", false, "--list の結果をJSONで出力")
+}
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/pkg/utils"
 	"github.com/qawatake/tkt/internal/ticket"
@@ -14,7 +17,8 @@ import (
 )
 
 var (
-	forceFlag bool
+	forceFlag    bool
+	planOnlyFlag bool
 )
 
 var mergeCmd = &cobra.Command{
@@ -22,7 +26,8 @@ var mergeCmd = &cobra.Command{
 	Short: "リモートにあるチケットでローカルのJIRAチケットを上書きします。",
 	Long: `リモートにあるチケットでローカルのJIRAチケットを上書きします。
 
-	-f, --force フラグを使用すると、確認なしで強制的に上書きされます。`,
+	-f, --force フラグを使用すると、確認なしで強制的に上書きされます。
+	--plan-only フラグを使用すると、取り込み計画をJSONで表示し、ファイルをコピーせずに終了します。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 1. 設定ファイルを読み込む
 		cfg, err := config.LoadConfig()
@@ -38,12 +43,14 @@ var mergeCmd = &cobra.Command{
 			outputDir = cfg.Directory
 		}
 
-		verbose.Printf("JIRAチケットを %s にマージします\n", outputDir)
+		verbose.Printf(verbose.Diff, "JIRAチケットを %s にマージします\n", outputDir)
 
 		// 出力ディレクトリを確保
 		if err := utils.EnsureDir(outputDir); err != nil {
 			return fmt.Errorf("出力ディレクトリの作成に失敗しました: %v", err)
 		}
+		// コピー先の既存のファイルはこの索引で探す
+		outputFiles := ticket.NewFileIndex(outputDir)
 
 		// 2. キャッシュディレクトリを確保
 		cacheDir, err := config.EnsureCacheDir()
@@ -52,14 +59,24 @@ var mergeCmd = &cobra.Command{
 		}
 
 		// 3. -fフラグが設定されていない場合は差分を確認してユーザーに問い合わせ
-		if !forceFlag {
-			verbose.Println("ローカルとキャッシュの差分を検出中...")
+		if !forceFlag || planOnlyFlag {
+			verbose.Println(verbose.Diff, "ローカルとキャッシュの差分を検出中...")
 			// キャッシュ→ローカルの差分を検出（mergeの場合は逆方向）
 			diffs, err := ticket.CompareDirs(cacheDir, outputDir)
 			if err != nil {
 				return fmt.Errorf("差分の検出に失敗しました: %v", err)
 			}
 
+			// 個別の確認の前に、取り込むファイルの概要 (ローカルの編集が失われるものなど) を表示
+			plan, err := ticket.PlanMerge(diffs, outputDir, nil)
+			if err != nil {
+				return fmt.Errorf("取り込み計画の作成に失敗しました: %v", err)
+			}
+			if planOnlyFlag {
+				return printMergePlanJSON(plan)
+			}
+			printMergePlan(plan)
+
 			// 差分があるチケットを抽出
 			var changedTickets []ticket.DiffResult
 			for _, diff := range diffs {
@@ -69,7 +86,7 @@ var mergeCmd = &cobra.Command{
 			}
 
 			if len(changedTickets) > 0 {
-				verbose.Printf("%d 件のファイルに差分があります\n", len(changedTickets))
+				verbose.Printf(verbose.Diff, "%d 件のファイルに差分があります\n", len(changedTickets))
 
 				// ユーザーに確認を取る
 				for _, diff := range changedTickets {
@@ -86,17 +103,17 @@ var mergeCmd = &cobra.Command{
 
 					// 確認されたファイルのみコピー
 					srcPath := diff.FilePath
-					dstPath := filepath.Join(outputDir, filepath.Base(diff.FilePath))
-					if err := copyFile(srcPath, dstPath); err != nil {
+					dstPath, err := copyToWorkspace(cfg, srcPath, outputFiles)
+					if err != nil {
 						return fmt.Errorf("ファイルのコピーに失敗しました: %v", err)
 					}
-					verbose.Printf("コピー: %s -> %s\n", srcPath, dstPath)
+					verbose.Printf(verbose.Diff, "コピー: %s -> %s\n", srcPath, dstPath)
 				}
 
-				verbose.Printf("キャッシュからローカルディレクトリへのマージが完了しました\n")
+				verbose.Printf(verbose.Diff, "キャッシュからローカルディレクトリへのマージが完了しました\n")
 				return nil
 			} else {
-				verbose.Println("差分はありません")
+				verbose.Println(verbose.Diff, "差分はありません")
 				return nil
 			}
 		}
@@ -108,24 +125,58 @@ var mergeCmd = &cobra.Command{
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() {
+			// チケット以外のファイル (last_fetch.txt や metadata.json) はコピーしない
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
 				continue
 			}
 			srcPath := filepath.Join(cacheDir, entry.Name())
-			dstPath := filepath.Join(outputDir, entry.Name())
 
 			// ファイルをコピー
-			if err := copyFile(srcPath, dstPath); err != nil {
+			dstPath, err := copyToWorkspace(cfg, srcPath, outputFiles)
+			if err != nil {
 				return fmt.Errorf("ファイルのコピーに失敗しました: %v", err)
 			}
-			verbose.Printf("コピー: %s -> %s\n", srcPath, dstPath)
+			verbose.Printf(verbose.Diff, "コピー: %s -> %s\n", srcPath, dstPath)
 		}
 
-		verbose.Printf("キャッシュからローカルディレクトリへのマージが完了しました\n")
+		verbose.Printf(verbose.Diff, "キャッシュからローカルディレクトリへのマージが完了しました\n")
 		return nil
 	},
 }
 
+// mergeDangerStyle はローカルの編集が失われるファイルの件数を目立たせるスタイルです
+var mergeDangerStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+
+// printMergePlan は取り込むファイルの件数を分類ごとに表示します
+func printMergePlan(plan ticket.MergePlan) {
+	if plan.Incoming == 0 {
+		return
+	}
+	fmt.Printf("取り込むファイル: %d 件\n", plan.Incoming)
+	fmt.Printf("  %-36s %4d\n", "更新 (ローカルは未編集)", plan.Incoming-plan.LocalEdits-plan.New)
+	line := fmt.Sprintf("  %-36s %4d", "⚠️  上書き (ローカルの編集が失われます)", plan.LocalEdits)
+	if plan.LocalEdits > 0 {
+		line = mergeDangerStyle.Render(line)
+	}
+	fmt.Println(line)
+	fmt.Printf("  %-36s %4d\n", "新規 (ローカルにないチケット)", plan.New)
+	for _, item := range plan.Items {
+		if item.Category == ticket.MergeOverwrite {
+			fmt.Println(mergeDangerStyle.Render(fmt.Sprintf("    %s %s", item.Key, item.Title)))
+		}
+	}
+}
+
+// printMergePlanJSON は取り込み計画をJSONで表示します
+func printMergePlanJSON(plan ticket.MergePlan) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
 // copyFile はファイルをコピーします
 func copyFile(src, dst string) error {
 	srcFile, err := os.Open(src)
@@ -148,4 +199,5 @@ func init() {
 	rootCmd.AddCommand(mergeCmd)
 
 	mergeCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "既存ファイルを上書き")
+	mergeCmd.Flags().BoolVar(&planOnlyFlag, "plan-only", false, "取り込み計画をJSONで表示してコピーせずに終了")
 }
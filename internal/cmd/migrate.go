@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateNormalize bool
+	migrateFilenames bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "既存のキャッシュとワークスペースのファイルを移行します",
+	Long: `既存のキャッシュとワークスペースのファイルを移行します。
+
+--normalize を指定すると、チケットの本文を差分検出と同じ形式に正規化し直します。
+設定ファイルで normalize_on_fetch: true を有効にしたあとに一度実行してください。
+
+--filenames を指定すると、ワークスペースのファイル名を設定ファイルの filename_template (filename_style) に従って変更します。
+削除マーク (ドットプレフィックス) 付きのファイルもドットを付けたまま変更します。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		if !migrateNormalize && !migrateFilenames {
+			return fmt.Errorf("移行内容を指定してください (例: tkt migrate --normalize, tkt migrate --filenames)")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		if migrateFilenames {
+			if cfg.Directory == "" {
+				return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+			}
+			count, err := renameDir(cfg.Directory, filenameTemplate(cfg))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d 件のファイル名を変更しました\n", count)
+			if !migrateNormalize {
+				return nil
+			}
+		}
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		count, err := ui.WithSpinnerValue("本文を正規化中...", func() (int, error) {
+			total, err := normalizeDir(cacheDir, true)
+			if err != nil || cfg.Directory == "" {
+				return total, err
+			}
+			n, err := normalizeDir(cfg.Directory, false)
+			return total + n, err
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d 件のチケットの本文を正規化しました\n", count)
+		return nil
+	},
+}
+
+// normalizeDir はディレクトリ内のチケットの本文を正規化し、変更したファイル数を返します
+// サブディレクトリのファイルも対象にし、ファイル名はそのまま維持します。
+// cache がtrueの場合は、読み取り専用の権限と更新日時を保つようにキャッシュとして保存します。
+func normalizeDir(dir string, cache bool) (int, error) {
+	files, _, err := ticket.WalkFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, file := range files {
+		t, err := ticket.FromFile(file)
+		if err != nil {
+			verbose.Printf(verbose.General, "警告: %s の読み込みに失敗しました: %v\n", file, err)
+			continue
+		}
+		normalized := ticket.NormalizeBody(t.Body)
+		if normalized == t.Body {
+			continue
+		}
+		t.Body = normalized
+		if cache {
+			// 更新日時を変えると改ざんされたキャッシュとして検出されるため、fetchと同じ方法で保存する
+			if _, err := t.SaveToCache(dir); err != nil {
+				return count, fmt.Errorf("%s の書き込みに失敗しました: %v", file, err)
+			}
+		} else if err := os.WriteFile(file, []byte(t.ToMarkdown()), 0644); err != nil {
+			return count, fmt.Errorf("%s の書き込みに失敗しました: %v", file, err)
+		}
+		verbose.Printf(verbose.General, "正規化: %s\n", file)
+		count++
+	}
+	return count, nil
+}
+
+// renameDir はディレクトリ内のチケットのファイル名をテンプレートに従って変更し、変更したファイル数を返します
+// キーのない下書きはそのままにします。変更先に別のファイルがある場合は上書きせずに警告します。
+func renameDir(dir, tmpl string) (int, error) {
+	files, deleted, err := ticket.WalkFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, file := range append(files, deleted...) {
+		t, err := ticket.FromFile(file)
+		if err != nil {
+			verbose.Printf(verbose.General, "警告: %s の読み込みに失敗しました: %v\n", file, err)
+			continue
+		}
+		if t.Key == "" {
+			continue
+		}
+		name, err := t.FileName(tmpl)
+		if err != nil {
+			return count, err
+		}
+		if strings.HasPrefix(filepath.Base(file), ".") {
+			name = "." + name
+		}
+		newPath := filepath.Join(filepath.Dir(file), name)
+		if newPath == file {
+			continue
+		}
+		if _, err := os.Stat(newPath); err == nil {
+			fmt.Printf("警告: %s が既に存在するため %s の名前を変更しません\n", newPath, file)
+			continue
+		}
+		if err := os.Rename(file, newPath); err != nil {
+			return count, fmt.Errorf("%s の名前の変更に失敗しました: %v", file, err)
+		}
+		verbose.Printf(verbose.General, "名前を変更: %s -> %s\n", file, newPath)
+		count++
+	}
+	return count, nil
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateCmd.Flags().BoolVar(&migrateNormalize, "normalize", false, "チケットの本文を差分検出と同じ形式に正規化する")
+	migrateCmd.Flags().BoolVar(&migrateFilenames, "filenames", false, "ワークスペースのファイル名を filename_template (filename_style) に従って変更する")
+}
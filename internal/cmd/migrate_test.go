@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDirCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	updated := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	cached := &ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Status: "To Do", Body: "* 項目\n", UpdatedAt: updated}
+	assert.NotEqual(t, cached.Body, ticket.NormalizeBody(cached.Body))
+	path, err := cached.SaveToCache(cacheDir)
+	assert.NoError(t, err)
+	lastFetch := time.Now()
+
+	count, err := normalizeDir(cacheDir, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// fetchと同じように読み取り専用のまま、更新日時はupdated_atのまま保存する
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(ticket.CacheFileMode), info.Mode().Perm())
+	assert.True(t, info.ModTime().Equal(updated))
+	tampered, err := ticket.TamperedCacheFiles(cacheDir, lastFetch)
+	assert.NoError(t, err)
+	assert.Empty(t, tampered)
+
+	normalized, err := ticket.FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ticket.NormalizeBody(cached.Body), normalized.Body)
+}
+
+func TestNormalizeDirNested(t *testing.T) {
+	dir := t.TempDir()
+	body := "* 項目\n"
+	top, err := (&ticket.Ticket{Key: "PRJ-1", Title: "直下", Type: "task", Body: body}).SaveToFile(dir)
+	assert.NoError(t, err)
+	epicDir := filepath.Join(dir, "epics", "PRJ-100")
+	assert.NoError(t, os.MkdirAll(epicDir, 0755))
+	nested, err := (&ticket.Ticket{Key: "PRJ-2", Title: "サブディレクトリ", Type: "task", Body: body}).SaveToFile(epicDir)
+	assert.NoError(t, err)
+
+	count, err := normalizeDir(dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// サブディレクトリのファイルも同じ場所のまま正規化する
+	for _, path := range []string{top, nested} {
+		got, err := ticket.FromFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, ticket.NormalizeBody(body), got.Body)
+	}
+}
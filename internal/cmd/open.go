@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var openPrint bool
+
+var openCmd = &cobra.Command{
+	Use:   "open [TICKET-KEY]",
+	Short: "チケットをブラウザで開きます",
+	Long: `チケットのJIRAのページをデフォルトのブラウザで開きます。
+TICKET-KEYを省略した場合は、キャッシュのチケットから対話的に選択します。
+--print を指定するとブラウザを開かずにURLを出力します。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		var key string
+		if len(args) > 0 {
+			key = ticket.CanonicalKey(args[0])
+			if !utils.IsValidJIRAKey(key) {
+				return fmt.Errorf("無効なチケットキーです: %s", args[0])
+			}
+		} else {
+			t, err := pickCachedTicket()
+			if err != nil {
+				return err
+			}
+			key = t.Key
+		}
+
+		url := browseURL(cfg.Server, key)
+		if openPrint {
+			fmt.Println(url)
+			return nil
+		}
+		if err := openBrowser(url); err != nil {
+			return err
+		}
+		fmt.Printf("%s を開きました\n", url)
+		return nil
+	},
+}
+
+// browseURL はチケットのJIRAのページのURLを返します
+func browseURL(server, key string) string {
+	return strings.TrimSuffix(server, "/") + "/browse/" + key
+}
+
+// openBrowser はURLをデフォルトのブラウザで開きます (ブラウザの終了は待ちません)
+func openBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("cmd", "/c", "start", "", url)
+	default:
+		c = exec.Command("xdg-open", url)
+	}
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("ブラウザの起動に失敗しました: %v", err)
+	}
+	// ブラウザの終了は待たずにプロセスを回収する
+	go func() { _ = c.Wait() }()
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+
+	openCmd.Flags().BoolVar(&openPrint, "print", false, "ブラウザを開かずにURLを出力する")
+}
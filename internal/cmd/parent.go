@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var (
+	parentPush bool
+	parentNone bool
+)
+
+var parentCmd = &cobra.Command{
+	Use:   "parent TICKET-KEY [EPIC-KEY]",
+	Short: "チケットの親を変更します",
+	Long: `チケットの親 (parentKey) を変更します。
+EPIC-KEY を省略した場合は、キャッシュにあるエピックから選択します。
+--none を指定すると親を解除します。
+--push を指定すると、親の変更のみをすぐにJIRAに反映します。`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := args[0]
+		if parentNone && len(args) > 1 {
+			return fmt.Errorf("--none とEPIC-KEYは同時に指定できません")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		t, err := loadWorkspaceTicket(cfg, key)
+		if err != nil {
+			return err
+		}
+
+		var newParent string
+		switch {
+		case parentNone:
+			newParent = ""
+		case len(args) > 1:
+			newParent = args[1]
+		default:
+			newParent, err = selectEpic(key)
+			if err != nil {
+				return err
+			}
+		}
+		if newParent == key {
+			return fmt.Errorf("チケット自身を親にすることはできません")
+		}
+
+		var jiraClient *jira.Client
+		newClient := func() error {
+			if jiraClient != nil {
+				return nil
+			}
+			jiraClient, err = jira.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return nil
+		}
+
+		oldParent := t.ParentKey
+		if newParent != "" && oldParent != newParent {
+			problem, err := ui.WithSpinnerValue("親のチケットタイプを確認中...", func() (string, error) {
+				if err := newClient(); err != nil {
+					return "", err
+				}
+				return parentTypeProblem(cfg, jiraClient, t.Type, newParent)
+			})
+			if err != nil {
+				return err
+			}
+			if problem != "" {
+				fmt.Fprintf(os.Stderr, "⚠️  %s: %s\n", key, problem)
+				if !utils.PromptForConfirmation("この親に変更しますか？") {
+					fmt.Println("親の変更をキャンセルしました")
+					return nil
+				}
+			}
+		}
+
+		if oldParent == newParent {
+			fmt.Printf("%s: 親は変更されていません\n", key)
+		} else {
+			t.ParentKey = newParent
+			if _, err := t.SaveToFile(cfg.Directory, ticket.WithFilenameTemplate(filenameTemplate(cfg))); err != nil {
+				return fmt.Errorf("チケットの保存に失敗しました: %v", err)
+			}
+			fmt.Printf("%s: 親 %s → %s\n", key, displayParent(oldParent), displayParent(newParent))
+		}
+
+		if !parentPush {
+			return nil
+		}
+
+		err = ui.WithSpinner("親の変更をJIRAに反映中...", func() error {
+			if err := newClient(); err != nil {
+				return err
+			}
+			return jiraClient.UpdateParent(key, newParent)
+		})
+		if err != nil {
+			return err
+		}
+
+		// キャッシュにも反映してdiffが出ないようにする
+		if err := updateCachedTicket(key, func(t *ticket.Ticket) {
+			t.ParentKey = newParent
+		}); err != nil {
+			verbose.Printf(verbose.General, "警告: %v\n", err)
+		}
+
+		fmt.Printf("✅ %s の親をJIRAに反映しました\n", key)
+		return nil
+	},
+}
+
+// selectEpic はキャッシュにあるエピックを更新日時の新しい順に表示し、選択されたキーを返します
+func selectEpic(excludeKey string) (string, error) {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	tickets, err := loadTickets(cacheDir, false)
+	if err != nil {
+		return "", fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+	}
+
+	var epics []*ticket.Ticket
+	for _, t := range tickets {
+		if isEpic(t) && t.Key != excludeKey {
+			epics = append(epics, t)
+		}
+	}
+	if len(epics) == 0 {
+		return "", fmt.Errorf("キャッシュにエピックが見つかりません。'tkt fetch' を実行するか、EPIC-KEYを指定してください")
+	}
+
+	sort.Slice(epics, func(i, j int) bool {
+		return epics[i].UpdatedAt.After(epics[j].UpdatedAt)
+	})
+
+	options := make([]ui.SelectorOption, 0, len(epics))
+	for _, e := range epics {
+		options = append(options, ui.SelectorOption{
+			Title: fmt.Sprintf("%s %s", e.Key, e.Title),
+			Value: e.Key,
+		})
+	}
+
+	selected, err := ui.Select("親にするエピックを選択してください", options)
+	if err != nil {
+		return "", fmt.Errorf("エピックの選択に失敗しました: %v", err)
+	}
+	if selected == nil {
+		return "", fmt.Errorf("エピックが選択されていません")
+	}
+	return selected.(string), nil
+}
+
+// parentTypeProblem は親のチケットタイプをJIRAから取得し、子との組み合わせが階層のルールに合わない場合はその内容を返します
+// 問題がなければ空文字を返します。pushとparentで共通の検証です。
+func parentTypeProblem(cfg *config.Config, jiraClient *jira.Client, childType, parentKey string) (string, error) {
+	parent, err := jiraClient.FetchIssue(parentKey)
+	if err != nil {
+		return "", fmt.Errorf("親チケット %s の取得に失敗しました: %v", parentKey, err)
+	}
+	if err := cfg.CheckParent(childType, parent.Type); err != nil {
+		return fmt.Sprintf("%s (%s)", err, parentKey), nil
+	}
+	return "", nil
+}
+
+// parentChanged はpushで親が設定されるチケットの差分かどうかを返します (新規作成または親の変更)
+func parentChanged(diff ticket.DiffResult) bool {
+	return diff.Change == ticket.ChangeCreate ||
+		(diff.Change == ticket.ChangeUpdate && slices.Contains(diff.ChangedFields, "parentKey"))
+}
+
+// parentFindings は親を設定・変更したチケットのうち、子と親のチケットタイプが階層のルールに合わないものを指摘にして返します
+// 親のチケットタイプはキャッシュから引き、キャッシュにない場合のみJIRAから取得します (jiraClientがnilの場合はスキップします)。
+func parentFindings(cfg *config.Config, jiraClient *jira.Client, cacheDir string, diffs []ticket.DiffResult) ([]string, error) {
+	var findings []string
+	for _, diff := range diffs {
+		if !parentChanged(diff) {
+			continue
+		}
+		t, err := ticket.FromFile(diff.FilePath)
+		if err != nil || t.ParentKey == "" {
+			continue
+		}
+		parentType, err := cachedIssueType(cacheDir, t.ParentKey)
+		if err != nil {
+			return findings, err
+		}
+		if parentType == "" {
+			if jiraClient == nil {
+				continue
+			}
+			problem, err := parentTypeProblem(cfg, jiraClient, t.Type, t.ParentKey)
+			if err != nil {
+				return findings, err
+			}
+			if problem != "" {
+				findings = append(findings, fmt.Sprintf("%s: %s", diff.FilePath, problem))
+			}
+			continue
+		}
+		if err := cfg.CheckParent(t.Type, parentType); err != nil {
+			findings = append(findings, fmt.Sprintf("%s: %s (%s)", diff.FilePath, err, t.ParentKey))
+		}
+	}
+	return findings, nil
+}
+
+// cachedIssueType はキャッシュのチケットのチケットタイプを返します (キャッシュにない場合は空文字)
+func cachedIssueType(cacheDir, key string) (string, error) {
+	path, err := ticket.FindFile(cacheDir, key)
+	if err != nil || path == "" {
+		return "", err
+	}
+	t, err := ticket.FromFile(path)
+	if err != nil {
+		return "", fmt.Errorf("キャッシュのチケット %s の読み込みに失敗しました: %v", key, err)
+	}
+	return t.Type, nil
+}
+
+// isEpic はチケットがエピックかどうかを返します
+func isEpic(t *ticket.Ticket) bool {
+	typ := strings.ToLower(t.Type)
+	return typ == "epic" || typ == "エピック"
+}
+
+func displayParent(key string) string {
+	if key == "" {
+		return "(なし)"
+	}
+	return newParentTitleIndex(nil).label(key)
+}
+
+// parentTitleIndex は親チケットをタイトル付き (PRJ-100 (決済基盤刷新)) で表示するための索引です
+// 表示中のチケット、キャッシュのチケット、フェッチ時に取得したJQLの対象外の親チケットのタイトル (parents.json) から引きます。
+// JIRAには問い合わせないので、オフラインでも使えます。タイトルが分からない場合はキーだけを表示します。
+type parentTitleIndex struct {
+	cacheDir string
+	titles   map[string]string // キー -> タイトル (キャッシュにもなかったキーは空文字)
+}
+
+// newParentTitleIndex は tickets のタイトルと保存した親チケットのタイトルから索引を作ります
+// キャッシュのチケットは表示するときに必要な分だけ読み込みます。
+func newParentTitleIndex(tickets []*ticket.Ticket) *parentTitleIndex {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		cacheDir = ""
+	}
+	return loadParentTitleIndex(cacheDir, tickets)
+}
+
+// loadParentTitleIndex は cacheDir のキャッシュを使う索引を作ります。cacheDir が空の場合は tickets のタイトルだけを使います。
+func loadParentTitleIndex(cacheDir string, tickets []*ticket.Ticket) *parentTitleIndex {
+	idx := &parentTitleIndex{cacheDir: cacheDir, titles: make(map[string]string)}
+	if cacheDir != "" {
+		titles, err := config.ReadParentTitles(cacheDir)
+		if err != nil {
+			verbose.Printf(verbose.General, "警告: %v\n", err)
+		}
+		for key, title := range titles {
+			idx.titles[ticket.CanonicalKey(key)] = title
+		}
+	}
+	for _, t := range tickets {
+		// 別のサイトのチケットは同じキーでも別物なので使わない
+		if t.Key != "" && t.Source == "" {
+			idx.titles[ticket.CanonicalKey(t.Key)] = t.Title
+		}
+	}
+	return idx
+}
+
+// title は親チケットのタイトルを返します。分からない場合は空文字を返します。
+func (idx *parentTitleIndex) title(key string) string {
+	if idx == nil {
+		return ""
+	}
+	key = ticket.CanonicalKey(key)
+	if title, ok := idx.titles[key]; ok {
+		return title
+	}
+	var title string
+	if idx.cacheDir != "" {
+		// キャッシュのファイル名は常に KEY.md
+		if t, err := ticket.FromFile(filepath.Join(idx.cacheDir, key+".md")); err == nil {
+			title = t.Title
+		}
+	}
+	idx.titles[key] = title
+	return title
+}
+
+// parentLabel はチケットの親を「キー (タイトル)」の形式で返します。親がない場合は空文字を返します。
+// 別のサイトのチケットの親は索引にないので、キーだけを返します。
+func parentLabel(t *ticket.Ticket, parents *parentTitleIndex) string {
+	if t.ParentKey == "" || t.Source != "" {
+		return t.ParentKey
+	}
+	return parents.label(t.ParentKey)
+}
+
+// label は親チケットを「キー (タイトル)」の形式で返します。タイトルが分からない場合はキーだけを返します。
+func (idx *parentTitleIndex) label(key string) string {
+	if title := idx.title(key); title != "" {
+		return fmt.Sprintf("%s (%s)", key, title)
+	}
+	return key
+}
+
+func init() {
+	rootCmd.AddCommand(parentCmd)
+
+	parentCmd.Flags().BoolVar(&parentPush, "push", false, "親の変更をすぐにJIRAに反映する")
+	parentCmd.Flags().BoolVar(&parentNone, "none", false, "親を解除する")
+}
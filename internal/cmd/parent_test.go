@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentTitleIndex(t *testing.T) {
+	cacheDir := t.TempDir()
+	_, err := (&ticket.Ticket{Key: "PRJ-100", Type: "epic", Title: "決済基盤刷新"}).SaveToCache(cacheDir)
+	assert.NoError(t, err)
+	assert.NoError(t, config.SaveParentTitles(cacheDir, map[string]string{"OPS-7": "運用改善"}))
+
+	idx := loadParentTitleIndex(cacheDir, []*ticket.Ticket{
+		{Key: "PRJ-200", Title: "ワークスペースのエピック"},
+		{Key: "ABC-1", Title: "別のサイト", Source: "other"},
+	})
+
+	tests := []struct {
+		name   string
+		ticket *ticket.Ticket
+		want   string
+	}{
+		{name: "表示中のチケットから引く", ticket: &ticket.Ticket{ParentKey: "PRJ-200"}, want: "PRJ-200 (ワークスペースのエピック)"},
+		{name: "キャッシュのチケットから引く", ticket: &ticket.Ticket{ParentKey: "PRJ-100"}, want: "PRJ-100 (決済基盤刷新)"},
+		{name: "フェッチ時に取得した親チケットのタイトル", ticket: &ticket.Ticket{ParentKey: "OPS-7"}, want: "OPS-7 (運用改善)"},
+		{name: "タイトルが分からない場合はキーだけ", ticket: &ticket.Ticket{ParentKey: "PRJ-999"}, want: "PRJ-999"},
+		{name: "別のサイトのチケットの親はキーだけ", ticket: &ticket.Ticket{ParentKey: "PRJ-100", Source: "other"}, want: "PRJ-100"},
+		{name: "親がない", ticket: &ticket.Ticket{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parentLabel(tt.ticket, idx))
+		})
+	}
+
+	// 索引がない場合はキーだけ
+	assert.Equal(t, "PRJ-100", parentLabel(&ticket.Ticket{ParentKey: "PRJ-100"}, nil))
+	// 別のサイトのチケットのタイトルは使わない
+	assert.Equal(t, "", idx.title("ABC-1"))
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [TICKET-KEY...]",
+	Short: "チケットをピン留めします",
+	Long: `チケットをピン留めします。
+ピン留めしたチケットは grep などのチケットの選択画面で先頭に ★ 付きで表示されます。
+TICKET-KEYを省略した場合は、ピン留めしたチケットの一覧を表示します。
+ピン留めはワークスペースごとにキャッシュディレクトリに保存されます。`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		pins, err := prunePins(cacheDir, os.Stderr)
+		if err != nil {
+			return err
+		}
+
+		cacheFiles := ticket.NewFileIndex(cacheDir)
+		if len(args) == 0 {
+			for _, key := range pins {
+				path, err := cacheFiles.Find(key)
+				if err != nil {
+					return err
+				}
+				t, err := ticket.FromFile(path)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s %s\n", key, t.Title)
+			}
+			return nil
+		}
+
+		for _, arg := range args {
+			key := ticket.CanonicalKey(arg)
+			if !utils.IsValidJIRAKey(key) {
+				return fmt.Errorf("無効なチケットキーです: %s", arg)
+			}
+			path, err := cacheFiles.Find(key)
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				return fmt.Errorf("チケット %s がキャッシュにありません。'tkt fetch' を実行してください", key)
+			}
+			if slices.Contains(pins, key) {
+				fmt.Printf("%s は既にピン留めされています\n", key)
+				continue
+			}
+			pins = append(pins, key)
+			fmt.Printf("%s をピン留めしました\n", key)
+		}
+		return config.SavePins(cacheDir, pins)
+	},
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin TICKET-KEY...",
+	Short: "チケットのピン留めを解除します",
+	Long:  `チケットのピン留めを解除します。`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		pins, err := config.ReadPins(cacheDir)
+		if err != nil {
+			return err
+		}
+
+		for _, arg := range args {
+			key := ticket.CanonicalKey(arg)
+			i := slices.Index(pins, key)
+			if i < 0 {
+				fmt.Printf("%s はピン留めされていません\n", key)
+				continue
+			}
+			pins = slices.Delete(pins, i, i+1)
+			fmt.Printf("%s のピン留めを解除しました\n", key)
+		}
+		return config.SavePins(cacheDir, pins)
+	},
+}
+
+// prunePins はピン留めのうちキャッシュにないチケットのピン留めを解除し、残ったキーを返します
+// 解除したチケットがあれば w に通知します。
+func prunePins(cacheDir string, w io.Writer) ([]string, error) {
+	pins, err := config.ReadPins(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	var kept, pruned []string
+	cacheFiles := ticket.NewFileIndex(cacheDir)
+	for _, key := range pins {
+		path, err := cacheFiles.Find(key)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			pruned = append(pruned, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	if len(pruned) == 0 {
+		return pins, nil
+	}
+	if err := config.SavePins(cacheDir, kept); err != nil {
+		return nil, err
+	}
+	for _, key := range pruned {
+		fmt.Fprintf(w, "チケット %s がキャッシュにないため、ピン留めを解除しました\n", key)
+	}
+	return kept, nil
+}
+
+// loadPinnedKeys はピン留めしたチケットのキーを読み込みます (読み込めない場合はピン留めなしとして扱います)
+func loadPinnedKeys() map[string]bool {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil
+	}
+	pins, err := config.ReadPins(cacheDir)
+	if err != nil {
+		return nil
+	}
+	pinned := make(map[string]bool, len(pins))
+	for _, key := range pins {
+		pinned[key] = true
+	}
+	return pinned
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+}
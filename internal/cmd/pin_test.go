@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrunePins(t *testing.T) {
+	cacheDir := t.TempDir()
+	tk := &ticket.Ticket{Key: "PRJ-1", Title: "残る", Type: "task"}
+	_, err := tk.SaveToCache(cacheDir)
+	assert.NoError(t, err)
+	assert.NoError(t, config.SavePins(cacheDir, []string{"PRJ-9", "PRJ-1"}))
+
+	var out bytes.Buffer
+	pins, err := prunePins(cacheDir, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PRJ-1"}, pins)
+	assert.Contains(t, out.String(), "PRJ-9")
+
+	saved, err := config.ReadPins(cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PRJ-1"}, saved)
+
+	// 解除するものがなければ通知しない
+	out.Reset()
+	_, err = prunePins(cacheDir, &out)
+	assert.NoError(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestNewTicketItemsPinnedFirst(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tickets := []*ticket.Ticket{
+		{Key: "PRJ-1", Title: "古いピン留め", UpdatedAt: base},
+		{Key: "PRJ-2", Title: "新しい", UpdatedAt: base.Add(2 * time.Hour)},
+		{Title: "下書き"},
+		{Key: "PRJ-3", Title: "普通", UpdatedAt: base.Add(time.Hour)},
+	}
+
+	items := newTicketItems(tickets, map[string]bool{"PRJ-1": true})
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, item.key)
+	}
+	assert.Equal(t, []string{"PRJ-1", "DRAFT", "PRJ-2", "PRJ-3"}, keys)
+}
@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/md"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var publishWorkspace bool
+
+var publishCmd = &cobra.Command{
+	Use:   "publish <dir>",
+	Short: "チケットを静的なHTMLサイトとして書き出します",
+	Long: `チケットを静的なHTMLサイトとして書き出します。
+JIRAにアクセスできない人にチケットを共有するための読み取り専用のスナップショットです。
+各チケットのページ (<KEY>.html) と、ステータス別・親チケット (エピック) 別の一覧ページ (index.html) を作成します。
+ダウンロード済みの添付ファイル (<directory>/assets/<KEY>/) もコピーします。
+JavaScriptは使わず、JIRAへのアクセスも行いません。
+
+デフォルトではキャッシュのチケットを書き出します。--workspace を指定するとワークスペースのチケットを書き出します。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		var srcDir string
+		if publishWorkspace {
+			if cfg.Directory == "" {
+				return fmt.Errorf("ワークスペースディレクトリが設定されていません")
+			}
+			srcDir = cfg.Directory
+		} else {
+			srcDir, err = config.EnsureCacheDir()
+			if err != nil {
+				return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+			}
+		}
+
+		outDir := args[0]
+		count, err := ui.WithSpinnerValue("HTMLを生成中...", func() (int, error) {
+			tickets, err := ticket.LoadDir(srcDir)
+			if err != nil {
+				return 0, err
+			}
+			return publishSite(outDir, tickets, cfg.Directory)
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d 件のチケットを %s に書き出しました\n", count, filepath.Join(outDir, "index.html"))
+		return nil
+	},
+}
+
+// publishTicket はHTMLのテンプレートに渡すチケットの情報です
+type publishTicket struct {
+	*ticket.Ticket
+	Page        string
+	Updated     string
+	ParentTitle string
+	ParentPage  string
+	BodyHTML    template.HTML
+	Comments    []publishComment
+}
+
+type publishComment struct {
+	Author   string
+	Created  string
+	BodyHTML template.HTML
+}
+
+// publishGroup は一覧ページの見出しとその下のチケットです
+type publishGroup struct {
+	Title string
+	Page  string
+	Items []*publishTicket
+}
+
+// publishSite はチケットをHTMLとしてoutDirに書き出し、書き出した件数を返します
+// assetsDir が空でなければ、assetsDir/assets/<KEY>/ の添付ファイルを outDir/assets/<KEY>/ にコピーします。
+func publishSite(outDir string, tickets map[string]*ticket.Ticket, assetsDir string) (int, error) {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return 0, fmt.Errorf("出力ディレクトリの作成に失敗しました: %v", err)
+	}
+
+	items := make(map[string]*publishTicket, len(tickets))
+	for key, t := range tickets {
+		item := &publishTicket{
+			Ticket:   t,
+			Page:     key + ".html",
+			BodyHTML: template.HTML(md.ToHTML(t.Body)),
+		}
+		if !t.UpdatedAt.IsZero() {
+			item.Updated = t.UpdatedAt.Local().Format(time.DateTime)
+		}
+		for _, c := range t.Comments {
+			item.Comments = append(item.Comments, publishComment{
+				Author:   c.Author,
+				Created:  c.CreatedAt.Local().Format(time.DateTime),
+				BodyHTML: template.HTML(md.ToHTML(c.Body)),
+			})
+		}
+		items[key] = item
+	}
+	for _, item := range items {
+		if parent, ok := items[item.ParentKey]; ok {
+			item.ParentTitle = parent.Title
+			item.ParentPage = parent.Page
+		}
+	}
+
+	for key, item := range items {
+		if err := writePublishPage(filepath.Join(outDir, item.Page), publishTicketTemplate, item); err != nil {
+			return 0, err
+		}
+		if assetsDir != "" {
+			if err := copyAssets(filepath.Join(assetsDir, ticket.AssetsDir, key), filepath.Join(outDir, ticket.AssetsDir, key)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	index := struct {
+		ByStatus []publishGroup
+		ByParent []publishGroup
+	}{
+		ByStatus: groupByStatus(items),
+		ByParent: groupByParent(items),
+	}
+	if err := writePublishPage(filepath.Join(outDir, "index.html"), publishIndexTemplate, index); err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// groupByStatus はチケットをステータスごとにまとめます
+func groupByStatus(items map[string]*publishTicket) []publishGroup {
+	groups := make(map[string][]*publishTicket)
+	for _, item := range items {
+		status := item.Status
+		if status == "" {
+			status = "(ステータスなし)"
+		}
+		groups[status] = append(groups[status], item)
+	}
+	var result []publishGroup
+	for status, group := range groups {
+		sortPublishTickets(group)
+		result = append(result, publishGroup{Title: status, Items: group})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Title < result[j].Title })
+	return result
+}
+
+// groupByParent はチケットを親チケット (エピック) ごとにまとめます
+// 親チケットのないチケットは最後にまとめます。
+func groupByParent(items map[string]*publishTicket) []publishGroup {
+	groups := make(map[string][]*publishTicket)
+	for _, item := range items {
+		groups[item.ParentKey] = append(groups[item.ParentKey], item)
+	}
+	var result []publishGroup
+	for parentKey, group := range groups {
+		if parentKey == "" {
+			continue
+		}
+		sortPublishTickets(group)
+		g := publishGroup{Title: parentKey, Items: group}
+		if parent, ok := items[parentKey]; ok {
+			g.Title = parentKey + ": " + parent.Title
+			g.Page = parent.Page
+		}
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Title < result[j].Title })
+	if group, ok := groups[""]; ok {
+		sortPublishTickets(group)
+		result = append(result, publishGroup{Title: "(親チケットなし)", Items: group})
+	}
+	return result
+}
+
+// sortPublishTickets は更新日時の新しい順に並べます
+func sortPublishTickets(items []*publishTicket) {
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].UpdatedAt.Equal(items[j].UpdatedAt) {
+			return items[i].UpdatedAt.After(items[j].UpdatedAt)
+		}
+		return items[i].Key < items[j].Key
+	})
+}
+
+func writePublishPage(path string, tmpl *template.Template, data any) error {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return fmt.Errorf("%s の生成に失敗しました: %v", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("%s の書き込みに失敗しました: %v", path, err)
+	}
+	return nil
+}
+
+// copyAssets はダウンロード済みの添付ファイルをコピーします (ディレクトリがなければ何もしません)
+func copyAssets(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("添付ファイルの読み込みに失敗しました: %v", err)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fmt.Errorf("添付ファイルのディレクトリの作成に失敗しました: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return fmt.Errorf("添付ファイル %s のコピーに失敗しました: %v", entry.Name(), err)
+		}
+		verbose.Printf(verbose.General, "添付ファイルをコピー: %s\n", filepath.Join(dst, entry.Name()))
+	}
+	return nil
+}
+
+const publishStyle = `<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "Hiragino Sans", sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #172b4d; line-height: 1.6; }
+a { color: #0052cc; text-decoration: none; }
+a:hover { text-decoration: underline; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; }
+th, td { border-bottom: 1px solid #dfe1e6; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { color: #5e6c84; font-weight: normal; white-space: nowrap; }
+.key { font-family: monospace; white-space: nowrap; }
+.status { display: inline-block; padding: 0 0.5em; border-radius: 3px; background: #dfe1e6; font-size: 0.85em; }
+.comment { border-left: 3px solid #dfe1e6; padding-left: 1em; margin-bottom: 1em; }
+img { max-width: 100%; }
+pre { background: #f4f5f7; padding: 0.8em; overflow-x: auto; }
+</style>`
+
+var publishIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>チケット一覧</title>
+` + publishStyle + `
+</head>
+<body>
+<h1>チケット一覧</h1>
+<h2>ステータス別</h2>
+{{range .ByStatus}}{{template "group" .}}{{end}}
+<h2>親チケット別</h2>
+{{range .ByParent}}{{template "group" .}}{{end}}
+</body>
+</html>
+{{define "group"}}<h3>{{if .Page}}<a href="{{.Page}}">{{.Title}}</a>{{else}}{{.Title}}{{end}} ({{len .Items}})</h3>
+<table>
+<tr><th>キー</th><th>タイトル</th><th>ステータス</th><th>更新日時</th></tr>
+{{range .Items}}<tr><td class="key"><a href="{{.Page}}">{{.Key}}</a></td><td>{{.Title}}</td><td><span class="status">{{.Status}}</span></td><td>{{.Updated}}</td></tr>
+{{end}}</table>
+{{end}}`))
+
+var publishTicketTemplate = template.Must(template.New("ticket").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Key}}: {{.Title}}</title>
+` + publishStyle + `
+</head>
+<body>
+<p><a href="index.html">← チケット一覧</a></p>
+<h1><span class="key">{{.Key}}</span> {{.Title}}</h1>
+<table>
+<tr><th>ステータス</th><td><span class="status">{{.Status}}</span></td></tr>
+{{if .Type}}<tr><th>タイプ</th><td>{{.Type}}</td></tr>{{end}}
+{{if .ParentKey}}<tr><th>親チケット</th><td>{{if .ParentPage}}<a href="{{.ParentPage}}">{{.ParentKey}}: {{.ParentTitle}}</a>{{else}}{{.ParentKey}}{{end}}</td></tr>{{end}}
+{{if .Assignee}}<tr><th>担当者</th><td>{{.Assignee}}</td></tr>{{end}}
+{{if .SprintName}}<tr><th>スプリント</th><td>{{.SprintName}}</td></tr>{{end}}
+{{if .Labels}}<tr><th>ラベル</th><td>{{range $i, $l := .Labels}}{{if $i}}, {{end}}{{$l}}{{end}}</td></tr>{{end}}
+{{if .Updated}}<tr><th>更新日時</th><td>{{.Updated}}</td></tr>{{end}}
+</table>
+{{.BodyHTML}}
+{{if .Comments}}<h2>コメント</h2>
+{{range .Comments}}<div class="comment">
+<p><strong>{{.Author}}</strong> ({{.Created}})</p>
+{{.BodyHTML}}
+</div>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+
+	publishCmd.Flags().BoolVarP(&publishWorkspace, "workspace", "w", false, "ワークスペースのチケットを書き出す")
+}
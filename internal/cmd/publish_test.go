@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishSite(t *testing.T) {
+	updated := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	tickets := map[string]*ticket.Ticket{
+		"PRJ-1": {Key: "PRJ-1", Title: "ログイン刷新", Type: "epic", Status: "In Progress", UpdatedAt: updated},
+		"PRJ-2": {Key: "PRJ-2", Title: "<タイムアウト>", Type: "task", Status: "Done", ParentKey: "PRJ-1", UpdatedAt: updated,
+			Body: "![画面](assets/PRJ-2/screen.png)\n\n<script>alert(1)</script>\n"},
+	}
+	workspace := t.TempDir()
+	assetDir := filepath.Join(workspace, "assets", "PRJ-2")
+	assert.NoError(t, os.MkdirAll(assetDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(assetDir, "screen.png"), []byte("png"), 0644))
+
+	outDir := filepath.Join(t.TempDir(), "site")
+	count, err := publishSite(outDir, tickets, workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	index, err := os.ReadFile(filepath.Join(outDir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `<a href="PRJ-2.html">PRJ-2</a>`)
+	assert.Contains(t, string(index), "&lt;タイムアウト&gt;")
+	assert.Contains(t, string(index), `<a href="PRJ-1.html">PRJ-1: ログイン刷新</a>`)
+	assert.Contains(t, string(index), "In Progress")
+	assert.NotContains(t, string(index), "<script")
+
+	page, err := os.ReadFile(filepath.Join(outDir, "PRJ-2.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<img src="assets/PRJ-2/screen.png" alt="画面" />`)
+	assert.Contains(t, string(page), `<a href="PRJ-1.html">PRJ-1: ログイン刷新</a>`)
+	assert.NotContains(t, string(page), "<script")
+	assert.FileExists(t, filepath.Join(outDir, "assets", "PRJ-2", "screen.png"))
+}
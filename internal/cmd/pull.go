@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/jira"
@@ -18,7 +19,8 @@ var pullCmd = &cobra.Command{
 	Short: "リモートにあるチケットの最新情報を取得し、それをもとにローカルのチケットを上書きします。",
 	Long: `リモートにあるチケットの最新情報を取得し、ローカルのチケットを上書きします。fetchとmergeコマンドを組み合わせたコマンドです。
 
-	-f, --force フラグを使用すると、確認なしで強制的に上書きされます。`,
+	-f, --force フラグを使用すると、確認なしで強制的に上書きされます。
+	--plan-only フラグを使用すると、取り込み計画をJSONで表示し、ファイルをコピーせずに終了します。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 1. 設定ファイルを読み込む
 		cfg, err := config.LoadConfig()
@@ -35,11 +37,11 @@ var pullCmd = &cobra.Command{
 		}
 
 		// 設定情報をデバッグ表示
-		verbose.Printf("JIRA Server: %s\n", cfg.Server)
-		verbose.Printf("Project Key: %s\n", cfg.Project.Key)
-		verbose.Printf("Auth Type: %s\n", cfg.AuthType)
+		verbose.Printf(verbose.General, "JIRA Server: %s\n", cfg.Server)
+		verbose.Printf(verbose.General, "Project Key: %s\n", cfg.Project.Key)
+		verbose.Printf(verbose.General, "Auth Type: %s\n", cfg.AuthType)
 		if cfg.JQL != "" {
-			verbose.Printf("Custom JQL: %s\n", cfg.JQL)
+			verbose.Printf(verbose.General, "Custom JQL: %s\n", cfg.JQL)
 		}
 
 		// 2. JIRAに接続
@@ -49,13 +51,16 @@ var pullCmd = &cobra.Command{
 		}
 
 		// 3. チケットを取得（fetch部分）
-		verbose.Println("JIRAからチケットを取得中...")
-		tickets, err := jiraClient.FetchIssues()
+		verbose.Println(verbose.General, "JIRAからチケットを取得中...")
+		tickets, err := jiraClient.FetchIssues(cmd.Context())
+		if cmd.Context().Err() != nil {
+			return errFetchCancelled
+		}
 		if err != nil {
 			return fmt.Errorf("チケットの取得に失敗しました: %v", err)
 		}
 
-		verbose.Printf("%d 件のチケットを取得しました\n", len(tickets))
+		verbose.Printf(verbose.General, "%d 件のチケットを取得しました\n", len(tickets))
 
 		// 4. キャッシュディレクトリを確保
 		cacheDir, err := config.EnsureCacheDir()
@@ -63,38 +68,56 @@ var pullCmd = &cobra.Command{
 			return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
 		}
 
+		// フェッチ前のキャッシュを、ローカルが編集されたかどうかの判断基準として保持する
+		base, err := ticket.LoadDir(cacheDir)
+		if err != nil {
+			return fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+		}
+
 		// 5. チケットをキャッシュに保存（fetch部分）
 		savedCount := 0
 		for _, ticket := range tickets {
 			// キャッシュディレクトリに保存
-			savedCachePath, err := ticket.SaveToFile(cacheDir)
+			savedCachePath, err := ticket.SaveToCache(cacheDir)
 			if err != nil {
-				verbose.Printf("警告: チケット %s のキャッシュ保存に失敗しました: %v\n", ticket.Key, err)
+				verbose.Printf(verbose.Cache, "警告: チケット %s のキャッシュ保存に失敗しました: %v\n", ticket.Key, err)
 			}
 
-			verbose.Printf("保存: %s -> %s\n", ticket.Key, savedCachePath)
+			verbose.Printf(verbose.Cache, "保存: %s -> %s\n", ticket.Key, savedCachePath)
 			savedCount++
 		}
 
-		verbose.Printf("\n%d 件のチケットを保存しました\n", savedCount)
+		verbose.Printf(verbose.General, "\n%d 件のチケットを保存しました\n", savedCount)
 
 		// 6. ローカルディレクトリにマージ（merge部分）
-		verbose.Printf("JIRAチケットを %s にマージします\n", outputDir)
+		verbose.Printf(verbose.General, "JIRAチケットを %s にマージします\n", outputDir)
 
 		// 出力ディレクトリを確保
 		if err := utils.EnsureDir(outputDir); err != nil {
 			return fmt.Errorf("出力ディレクトリの作成に失敗しました: %v", err)
 		}
+		// コピー先の既存のファイルはこの索引で探す
+		outputFiles := ticket.NewFileIndex(outputDir)
 
 		// 7. -fフラグが設定されていない場合は差分を確認してユーザーに問い合わせ
-		if !forceFlag {
-			verbose.Println("ローカルとキャッシュの差分を検出中...")
+		if !forceFlag || planOnlyFlag {
+			verbose.Println(verbose.Diff, "ローカルとキャッシュの差分を検出中...")
 			// キャッシュ→ローカルの差分を検出（mergeの場合は逆方向）
 			diffs, err := ticket.CompareDirs(cacheDir, outputDir)
 			if err != nil {
 				return fmt.Errorf("差分の検出に失敗しました: %v", err)
 			}
 
+			// 個別の確認の前に、取り込むファイルの概要 (ローカルの編集が失われるものなど) を表示
+			plan, err := ticket.PlanMerge(diffs, outputDir, base)
+			if err != nil {
+				return fmt.Errorf("取り込み計画の作成に失敗しました: %v", err)
+			}
+			if planOnlyFlag {
+				return printMergePlanJSON(plan)
+			}
+			printMergePlan(plan)
+
 			// 差分があるチケットを抽出
 			var changedTickets []ticket.DiffResult
 			for _, diff := range diffs {
@@ -104,7 +127,7 @@ var pullCmd = &cobra.Command{
 			}
 
 			if len(changedTickets) > 0 {
-				verbose.Printf("%d 件のファイルに差分があります\n", len(changedTickets))
+				verbose.Printf(verbose.Diff, "%d 件のファイルに差分があります\n", len(changedTickets))
 
 				// ユーザーに確認を取る
 				for _, diff := range changedTickets {
@@ -121,17 +144,17 @@ var pullCmd = &cobra.Command{
 
 					// 確認されたファイルのみコピー
 					srcPath := diff.FilePath
-					dstPath := filepath.Join(outputDir, filepath.Base(diff.FilePath))
-					if err := copyFile(srcPath, dstPath); err != nil {
+					dstPath, err := copyToWorkspace(cfg, srcPath, outputFiles)
+					if err != nil {
 						return fmt.Errorf("ファイルのコピーに失敗しました: %v", err)
 					}
-					verbose.Printf("コピー: %s -> %s\n", srcPath, dstPath)
+					verbose.Printf(verbose.Diff, "コピー: %s -> %s\n", srcPath, dstPath)
 				}
 
-				verbose.Printf("キャッシュからローカルディレクトリへのマージが完了しました\n")
+				verbose.Printf(verbose.General, "キャッシュからローカルディレクトリへのマージが完了しました\n")
 				return nil
 			} else {
-				verbose.Println("差分はありません")
+				verbose.Println(verbose.Diff, "差分はありません")
 				return nil
 			}
 		}
@@ -143,20 +166,21 @@ var pullCmd = &cobra.Command{
 		}
 
 		for _, entry := range entries {
-			if entry.IsDir() {
+			// チケット以外のファイル (last_fetch.txt や metadata.json) はコピーしない
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
 				continue
 			}
 			srcPath := filepath.Join(cacheDir, entry.Name())
-			dstPath := filepath.Join(outputDir, entry.Name())
 
 			// ファイルをコピー
-			if err := copyFile(srcPath, dstPath); err != nil {
+			dstPath, err := copyToWorkspace(cfg, srcPath, outputFiles)
+			if err != nil {
 				return fmt.Errorf("ファイルのコピーに失敗しました: %v", err)
 			}
-			verbose.Printf("コピー: %s -> %s\n", srcPath, dstPath)
+			verbose.Printf(verbose.Diff, "コピー: %s -> %s\n", srcPath, dstPath)
 		}
 
-		verbose.Printf("キャッシュからローカルディレクトリへのマージが完了しました\n")
+		verbose.Printf(verbose.General, "キャッシュからローカルディレクトリへのマージが完了しました\n")
 		return nil
 	},
 }
@@ -166,4 +190,5 @@ func init() {
 
 	pullCmd.Flags().StringVarP(&outputDir, "output", "o", "", "出力ディレクトリ")
 	pullCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "既存ファイルを上書き")
+	pullCmd.Flags().BoolVar(&planOnlyFlag, "plan-only", false, "取り込み計画をJSONで表示してコピーせずに終了")
 }
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/qawatake/tkt/internal/adf"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/jira"
 	"github.com/qawatake/tkt/internal/pkg/utils"
@@ -18,18 +22,42 @@ import (
 )
 
 var (
-	pushDir string
-	dryRun  bool
-	force   bool
+	pushDir     string
+	dryRun      bool
+	force       bool
+	pushContext int
+	pushFull    bool
+
+	forceRemoteOverwrite bool
+	pushNoMultiHop       bool
 )
 
 var pushCmd = &cobra.Command{
-	Use:   "push",
+	Use:   "push [TICKET-KEY|FILE...]",
 	Short: "ローカルでの編集差分をリモートのJIRAチケットに適用します。",
 	Long: `ローカルでの編集差分をリモートのJIRAチケットに適用します。
 keyがチケットはリモートにないチケットのため、JIRAにチケットを作成したあとにファイルのkeyを更新します。
 
--f, --force フラグを使用すると、確認なしで強制的にpushされます。`,
+チケットのキーまたはファイル (下書きの TMP-... のファイル名やパス) を指定すると、それらのチケットのみをpushします。
+ワークスペースに見つからないものがある場合は、JIRAにアクセスする前にエラーになります。
+
+複数のチケットに差分がある場合は、まず変更の概要を表示し、すべてpushするか・対象を選択するか・中止するかを選べます。
+その後、選択したチケットごとに差分を表示して確認します。
+
+-f, --force フラグを使用すると、確認なしで強制的にpushされます。
+--context で確認時の差分の前後に表示する行数を、--full で変更されたチケットのファイル全体を表示します。
+
+完了済み (closed) のスプリントを指定したチケットがある場合は警告し、確認を求めます。
+
+最後のフェッチから push.max_cache_age (デフォルト: 24h) 以上経過している場合は警告し、確認を求めます。
+push.auto_fetch: true の場合は確認の代わりに増分フェッチを行ってからpushします。
+
+最後のフェッチの後にJIRAで更新されたチケットは、ローカルで変更した項目と同じ項目が変更されていれば競合としてエラーになります。
+重ならない場合はリモートの変更をローカルのファイルに取り込んでからpushします。
+--force-remote-overwrite を指定すると、競合してもリモートの変更を上書きします。`,
+	Example: `  tkt push
+  tkt push PRJ-12 PRJ-34
+  tkt push TMP-20250601-150405-k3x9qa`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// 1. 設定ファイルを読み込む
 		cfg, err := config.LoadConfig()
@@ -45,12 +73,41 @@ keyがチケットはリモートにないチケットのため、JIRAにチケ
 			pushDir = cfg.Directory
 		}
 
-		verbose.Printf("ローカルの編集差分を %s からJIRAに適用します\n", pushDir)
+		verbose.Printf(verbose.General, "ローカルの編集差分を %s からJIRAに適用します\n", pushDir)
+
+		contextLines, err := diffContextLines(cmd, cfg, pushContext, pushFull)
+		if err != nil {
+			return err
+		}
+		diffOpts := append(compareOptions(cfg), ticket.WithContextLines(contextLines))
+
+		// 対象のチケットを指定した場合は、JIRAにアクセスする前にファイルを確認する
+		var targets map[string]bool
+		if len(args) > 0 {
+			targets, err = resolvePushTargets(pushDir, args)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !force {
+			ok, err := ensureFreshCache(cmd.Context(), cfg)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("pushをキャンセルしました")
+				return nil
+			}
+		}
 
 		// 差分検出処理を一括実行
 		type diffResult struct {
 			changedTickets []ticket.DiffResult
+			readonlyEdits  []ticket.DiffResult
 			jiraClient     *jira.Client
+			cacheDir       string
+			rebases        map[string]ticket.PendingRebase
 		}
 
 		result, err := ui.WithSpinnerValue("差分を検出中...", func() (diffResult, error) {
@@ -65,23 +122,28 @@ keyがチケットはリモートにないチケットのため、JIRAにチケ
 			if err != nil {
 				return diffResult{}, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
 			}
+			if pushNoMultiHop {
+				jiraClient.DisableMultiHopTransitions()
+			}
+			// スプリント名の解決はチケットごとにボードのスプリントを取得せず、sprints.json を使う
+			jiraClient.SetSprintCacheDir(cacheDir)
 
 			// 4. ローカルとキャッシュの差分を検出
-			diffs, err := ticket.CompareDirs(pushDir, cacheDir)
+			diffs, err := ticket.CompareDirs(pushDir, cacheDir, diffOpts...)
 			if err != nil {
 				return diffResult{}, fmt.Errorf("差分の検出に失敗しました: %v", err)
 			}
 
 			// 差分があるチケットを抽出
 			var changedTickets []ticket.DiffResult
-			for _, diff := range diffs {
+			for _, diff := range filterPushTargets(diffs, targets) {
 				if diff.HasDiff {
 					changedTickets = append(changedTickets, diff)
 				}
 			}
 
 			if len(changedTickets) == 0 {
-				return diffResult{changedTickets: changedTickets, jiraClient: jiraClient}, nil
+				return diffResult{changedTickets: changedTickets, readonlyEdits: readonlyEditDiffs(filterPushTargets(diffs, targets)), jiraClient: jiraClient}, nil
 			}
 
 			// 差分があるチケットについては最新の状態をキャッシュに保存し直す。
@@ -94,36 +156,29 @@ keyがチケットはリモートにないチケットのため、JIRAにチケ
 			}
 
 			// Bulk Fetch APIを使って一括取得
+			var remoteTickets []*ticket.Ticket
 			if len(keysToFetch) > 0 {
-				remoteTickets, err := jiraClient.BulkFetchIssues(keysToFetch)
+				remoteTickets, err = jiraClient.BulkFetchIssues(cmd.Context(), keysToFetch)
 				if err != nil {
 					return diffResult{}, err
 				}
-
-				// 取得したチケットをキャッシュに保存
-				for _, remoteTicket := range remoteTickets {
-					_, err = remoteTicket.SaveToFile(cacheDir)
-					if err != nil {
-						return diffResult{}, err
-					}
-				}
 			}
 
 			// 改めて差分を検出
-			diffs, err = ticket.CompareDirs(pushDir, cacheDir)
+			diffs, rebases, err := refreshPushDiffs(pushDir, cacheDir, changedTickets, remoteTickets, diffOpts)
 			if err != nil {
-				return diffResult{}, fmt.Errorf("差分の検出に失敗しました: %v", err)
+				return diffResult{}, err
 			}
 
 			// 差分があるチケットを抽出
 			changedTickets = nil
-			for _, diff := range diffs {
+			for _, diff := range filterPushTargets(diffs, targets) {
 				if diff.HasDiff {
 					changedTickets = append(changedTickets, diff)
 				}
 			}
 
-			return diffResult{changedTickets: changedTickets, jiraClient: jiraClient}, nil
+			return diffResult{changedTickets: changedTickets, readonlyEdits: readonlyEditDiffs(filterPushTargets(diffs, targets)), jiraClient: jiraClient, cacheDir: cacheDir, rebases: rebases}, nil
 		})
 		if err != nil {
 			return err
@@ -131,38 +186,80 @@ keyがチケットはリモートにないチケットのため、JIRAにチケ
 
 		changedTickets := result.changedTickets
 		jiraClient := result.jiraClient
+		warnNonTicketFiles(cfg)
+
+		// 読み取り専用の項目の編集はpushしても反映されないので、push前に知らせる
+		if err := reportReadonlyEdits(cfg, result.readonlyEdits); err != nil {
+			return err
+		}
 
 		if len(changedTickets) == 0 {
-			verbose.Println("差分はありません")
+			verbose.Println(verbose.Diff, "差分はありません")
 			return nil
 		}
 
-		verbose.Printf("%d 件のチケットに差分があります\n", len(changedTickets))
+		verbose.Printf(verbose.Diff, "%d 件のチケットに差分があります\n", len(changedTickets))
+
+		if !force {
+			if err := checkPushable(cmd.Context(), jiraClient, changedTickets); err != nil {
+				return err
+			}
+		}
 
 		if force {
-			verbose.Println("フォースモード: 確認なしで全てのファイルをpushします")
+			verbose.Println(verbose.General, "フォースモード: 確認なしで全てのファイルをpushします")
+		}
+
+		// 完了済みのスプリントへのpushを警告する
+		ok, err := confirmClosedSprints(cmd.Context(), jiraClient, changedTickets)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("pushをキャンセルしました")
+			return nil
+		}
+
+		// 親のチケットタイプが階層のルールに合わない場合を警告する
+		ok, err = confirmParentTypes(cfg, jiraClient, changedTickets)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("pushをキャンセルしました")
+			return nil
 		}
 
 		// 5. 差分をJIRAに適用
 		if dryRun {
-			verbose.Println("ドライラン: 実際には適用されません")
+			printPushSummary(changedTickets)
+			verbose.Println(verbose.General, "ドライラン: 実際には適用されません")
 			for _, diff := range changedTickets {
-				verbose.Printf("\n--- %s ---\n", diff.Key)
-				verbose.Println(diff.DiffText)
+				verbose.Printf(verbose.Diff, "\n--- %s ---\n", diff.Header())
+				verbose.Println(verbose.Diff, diff.DiffText)
 			}
 			return nil
 		}
 
+		// 複数のチケットに差分がある場合は、まず概要を表示して対象を絞り込む
+		if !force && len(changedTickets) > 1 {
+			printPushSummary(changedTickets)
+			changedTickets, err = selectPushTargets(changedTickets)
+			if err != nil {
+				return err
+			}
+			if len(changedTickets) == 0 {
+				fmt.Println("pushをキャンセルしました")
+				return nil
+			}
+		}
+
 		// ユーザーに確認を取る
 		var confirmedTickets []ticket.DiffResult
 		for _, diff := range changedTickets {
 			if !dryRun && !force {
 				fmt.Printf("\n=== ファイル: %s ===\n", diff.FilePath)
-				if diff.Key != "" {
-					fmt.Printf("チケット: %s\n", diff.Key)
-				} else {
-					fmt.Printf("新規チケット\n")
-				}
+				fmt.Println(diff.Header())
 				fmt.Printf("差分:\n%s\n", diff.DiffText)
 
 				if !utils.PromptForConfirmation("このファイルをpushしますか？") {
@@ -173,150 +270,813 @@ keyがチケットはリモートにないチケットのため、JIRAにチケ
 			confirmedTickets = append(confirmedTickets, diff)
 		}
 
+		// 前回の作成がタイムアウトした下書きは、作成済みのチケットがあればそれを使う
+		confirmedTickets, err = adoptPendingCreates(cmd.Context(), jiraClient, filenameTemplate(cfg), confirmedTickets)
+		if err != nil {
+			return err
+		}
+
 		if len(confirmedTickets) == 0 {
-			verbose.Println("適用するチケットがありません")
+			verbose.Println(verbose.General, "適用するチケットがありません")
 			return nil
 		}
 
-		// 実際に適用（conc poolを使用して最大5並列で処理）
-		var updatedCount, createdCount, deletedCount int
-		var mu sync.Mutex
+		// pushを確定したチケットに、確認時に表示したリモートの変更を取り込む
+		if err := applyRemoteRebases(result.cacheDir, result.rebases, confirmedTickets); err != nil {
+			return err
+		}
 
+		// 実際に適用（conc poolを使用して最大5並列で処理）
+		var stats pushStats
 		err = ui.WithSpinner("変更を適用中...", func() error {
-			// キャッシュディレクトリを再取得
-			cacheDir, err := config.EnsureCacheDir()
-			if err != nil {
-				return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+			stats, err = applyPush(jiraClient, newPushOptions(cfg), confirmedTickets)
+			return err
+		})
+		printPushNotices(stats)
+		if err != nil {
+			fmt.Printf("以下のエラーが発生しました:\n%v\n", err)
+			fmt.Printf("成功した分: %d 件作成, %d 件更新, %d 件削除\n", stats.created, stats.updated, stats.deleted)
+			return fmt.Errorf("一部の処理でエラーが発生しました")
+		}
+
+		verbose.Printf(verbose.General, "\n完了: %d 件作成, %d 件更新, %d 件削除\n", stats.created, stats.updated, stats.deleted)
+		return nil
+	},
+}
+
+// adoptPendingCreates は前回のpushで作成を始めたまま完了しなかった下書きについて、JIRAに作成済みのチケットを探します
+// 見つかったチケットを使うことにした場合は、下書きをそのチケットのキーで保存し直し、pushの対象から外します。
+// 下書きの内容とチケットの差分は、次回のpushで更新として反映されます。
+func adoptPendingCreates(ctx context.Context, jiraClient *jira.Client, filenameTemplate string, diffs []ticket.DiffResult) ([]ticket.DiffResult, error) {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+	}
+
+	var remaining []ticket.DiffResult
+	for _, diff := range diffs {
+		if diff.Change != ticket.ChangeCreate {
+			remaining = append(remaining, diff)
+			continue
+		}
+		pending, err := ticket.ReadPendingCreate(cacheDir, ticket.DraftID(diff.FilePath))
+		if err != nil {
+			return nil, err
+		}
+		if pending == nil {
+			remaining = append(remaining, diff)
+			continue
+		}
+
+		localTicket, err := ticket.FromFile(diff.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("チケット %s の読み込みに失敗しました: %v", diff.FilePath, err)
+		}
+		// タイトルを変えた場合は語句で絞り込めないので、期間内に作成したチケットから記録のタイトルと一致するものを探す
+		var summary string
+		if pending.SameTitle(localTicket.Title) {
+			summary = localTicket.Title
+		}
+		candidates, err := ui.WithSpinnerValue("作成済みのチケットを確認中...", func() ([]*ticket.Ticket, error) {
+			return jiraClient.FindRecentIssuesByMe(ctx, pending.Since(), summary)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: 前回のpushで作成済みのチケットを確認できませんでした: %v\n", diff.FilePath, err)
+			if !force && !utils.PromptForConfirmation("新しいチケットとして作成しますか？") {
+				fmt.Printf("スキップ: %s\n", diff.FilePath)
+				continue
+			}
+			remaining = append(remaining, diff)
+			continue
+		}
+		var existing *ticket.Ticket
+		for _, c := range candidates {
+			if pending.Matches(c) && (existing == nil || c.CreatedAt.Before(existing.CreatedAt)) {
+				existing = c
 			}
+		}
+		if existing == nil {
+			remaining = append(remaining, diff)
+			continue
+		}
 
-			p := pool.New().WithMaxGoroutines(5).WithErrors()
-			for _, diff := range confirmedTickets {
-				p.Go(func() error {
-					// 削除されたチケットかどうかをチェック
-					if strings.HasPrefix(filepath.Base(diff.FilePath), ".") {
-						// 削除されたチケットの処理
-						localTicket, err := ticket.FromFile(diff.FilePath)
-						if err != nil {
-							return fmt.Errorf("削除対象チケット %s の読み込みに失敗しました: %v", diff.Key, err)
-						}
+		fmt.Printf("\n%s は前回のpushで %s (%s) として作成済みの可能性があります\n", diff.FilePath, existing.Key, existing.Title)
+		if !force && !utils.PromptForConfirmation(fmt.Sprintf("新しく作成せずに %s を使いますか？", existing.Key)) {
+			remaining = append(remaining, diff)
+			continue
+		}
 
-						verbose.Printf("チケットを削除中: %s\n", localTicket.Key)
+		localTicket.Key = existing.Key
+		newFilePath, err := localTicket.SaveToFile(filepath.Dir(diff.FilePath), ticket.WithFilenameTemplate(filenameTemplate))
+		if err != nil {
+			return nil, fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+		}
+		if newFilePath != diff.FilePath {
+			if err := os.Remove(diff.FilePath); err != nil {
+				verbose.Printf(verbose.General, "警告: 元のファイル %s の削除に失敗しました: %v\n", diff.FilePath, err)
+			}
+		}
+		if _, err := existing.SaveToCache(cacheDir); err != nil {
+			return nil, fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+		}
+		if err := ticket.ClearPendingCreate(cacheDir, pending.DraftID); err != nil {
+			verbose.Printf(verbose.General, "警告: %v\n", err)
+		}
+		fmt.Printf("✅ %s を %s として保存しました。下書きとの差分は tkt push で反映してください\n", newFilePath, existing.Key)
+	}
+	return remaining, nil
+}
 
-						// JIRAからチケットを削除
-						err = jiraClient.DeleteIssue(localTicket.Key)
-						if err != nil {
-							return fmt.Errorf("チケット削除に失敗しました: %v", err)
-						}
+// resolvePushTargets はpushの対象に指定したチケットのキーまたはファイルを、ワークスペースのファイルの絶対パスの集合にします
+// キーは削除マークの付いたファイルも探します。見つからないものがあればエラーを返します。
+func resolvePushTargets(dir string, args []string) (map[string]bool, error) {
+	files, deletedFiles, err := ticket.WalkFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("ローカルファイルの検索に失敗しました: %v", err)
+	}
+	files = append(files, deletedFiles...)
+	index := ticket.NewFileIndex(dir)
+	targets := make(map[string]bool, len(args))
+	var notFound []string
+	for _, arg := range args {
+		path, err := resolvePushTarget(index, arg, files)
+		if err != nil {
+			return nil, err
+		}
+		if path == "" {
+			notFound = append(notFound, arg)
+			continue
+		}
+		targets[absPath(path)] = true
+	}
+	for _, arg := range notFound {
+		if err := checkExtraSourceTarget(arg); err != nil {
+			return nil, err
+		}
+	}
+	if len(notFound) > 0 {
+		return nil, fmt.Errorf("ワークスペース (%s) にチケットが見つかりません: %s", dir, strings.Join(notFound, ", "))
+	}
+	return targets, nil
+}
 
-						// 削除マークファイル（ドットプレフィックス）を削除
-						err = os.Remove(diff.FilePath)
-						if err != nil {
-							verbose.Printf("警告: 削除マークファイル %s の削除に失敗しました: %v\n", diff.FilePath, err)
-						}
+// checkExtraSourceTarget はワークスペースにないキー (other:ABC-12 の形式も可) が extra_sources のチケットであれば、
+// 読み取り専用のソースであることを示すエラーを返します
+func checkExtraSourceTarget(arg string) error {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil
+	}
+	source, key, ok := strings.Cut(arg, ":")
+	if !ok {
+		source, key = "", arg
+	}
+	key = ticket.CanonicalKey(key)
+	tickets, err := loadTickets(filepath.Join(cacheDir, config.ExtraSourcesDir), false)
+	if err != nil {
+		return nil
+	}
+	for _, t := range tickets {
+		if t.Key == key && (source == "" || strings.EqualFold(t.Source, source)) {
+			return t.CheckWritable()
+		}
+	}
+	return nil
+}
 
-						// キャッシュからも削除
-						originalFileName := filepath.Base(diff.FilePath)[1:] // .PRJ-123.md -> PRJ-123.md
-						cacheFile := filepath.Join(cacheDir, originalFileName)
-						err = os.Remove(cacheFile)
-						if err != nil && !os.IsNotExist(err) {
-							verbose.Printf("警告: キャッシュファイル %s の削除に失敗しました: %v\n", cacheFile, err)
-						}
+// resolvePushTarget は1つの引数に対応するファイルを返します。見つからない場合は空文字を返します。
+// キーで指定した場合は index から探します。
+func resolvePushTarget(index *ticket.FileIndex, arg string, files []string) (string, error) {
+	dir := index.Dir()
+	// パスで指定した場合
+	if strings.HasSuffix(arg, ".md") || strings.ContainsRune(arg, filepath.Separator) {
+		for _, candidate := range []string{arg, filepath.Join(dir, arg)} {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				// キャッシュの .sources/ 以下のファイルを直接指定した場合など
+				if t, err := ticket.FromFile(candidate); err == nil {
+					if err := t.CheckWritable(); err != nil {
+						return "", err
+					}
+				}
+				return candidate, nil
+			}
+		}
+	}
 
-						verbose.Printf("削除完了: %s\n", localTicket.Key)
-						mu.Lock()
-						deletedCount++
-						mu.Unlock()
-						return nil
+	// キーで指定した場合
+	if key := ticket.CanonicalKey(arg); utils.IsValidJIRAKey(key) {
+		path, err := index.Find(key)
+		if err != nil || path != "" {
+			return path, err
+		}
+		return index.FindDeleted(key)
+	}
+
+	// 下書きのファイル名 (拡張子は省略可) で指定した場合
+	name := strings.TrimSuffix(filepath.Base(arg), ".md") + ".md"
+	for _, file := range files {
+		if filepath.Base(file) == name {
+			return file, nil
+		}
+	}
+	return "", nil
+}
+
+// checkRemoteConflicts は最後のフェッチの後にリモートで更新されたチケットについて、ローカルの変更と競合しないかを確認します
+// 変更した項目が重なる場合はエラーを返します (--force-remote-overwrite の場合はリモートの変更を上書きします)。
+// 重ならない場合はリモートの変更をローカルのファイルに取り込み、pushでリモートの変更を戻さないようにします。
+func checkRemoteConflicts(cacheDir string, diffs []ticket.DiffResult, remoteTickets []*ticket.Ticket) (map[string]ticket.PendingRebase, error) {
+	localPaths := make(map[string]string, len(diffs))
+	for _, diff := range diffs {
+		if diff.Change == ticket.ChangeUpdate {
+			localPaths[diff.Key] = diff.FilePath
+		}
+	}
+
+	var conflicts []*ticket.Conflict
+	rebases := make(map[string]ticket.PendingRebase)
+	for _, remote := range remoteTickets {
+		localPath, ok := localPaths[remote.Key]
+		if !ok {
+			continue
+		}
+		base, err := ticket.FromFile(filepath.Join(cacheDir, remote.Key+".md"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("キャッシュのチケット %s の読み込みに失敗しました: %v", remote.Key, err)
+		}
+		if !remote.UpdatedAt.After(base.UpdatedAt) {
+			continue
+		}
+		local, err := ticket.FromFile(localPath)
+		if err != nil {
+			return nil, fmt.Errorf("チケット %s の読み込みに失敗しました: %v", remote.Key, err)
+		}
+
+		if conflict := ticket.DetectConflict(local, base, remote); conflict != nil {
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+		rebases[localPath] = ticket.PendingRebase{Rebased: ticket.Rebase(local, base, remote), Remote: remote}
+		verbose.Printf(verbose.General, "%s はリモートで更新されているため、リモートの変更を取り込んで比較します\n", remote.Key)
+	}
+
+	if len(conflicts) == 0 || forceRemoteOverwrite {
+		return rebases, nil
+	}
+	lines := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		lines = append(lines, fmt.Sprintf("  %s: %s (リモートの更新: %s)", c.Key, strings.Join(c.Fields, ", "), c.RemoteUpdatedAt.Local().Format("2006-01-02 15:04")))
+	}
+	return nil, fmt.Errorf("最後のフェッチの後にJIRAで更新されたチケットとローカルの変更が競合しています:\n%s\n'tkt pull' でリモートの変更を取り込んでから編集し直すか、--force-remote-overwrite でリモートの変更を上書きしてください", strings.Join(lines, "\n"))
+}
+
+// refreshPushDiffs は取得したリモートのチケットをキャッシュに保存し、改めて差分を検出します
+// リモートの変更を取り込むチケットはファイルを書き換えず、取り込んだ内容で比較します。
+// ワークスペースとキャッシュへの書き込みは、pushが確定してから applyRemoteRebases で行います (ドライランでは行いません)。
+func refreshPushDiffs(pushDir, cacheDir string, changed []ticket.DiffResult, remoteTickets []*ticket.Ticket, opts []ticket.CompareOption) ([]ticket.DiffResult, map[string]ticket.PendingRebase, error) {
+	// キャッシュを上書きする前に、フェッチ後にリモートで更新されていないかを確認する
+	rebases, err := checkRemoteConflicts(cacheDir, changed, remoteTickets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 取得したチケットをキャッシュに保存
+	// (リモートの変更を取り込むチケットは、ワークスペースに書き込むまでフェッチ時の内容を基準として残す)
+	pending := make(map[string]bool, len(rebases))
+	for _, rebase := range rebases {
+		pending[rebase.Remote.Key] = true
+	}
+	for _, remoteTicket := range remoteTickets {
+		if pending[remoteTicket.Key] {
+			continue
+		}
+		if _, err := remoteTicket.SaveToCache(cacheDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	diffs, err := ticket.CompareDirs(pushDir, cacheDir, append(slices.Clone(opts), ticket.WithPendingRebases(rebases))...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("差分の検出に失敗しました: %v", err)
+	}
+	return diffs, rebases, nil
+}
+
+// applyRemoteRebases はpushするチケットのうちリモートの変更を取り込むものについて、
+// 取り込んだ内容をワークスペースに、リモートのチケットをキャッシュに書き込みます
+func applyRemoteRebases(cacheDir string, rebases map[string]ticket.PendingRebase, diffs []ticket.DiffResult) error {
+	for _, diff := range diffs {
+		rebase, ok := rebases[diff.FilePath]
+		if !ok {
+			continue
+		}
+		if err := utils.WriteFileAtomic(diff.FilePath, []byte(rebase.Rebased.ToMarkdown()), 0644); err != nil {
+			return fmt.Errorf("リモートの変更の取り込みに失敗しました: %v", err)
+		}
+		if _, err := rebase.Remote.SaveToCache(cacheDir); err != nil {
+			return err
+		}
+		verbose.Printf(verbose.General, "%s はリモートで更新されているため、リモートの変更を %s に取り込みました\n", diff.Key, diff.FilePath)
+	}
+	return nil
+}
+
+// filterPushTargets は対象に指定したファイルの差分のみを返します (targetsがnilの場合はすべて)
+func filterPushTargets(diffs []ticket.DiffResult, targets map[string]bool) []ticket.DiffResult {
+	if targets == nil {
+		return diffs
+	}
+	var filtered []ticket.DiffResult
+	for _, diff := range diffs {
+		if targets[absPath(diff.FilePath)] {
+			filtered = append(filtered, diff)
+		}
+	}
+	return filtered
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// printPushSummary はpushする変更の概要を1チケット1行の表で表示します
+func printPushSummary(diffs []ticket.DiffResult) {
+	fmt.Printf("変更の概要 (%d件)\n", len(diffs))
+	keys := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		keys = append(keys, displayDiffKey(diff))
+	}
+	keyWidth := keyColumnWidth(keys)
+	fmt.Printf("  %s %s %s %s\n", padRight("KEY", keyWidth), padRight("種別", 4), padRight("変更項目", 36), "行数")
+	for _, diff := range diffs {
+		fields := strings.Join(diff.ChangedFields, ", ")
+		if diff.Change == ticket.ChangeCreate {
+			fields = filepath.Base(diff.FilePath)
+		}
+		fmt.Printf("  %s %s %s %s\n", padRight(displayDiffKey(diff), keyWidth), padRight(changeTypeLabel(diff.Change), 4), padRight(fields, 36), formatLineCounts(diff))
+	}
+	fmt.Println()
+}
+
+// selectPushTargets はすべてpushするか、対象を選択するか、中止するかを確認し、pushするチケットを返します
+func selectPushTargets(diffs []ticket.DiffResult) ([]ticket.DiffResult, error) {
+	const (
+		actionAll    = "all"
+		actionSelect = "select"
+		actionAbort  = "abort"
+	)
+	action, err := ui.Select("どのようにpushしますか？", []ui.SelectorOption{
+		{Title: fmt.Sprintf("すべてのチケット (%d件) を確認してpushする", len(diffs)), Value: actionAll},
+		{Title: "pushするチケットを選択する", Value: actionSelect},
+		{Title: "中止する", Value: actionAbort},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("操作の選択に失敗しました: %v", err)
+	}
+
+	switch action {
+	case actionAll:
+		return diffs, nil
+	case actionSelect:
+		options := make([]ui.SelectorOption, 0, len(diffs))
+		for i, diff := range diffs {
+			options = append(options, ui.SelectorOption{
+				Title: fmt.Sprintf("[%s] %s %s", changeTypeLabel(diff.Change), displayDiffKey(diff), diff.Title),
+				Value: i,
+			})
+		}
+		selected, err := ui.MultiSelect("pushするチケットを選択してください (space: 選択, enter: 決定)", options)
+		if err != nil {
+			return nil, fmt.Errorf("チケットの選択に失敗しました: %v", err)
+		}
+		targets := make([]ticket.DiffResult, 0, len(selected))
+		for _, v := range selected {
+			targets = append(targets, diffs[v.(int)])
+		}
+		return targets, nil
+	default:
+		return nil, nil
+	}
+}
+
+// displayDiffKey は差分の表示用のキーを返します
+// 新規作成の場合はキーがないため (新規) と表示します。
+func displayDiffKey(diff ticket.DiffResult) string {
+	if diff.Key == "" {
+		return "(新規)"
+	}
+	return diff.Key
+}
+
+// changeTypeLabel は変更の種類の表示名を返します
+func changeTypeLabel(change ticket.ChangeType) string {
+	switch change {
+	case ticket.ChangeCreate:
+		return "作成"
+	case ticket.ChangeDelete:
+		return "削除"
+	default:
+		return "更新"
+	}
+}
+
+// formatLineCounts は追加・削除された行数を +N -M の形式で返します
+func formatLineCounts(diff ticket.DiffResult) string {
+	var parts []string
+	if diff.Added > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", diff.Added))
+	}
+	if diff.Removed > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", diff.Removed))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pushStats はpushの結果の件数です
+type pushStats struct {
+	created, updated, deleted int
+	// notices は作成時に省略したフィールドなど、ユーザーに知らせること
+	notices []string
+	// syncedBack はJIRAが正規化した内容を書き戻したワークスペースのファイルです
+	syncedBack []string
+}
+
+// pushOptions は applyPush の設定ファイル由来のオプションです
+type pushOptions struct {
+	filenameTemplate string
+	// syncBack がtrueの場合、pushしたチケットのファイルのうち送った項目をJIRAから取得し直した値で書き換えます (push.sync_back)
+	syncBack bool
+}
+
+func newPushOptions(cfg *config.Config) pushOptions {
+	return pushOptions{
+		filenameTemplate: filenameTemplate(cfg),
+		syncBack:         cfg.PushSyncBack(),
+	}
+}
+
+// checkPushable はJIRAに送る前に、そのままpushすると問題になるチケットがないかを確認します
+// 未対応のADFの目印が残った本文での更新 (JIRAの元の内容が失われる) と、作成時の必須項目が未入力の下書きをエラーにします。
+// pushとsyncで共通に使います。
+func checkPushable(ctx context.Context, jiraClient *jira.Client, diffs []ticket.DiffResult) error {
+	if err := checkUnsupportedADF(diffs); err != nil {
+		return err
+	}
+	drafts := draftDiffs(diffs)
+	if len(drafts) == 0 {
+		return nil
+	}
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	findings, err := requiredFieldFindings(ctx, jiraClient, cacheDir, drafts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  必須項目を確認できませんでした: %v\n", err)
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("作成時の必須項目が未入力の下書きがあります。入力してからpushするか、--force でそのままpushしてください\n%s", strings.Join(findings, "\n"))
+	}
+	return nil
+}
+
+// checkUnsupportedADF は本文を変更したチケットに、fetchで未対応のADFのノードを置き換えた目印が残っていないかを確認します
+// 目印が残ったまま本文を更新すると、JIRAの元の内容 (展開やレイアウトなど) が失われるためエラーにします。
+func checkUnsupportedADF(diffs []ticket.DiffResult) error {
+	var lines []string
+	for _, d := range diffs {
+		if d.Change != ticket.ChangeUpdate || !slices.Contains(d.ChangedFields, "body") {
+			continue
+		}
+		t, err := ticket.FromFile(d.FilePath)
+		if err != nil {
+			return fmt.Errorf("%s の読み込みに失敗しました: %v", d.FilePath, err)
+		}
+		if adf.HasUnsupportedPlaceholder(t.Body) {
+			lines = append(lines, fmt.Sprintf("  %s (%s)", d.Key, d.FilePath))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("以下のチケットの本文に、tktが対応していないJIRAの内容の目印 (%s ... -->) が残っています:\n%s\npushするとJIRAの元の内容が失われます。目印の部分を削除してからpushするか、--force でそのままpushしてください", adf.UnsupportedPlaceholderPrefix, strings.Join(lines, "\n"))
+}
+
+// printPushNotices はpush中にJIRAクライアントが記録したお知らせを表示します
+func printPushNotices(stats pushStats) {
+	for _, notice := range stats.notices {
+		fmt.Printf("⚠️  %s\n", notice)
+	}
+	if len(stats.syncedBack) > 0 {
+		fmt.Println("JIRAで正規化された内容をワークスペースのファイルに反映しました:")
+		for _, path := range slices.Sorted(slices.Values(stats.syncedBack)) {
+			fmt.Printf("   %s\n", path)
+		}
+	}
+}
+
+// writeSyncBack はpush後にJIRAから取得し直した内容をワークスペースのファイルに書き戻します
+// ファイル名も内容も変わらない場合は書き込まず (更新日時を変えず)、falseを返します。
+func writeSyncBack(synced *ticket.Ticket, filenameTemplate string) (string, bool, error) {
+	fileName, err := synced.FileName(filenameTemplate)
+	if err != nil {
+		return "", false, err
+	}
+	if filepath.Base(synced.FilePath) == fileName {
+		current, err := os.ReadFile(synced.FilePath)
+		if err == nil && string(current) == synced.ToMarkdown() {
+			return synced.FilePath, false, nil
+		}
+	}
+	path, err := synced.SaveToFile(filepath.Dir(synced.FilePath), ticket.WithFilenameTemplate(filenameTemplate))
+	if err != nil {
+		return "", false, fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+	}
+	return path, true, nil
+}
+
+// uploadBodyImages は本文で相対パスで参照している画像をチケットに添付し、リンク先を書き換えた本文を返します
+func uploadBodyImages(jiraClient *jira.Client, key, body, filePath string) (string, error) {
+	uploaded, err := jiraClient.UploadImages(key, body, filepath.Dir(filePath))
+	if err != nil {
+		return "", fmt.Errorf("チケット %s の画像の添付に失敗しました: %v", key, err)
+	}
+	return uploaded, nil
+}
+
+// resolvePushPlaceholders は assignee: me と sprint: @active、sprint: backlog をpush後のJIRA上の値に置き換え、置き換えたかどうかを返します
+func resolvePushPlaceholders(local, remote *ticket.Ticket) bool {
+	resolved := false
+	if strings.EqualFold(local.Assignee, jira.AssigneeMe) {
+		local.Assignee = remote.Assignee
+		resolved = true
+	}
+	if local.SprintName == jira.SprintActive || (local.SprintName != "" && local.InBacklog()) {
+		local.SprintName = remote.SprintName
+		resolved = true
+	}
+	return resolved
+}
+
+// applyPush は差分のあるチケットをJIRAに反映します (最大5並列)
+// 作成・更新・削除したチケットはキャッシュにも反映します。ローカルファイルは元のファイルと同じディレクトリに保存します。
+// 一部が失敗した場合も、成功した分の件数を返します。
+func applyPush(jiraClient *jira.Client, opts pushOptions, diffs []ticket.DiffResult) (pushStats, error) {
+	filenameTemplate := opts.filenameTemplate
+	var stats pushStats
+	var mu sync.Mutex
+
+	// キャッシュディレクトリを再取得
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return pushStats{}, fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+	}
+
+	p := pool.New().WithMaxGoroutines(jiraClient.Concurrency()).WithErrors()
+	for _, diff := range diffs {
+		p.Go(func() error {
+			// 削除されたチケットかどうかをチェック
+			if strings.HasPrefix(filepath.Base(diff.FilePath), ".") {
+				// 削除されたチケットの処理
+				localTicket, err := ticket.FromFile(diff.FilePath)
+				if err != nil {
+					return fmt.Errorf("削除対象チケット %s の読み込みに失敗しました: %v", diff.Key, err)
+				}
+
+				verbose.Printf(verbose.General, "チケットを削除中: %s\n", localTicket.Key)
+				previous, err := ticket.ReadCacheFile(cacheDir, localTicket.Key)
+				if err != nil {
+					return err
+				}
+
+				// JIRAからチケットを削除
+				err = jiraClient.DeleteIssue(localTicket.Key)
+				if err != nil {
+					return fmt.Errorf("チケット削除に失敗しました: %v", err)
+				}
+
+				// 削除マークファイル（ドットプレフィックス）を削除
+				err = os.Remove(diff.FilePath)
+				if err != nil {
+					verbose.Printf(verbose.General, "警告: 削除マークファイル %s の削除に失敗しました: %v\n", diff.FilePath, err)
+				}
+
+				// キャッシュからも削除
+				// (ワークスペースのファイル名は filename_style によって異なるが、キャッシュは常に PRJ-123.md)
+				cacheFile := filepath.Join(cacheDir, localTicket.Key+".md")
+				err = os.Remove(cacheFile)
+				if err != nil && !os.IsNotExist(err) {
+					verbose.Printf(verbose.General, "警告: キャッシュファイル %s の削除に失敗しました: %v\n", cacheFile, err)
+				}
+
+				recordPushHistory(cacheDir, ticket.HistoryEntry{Key: localTicket.Key, Action: ticket.ChangeDelete}, previous)
+				verbose.Printf(verbose.General, "削除完了: %s\n", localTicket.Key)
+				mu.Lock()
+				stats.deleted++
+				mu.Unlock()
+				return nil
+			}
+
+			localTicket, err := ticket.FromFile(diff.FilePath)
+			if err != nil {
+				return fmt.Errorf("チケット %s の読み込みに失敗しました: %v", diff.Key, err)
+			}
+
+			if localTicket.Key == "" {
+				// 新規チケット作成
+				verbose.Printf(verbose.General, "新規チケットを作成中: %s\n", localTicket.Title)
+
+				// 作成のレスポンスを受け取れなかった場合に再実行で二重に作成しないよう、作成を始めたことを記録する
+				pending := ticket.NewPendingCreate(diff.FilePath, localTicket.Title, time.Now())
+				if err := ticket.SavePendingCreate(cacheDir, pending); err != nil {
+					return err
+				}
+
+				// JIRAにチケットを作成
+				createdTicket, err := jiraClient.CreateIssue(localTicket)
+				if err != nil {
+					return fmt.Errorf("チケット作成に失敗しました: %v", err)
+				}
+
+				// 本文のローカル画像とリンクは作成後に追加し、キャッシュに反映するため取得し直す
+				uploaded, err := uploadBodyImages(jiraClient, createdTicket.Key, localTicket.Body, diff.FilePath)
+				if err != nil {
+					return err
+				}
+				if uploaded != localTicket.Body {
+					if err := jiraClient.UpdateDescription(createdTicket.Key, uploaded); err != nil {
+						return fmt.Errorf("画像のリンクの更新に失敗しました: %v", err)
+					}
+				}
+				if len(localTicket.Links) > 0 {
+					if err := jiraClient.SyncLinks(createdTicket.Key, localTicket.Links); err != nil {
+						return fmt.Errorf("リンクの作成に失敗しました: %v", err)
+					}
+				}
+				if uploaded != localTicket.Body || len(localTicket.Links) > 0 {
+					createdTicket, err = jiraClient.FetchIssue(createdTicket.Key)
+					if err != nil {
+						return fmt.Errorf("作成後のチケット取得に失敗しました: %v", err)
 					}
+				}
+
+				// 元のファイルパスを保存
+				originalFilePath := diff.FilePath
+
+				// ローカルファイルのKeyを更新 (assignee: me や sprint: @active は実際の値に置き換える)
+				// push.sync_back の場合は送った項目についてJIRAが正規化した内容を保存する
+				pushed := ticket.CreatedFields(localTicket)
+				localTicket.Key = createdTicket.Key
+				resolvePushPlaceholders(localTicket, createdTicket)
+				saved := localTicket
+				if opts.syncBack {
+					saved = ticket.SyncBack(localTicket, createdTicket, pushed)
+				}
+				// サブディレクトリの下書きは同じディレクトリに保存する
+				newFilePath, err := saved.SaveToFile(filepath.Dir(originalFilePath), ticket.WithFilenameTemplate(filenameTemplate))
+				if err != nil {
+					return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+				}
 
-					localTicket, err := ticket.FromFile(diff.FilePath)
+				// 元のファイルを削除（新しいファイルパスと異なる場合のみ）
+				if originalFilePath != newFilePath {
+					err = os.Remove(originalFilePath)
 					if err != nil {
-						return fmt.Errorf("チケット %s の読み込みに失敗しました: %v", diff.Key, err)
+						verbose.Printf(verbose.General, "警告: 元のファイル %s の削除に失敗しました: %v\n", originalFilePath, err)
+					} else {
+						verbose.Printf(verbose.General, "元のファイル %s を削除し、%s にリネームしました\n", originalFilePath, newFilePath)
 					}
+				}
 
-					if localTicket.Key == "" {
-						// 新規チケット作成
-						verbose.Printf("新規チケットを作成中: %s\n", localTicket.Title)
+				// キャッシュも更新（CreateIssueが既に正しいフォーマットで返すため直接保存）
+				_, err = createdTicket.SaveToCache(cacheDir)
+				if err != nil {
+					return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+				}
 
-						// JIRAにチケットを作成
-						createdTicket, err := jiraClient.CreateIssue(localTicket)
-						if err != nil {
-							return fmt.Errorf("チケット作成に失敗しました: %v", err)
-						}
+				recordPushHistory(cacheDir, ticket.HistoryEntry{Key: createdTicket.Key, Action: ticket.ChangeCreate}, nil)
+				if err := ticket.ClearPendingCreate(cacheDir, pending.DraftID); err != nil {
+					verbose.Printf(verbose.General, "警告: %v\n", err)
+				}
+				verbose.Printf(verbose.General, "作成完了: %s\n", createdTicket.Key)
+				mu.Lock()
+				stats.created++
+				mu.Unlock()
+			} else {
+				// 既存チケット更新
+				verbose.Printf(verbose.General, "チケットを更新中: %s\n", localTicket.Key)
+				previous, err := ticket.ReadCacheFile(cacheDir, localTicket.Key)
+				if err != nil {
+					return err
+				}
 
-						// 元のファイルパスを保存
-						originalFilePath := diff.FilePath
+				// 本文のローカル画像を添付し、JIRAにはリンク先を添付ファイルのURLにした本文を送る
+				// (ローカルのファイルは相対パスのまま残す)
+				pushTicket := *localTicket
+				if slices.Contains(diff.ChangedFields, "body") {
+					pushTicket.Body, err = uploadBodyImages(jiraClient, localTicket.Key, localTicket.Body, diff.FilePath)
+					if err != nil {
+						return err
+					}
+				}
 
-						// ローカルファイルのKeyを更新
-						localTicket.Key = createdTicket.Key
-						newFilePath, err := localTicket.SaveToFile(pushDir)
-						if err != nil {
-							return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
-						}
+				// JIRAを更新 (キャッシュから変更された項目のみ送信する)
+				err = jiraClient.UpdateIssue(pushTicket, diff.ChangedFields)
+				if err != nil {
+					return fmt.Errorf("チケット更新に失敗しました: %v", err)
+				}
 
-						// 元のファイルを削除（新しいファイルパスと異なる場合のみ）
-						if originalFilePath != newFilePath {
-							err = os.Remove(originalFilePath)
-							if err != nil {
-								verbose.Printf("警告: 元のファイル %s の削除に失敗しました: %v\n", originalFilePath, err)
-							} else {
-								verbose.Printf("元のファイル %s を削除し、%s にリネームしました\n", originalFilePath, newFilePath)
-							}
-						}
+				// 担当者の行を削除した場合は担当者を外す
+				if slices.Contains(diff.ChangedFields, "assignee") && localTicket.Assignee == "" {
+					if err := jiraClient.UpdateIssueFields(localTicket.Key, map[string]interface{}{"assignee": nil}); err != nil {
+						return fmt.Errorf("担当者の解除に失敗しました: %v", err)
+					}
+				}
 
-						// キャッシュも更新（CreateIssueが既に正しいフォーマットで返すため直接保存）
-						_, err = createdTicket.SaveToFile(cacheDir)
-						if err != nil {
-							return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
-						}
+				// リンクは別のAPIで追加・削除する
+				if slices.Contains(diff.ChangedFields, "links") {
+					if err := jiraClient.SyncLinks(localTicket.Key, localTicket.Links); err != nil {
+						return fmt.Errorf("リンクの更新に失敗しました: %v", err)
+					}
+				}
 
-						verbose.Printf("作成完了: %s\n", createdTicket.Key)
+				// キャッシュを更新（pushが成功したので最新の状態をキャッシュに保存）
+				// ローカルチケットをそのまま使わずにremoteからfetchする理由：
+				// - JIRAが自動更新する項目（updated日時、version等）を確実に取得
+				// - 権限やvalidationでJIRA側で値が変更される可能性への対応
+				// - データフロー（fetch→cache）の一貫性維持
+				remoteTicket, err := jiraClient.FetchIssue(localTicket.Key)
+				if err != nil {
+					return fmt.Errorf("更新後のチケット取得に失敗しました: %v", err)
+				}
+				_, err = remoteTicket.SaveToCache(cacheDir)
+				if err != nil {
+					return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+				}
+
+				// push.sync_back の場合は、送った項目についてJIRAが正規化した内容 (本文の末尾の空白など) をワークスペースのファイルに書き戻す
+				// 何も送っていない場合は書き戻さない (送っていないローカルの編集をリモートの値で上書きしない)
+				// そうでない場合も assignee: me や sprint: @active はJIRA上の値に置き換えて、次回以降の差分にならないようにする
+				// タイトルの変更でファイル名 (filename_template) が変わる場合もここでリネームする
+				if opts.syncBack && len(diff.ChangedFields) > 0 {
+					oldFilePath := localTicket.FilePath
+					synced := ticket.SyncBack(localTicket, remoteTicket, diff.ChangedFields)
+					newFilePath, changed, err := writeSyncBack(synced, filenameTemplate)
+					if err != nil {
+						return err
+					}
+					if changed {
 						mu.Lock()
-						createdCount++
+						stats.syncedBack = append(stats.syncedBack, newFilePath)
 						mu.Unlock()
-					} else {
-						// 既存チケット更新
-						verbose.Printf("チケットを更新中: %s\n", localTicket.Key)
-
-						// JIRAを更新
-						err := jiraClient.UpdateIssue(*localTicket)
-						if err != nil {
-							return fmt.Errorf("チケット更新に失敗しました: %v", err)
-						}
-
-						// キャッシュを更新（pushが成功したので最新の状態をキャッシュに保存）
-						// ローカルチケットをそのまま使わずにremoteからfetchする理由：
-						// - JIRAが自動更新する項目（updated日時、version等）を確実に取得
-						// - 権限やvalidationでJIRA側で値が変更される可能性への対応
-						// - データフロー（fetch→cache）の一貫性維持
-						remoteTicket, err := jiraClient.FetchIssue(localTicket.Key)
+					}
+					if newFilePath != oldFilePath {
+						verbose.Printf(verbose.General, "ファイル名を変更しました: %s\n", newFilePath)
+					}
+				} else {
+					fileName, err := localTicket.FileName(filenameTemplate)
+					if err != nil {
+						return err
+					}
+					renamed := filepath.Base(localTicket.FilePath) != fileName
+					if resolvePushPlaceholders(localTicket, remoteTicket) || renamed {
+						newFilePath, err := localTicket.SaveToFile(filepath.Dir(localTicket.FilePath), ticket.WithFilenameTemplate(filenameTemplate))
 						if err != nil {
-							return fmt.Errorf("更新後のチケット取得に失敗しました: %v", err)
+							return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
 						}
-						_, err = remoteTicket.SaveToFile(cacheDir)
-						if err != nil {
-							return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+						if renamed {
+							verbose.Printf(verbose.General, "ファイル名を変更しました: %s\n", newFilePath)
 						}
-
-						verbose.Printf("更新完了: %s\n", localTicket.Key)
-						mu.Lock()
-						updatedCount++
-						mu.Unlock()
 					}
-					return nil
-				})
+				}
+
+				recordPushHistory(cacheDir, ticket.HistoryEntry{Key: localTicket.Key, Action: ticket.ChangeUpdate, ChangedFields: diff.ChangedFields}, previous)
+				verbose.Printf(verbose.General, "更新完了: %s\n", localTicket.Key)
+				mu.Lock()
+				stats.updated++
+				mu.Unlock()
 			}
-			return p.Wait()
+			return nil
 		})
-		if err != nil {
-			fmt.Printf("以下のエラーが発生しました:\n%v\n", err)
-			fmt.Printf("成功した分: %d 件作成, %d 件更新, %d 件削除\n", createdCount, updatedCount, deletedCount)
-			return fmt.Errorf("一部の処理でエラーが発生しました")
-		}
+	}
+	err = p.Wait()
+	stats.notices = jiraClient.TakeNotices()
+	return stats, err
+}
 
-		verbose.Printf("\n完了: %d 件作成, %d 件更新, %d 件削除\n", createdCount, updatedCount, deletedCount)
-		return nil
-	},
+// recordPushHistory はpushした操作を履歴に記録します (tkt history --local, tkt undo で使います)
+// 記録に失敗してもpushは失敗させません。
+func recordPushHistory(cacheDir string, entry ticket.HistoryEntry, previous []byte) {
+	entry.Time = time.Now()
+	if err := ticket.AppendHistory(cacheDir, entry, previous); err != nil {
+		verbose.Printf(verbose.General, "警告: pushの履歴の記録に失敗しました: %v\n", err)
+	}
 }
 
 func init() {
@@ -326,4 +1086,139 @@ func init() {
 	pushCmd.Flags().StringVarP(&pushDir, "dir", "d", "", "チケットディレクトリ")
 	pushCmd.Flags().BoolVar(&dryRun, "dry-run", false, "実際に適用せずに差分のみ表示")
 	pushCmd.Flags().BoolVarP(&force, "force", "f", false, "確認なしで強制的にpush")
+	pushCmd.Flags().IntVar(&pushContext, "context", ticket.DefaultContextLines, "確認時の差分の前後に表示する行数")
+	pushCmd.Flags().BoolVar(&pushFull, "full", false, "確認時に変更されたチケットのファイル全体を表示する")
+	pushCmd.Flags().BoolVar(&pushNoMultiHop, "no-multi-hop", false, "目標のステータスに直接遷移できない場合に他のステータスを経由しない")
+	pushCmd.Flags().BoolVar(&forceRemoteOverwrite, "force-remote-overwrite", false, "リモートの変更と競合してもpushする")
+}
+
+// ensureFreshCache はキャッシュが push.max_cache_age より古い場合に、
+// 増分フェッチを行うか、ユーザーに続行するかを確認します。続行しない場合はfalseを返します。
+func ensureFreshCache(ctx context.Context, cfg *config.Config) (bool, error) {
+	staleness, err := checkCacheStaleness(cfg)
+	if err != nil {
+		return false, err
+	}
+	if !staleness.Stale() {
+		return true, nil
+	}
+
+	if cfg.Push.AutoFetch {
+		_, err := ui.WithSpinnerValue("キャッシュが古いためフェッチ中...", func() (int, error) {
+			return fetchToCache(ctx, cfg, false, "")
+		})
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  キャッシュが古くなっています: 最終フェッチ %s (push.max_cache_age: %s)\n", staleness, staleness.MaxAge)
+	fmt.Fprintf(os.Stderr, "   リモートの変更を上書きする可能性があります。'tkt fetch' の実行をおすすめします\n")
+	if dryRun {
+		return true, nil
+	}
+	return utils.PromptForConfirmation("このままpushしますか？"), nil
+}
+
+// sprintTargets はpushでスプリントが設定・変更されるチケットのスプリント名を返します (キーはファイルパス)
+func sprintTargets(diffs []ticket.DiffResult) map[string]string {
+	targets := make(map[string]string)
+	for _, diff := range diffs {
+		sprintChanged := diff.Change == ticket.ChangeCreate ||
+			(diff.Change == ticket.ChangeUpdate && slices.Contains(diff.ChangedFields, "sprint"))
+		if !sprintChanged {
+			continue
+		}
+		t, err := ticket.FromFile(diff.FilePath)
+		if err != nil || t.InBacklog() {
+			continue
+		}
+		targets[diff.FilePath] = t.SprintName
+	}
+	return targets
+}
+
+// confirmClosedSprints は完了済みのスプリントを指定したチケットがある場合に警告し、続行するかを確認します
+// JIRAは完了済みのスプリントへの設定も受け付けるため、気づかないまま終わったスプリントに入るのを防ぎます。
+// --forceやドライランの場合は警告のみ表示します。続行しない場合はfalseを返します。
+func confirmClosedSprints(ctx context.Context, jiraClient *jira.Client, diffs []ticket.DiffResult) (bool, error) {
+	targets := sprintTargets(diffs)
+	if len(targets) == 0 {
+		return true, nil
+	}
+
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return false, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	sprints, err := jiraClient.CachedSprints(ctx, cacheDir)
+	if err != nil {
+		verbose.Printf(verbose.General, "スプリント一覧を取得できないため、スプリントの状態の確認をスキップします: %v\n", err)
+		return true, nil
+	}
+
+	var closed []string
+	for _, diff := range diffs {
+		name, ok := targets[diff.FilePath]
+		if !ok {
+			continue
+		}
+		if sprint := jira.LookupSprint(sprints, name); sprint != nil && sprint.State == "closed" {
+			closed = append(closed, fmt.Sprintf("%s: %s", displayDiffKey(diff), sprint.Label()))
+		}
+	}
+	if len(closed) == 0 {
+		return true, nil
+	}
+
+	fmt.Fprintln(os.Stderr, "⚠️  完了済みのスプリントが指定されています:")
+	for _, line := range closed {
+		fmt.Fprintf(os.Stderr, "   %s\n", line)
+	}
+	if open := jira.OpenSprints(sprints); len(open) > 0 {
+		fmt.Fprintln(os.Stderr, "   指定できるスプリント:")
+		for _, sprint := range open {
+			fmt.Fprintf(os.Stderr, "     - %s\n", sprint.Label())
+		}
+	}
+	if force || dryRun {
+		return true, nil
+	}
+	return utils.PromptForConfirmation("完了済みのスプリントのままpushしますか？"), nil
+}
+
+// confirmParentTypes は親を設定・変更するチケットについて親のチケットタイプを取得し、
+// 階層のルール (サブタスクの親は標準のチケット、標準のチケットの親はエピック) に合わないものがあれば警告して続行するかを確認します。
+// --forceやドライランの場合は警告のみ表示します。続行しない場合はfalseを返します。
+func confirmParentTypes(cfg *config.Config, jiraClient *jira.Client, diffs []ticket.DiffResult) (bool, error) {
+	var problems []string
+	for _, diff := range diffs {
+		if !parentChanged(diff) {
+			continue
+		}
+		t, err := ticket.FromFile(diff.FilePath)
+		if err != nil || t.ParentKey == "" {
+			continue
+		}
+		problem, err := parentTypeProblem(cfg, jiraClient, t.Type, t.ParentKey)
+		if err != nil {
+			return false, err
+		}
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", displayDiffKey(diff), problem))
+		}
+	}
+	if len(problems) == 0 {
+		return true, nil
+	}
+
+	fmt.Fprintln(os.Stderr, "⚠️  親のチケットタイプが階層のルールに合いません:")
+	for _, line := range problems {
+		fmt.Fprintf(os.Stderr, "   %s\n", line)
+	}
+	if force || dryRun {
+		return true, nil
+	}
+	return utils.PromptForConfirmation("このままpushしますか？"), nil
 }
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePushTargets(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) string {
+		path := filepath.Join(dir, rel)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	prj12 := write("PRJ-12.md", "---\nkey: PRJ-12\ntitle: a\ntype: task\n---\n")
+	prj34 := write("epics/PRJ-34-slug.md", "---\nkey: PRJ-34\ntitle: b\ntype: epic\n---\n")
+	deleted := write(".PRJ-56.md", "---\nkey: PRJ-56\ntitle: c\ntype: task\n---\n")
+	draft := write("TMP-20250601-150405-k3x9qa.md", "---\ntitle: d\ntype: task\n---\n")
+	other := write("TMP-20250601-150406-zzzzzz.md", "---\ntitle: e\ntype: task\n---\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "キー (小文字やサブディレクトリも)", args: []string{"prj-12", "PRJ-34"}, want: []string{prj12, prj34}},
+		{name: "削除マークのチケット", args: []string{"PRJ-56"}, want: []string{deleted}},
+		{name: "下書きのファイル名", args: []string{"TMP-20250601-150405-k3x9qa"}, want: []string{draft}},
+		{name: "パス", args: []string{other}, want: []string{other}},
+		{name: "見つからない", args: []string{"PRJ-12", "PRJ-99", "TMP-nothing"}, wantErr: "PRJ-99, TMP-nothing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := resolvePushTargets(dir, tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var got []string
+			for path := range targets {
+				got = append(got, path)
+			}
+			var want []string
+			for _, path := range tt.want {
+				want = append(want, absPath(path))
+			}
+			assert.ElementsMatch(t, want, got)
+
+			// 指定したファイルの差分のみが残る
+			diffs := []ticket.DiffResult{{FilePath: prj12}, {FilePath: prj34}, {FilePath: deleted}, {FilePath: draft}, {FilePath: other}}
+			assert.Len(t, filterPushTargets(diffs, targets), len(tt.want))
+		})
+	}
+
+	assert.Len(t, filterPushTargets([]ticket.DiffResult{{FilePath: prj12}}, nil), 1)
+}
+
+func TestCheckRemoteConflicts(t *testing.T) {
+	fetchedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	base := ticket.Ticket{Key: "PRJ-123", Title: "ログイン画面", Type: "task", Body: "本文", UpdatedAt: fetchedAt}
+
+	tests := []struct {
+		name        string
+		local       func(t ticket.Ticket) ticket.Ticket
+		remote      func(t ticket.Ticket) ticket.Ticket
+		wantErr     string
+		wantRebased func(t ticket.Ticket) ticket.Ticket
+	}{
+		{
+			name:   "リモートがキャッシュと同じ",
+			local:  func(t ticket.Ticket) ticket.Ticket { t.Title = "ログイン画面の修正"; return t },
+			remote: func(t ticket.Ticket) ticket.Ticket { return t },
+		},
+		{
+			name:  "リモートが新しいが変更した項目が重ならない",
+			local: func(t ticket.Ticket) ticket.Ticket { t.Title = "ログイン画面の修正"; return t },
+			remote: func(t ticket.Ticket) ticket.Ticket {
+				t.Body = "同僚の本文"
+				t.UpdatedAt = fetchedAt.Add(time.Hour)
+				return t
+			},
+			wantRebased: func(t ticket.Ticket) ticket.Ticket {
+				t.Title = "ログイン画面の修正"
+				t.Body = "同僚の本文"
+				t.UpdatedAt = fetchedAt.Add(time.Hour)
+				return t
+			},
+		},
+		{
+			name:  "競合",
+			local: func(t ticket.Ticket) ticket.Ticket { t.Body = "自分の本文"; return t },
+			remote: func(t ticket.Ticket) ticket.Ticket {
+				t.Body = "同僚の本文"
+				t.UpdatedAt = fetchedAt.Add(time.Hour)
+				return t
+			},
+			wantErr: "PRJ-123: body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			localDir, cacheDir := t.TempDir(), t.TempDir()
+			cached := base
+			_, err := cached.SaveToCache(cacheDir)
+			assert.NoError(t, err)
+			local := tt.local(base)
+			localPath, err := local.SaveToFile(localDir)
+			assert.NoError(t, err)
+			remote := tt.remote(base)
+
+			diffs := []ticket.DiffResult{{Key: "PRJ-123", FilePath: localPath, Change: ticket.ChangeUpdate, HasDiff: true}}
+			rebases, err := checkRemoteConflicts(cacheDir, diffs, []*ticket.Ticket{&remote})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.ErrorContains(t, err, "--force-remote-overwrite")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// 取り込みは確認の後に行うので、ローカルのファイルは変更しない
+			got, err := ticket.FromFile(localPath)
+			assert.NoError(t, err)
+			assert.Equal(t, local.Title, got.Title)
+			assert.Equal(t, local.Body, got.Body)
+
+			if tt.wantRebased == nil {
+				assert.Empty(t, rebases)
+				return
+			}
+			want := tt.wantRebased(base)
+			rebased := rebases[localPath].Rebased
+			assert.Equal(t, want.Title, rebased.Title)
+			assert.Equal(t, want.Body, rebased.Body)
+			assert.True(t, want.UpdatedAt.Equal(rebased.UpdatedAt))
+		})
+	}
+}
+
+func TestRefreshPushDiffsDryRun(t *testing.T) {
+	fetchedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	base := ticket.Ticket{Key: "PRJ-123", Title: "ログイン画面", Type: "task", Body: "本文", UpdatedAt: fetchedAt}
+	localDir, cacheDir := t.TempDir(), t.TempDir()
+	cachePath, err := base.SaveToCache(cacheDir)
+	assert.NoError(t, err)
+	local := base
+	local.Title = "ログイン画面の修正"
+	localPath, err := local.SaveToFile(localDir)
+	assert.NoError(t, err)
+	remote := base
+	remote.Body = "同僚の本文"
+	remote.UpdatedAt = fetchedAt.Add(time.Hour)
+
+	readFiles := func() (string, string) {
+		localData, err := os.ReadFile(localPath)
+		assert.NoError(t, err)
+		cacheData, err := os.ReadFile(cachePath)
+		assert.NoError(t, err)
+		return string(localData), string(cacheData)
+	}
+	localBefore, cacheBefore := readFiles()
+
+	changed := []ticket.DiffResult{{Key: "PRJ-123", FilePath: localPath, Change: ticket.ChangeUpdate, HasDiff: true}}
+	diffs, rebases, err := refreshPushDiffs(localDir, cacheDir, changed, []*ticket.Ticket{&remote}, nil)
+	assert.NoError(t, err)
+
+	// リモートの変更を取り込んだ内容と比較するので、差分はローカルで変更したタイトルのみ
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, []string{"title"}, diffs[0].ChangedFields)
+	// ドライランで終わる場合に備えて、ワークスペースとキャッシュのファイルは書き換えない
+	localAfter, cacheAfter := readFiles()
+	assert.Equal(t, localBefore, localAfter)
+	assert.Equal(t, cacheBefore, cacheAfter)
+
+	// pushが確定したら取り込んだ内容を書き込む
+	assert.NoError(t, applyRemoteRebases(cacheDir, rebases, diffs))
+	got, err := ticket.FromFile(localPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "ログイン画面の修正", got.Title)
+	assert.Equal(t, "同僚の本文", got.Body)
+	cached, err := ticket.FromFile(cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "同僚の本文", cached.Body)
+}
+
+func TestCheckUnsupportedADF(t *testing.T) {
+	placeholder := "<!-- tkt:unsupported-adf type=expand -->\n詳細\n"
+
+	tests := []struct {
+		name    string
+		body    string
+		change  ticket.ChangeType
+		fields  []string
+		wantErr bool
+	}{
+		{name: "目印がない", body: "本文\n", change: ticket.ChangeUpdate, fields: []string{"body"}},
+		{name: "本文を変更していない", body: placeholder, change: ticket.ChangeUpdate, fields: []string{"title"}},
+		{name: "目印が残った本文を変更", body: placeholder + "追記\n", change: ticket.ChangeUpdate, fields: []string{"body"}, wantErr: true},
+		{name: "新規作成", body: placeholder, change: ticket.ChangeCreate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: tt.body}
+			path, err := local.SaveToFile(t.TempDir())
+			assert.NoError(t, err)
+
+			err = checkUnsupportedADF([]ticket.DiffResult{{Key: "PRJ-1", FilePath: path, Change: tt.change, ChangedFields: tt.fields}})
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "PRJ-1")
+				assert.ErrorContains(t, err, "--force")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteSyncBack(t *testing.T) {
+	dir := t.TempDir()
+	local := &ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "末尾に空白  \n\n| a | b |\n|---|---|\n"}
+	path, err := local.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	// JIRAが本文を正規化した場合は書き戻す
+	remote := &ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "末尾に空白\n\n| a | b |\n| --- | --- |\n"}
+	synced := ticket.SyncBack(local, remote, []string{"body"})
+	got, changed, err := writeSyncBack(synced, "")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, path, got)
+	loaded, err := ticket.FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, remote.Body, loaded.Body)
+
+	// 内容が同じ場合は書き込まない (更新日時を変えない)
+	old := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, old, old))
+	_, changed, err = writeSyncBack(ticket.SyncBack(loaded, remote, []string{"body"}), "")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(old))
+}
@@ -1,18 +1,21 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/qawatake/tkt/internal/cache"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/pkg/markdown"
+	"github.com/qawatake/tkt/internal/ticket"
 	"github.com/qawatake/tkt/internal/verbose"
 	"github.com/spf13/cobra"
 )
@@ -27,10 +30,15 @@ var queryCmd = &cobra.Command{
 	Use:     "query",
 	Aliases: []string{"q"},
 	Short:   "ローカルのファイルをSQLで検索します。",
-	Long:    `ローカルのファイルをSQLで検索します。`,
+	Long: `ローカルのファイルをSQLで検索します。
+
+フロントマターは tickets テーブル、本文は bodies (key, body) ビューとして参照できます。
+bodies の key はフロントマターの key なので、tickets と key で結合できます。`,
+	Example: `  tkt query -c "SELECT key, title FROM tickets WHERE status = 'To Do'"
+  tkt query -c "SELECT t.key FROM tickets t JOIN bodies b USING (key) WHERE b.body ILIKE '%timeout%'"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Start background cache update
-		cache.StartBackgroundUpdate()
+		cache.StartBackgroundUpdate(cmd.Context())
 
 		// queryDirが指定されていない場合は、-wフラグに応じてディレクトリを決定
 		if queryDir == "" {
@@ -54,44 +62,57 @@ var queryCmd = &cobra.Command{
 			}
 		}
 
-		// 2. マークダウンファイルを検索
-		var markdownFiles []string
-		err := filepath.WalkDir(queryDir, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !d.IsDir() && strings.HasSuffix(path, ".md") {
-				markdownFiles = append(markdownFiles, path)
-			}
-			return nil
-		})
+		// bodies ビューで読み込むファイルのパスと _file_path をそろえるため、絶対パスにする
+		if abs, err := filepath.Abs(queryDir); err == nil {
+			queryDir = abs
+		}
+
+		// 2. マークダウンファイルを検索 (.tktignore に一致するファイルは除く)
+		files, deletedFiles, err := ticket.WalkFiles(queryDir)
 		if err != nil {
 			return fmt.Errorf("ファイル検索に失敗しました: %v", err)
 		}
+		markdownFiles := append(files, deletedFiles...)
 
 		if len(markdownFiles) == 0 {
 			return fmt.Errorf("マークダウンファイルが見つかりません")
 		}
 
-		verbose.Printf("%d 件のマークダウンファイルを発見しました\n", len(markdownFiles))
+		verbose.Printf(verbose.General, "%d 件のマークダウンファイルを発見しました\n", len(markdownFiles))
 
 		// 3. フロントマターを抽出してJSONに変換
 		var allFrontmatters []map[string]any
 		for _, file := range markdownFiles {
 			content, err := os.ReadFile(file)
 			if err != nil {
-				verbose.Printf("警告: %s の読み込みに失敗しました: %v\n", file, err)
+				verbose.Printf(verbose.General, "警告: %s の読み込みに失敗しました: %v\n", file, err)
 				continue
 			}
 
 			frontmatter, _, err := markdown.ParseFrontMatter(string(content))
 			if err != nil {
-				verbose.Printf("警告: %s のフロントマターパースに失敗しました: %v\n", file, err)
+				verbose.Printf(verbose.General, "警告: %s のフロントマターパースに失敗しました: %v\n", file, err)
 				continue
 			}
 
 			if frontmatter != nil {
-				// ファイルパスも追加
+				// 本文の文字数・単語数を追加 (コメントは含めない)
+				if t, err := ticket.FromFile(file); err == nil {
+					stats := t.BodyStats()
+					frontmatter["body_chars"] = stats.Chars
+					frontmatter["body_words"] = stats.Words
+				}
+				// 下書きしかない場合も bodies ビューで key を参照できるように、key の列を必ず作る
+				if _, ok := frontmatter["key"]; !ok {
+					frontmatter["key"] = nil
+				}
+				// creator や epic のない古いファイル (Teamフィールドのないインスタンスの team も) しかない場合もクエリが失敗しないように、列を必ず作る
+				for _, column := range []string{"creator", "epic", "team"} {
+					if _, ok := frontmatter[column]; !ok {
+						frontmatter[column] = nil
+					}
+				}
+				// ファイルパスも追加 (bodies ビューでファイルとチケットを対応付けるのに使う)
 				frontmatter["_file_path"] = file
 				allFrontmatters = append(allFrontmatters, frontmatter)
 			}
@@ -101,10 +122,21 @@ var queryCmd = &cobra.Command{
 			return fmt.Errorf("有効なフロントマターが見つかりません")
 		}
 
-		verbose.Printf("%d 件のフロントマターを抽出しました\n", len(allFrontmatters))
+		verbose.Printf(verbose.General, "%d 件のフロントマターを抽出しました\n", len(allFrontmatters))
 
-		// 4. 一時JSONファイルを作成
-		tempFile := filepath.Join("/tmp", fmt.Sprintf("tkt_query_%d.json", time.Now().Unix()))
+		// 4. 一時ディレクトリにJSONファイルと初期化SQLファイルを作成
+		// DuckDBやtktが途中で終了しても残らないよう、まとめて1つのディレクトリに置いて最後に削除する
+		removeStaleQueryTempDirs(os.TempDir(), time.Now())
+		tempDir, err := os.MkdirTemp("", queryTempPrefix+"*")
+		if err != nil {
+			return fmt.Errorf("一時ディレクトリの作成に失敗しました: %v", err)
+		}
+		defer func() {
+			os.RemoveAll(tempDir)
+			verbose.Printf(verbose.General, "\n一時ディレクトリを削除しました: %s\n", tempDir)
+		}()
+
+		tempFile := filepath.Join(tempDir, "tickets.json")
 		jsonData, err := json.MarshalIndent(allFrontmatters, "", "  ")
 		if err != nil {
 			return fmt.Errorf("JSON変換に失敗しました: %v", err)
@@ -115,75 +147,53 @@ var queryCmd = &cobra.Command{
 			return fmt.Errorf("一時ファイルの作成に失敗しました: %v", err)
 		}
 
-		verbose.Printf("一時ファイルを作成しました: %s\n", tempFile)
+		verbose.Printf(verbose.General, "一時ファイルを作成しました: %s\n", tempFile)
 
 		// 初期化SQLファイルを作成
-		initSQL := fmt.Sprintf("CREATE TABLE tickets AS SELECT * FROM read_json_auto('%s');", tempFile)
-		initFile := filepath.Join("/tmp", fmt.Sprintf("tkt_init_%d.sql", time.Now().Unix()))
+		initSQL := queryInitSQL(tempFile, queryDir)
+		initFile := filepath.Join(tempDir, "init.sql")
 		err = os.WriteFile(initFile, []byte(initSQL), 0644)
 		if err != nil {
-			os.Remove(tempFile)
 			return fmt.Errorf("初期化SQLファイルの作成に失敗しました: %v", err)
 		}
 
 		// sqlQueryが指定されている場合は、直接SQLを実行してJSON出力
 		if sqlQuery != "" {
-			// SQL実行用のファイルを作成
-			sqlFile := filepath.Join("/tmp", fmt.Sprintf("tkt_query_%d.sql", time.Now().Unix()))
 			fullSQL := fmt.Sprintf("%s\nCOPY (%s) TO '/dev/stdout' (FORMAT JSON);", initSQL, sqlQuery)
-			err = os.WriteFile(sqlFile, []byte(fullSQL), 0644)
-			if err != nil {
-				os.Remove(tempFile)
-				os.Remove(initFile)
-				return fmt.Errorf("SQLファイルの作成に失敗しました: %v", err)
-			}
 
 			// DuckDBでSQLを実行
+			var output bytes.Buffer
 			duckdbCmd := exec.Command("duckdb", ":memory:", "-s", fullSQL)
+			duckdbCmd.Stdout = &output
 			duckdbCmd.Stderr = os.Stderr
 
-			output, err := duckdbCmd.Output()
-			if err != nil {
-				os.Remove(tempFile)
-				os.Remove(initFile)
-				os.Remove(sqlFile)
+			if err := runDuckDB(duckdbCmd); err != nil {
 				return fmt.Errorf("SQLの実行に失敗しました: %v", err)
 			}
 
 			// JSON出力
-			fmt.Print(string(output))
-
-			// 一時ファイルを削除
-			os.Remove(sqlFile)
-		} else {
-			// 5. DuckDBのREPLを起動
-			verbose.Println("DuckDBのREPLを起動中...")
-			verbose.Printf("データベースのテーブル名: tickets\n")
-			verbose.Printf("使用例: SELECT * FROM tickets WHERE status = 'To Do';\n")
-			verbose.Println("終了するには .exit を入力してください")
-
-			// DuckDBコマンドを構築（初期化SQLファイルを読み込んでREPLを起動）
-			duckdbCmd := exec.Command("duckdb", ":memory:", "-init", initFile)
-			duckdbCmd.Stdin = os.Stdin
-			duckdbCmd.Stdout = os.Stdout
-			duckdbCmd.Stderr = os.Stderr
-
-			// DuckDBを実行
-			err = duckdbCmd.Run()
+			fmt.Print(output.String())
+			return nil
 		}
 
-		// 初期化ファイルも削除
-		os.Remove(initFile)
+		// 5. DuckDBのREPLを起動
+		verbose.Println(verbose.General, "DuckDBのREPLを起動中...")
+		verbose.Printf(verbose.General, "データベースのテーブル名: tickets, ビュー名: bodies (key, body)\n")
+		verbose.Printf(verbose.General, "使用例: SELECT * FROM tickets WHERE status = 'To Do';\n")
+		verbose.Printf(verbose.General, "使用例: SELECT t.key FROM tickets t JOIN bodies b USING (key) WHERE b.body ILIKE '%%timeout%%';\n")
+		verbose.Println(verbose.General, "終了するには .exit を入力してください")
 
-		// 6. 一時ファイルを削除
-		os.Remove(tempFile)
-		verbose.Printf("\n一時ファイルを削除しました: %s\n", tempFile)
+		// DuckDBコマンドを構築（初期化SQLファイルを読み込んでREPLを起動）
+		duckdbCmd := exec.Command("duckdb", ":memory:", "-init", initFile)
+		duckdbCmd.Stdin = os.Stdin
+		duckdbCmd.Stdout = os.Stdout
+		duckdbCmd.Stderr = os.Stderr
 
 		// DuckDBの正常終了（ユーザーが.exitで終了）は成功として扱う
-		if err != nil {
+		if err := runDuckDB(duckdbCmd); err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
 				// 終了コード0以外でも、ユーザーが意図的に終了した場合は成功とする
-				verbose.Printf("DuckDBが終了しました (exit code: %d)\n", exitError.ExitCode())
+				verbose.Printf(verbose.General, "DuckDBが終了しました (exit code: %d)\n", exitError.ExitCode())
 			} else {
 				return fmt.Errorf("DuckDBの実行に失敗しました: %v", err)
 			}
@@ -193,6 +203,77 @@ var queryCmd = &cobra.Command{
 	},
 }
 
+const (
+	// queryTempPrefix は query が一時ファイルを置くディレクトリの名前の接頭辞です
+	queryTempPrefix = "tkt-query-"
+	// queryTempMaxAge を過ぎた一時ディレクトリは、tktが強制終了されて残ったものとみなして削除します
+	queryTempMaxAge = 24 * time.Hour
+)
+
+// queryInitSQL は tickets テーブルと bodies ビューを作成するSQLを返します
+// bodies はマークダウンファイルを直接読み込み、本文 (フロントマターを除いた部分) を key と組にします。
+// key はファイル名ではなくフロントマターの key (tickets と同じ値) なので、ファイル名がキーと異なっても結合できます。
+func queryInitSQL(jsonPath, dir string) string {
+	glob := filepath.ToSlash(filepath.Join(dir, "**", "*.md"))
+	return fmt.Sprintf(`CREATE TABLE tickets AS SELECT * FROM read_json_auto(%s);
+CREATE VIEW bodies AS
+  SELECT t.key AS key, regexp_replace(f.content, '^---\n.*?\n---\n?', '', 's') AS body
+  FROM read_text(%s) f JOIN tickets t ON f.filename = t._file_path;
+`, sqlString(jsonPath), sqlString(glob))
+}
+
+// sqlString はSQLの文字列リテラルを返します
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// runDuckDB はDuckDBを実行し、終了するまで待ちます
+// tktが受け取った割り込みや終了のシグナルはDuckDBに渡し、tkt自体は終了せずに一時ファイルを削除できるようにします。
+func runDuckDB(c *exec.Cmd) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := c.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = c.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return c.Wait()
+}
+
+// removeStaleQueryTempDirs は以前の実行で削除できなかった一時ディレクトリ (tktが強制終了された場合など) を削除します
+// 実行中の他の query のものを消さないよう、queryTempMaxAge より古いものだけを対象にします。
+func removeStaleQueryTempDirs(tempDir string, now time.Time) {
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), queryTempPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < queryTempMaxAge {
+			continue
+		}
+		path := filepath.Join(tempDir, entry.Name())
+		if err := os.RemoveAll(path); err == nil {
+			verbose.Printf(verbose.General, "古い一時ディレクトリを削除しました: %s\n", path)
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(queryCmd)
 
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryInitSQL(t *testing.T) {
+	got := queryInitSQL("/tmp/tkt-query-1/tickets.json", "/home/me/it's/tickets")
+
+	assert.Contains(t, got, "CREATE TABLE tickets AS SELECT * FROM read_json_auto('/tmp/tkt-query-1/tickets.json');")
+	// 本文のファイルはglobで読み込み、フロントマターの key を持つ tickets とファイルのパスで結合する
+	assert.Contains(t, got, "FROM read_text('/home/me/it''s/tickets/**/*.md') f JOIN tickets t ON f.filename = t._file_path;")
+	assert.Contains(t, got, "CREATE VIEW bodies AS\n  SELECT t.key AS key,")
+}
+
+func TestRemoveStaleQueryTempDirs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	mkdir := func(name string, age time.Duration) {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(path, 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(path, "tickets.json"), []byte("[]"), 0644))
+		assert.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+	mkdir(queryTempPrefix+"stale", 2*queryTempMaxAge)
+	mkdir(queryTempPrefix+"running", time.Minute)
+	mkdir("other-stale", 2*queryTempMaxAge)
+
+	removeStaleQueryTempDirs(dir, now)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"other-stale", queryTempPrefix + "running"}, names)
+}
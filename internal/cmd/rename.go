@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renamePush bool
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename TICKET-KEY NEW-TITLE",
+	Short: "チケットのタイトルを変更します",
+	Long: `チケットのタイトルを変更します。
+ワークスペースにファイルがない場合はキャッシュからコピーしてから変更します。
+--push を指定すると、タイトルのみをJIRAに反映します。`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := args[0]
+		newTitle := strings.TrimSpace(args[1])
+		if newTitle == "" {
+			return fmt.Errorf("タイトルが空です")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		t, err := loadWorkspaceTicket(cfg, key)
+		if err != nil {
+			return err
+		}
+
+		oldTitle := t.Title
+		if oldTitle == newTitle {
+			fmt.Printf("%s: タイトルは変更されていません\n", key)
+		} else {
+			t.Title = newTitle
+			if _, err := t.SaveToFile(cfg.Directory, ticket.WithFilenameTemplate(filenameTemplate(cfg))); err != nil {
+				return fmt.Errorf("チケットの保存に失敗しました: %v", err)
+			}
+			fmt.Printf("%s: %s → %s\n", key, oldTitle, newTitle)
+		}
+
+		if !renamePush {
+			return nil
+		}
+
+		err = ui.WithSpinner("タイトルをJIRAに反映中...", func() error {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			return jiraClient.UpdateIssueFields(key, map[string]interface{}{
+				"summary": newTitle,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		// キャッシュにも反映してdiffが出ないようにする
+		if err := updateCachedTicket(key, func(t *ticket.Ticket) {
+			t.Title = newTitle
+		}); err != nil {
+			verbose.Printf(verbose.General, "警告: %v\n", err)
+		}
+
+		fmt.Printf("✅ %s のタイトルをJIRAに反映しました\n", key)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+
+	renameCmd.Flags().BoolVar(&renamePush, "push", false, "タイトルの変更をすぐにJIRAに反映する")
+}
@@ -1,10 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -13,7 +14,6 @@ import (
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
-	tty "github.com/mattn/go-tty"
 	"github.com/qawatake/tkt/internal/config"
 	"github.com/qawatake/tkt/internal/derrors"
 	"github.com/qawatake/tkt/internal/pkg/utils"
@@ -22,11 +22,24 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	rmDrafts bool
+	rmMatch  string
+	rmDryRun bool
+)
+
 var rmCmd = &cobra.Command{
 	Use:     "rm [ticket-key...]",
 	Aliases: []string{"remove", "delete"},
 	Short:   "ローカルのチケットを削除します",
-	Long:    `ローカルのチケットを削除します。引数なしの場合はインタラクティブに選択、引数ありの場合は指定されたチケットを削除します。`,
+	Long: `ローカルのチケットを削除します。引数なしの場合はインタラクティブに選択、引数ありの場合は指定されたチケットを削除します。
+
+--drafts で未pushのチケットをすべて、--match でタイトルがパターンに一致するチケットをまとめて削除します。
+両方を指定した場合は、タイトルが一致する未pushのチケットだけを削除します。
+未pushのチケットはファイルを削除し、JIRAキーのあるチケットは次のpushで削除されるようドットでマークします。`,
+	Example: `  tkt rm PRJ-123
+  tkt rm --drafts
+  tkt rm --match 'spike*' --dry-run`,
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		defer derrors.Wrap(&err)
 
@@ -35,6 +48,13 @@ var rmCmd = &cobra.Command{
 			return fmt.Errorf("設定の読み込みに失敗しました: %v", err)
 		}
 
+		if rmDrafts || rmMatch != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--drafts/--match とチケットキーは同時に指定できません")
+			}
+			return runSelectorRM(cfg)
+		}
+
 		if len(args) == 0 {
 			// インタラクティブモード
 			return runInteractiveRM(cfg)
@@ -57,7 +77,10 @@ func runInteractiveRM(cfg *config.Config) error {
 		return nil
 	}
 
-	tty, err := tty.Open()
+	tty, err := openInteractiveTTY()
+	if errors.Is(err, errNoTTY) {
+		return fmt.Errorf("TTYが利用できないため、インタラクティブに削除するチケットを選択できません。削除するチケットのキーを引数で指定してください (例: tkt rm PRJ-123)")
+	}
 	if err != nil {
 		return err
 	}
@@ -86,44 +109,116 @@ func runInteractiveRM(cfg *config.Config) error {
 		return nil
 	}
 
-	// 削除実行
-	return ui.WithSpinner("チケットを削除中...", func() error {
-		for _, item := range selectedTickets {
-			if err := deleteTicketWithPath(item); err != nil {
-				return fmt.Errorf("チケット %s の削除に失敗しました: %v", item.ticket.Key, err)
-			}
-		}
-		return nil
-	})
+	return deleteRMItems(selectedTickets)
 }
 
 func runDirectRM(cfg *config.Config, ticketKeys []string) error {
 	// 指定されたチケットを読み込み
 	var ticketItems []rmTicketItem
+	files := ticket.NewFileIndex(cfg.Directory)
 	for _, key := range ticketKeys {
-		filePath := filepath.Join(cfg.Directory, key+".md")
+		filePath, err := files.Find(key)
+		if err != nil {
+			return err
+		}
+		if filePath == "" {
+			filePath = filepath.Join(cfg.Directory, key+".md")
+		}
 		t, err := ticket.FromFile(filePath)
 		if err != nil {
 			return fmt.Errorf("チケット %s が見つかりません: %v", key, err)
 		}
-		// 未pushファイルの場合はキーを「DRAFT」として表示
-		displayKey := t.Key
-		if !utils.IsValidJIRAKey(t.Key) {
-			displayKey = "DRAFT"
+		ticketItems = append(ticketItems, newRMTicketItem(ticketWithPath{ticket: t, filePath: filePath}))
+	}
+
+	if rmDryRun {
+		printRMTargets(ticketItems)
+		return nil
+	}
+
+	return deleteRMItems(ticketItems)
+}
+
+// runSelectorRM は--drafts/--matchに一致するチケットを一覧表示し、確認してからまとめて削除します
+func runSelectorRM(cfg *config.Config) error {
+	ticketsWithPath, err := loadTicketsFromTmp(cfg.Directory)
+	if err != nil {
+		return fmt.Errorf("チケットの読み込みに失敗しました: %v", err)
+	}
+
+	items := selectRMTargets(ticketsWithPath, rmDrafts, rmMatch)
+	if len(items) == 0 {
+		fmt.Println("条件に一致するチケットが見つかりません")
+		return nil
+	}
+
+	printRMTargets(items)
+	if rmDryRun {
+		return nil
+	}
+	if !utils.PromptForConfirmation(fmt.Sprintf("%d 件のチケットを削除しますか？", len(items))) {
+		fmt.Println("削除がキャンセルされました")
+		return nil
+	}
+
+	return deleteRMItems(items)
+}
+
+// selectRMTargets はdraftsとpatternの条件をすべて満たすチケットを返します
+// draftsがtrueなら未pushのチケットのみ、patternが空でなければタイトルがglobに一致するチケットのみを選びます。
+func selectRMTargets(ticketsWithPath []ticketWithPath, drafts bool, pattern string) []rmTicketItem {
+	var items []rmTicketItem
+	for _, tp := range ticketsWithPath {
+		if drafts && utils.IsValidJIRAKey(tp.ticket.Key) {
+			continue
+		}
+		if pattern != "" && !matchTitleGlob(pattern, tp.ticket.Title) {
+			continue
 		}
+		items = append(items, newRMTicketItem(tp))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].filePath < items[j].filePath
+	})
+	return items
+}
+
+// matchTitleGlob はタイトルがglobパターン (* と ?) に一致するかを大文字小文字を区別せずに判定します
+// タイトルには / が含まれることがあるため、path.Match ではなく * が任意の文字列に一致する独自の判定を使います。
+func matchTitleGlob(pattern, title string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(title)
+}
 
-		ticketItems = append(ticketItems, rmTicketItem{
-			key:      displayKey,
-			title:    t.Title,
-			content:  t.Body,
-			ticket:   t,
-			filePath: filePath,
-		})
+// printRMTargets は削除対象と削除方法を一覧表示します
+func printRMTargets(items []rmTicketItem) {
+	fmt.Println("削除対象のチケット:")
+	keyWidth := rmKeyColumnWidth(items)
+	for _, item := range items {
+		action := "ファイルを削除"
+		if utils.IsValidJIRAKey(item.ticket.Key) {
+			action = "削除マーク (次のpushでJIRAから削除)"
+		}
+		fmt.Printf("  %s %s  [%s] %s\n", padRight(item.key, keyWidth), item.title, action, item.filePath)
 	}
+}
 
-	// 削除実行
+// deleteRMItems はチケットをまとめて削除します
+func deleteRMItems(items []rmTicketItem) error {
 	return ui.WithSpinner("チケットを削除中...", func() error {
-		for _, item := range ticketItems {
+		for _, item := range items {
 			if err := deleteTicketWithPath(item); err != nil {
 				return fmt.Errorf("チケット %s の削除に失敗しました: %v", item.key, err)
 			}
@@ -150,8 +245,9 @@ func deleteTicketWithPath(item rmTicketItem) error {
 	// チケットがJIRAキーを持つかどうかをチェック
 	if utils.IsValidJIRAKey(item.ticket.Key) {
 		// JIRAキー付きチケットの場合：ドットプレフィックスでマーク
+		// ファイル名 (filename_style: key-slug ならスラッグ付き) はそのままにドットを付ける
 		dir := filepath.Dir(item.filePath)
-		deletedPath := filepath.Join(dir, "."+item.ticket.Key+".md")
+		deletedPath := filepath.Join(dir, "."+filepath.Base(item.filePath))
 		return os.Rename(item.filePath, deletedPath)
 	} else {
 		// 一時ファイルの場合：実際のファイルパスを使って物理削除
@@ -159,35 +255,6 @@ func deleteTicketWithPath(item rmTicketItem) error {
 	}
 }
 
-func isValidJIRAKey(key string) bool {
-	// JIRAキーの形式をチェック (例: PRJ-123)
-	// プロジェクトキー-数字の形式
-	parts := strings.Split(key, "-")
-	if len(parts) != 2 {
-		return false
-	}
-
-	// プロジェクトキーが英字のみ、数字部分が数字のみかチェック
-	projectKey := parts[0]
-	issueNumber := parts[1]
-
-	// プロジェクトキーは英字のみ
-	for _, r := range projectKey {
-		if !((r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
-			return false
-		}
-	}
-
-	// 数字部分は数字のみ
-	for _, r := range issueNumber {
-		if !(r >= '0' && r <= '9') {
-			return false
-		}
-	}
-
-	return true
-}
-
 type ticketWithPath struct {
 	ticket   *ticket.Ticket
 	filePath string
@@ -196,34 +263,27 @@ type ticketWithPath struct {
 func loadTicketsFromTmp(ticketDir string) ([]ticketWithPath, error) {
 	var tickets []ticketWithPath
 
-	err := filepath.WalkDir(ticketDir, func(path string, d fs.DirEntry, err error) error {
+	// ドットで始まるファイル（既に削除マークされたもの）と .tktignore に一致するファイルはスキップ
+	files, _, err := ticket.WalkFiles(ticketDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		t, err := ticket.FromFile(path)
 		if err != nil {
-			return err
+			// エラーは無視してスキップ
+			continue
 		}
-		if !d.IsDir() && strings.HasSuffix(path, ".md") {
-			// ドットで始まるファイル（既に削除マークされたもの）はスキップ
-			filename := filepath.Base(path)
-			if strings.HasPrefix(filename, ".") {
-				return nil
-			}
-
-			t, err := ticket.FromFile(path)
-			if err != nil {
-				// エラーは無視してスキップ
-				return nil
-			}
-			// 有効なチケット（keyまたはtitleが存在）のみを追加
-			if t.Key != "" || t.Title != "" {
-				tickets = append(tickets, ticketWithPath{
-					ticket:   t,
-					filePath: path,
-				})
-			}
+		// 有効なチケット（keyまたはtitleが存在）のみを追加
+		if t.Key != "" || t.Title != "" {
+			tickets = append(tickets, ticketWithPath{
+				ticket:   t,
+				filePath: path,
+			})
 		}
-		return nil
-	})
+	}
 
-	return tickets, err
+	return tickets, nil
 }
 
 var (
@@ -259,7 +319,8 @@ type rmModel struct {
 	width         int
 	height        int
 	ticketDir     string
-	selectedMap   map[int]bool // 選択状態を追跡
+	selectedMap   map[int]bool      // 選択状態を追跡
+	parents       *parentTitleIndex // 親チケットのタイトルの索引 (親チケットの表示に使用)
 	cancelled     bool
 }
 
@@ -271,6 +332,22 @@ type rmTicketItem struct {
 	filePath string
 }
 
+// newRMTicketItem は削除候補の項目を作成します
+func newRMTicketItem(tp ticketWithPath) rmTicketItem {
+	// 未pushファイルの場合はキーを「DRAFT」として表示
+	displayKey := tp.ticket.Key
+	if !utils.IsValidJIRAKey(tp.ticket.Key) {
+		displayKey = "DRAFT"
+	}
+	return rmTicketItem{
+		key:      displayKey,
+		title:    tp.ticket.Title,
+		content:  tp.ticket.Body,
+		ticket:   tp.ticket,
+		filePath: tp.filePath,
+	}
+}
+
 func newRMModel(ticketsWithPath []ticketWithPath, ticketDir string) (_ *rmModel, err error) {
 	defer derrors.Wrap(&err)
 	input := textinput.New()
@@ -306,25 +383,15 @@ func newRMModel(ticketsWithPath []ticketWithPath, ticketDir string) (_ *rmModel,
 	})
 
 	var items []rmTicketItem
+	tickets := make([]*ticket.Ticket, 0, len(ticketsWithPath))
 	for _, tp := range ticketsWithPath {
+		tickets = append(tickets, tp.ticket)
 		// 空のチケット（keyもtitleも空）をスキップ
 		if tp.ticket.Key == "" && tp.ticket.Title == "" {
 			continue
 		}
 
-		// 未pushファイルの場合はキーを「DRAFT」として表示
-		displayKey := tp.ticket.Key
-		if !utils.IsValidJIRAKey(tp.ticket.Key) {
-			displayKey = "DRAFT"
-		}
-
-		items = append(items, rmTicketItem{
-			key:      displayKey,
-			title:    tp.ticket.Title,
-			content:  tp.ticket.Body,
-			ticket:   tp.ticket,
-			filePath: tp.filePath,
-		})
+		items = append(items, newRMTicketItem(tp))
 	}
 
 	model := &rmModel{
@@ -336,6 +403,7 @@ func newRMModel(ticketsWithPath []ticketWithPath, ticketDir string) (_ *rmModel,
 		cursor:        0,
 		ticketDir:     ticketDir,
 		selectedMap:   make(map[int]bool),
+		parents:       newParentTitleIndex(tickets),
 	}
 
 	// 初期状態で最初のファイルを確実に選択
@@ -509,12 +577,10 @@ func (m *rmModel) View() string {
 	}
 
 	searchLine := fmt.Sprintf("検索: %s", m.searchQuery)
-	if selectedCount > 0 {
-		searchLine += fmt.Sprintf(" (選択中: %d)", selectedCount)
-	}
+	countLine := rmHelpStyle.Render(fmt.Sprintf("%s, %d selected", ticketCountLabel(len(m.filteredItems), len(m.tickets)), selectedCount))
 
 	helpLine := rmHelpStyle.Render("Tab: 選択/解除  Enter: 削除実行  Esc: キャンセル")
-	header := lipgloss.JoinVertical(lipgloss.Left, searchLine, helpLine)
+	header := lipgloss.JoinVertical(lipgloss.Left, searchLine, countLine, helpLine)
 
 	if len(m.filteredItems) == 0 {
 		emptyMsg := lipgloss.NewStyle().
@@ -566,6 +632,20 @@ func (m *rmModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, header, body)
 }
 
+// keyColumnWidth は一覧のキーの列の幅を返します
+// 絞り込んでも列の幅が変わらないように、すべてのチケットのキーから決めます。
+func (m *rmModel) keyColumnWidth() int {
+	return rmKeyColumnWidth(m.tickets)
+}
+
+func rmKeyColumnWidth(items []rmTicketItem) int {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.key)
+	}
+	return keyColumnWidth(keys)
+}
+
 func (m *rmModel) renderLeftPane(width, height int) string {
 	var items []string
 
@@ -574,6 +654,7 @@ func (m *rmModel) renderLeftPane(width, height int) string {
 		start = m.cursor - height + 1
 	}
 
+	keyWidth := m.keyColumnWidth()
 	for i := start; i < start+height && i < len(m.filteredItems); i++ {
 		item := m.filteredItems[i]
 
@@ -592,8 +673,8 @@ func (m *rmModel) renderLeftPane(width, height int) string {
 			checkbox = "[✓]"
 		}
 
-		// キーを固定幅で左詰めパディング（DRAFTやJIRAキーに対応）
-		keyPadded := fmt.Sprintf("%-8s", item.key)
+		// キーを表示幅で左詰めパディング（DRAFTや長いJIRAキーに対応）
+		keyPadded := padRight(item.key, keyWidth)
 		line := fmt.Sprintf("%s %s", checkbox, keyPadded)
 
 		// タイトルがある場合は表示
@@ -698,7 +779,7 @@ func (m *rmModel) renderRightPane(width, height int) string {
 		if selectedTicket.ParentKey != "" {
 			items = append(items, fmt.Sprintf("%s: %s",
 				frontmatterStyle.Render("Parent"),
-				valueStyle.Render(selectedTicket.ParentKey)))
+				valueStyle.Render(parentLabel(selectedTicket, m.parents))))
 		} else {
 			items = append(items, fmt.Sprintf("%s: %s",
 				frontmatterStyle.Render("Parent"),
@@ -755,4 +836,8 @@ func (m *rmModel) SelectedTickets() []rmTicketItem {
 
 func init() {
 	rootCmd.AddCommand(rmCmd)
+
+	rmCmd.Flags().BoolVar(&rmDrafts, "drafts", false, "未pushのチケットをすべて削除")
+	rmCmd.Flags().StringVar(&rmMatch, "match", "", "タイトルがglobパターンに一致するチケットを削除 (例: 'spike*')")
+	rmCmd.Flags().BoolVar(&rmDryRun, "dry-run", false, "削除せずに対象のチケットを表示")
 }
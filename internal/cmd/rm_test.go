@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTitleGlob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		title   string
+		want    bool
+	}{
+		{name: "前方一致", pattern: "spike*", title: "spike: 検索の高速化", want: true},
+		{name: "大文字小文字を区別しない", pattern: "spike*", title: "Spike API", want: true},
+		{name: "スラッシュを含むタイトル", pattern: "*a/b*", title: "fix a/b test", want: true},
+		{name: "1文字", pattern: "v?", title: "v2", want: true},
+		{name: "一致しない", pattern: "spike*", title: "fix spike", want: false},
+		{name: "正規表現の記号はそのまま比較", pattern: "[WIP]*", title: "W draft", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchTitleGlob(tt.pattern, tt.title))
+		})
+	}
+}
+
+func TestSelectRMTargets(t *testing.T) {
+	tickets := []ticketWithPath{
+		{ticket: &ticket.Ticket{Key: "PRJ-1", Title: "spike: keyed"}, filePath: "tickets/PRJ-1.md"},
+		{ticket: &ticket.Ticket{Title: "spike: draft"}, filePath: "tickets/TMP-1.md"},
+		{ticket: &ticket.Ticket{Title: "other draft"}, filePath: "tickets/TMP-2.md"},
+	}
+
+	paths := func(items []rmTicketItem) []string {
+		var result []string
+		for _, item := range items {
+			result = append(result, item.filePath)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		drafts  bool
+		pattern string
+		want    []string
+	}{
+		{name: "未pushのみ", drafts: true, want: []string{"tickets/TMP-1.md", "tickets/TMP-2.md"}},
+		{name: "タイトルのみ", pattern: "spike*", want: []string{"tickets/PRJ-1.md", "tickets/TMP-1.md"}},
+		{name: "両方", drafts: true, pattern: "spike*", want: []string{"tickets/TMP-1.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, paths(selectRMTargets(tickets, tt.drafts, tt.pattern)))
+		})
+	}
+}
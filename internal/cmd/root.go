@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/qawatake/tkt/internal/extension"
+	"github.com/qawatake/tkt/internal/jira"
 	"github.com/qawatake/tkt/internal/verbose"
 	"github.com/spf13/cobra"
 )
@@ -14,22 +18,57 @@ var rootCmd = &cobra.Command{
 	Use:   "tkt",
 	Short: "JIRAチケットローカル同期CLI",
 	Long:  `tktはJIRAチケットをローカルで編集し、それをリモートと同期するCLIツールです。`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return verbose.SetDomains(debugDomains)
+	},
 }
 
+// debugDomains は --debug で指定されたデバッグ出力の分類です
+var debugDomains []string
+
 // Execute executes the root command.
+// Ctrl-C (SIGINT) と SIGTERM でコマンドのcontextをキャンセルします。
 func Execute() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// 2回目のCtrl-Cではキャンセルの完了を待たずに終了できるよう、シグナルの扱いを元に戻す
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := execute(ctx)
+
+	// verboseモードではAPI呼び出し回数のサマリーを表示する
+	if summary := jira.APICallSummary(); summary != "" {
+		verbose.Println(verbose.API, summary)
+	}
+	return err
+}
+
+func execute(ctx context.Context) error {
 	// Parse arguments to find the actual command after flags
 	args := os.Args[1:]
 	commandIndex := -1
 
 	// Skip flags to find the actual command
+	skipNext := false
 	for i, arg := range args {
+		if skipNext {
+			skipNext = false
+			continue
+		}
 		if !strings.HasPrefix(arg, "-") {
 			commandIndex = i
 			break
 		}
 		// Skip flag values - simple approach for common cases
-		if arg == "-v" || arg == "--verbose" {
+		if arg == "-v" || arg == "-vv" || arg == "--verbose" {
+			continue
+		}
+		if arg == "--debug" {
+			// 次の引数は分類の値なので、コマンドとして扱わない
+			skipNext = true
 			continue
 		}
 		if strings.Contains(arg, "=") {
@@ -46,7 +85,7 @@ func Execute() error {
 		cmd, _, err := rootCmd.Find([]string{subCmd})
 		if err == nil && cmd != rootCmd {
 			// It's a known subcommand, execute normally
-			return rootCmd.Execute()
+			return rootCmd.ExecuteContext(ctx)
 		}
 
 		// Try to execute as extension
@@ -58,11 +97,22 @@ func Execute() error {
 	}
 
 	// Default behavior
-	return rootCmd.Execute()
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// cancelledError はCtrl-Cでコマンドを中断したことを表します
+// context.Canceled としても扱えるので、mainはスタックトレースを表示せずにメッセージのみを表示します。
+type cancelledError struct {
+	message string
 }
 
+func (e *cancelledError) Error() string { return e.message }
+
+func (e *cancelledError) Unwrap() error { return context.Canceled }
+
 func init() {
-	rootCmd.PersistentFlags().BoolVarP(&verbose.Enabled, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().CountVarP(&verbose.Level, "verbose", "v", "enable verbose output (-vv also dumps API request and response bodies)")
+	rootCmd.PersistentFlags().StringSliceVar(&debugDomains, "debug", nil, "show verbose output only for these domains (general, api, cache, diff), e.g. --debug api,diff")
 
 	// Custom help template that includes extensions
 	rootCmd.SetHelpTemplate(getHelpTemplate())
@@ -0,0 +1,496 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sprintStatusJSON bool
+	sprintStatusBy   string
+	sprintRemovePush bool
+)
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "スプリントに関する操作を行います",
+	Long:  `スプリントに関する操作を行います。`,
+}
+
+var sprintStatusCmd = &cobra.Command{
+	Use:   "status [SPRINT]",
+	Short: "スプリントの進捗状況を表示します",
+	Long: `スプリントの進捗状況を表示します。
+スプリント名を省略した場合はボードのアクティブなスプリントを対象とします。
+キャッシュにあるチケットに加えて、設定のJQLの範囲外にあるスプリント内のチケットも取得して集計します。
+--by team で担当者別の代わりにチーム別 (Teamフィールド) の集計を表示します。
+見積もり時間 (original_estimate) のないチケットは、ストーリーポイントのカスタムフィールドを設定していればポイントで集計します。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		if sprintStatusBy != "assignee" && sprintStatusBy != "team" {
+			return fmt.Errorf("無効な集計の項目です: %s (assignee, team のいずれかを指定してください)", sprintStatusBy)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		type sprintResult struct {
+			sprint  *jira.Sprint
+			tickets []*ticket.Ticket
+		}
+
+		result, err := ui.WithSpinnerValue("スプリント情報を取得中...", func() (sprintResult, error) {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return sprintResult{}, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+
+			// 対象スプリントを決定
+			var sprint *jira.Sprint
+			if len(args) > 0 {
+				sprint, err = jiraClient.FindSprintByName(cmd.Context(), args[0])
+			} else {
+				sprint, err = jiraClient.FindActiveSprint(cmd.Context())
+			}
+			if err != nil {
+				return sprintResult{}, err
+			}
+
+			// キャッシュからスプリントに含まれるチケットを集める
+			cachedTickets, err := loadTickets(cacheDir, false)
+			if err != nil {
+				return sprintResult{}, fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+			}
+			ticketsByKey := make(map[string]*ticket.Ticket)
+			for _, t := range cachedTickets {
+				if t.SprintName == sprint.Name {
+					ticketsByKey[t.Key] = t
+				}
+			}
+
+			// JQLの範囲外のチケットを補完する
+			remoteTickets, err := jiraClient.FetchSprintIssues(cmd.Context(), sprint.ID)
+			if err != nil {
+				return sprintResult{}, fmt.Errorf("スプリントのチケット取得に失敗しました: %v", err)
+			}
+			for _, t := range remoteTickets {
+				if _, ok := ticketsByKey[t.Key]; !ok {
+					verbose.Printf(verbose.General, "キャッシュにないチケットを追加: %s\n", t.Key)
+					ticketsByKey[t.Key] = t
+				}
+			}
+
+			tickets := make([]*ticket.Ticket, 0, len(ticketsByKey))
+			for _, t := range ticketsByKey {
+				tickets = append(tickets, t)
+			}
+			return sprintResult{sprint: sprint, tickets: tickets}, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		report := newSprintReport(result.sprint, result.tickets, storyPointsKey(cfg))
+		report.by = sprintStatusBy
+		if sprintStatusJSON {
+			b, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+		fmt.Print(report.String())
+		return nil
+	},
+}
+
+var sprintRemoveCmd = &cobra.Command{
+	Use:   "remove TICKET-KEY...",
+	Short: "チケットをスプリントから外します",
+	Long: `チケットをスプリントから外してバックログに戻します。
+ワークスペースのチケットのsprintを空にします。pushするとJIRAのスプリントからも外れます。
+--push を指定すると、スプリントから外す変更のみをすぐにJIRAに反映します。`,
+	Example: `  tkt sprint remove PRJ-123
+  tkt sprint remove PRJ-123 PRJ-124 --push`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		for _, key := range args {
+			t, err := loadWorkspaceTicket(cfg, key)
+			if err != nil {
+				return err
+			}
+			if t.SprintName == "" {
+				fmt.Printf("%s: スプリントに入っていません\n", key)
+				continue
+			}
+			oldSprint := t.SprintName
+			t.SprintName = ""
+			if _, err := t.SaveToFile(cfg.Directory, ticket.WithFilenameTemplate(filenameTemplate(cfg))); err != nil {
+				return fmt.Errorf("チケットの保存に失敗しました: %v", err)
+			}
+			fmt.Printf("%s: スプリント %s → バックログ\n", key, oldSprint)
+		}
+
+		if !sprintRemovePush {
+			return nil
+		}
+
+		err = ui.WithSpinner("スプリントから外す変更をJIRAに反映中...", func() error {
+			jiraClient, err := jira.NewClient(cfg)
+			if err != nil {
+				return fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+			}
+			for _, key := range args {
+				if err := jiraClient.RemoveFromSprint(key); err != nil {
+					return fmt.Errorf("%s をスプリントから外せませんでした: %v", key, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// キャッシュにも反映してdiffが出ないようにする
+		for _, key := range args {
+			if err := updateCachedTicket(key, func(t *ticket.Ticket) {
+				t.SprintName = ""
+			}); err != nil {
+				verbose.Printf(verbose.General, "警告: %v\n", err)
+			}
+		}
+
+		fmt.Printf("✅ %d 件のチケットをJIRAのスプリントから外しました\n", len(args))
+		return nil
+	},
+}
+
+// statusCategoryOrder はステータスカテゴリの表示順です
+var statusCategoryOrder = []string{"new", "indeterminate", "done", ""}
+
+// statusCategoryLabel はステータスカテゴリの表示名を返します
+func statusCategoryLabel(key string) string {
+	switch key {
+	case "new":
+		return "To Do"
+	case "indeterminate":
+		return "In Progress"
+	case "done":
+		return "Done"
+	default:
+		return "Unknown"
+	}
+}
+
+type sprintReport struct {
+	Sprint         sprintReportSprint `json:"sprint"`
+	TotalCount     int                `json:"total_count"`
+	TotalHours     float64            `json:"total_hours"`
+	CompletedHours float64            `json:"completed_hours"`
+	RemainingHours float64            `json:"remaining_hours"`
+	CompletionRate float64            `json:"completion_rate"`
+	// TotalPoints などは見積もり時間のないチケットのストーリーポイントの集計です
+	TotalPoints     float64                `json:"total_points"`
+	CompletedPoints float64                `json:"completed_points"`
+	RemainingPoints float64                `json:"remaining_points"`
+	Groups          []sprintReportGroup    `json:"groups"`
+	Assignees       []sprintReportAssignee `json:"assignees"`
+	// Teams はチーム別の集計です (チームが設定されたチケットがない場合は出力しません)
+	Teams []sprintReportTeam `json:"teams,omitempty"`
+
+	by string // テキストで表示する集計 (assignee または team)
+}
+
+type sprintReportSprint struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type sprintReportGroup struct {
+	StatusCategory string               `json:"status_category"`
+	Label          string               `json:"label"`
+	Count          int                  `json:"count"`
+	Hours          float64              `json:"hours"`
+	Points         float64              `json:"points"`
+	Tickets        []sprintReportTicket `json:"tickets"`
+}
+
+type sprintReportTicket struct {
+	Key      string  `json:"key"`
+	Title    string  `json:"title"`
+	Status   string  `json:"status"`
+	Assignee string  `json:"assignee"`
+	Hours    float64 `json:"hours"`
+	Points   float64 `json:"points"`
+}
+
+type sprintReportAssignee struct {
+	Assignee        string  `json:"assignee"`
+	Count           int     `json:"count"`
+	CompletedHours  float64 `json:"completed_hours"`
+	RemainingHours  float64 `json:"remaining_hours"`
+	CompletedPoints float64 `json:"completed_points"`
+	RemainingPoints float64 `json:"remaining_points"`
+}
+
+type sprintReportTeam struct {
+	Team            string  `json:"team"`
+	Count           int     `json:"count"`
+	CompletedHours  float64 `json:"completed_hours"`
+	RemainingHours  float64 `json:"remaining_hours"`
+	CompletedPoints float64 `json:"completed_points"`
+	RemainingPoints float64 `json:"remaining_points"`
+}
+
+// storyPointsKey は設定のカスタムフィールドのうち、ストーリーポイントのフロントマターのキーを返します
+// "Story Points" や "Story point estimate" のように名前に story point を含む数値のフィールドを探します (ない場合は空文字)。
+func storyPointsKey(cfg *config.Config) string {
+	for _, f := range cfg.Issue.Fields.Custom {
+		if !f.IsNumber() {
+			continue
+		}
+		if strings.Contains(strings.ToLower(f.Name), "story point") || f.FrontmatterKey() == "story_points" {
+			return f.FrontmatterKey()
+		}
+	}
+	return ""
+}
+
+// sprintEstimate はチケットの見積もりを返します
+// 見積もり時間 (original_estimate) がない場合のみ、pointsKeyのカスタムフィールドのストーリーポイントを返します。
+func sprintEstimate(t *ticket.Ticket, pointsKey string) (hours, points float64) {
+	if t.OriginalEstimate > 0 {
+		return float64(t.OriginalEstimate), 0
+	}
+	if pointsKey == "" {
+		return 0, 0
+	}
+	if value := t.CustomFields[pointsKey]; value != nil {
+		return 0, *value
+	}
+	return 0, 0
+}
+
+// newSprintReport はスプリントのチケットを集計します
+// 見積もり時間のないチケットは、pointsKeyのカスタムフィールドのストーリーポイントを別に集計します。
+func newSprintReport(sprint *jira.Sprint, tickets []*ticket.Ticket, pointsKey string) *sprintReport {
+	report := &sprintReport{
+		Sprint: sprintReportSprint{
+			ID:        sprint.ID,
+			Name:      sprint.Name,
+			State:     sprint.State,
+			StartDate: sprint.StartDate,
+			EndDate:   sprint.EndDate,
+		},
+		TotalCount: len(tickets),
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		return tickets[i].Key < tickets[j].Key
+	})
+
+	groups := make(map[string]*sprintReportGroup)
+	assignees := make(map[string]*sprintReportAssignee)
+	teams := make(map[string]*sprintReportTeam)
+	hasTeam := false
+	for _, t := range tickets {
+		hours, points := sprintEstimate(t, pointsKey)
+		done := t.StatusCategory == "done"
+
+		group, ok := groups[t.StatusCategory]
+		if !ok {
+			group = &sprintReportGroup{
+				StatusCategory: t.StatusCategory,
+				Label:          statusCategoryLabel(t.StatusCategory),
+			}
+			groups[t.StatusCategory] = group
+		}
+		group.Count++
+		group.Hours += hours
+		group.Points += points
+		group.Tickets = append(group.Tickets, sprintReportTicket{
+			Key:      t.Key,
+			Title:    t.Title,
+			Status:   t.Status,
+			Assignee: t.Assignee,
+			Hours:    hours,
+			Points:   points,
+		})
+
+		name := t.Assignee
+		if name == "" {
+			name = "(未割り当て)"
+		}
+		assignee, ok := assignees[name]
+		if !ok {
+			assignee = &sprintReportAssignee{Assignee: name}
+			assignees[name] = assignee
+		}
+		assignee.Count++
+
+		teamName := t.Team
+		if teamName == "" {
+			teamName = "(チームなし)"
+		} else {
+			hasTeam = true
+		}
+		team, ok := teams[teamName]
+		if !ok {
+			team = &sprintReportTeam{Team: teamName}
+			teams[teamName] = team
+		}
+		team.Count++
+
+		report.TotalHours += hours
+		report.TotalPoints += points
+		if done {
+			report.CompletedHours += hours
+			assignee.CompletedHours += hours
+			team.CompletedHours += hours
+			report.CompletedPoints += points
+			assignee.CompletedPoints += points
+			team.CompletedPoints += points
+		} else {
+			report.RemainingHours += hours
+			assignee.RemainingHours += hours
+			team.RemainingHours += hours
+			report.RemainingPoints += points
+			assignee.RemainingPoints += points
+			team.RemainingPoints += points
+		}
+	}
+
+	// 完了率は見積もり時間で求め、見積もり時間がひとつもない場合はストーリーポイントで求める
+	if report.TotalHours > 0 {
+		report.CompletionRate = report.CompletedHours / report.TotalHours * 100
+	} else if report.TotalPoints > 0 {
+		report.CompletionRate = report.CompletedPoints / report.TotalPoints * 100
+	}
+
+	for _, key := range statusCategoryOrder {
+		if group, ok := groups[key]; ok {
+			report.Groups = append(report.Groups, *group)
+		}
+	}
+
+	for _, assignee := range assignees {
+		report.Assignees = append(report.Assignees, *assignee)
+	}
+	sort.Slice(report.Assignees, func(i, j int) bool {
+		return report.Assignees[i].Assignee < report.Assignees[j].Assignee
+	})
+
+	// Teamフィールドのないインスタンスではチーム別の集計を出力しない
+	if hasTeam {
+		for _, team := range teams {
+			report.Teams = append(report.Teams, *team)
+		}
+		sort.Slice(report.Teams, func(i, j int) bool {
+			return report.Teams[i].Team < report.Teams[j].Team
+		})
+	}
+
+	return report
+}
+
+// String はスプリントの集計結果をテキスト形式で返します
+func (r *sprintReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "🏃 %s (%s)", r.Sprint.Name, r.Sprint.State)
+	if r.Sprint.StartDate != "" || r.Sprint.EndDate != "" {
+		fmt.Fprintf(&b, " %s 〜 %s", shortDate(r.Sprint.StartDate), shortDate(r.Sprint.EndDate))
+	}
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "進捗: %.1f%% (完了 %.1fh / 残り %.1fh / 合計 %.1fh, %d件)\n",
+		r.CompletionRate, r.CompletedHours, r.RemainingHours, r.TotalHours, r.TotalCount)
+	if r.TotalPoints > 0 {
+		fmt.Fprintf(&b, "ストーリーポイント: 完了 %.1fpt / 残り %.1fpt / 合計 %.1fpt\n", r.CompletedPoints, r.RemainingPoints, r.TotalPoints)
+	}
+
+	for _, group := range r.Groups {
+		fmt.Fprintf(&b, "\n[%s] %d件 %s\n", group.Label, group.Count, formatSprintEstimate(group.Hours, group.Points))
+		for _, t := range group.Tickets {
+			fmt.Fprintf(&b, "  %-10s %s (%s, %s)\n", t.Key, t.Title, t.Status, formatSprintEstimate(t.Hours, t.Points))
+		}
+	}
+
+	if r.by == "team" {
+		b.WriteString("\nチーム別:\n")
+		if len(r.Teams) == 0 {
+			b.WriteString("  チームが設定されたチケットはありません\n")
+		}
+		for _, t := range r.Teams {
+			fmt.Fprintf(&b, "  %s: %d件 完了 %s / 残り %s\n", t.Team, t.Count,
+				formatSprintEstimate(t.CompletedHours, t.CompletedPoints), formatSprintEstimate(t.RemainingHours, t.RemainingPoints))
+		}
+	} else if len(r.Assignees) > 0 {
+		b.WriteString("\n担当者別:\n")
+		for _, a := range r.Assignees {
+			fmt.Fprintf(&b, "  %s: %d件 完了 %s / 残り %s\n", a.Assignee, a.Count,
+				formatSprintEstimate(a.CompletedHours, a.CompletedPoints), formatSprintEstimate(a.RemainingHours, a.RemainingPoints))
+		}
+	}
+
+	return b.String()
+}
+
+// formatSprintEstimate は見積もり時間を表示し、ストーリーポイントがあれば併記します (例: 3.0h, 3.0h + 5.0pt)
+func formatSprintEstimate(hours, points float64) string {
+	if points == 0 {
+		return fmt.Sprintf("%.1fh", hours)
+	}
+	if hours == 0 {
+		return fmt.Sprintf("%.1fpt", points)
+	}
+	return fmt.Sprintf("%.1fh + %.1fpt", hours, points)
+}
+
+// shortDate はJIRAの日時文字列から日付部分のみを取り出します
+func shortDate(s string) string {
+	if len(s) >= len("2006-01-02") {
+		return s[:len("2006-01-02")]
+	}
+	return s
+}
+
+func init() {
+	sprintCmd.AddCommand(sprintStatusCmd)
+	sprintCmd.AddCommand(sprintRemoveCmd)
+	rootCmd.AddCommand(sprintCmd)
+
+	sprintStatusCmd.Flags().BoolVar(&sprintStatusJSON, "json", false, "JSON形式で出力")
+	sprintStatusCmd.Flags().StringVar(&sprintStatusBy, "by", "assignee", "集計の項目 (assignee, team)")
+	sprintRemoveCmd.Flags().BoolVar(&sprintRemovePush, "push", false, "スプリントから外す変更をすぐにJIRAに反映する")
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSprintReport(t *testing.T) {
+	points := func(v float64) map[string]*float64 { return map[string]*float64{"story_points": &v} }
+	sprint := &jira.Sprint{ID: 1, Name: "Sprint 1", State: "active"}
+
+	tests := []struct {
+		name          string
+		tickets       []*ticket.Ticket
+		pointsKey     string
+		wantGroups    []sprintReportGroup
+		wantTotal     [3]float64 // 合計・完了・残りの時間
+		wantPoints    [3]float64 // 合計・完了・残りのポイント
+		wantRate      float64
+		wantAssignees []sprintReportAssignee
+	}{
+		{
+			name: "ステータスカテゴリごとにまとめて見積もり時間を合計する",
+			tickets: []*ticket.Ticket{
+				{Key: "PRJ-3", StatusCategory: "done", Assignee: "alice", OriginalEstimate: 3},
+				{Key: "PRJ-1", StatusCategory: "new", Assignee: "bob", OriginalEstimate: 2},
+				{Key: "PRJ-2", StatusCategory: "indeterminate", Assignee: "alice", OriginalEstimate: 4},
+				{Key: "PRJ-4", StatusCategory: "done", OriginalEstimate: 1},
+				{Key: "PRJ-5", StatusCategory: ""},
+			},
+			wantGroups: []sprintReportGroup{
+				{StatusCategory: "new", Count: 1, Hours: 2},
+				{StatusCategory: "indeterminate", Count: 1, Hours: 4},
+				{StatusCategory: "done", Count: 2, Hours: 4},
+				{StatusCategory: "", Count: 1},
+			},
+			wantTotal: [3]float64{10, 4, 6},
+			wantRate:  40,
+			wantAssignees: []sprintReportAssignee{
+				{Assignee: "(未割り当て)", Count: 2, CompletedHours: 1},
+				{Assignee: "alice", Count: 2, CompletedHours: 3, RemainingHours: 4},
+				{Assignee: "bob", Count: 1, RemainingHours: 2},
+			},
+		},
+		{
+			name: "見積もり時間がない場合はストーリーポイントで集計する",
+			tickets: []*ticket.Ticket{
+				{Key: "PRJ-1", StatusCategory: "done", Assignee: "alice", CustomFields: points(5)},
+				{Key: "PRJ-2", StatusCategory: "new", Assignee: "alice", CustomFields: points(3)},
+				// 見積もり時間がある場合はストーリーポイントを使わない
+				{Key: "PRJ-3", StatusCategory: "new", Assignee: "bob", OriginalEstimate: 2, CustomFields: points(8)},
+			},
+			pointsKey: "story_points",
+			wantGroups: []sprintReportGroup{
+				{StatusCategory: "new", Count: 2, Hours: 2, Points: 3},
+				{StatusCategory: "done", Count: 1, Points: 5},
+			},
+			wantTotal:  [3]float64{2, 0, 2},
+			wantPoints: [3]float64{8, 5, 3},
+			wantRate:   0,
+			wantAssignees: []sprintReportAssignee{
+				{Assignee: "alice", Count: 2, CompletedPoints: 5, RemainingPoints: 3},
+				{Assignee: "bob", Count: 1, RemainingHours: 2},
+			},
+		},
+		{
+			name: "見積もり時間がひとつもない場合は完了率をストーリーポイントで求める",
+			tickets: []*ticket.Ticket{
+				{Key: "PRJ-1", StatusCategory: "done", Assignee: "alice", CustomFields: points(1)},
+				{Key: "PRJ-2", StatusCategory: "indeterminate", Assignee: "alice", CustomFields: points(3)},
+			},
+			pointsKey: "story_points",
+			wantGroups: []sprintReportGroup{
+				{StatusCategory: "indeterminate", Count: 1, Points: 3},
+				{StatusCategory: "done", Count: 1, Points: 1},
+			},
+			wantPoints: [3]float64{4, 1, 3},
+			wantRate:   25,
+			wantAssignees: []sprintReportAssignee{
+				{Assignee: "alice", Count: 2, CompletedPoints: 1, RemainingPoints: 3},
+			},
+		},
+		{
+			name: "ストーリーポイントのフィールドを設定していない場合は集計しない",
+			tickets: []*ticket.Ticket{
+				{Key: "PRJ-1", StatusCategory: "done", Assignee: "alice", CustomFields: points(5)},
+			},
+			wantGroups: []sprintReportGroup{
+				{StatusCategory: "done", Count: 1},
+			},
+			wantAssignees: []sprintReportAssignee{
+				{Assignee: "alice", Count: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := newSprintReport(sprint, tt.tickets, tt.pointsKey)
+
+			var groups []sprintReportGroup
+			for _, g := range report.Groups {
+				groups = append(groups, sprintReportGroup{StatusCategory: g.StatusCategory, Count: g.Count, Hours: g.Hours, Points: g.Points})
+			}
+			assert.Equal(t, tt.wantGroups, groups)
+			assert.Equal(t, len(tt.tickets), report.TotalCount)
+			assert.Equal(t, tt.wantTotal, [3]float64{report.TotalHours, report.CompletedHours, report.RemainingHours})
+			assert.Equal(t, tt.wantPoints, [3]float64{report.TotalPoints, report.CompletedPoints, report.RemainingPoints})
+			assert.InDelta(t, tt.wantRate, report.CompletionRate, 0.001)
+			assert.Equal(t, tt.wantAssignees, report.Assignees)
+		})
+	}
+}
+
+func TestStoryPointsKey(t *testing.T) {
+	textField := func(name string) config.CustomField {
+		f := config.CustomField{Name: name}
+		f.Schema.Datatype = "string"
+		return f
+	}
+
+	tests := []struct {
+		name   string
+		fields []config.CustomField
+		want   string
+	}{
+		{name: "Story Points", fields: []config.CustomField{{Name: "Rank"}, {Name: "Story Points"}}, want: "story_points"},
+		{name: "Story point estimate", fields: []config.CustomField{{Name: "Story point estimate"}}, want: "story_point_estimate"},
+		{name: "フロントマターのキーを指定", fields: []config.CustomField{{Name: "見積もり", Frontmatter: "story_points"}}, want: "story_points"},
+		{name: "数値でないフィールドは使わない", fields: []config.CustomField{textField("Story Points Note")}, want: ""},
+		{name: "なし", fields: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Issue.Fields.Custom = tt.fields
+			assert.Equal(t, tt.want, storyPointsKey(cfg))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+// exitCodeStatusPending はpushしていない変更がある場合の終了コードです
+const exitCodeStatusPending = 1
+
+var statusPorcelain bool
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "ワークスペースの変更の状況を表示します",
+	Long: `git status のように、ワークスペースとキャッシュの差分を新規作成・変更・削除マークに分けて表示します。
+JIRAにはアクセスしません。
+キャッシュが古い場合や、フェッチしたときから設定が変わった場合、キャッシュのファイルが手動で編集されている場合は警告も表示します。
+
+変更がない場合は終了コード0、pushしていない変更がある場合は終了コード1で終了するので、シェルのプロンプトやスクリプトで使えます。
+
+--porcelain を指定すると、1行1件の「記号<TAB>キー<TAB>パス」の形式で出力します。
+記号は A (新規作成), M (変更), D (削除マーク), ? (strict_ticket_detection でスキップしたファイル) です。
+keyのない下書きのキーは - です。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+		if cfg.Directory == "" {
+			return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+		}
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		diffs, err := ticket.CompareDirs(cfg.Directory, cacheDir, compareOptions(cfg)...)
+		if err != nil {
+			return fmt.Errorf("差分の検出に失敗しました: %v", err)
+		}
+		var skipped []string
+		if cfg.StrictTicketDetection {
+			skipped, err = ticket.FindNonTicketFiles(cfg.Directory)
+			if err != nil {
+				return err
+			}
+		}
+
+		status := newWorkspaceStatus(diffs, skipped)
+		status.cache = checkCacheHealth(cfg, cacheDir)
+		if statusPorcelain {
+			status.printPorcelain(os.Stdout, cfg.Directory)
+			// porcelainの出力形式を変えないよう、キャッシュの警告は標準エラー出力に表示する
+			status.cache.print(os.Stderr)
+		} else {
+			status.print(os.Stdout)
+		}
+		if status.pending() {
+			os.Exit(exitCodeStatusPending)
+		}
+		return nil
+	},
+}
+
+// workspaceStatus は差分を種類ごとに分けたものです
+type workspaceStatus struct {
+	created, modified, deleted []ticket.DiffResult
+	unchanged                  int
+	skipped                    []string
+	cache                      cacheHealth
+}
+
+// cacheHealth はキャッシュについての警告です
+type cacheHealth struct {
+	// stale はキャッシュが push.max_cache_age より古い場合に設定されます
+	stale *cacheStaleness
+	// configChanges はフェッチしたときから変わった設定です
+	configChanges []string
+	// tampered は手動で編集されたキャッシュのファイルです
+	tampered []string
+}
+
+// checkCacheHealth はキャッシュの古さ、フェッチしたときの設定との違い、手動で編集されたファイルを調べます
+func checkCacheHealth(cfg *config.Config, cacheDir string) cacheHealth {
+	var h cacheHealth
+	if staleness, err := checkCacheStaleness(cfg); err == nil && staleness.Stale() {
+		h.stale = &staleness
+	}
+	h.configChanges = cacheMetadataMismatch(cfg)
+	h.tampered = tamperedCacheFiles(cacheDir)
+	return h
+}
+
+// print はキャッシュの警告を表示します (警告がない場合は何も表示しません)
+func (h cacheHealth) print(w io.Writer) {
+	if h.stale == nil && len(h.configChanges) == 0 && len(h.tampered) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "キャッシュの警告")
+	if h.stale != nil {
+		fmt.Fprintf(w, "  ⚠️  キャッシュが古くなっています (最終フェッチ: %s, push.max_cache_age: %s)\n", h.stale, h.stale.MaxAge)
+	}
+	if len(h.configChanges) > 0 {
+		fmt.Fprintln(w, "  ⚠️  現在の設定はキャッシュ取得時の設定と異なります")
+		for _, change := range h.configChanges {
+			fmt.Fprintf(w, "     %s\n", change)
+		}
+	}
+	if len(h.tampered) > 0 {
+		fmt.Fprintln(w, "  ⚠️  キャッシュのファイルが手動で編集されています (差分が正しくない可能性があります)")
+		for _, path := range h.tampered {
+			fmt.Fprintf(w, "     %s\n", path)
+		}
+	}
+	fmt.Fprintln(w, "  'tkt fetch' (設定の変更や手動の編集の場合は 'tkt fetch --clean') でキャッシュを取得し直してください")
+	fmt.Fprintln(w)
+}
+
+func newWorkspaceStatus(diffs []ticket.DiffResult, skipped []string) workspaceStatus {
+	var s workspaceStatus
+	for _, d := range diffs {
+		switch {
+		case !d.HasDiff:
+			s.unchanged++
+		case d.Change == ticket.ChangeCreate:
+			s.created = append(s.created, d)
+		case d.Change == ticket.ChangeDelete:
+			s.deleted = append(s.deleted, d)
+		default:
+			s.modified = append(s.modified, d)
+		}
+	}
+	for _, group := range [][]ticket.DiffResult{s.created, s.modified, s.deleted} {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Key != group[j].Key {
+				return group[i].Key < group[j].Key
+			}
+			return group[i].FilePath < group[j].FilePath
+		})
+	}
+	s.skipped = slices.Clone(skipped)
+	slices.Sort(s.skipped)
+	return s
+}
+
+// pending はpushしていない変更があるかを返します
+func (s workspaceStatus) pending() bool {
+	return len(s.created)+len(s.modified)+len(s.deleted) > 0
+}
+
+func (s workspaceStatus) print(w io.Writer) {
+	var keys []string
+	for _, group := range [][]ticket.DiffResult{s.created, s.modified, s.deleted} {
+		for _, d := range group {
+			keys = append(keys, displayDiffKey(d))
+		}
+	}
+	keyWidth := keyColumnWidth(keys)
+	printGroup := func(header string, diffs []ticket.DiffResult) {
+		if len(diffs) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s (%d件)\n", header, len(diffs))
+		for _, d := range diffs {
+			fmt.Fprintf(w, "  %s %s\n", padRight(displayDiffKey(d), keyWidth), d.Title)
+		}
+		fmt.Fprintln(w)
+	}
+	printGroup("新規作成 (pushで作成されます)", s.created)
+	printGroup("変更", s.modified)
+	printGroup("削除マーク (pushで削除されます)", s.deleted)
+	if len(s.skipped) > 0 {
+		fmt.Fprintf(w, "チケットとして扱わないファイル (%d件)\n", len(s.skipped))
+		for _, file := range s.skipped {
+			fmt.Fprintf(w, "  %s\n", file)
+		}
+		fmt.Fprintln(w)
+	}
+	s.cache.print(w)
+
+	if !s.pending() {
+		fmt.Fprintf(w, "変更はありません (変更なし: %d件)\n", s.unchanged)
+		return
+	}
+	fmt.Fprintf(w, "変更なし: %d件\n", s.unchanged)
+}
+
+// printPorcelain はスクリプト向けに1行1件で出力します (パスはワークスペースからの相対パス)
+func (s workspaceStatus) printPorcelain(w io.Writer, dir string) {
+	rel := func(path string) string {
+		if r, err := filepath.Rel(dir, path); err == nil {
+			return filepath.ToSlash(r)
+		}
+		return path
+	}
+	printGroup := func(code string, diffs []ticket.DiffResult) {
+		for _, d := range diffs {
+			key := d.Key
+			if key == "" {
+				key = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", code, key, rel(d.FilePath))
+		}
+	}
+	printGroup("A", s.created)
+	printGroup("M", s.modified)
+	printGroup("D", s.deleted)
+	for _, file := range s.skipped {
+		fmt.Fprintf(w, "?\t-\t%s\n", rel(file))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().BoolVar(&statusPorcelain, "porcelain", false, "スクリプト向けの安定した形式で出力する")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceStatus(t *testing.T) {
+	dir := filepath.Join("work", "tickets")
+	diffs := []ticket.DiffResult{
+		{Key: "PRJ-2", Title: "変更したチケット", FilePath: filepath.Join(dir, "PRJ-2.md"), HasDiff: true, Change: ticket.ChangeUpdate},
+		{Key: "PRJ-3", Title: "変更なし", FilePath: filepath.Join(dir, "PRJ-3.md"), Change: ticket.ChangeUpdate},
+		{Title: "下書き", FilePath: filepath.Join(dir, "TMP-1.md"), HasDiff: true, Change: ticket.ChangeCreate},
+		{Key: "PRJ-1", Title: "消すチケット", FilePath: filepath.Join(dir, "epics", ".PRJ-1.md"), HasDiff: true, Change: ticket.ChangeDelete},
+	}
+
+	tests := []struct {
+		name        string
+		diffs       []ticket.DiffResult
+		skipped     []string
+		porcelain   bool
+		want        string
+		wantPending bool
+	}{
+		{
+			name:  "変更あり",
+			diffs: diffs,
+			want: "新規作成 (pushで作成されます) (1件)\n" +
+				"  (新規)   下書き\n\n" +
+				"変更 (1件)\n" +
+				"  PRJ-2    変更したチケット\n\n" +
+				"削除マーク (pushで削除されます) (1件)\n" +
+				"  PRJ-1    消すチケット\n\n" +
+				"変更なし: 1件\n",
+			wantPending: true,
+		},
+		{
+			name:      "porcelain",
+			diffs:     diffs,
+			skipped:   []string{filepath.Join(dir, "note.md")},
+			porcelain: true,
+			want: "A\t-\tTMP-1.md\n" +
+				"M\tPRJ-2\tPRJ-2.md\n" +
+				"D\tPRJ-1\tepics/.PRJ-1.md\n" +
+				"?\t-\tnote.md\n",
+			wantPending: true,
+		},
+		{
+			name:        "変更なし",
+			diffs:       diffs[1:2],
+			want:        "変更はありません (変更なし: 1件)\n",
+			wantPending: false,
+		},
+		{
+			name:        "スキップしたファイルだけでは変更ありにしない",
+			diffs:       nil,
+			skipped:     []string{filepath.Join(dir, "note.md")},
+			want:        "チケットとして扱わないファイル (1件)\n  " + filepath.Join(dir, "note.md") + "\n\n変更はありません (変更なし: 0件)\n",
+			wantPending: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWorkspaceStatus(tt.diffs, tt.skipped)
+			var buf bytes.Buffer
+			if tt.porcelain {
+				s.printPorcelain(&buf, dir)
+			} else {
+				s.print(&buf)
+			}
+			assert.Equal(t, tt.want, buf.String())
+			assert.Equal(t, tt.wantPending, s.pending())
+		})
+	}
+}
+
+func TestCheckCacheHealth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	assert.NoError(t, os.WriteFile("tkt.yml", []byte("server: https://company.atlassian.net\nproject:\n  key: PRJ\njql: project = PRJ\ndirectory: tickets\n"), 0644))
+	cfg, err := config.LoadConfig()
+	assert.NoError(t, err)
+	cacheDir, err := config.EnsureCacheDir()
+	assert.NoError(t, err)
+
+	// 最終フェッチが2日前で、その後にキャッシュのファイルを手動で編集し、JQLも変えた状態にする
+	updated := time.Now().Add(-72 * time.Hour)
+	cached := &ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", UpdatedAt: updated}
+	path, err := cached.SaveToCache(cacheDir)
+	assert.NoError(t, err)
+	assert.NoError(t, config.WriteLastFetchTime(cacheDir, time.Now().Add(-48*time.Hour)))
+	now := time.Now()
+	assert.NoError(t, os.Chtimes(path, now, now))
+	assert.NoError(t, config.SaveWorkspaceMetadata(config.CacheMetadata{Server: cfg.Server, ProjectKey: "PRJ", JQL: "project = PRJ AND assignee = currentUser()"}))
+
+	h := checkCacheHealth(cfg, cacheDir)
+	assert.NotNil(t, h.stale)
+	assert.Equal(t, []string{"JQL: project = PRJ AND assignee = currentUser() (キャッシュ) → project = PRJ (現在)"}, h.configChanges)
+	assert.Equal(t, []string{path}, h.tampered)
+
+	s := newWorkspaceStatus(nil, nil)
+	s.cache = h
+	var buf bytes.Buffer
+	s.print(&buf)
+	out := buf.String()
+	assert.Contains(t, out, "キャッシュが古くなっています")
+	assert.Contains(t, out, "現在の設定はキャッシュ取得時の設定と異なります")
+	assert.Contains(t, out, "JQL: project = PRJ AND assignee = currentUser()")
+	assert.Contains(t, out, "キャッシュのファイルが手動で編集されています")
+	assert.Contains(t, out, path)
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+// exitCodeSyncConflict は競合が残っている場合の終了コードです
+// エラーの場合は1、競合なく同期できた場合は0で終了します。
+const exitCodeSyncConflict = 2
+
+var (
+	syncDryRun   bool
+	syncPushOnly bool
+	syncPullOnly bool
+	syncForce    bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "ローカルとリモートのJIRAチケットを同期します",
+	Long: `ローカルとリモートのJIRAチケットを同期します。fetch・merge・pushを組み合わせたコマンドです。
+
+リモートの最新情報を取得したあと、取り込む変更・反映する変更・競合をまとめた計画を表示し、
+確認後に取り込みとpushを行います。競合しているチケットには触れません。
+
+終了コード:
+  0  競合なく同期できた
+  1  エラーが発生した
+  2  競合が残っている`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		if syncPushOnly && syncPullOnly {
+			return fmt.Errorf("--push-only と --pull-only は同時に指定できません")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+		if cfg.Directory == "" {
+			return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+		}
+		if err := utils.EnsureDir(cfg.Directory); err != nil {
+			return fmt.Errorf("出力ディレクトリの作成に失敗しました: %v", err)
+		}
+
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+		}
+
+		// フェッチ前のキャッシュを、ローカルが編集されたかどうかの判断基準として保持する
+		base, err := ticket.LoadDir(cacheDir)
+		if err != nil {
+			return fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+		}
+
+		items, err := ui.WithSpinnerValue("同期計画を作成中...", func() ([]ticket.SyncItem, error) {
+			if _, err := fetchToCache(cmd.Context(), cfg, false, ""); err != nil {
+				return nil, err
+			}
+			return ticket.PlanSync(cfg.Directory, cacheDir, base, compareOptions(cfg)...)
+		})
+		if err != nil {
+			return err
+		}
+		warnNonTicketFiles(cfg)
+
+		var incoming, outgoing, conflicts []ticket.SyncItem
+		for _, item := range items {
+			switch item.Direction {
+			case ticket.SyncIncoming:
+				if !syncPushOnly {
+					incoming = append(incoming, item)
+				}
+			case ticket.SyncOutgoing:
+				if !syncPullOnly {
+					outgoing = append(outgoing, item)
+				}
+			case ticket.SyncConflict:
+				conflicts = append(conflicts, item)
+			}
+		}
+
+		printSyncPlan(incoming, outgoing, conflicts)
+
+		if syncDryRun || len(incoming)+len(outgoing) == 0 {
+			if len(incoming)+len(outgoing)+len(conflicts) == 0 {
+				fmt.Println("ローカルとリモートは同期済みです")
+			}
+			exitIfConflicts(conflicts)
+			return nil
+		}
+
+		// 反映する変更はpushと同じく、キャッシュとの差分から変更した項目を求めて確認する
+		var outgoingDiffs []ticket.DiffResult
+		var jiraClient *jira.Client
+		if len(outgoing) > 0 {
+			outgoingDiffs, err = syncOutgoingDiffs(cfg.Directory, cacheDir, outgoing, compareOptions(cfg)...)
+			if err != nil {
+				return err
+			}
+			jiraClient, err = ui.WithSpinnerValue("反映する変更を確認中...", func() (*jira.Client, error) {
+				jiraClient, err := jira.NewClient(cfg)
+				if err != nil {
+					return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+				}
+				jiraClient.SetSprintCacheDir(cacheDir)
+				return jiraClient, nil
+			})
+			if err != nil {
+				return err
+			}
+			if !syncForce {
+				if err := checkPushable(cmd.Context(), jiraClient, outgoingDiffs); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !syncForce && !utils.PromptForConfirmation("この計画で同期しますか？") {
+			fmt.Println("同期をキャンセルしました")
+			return nil
+		}
+
+		// 1. リモートの変更を取り込む (ローカルのファイルのみを変更するため先に行う)
+		// 取り込み先の既存のファイル (サブディレクトリにあるものを含む) は索引からキーで探す
+		localFiles := ticket.NewFileIndex(cfg.Directory)
+		for _, item := range incoming {
+			localPath, err := copyToWorkspace(cfg, item.CachePath, localFiles)
+			if err != nil {
+				return fmt.Errorf("%s の取り込みに失敗しました: %v", item.Key, err)
+			}
+			verbose.Printf(verbose.Diff, "取り込み: %s -> %s\n", item.CachePath, localPath)
+		}
+
+		// 2. ローカルの変更をpushする
+		if len(outgoingDiffs) > 0 {
+			var stats pushStats
+			err = ui.WithSpinner("変更を適用中...", func() error {
+				stats, err = applyPush(jiraClient, newPushOptions(cfg), outgoingDiffs)
+				return err
+			})
+			printPushNotices(stats)
+			if err != nil {
+				fmt.Printf("以下のエラーが発生しました:\n%v\n", err)
+				fmt.Printf("成功した分: %d 件作成, %d 件更新, %d 件削除\n", stats.created, stats.updated, stats.deleted)
+				return fmt.Errorf("一部の処理でエラーが発生しました")
+			}
+		}
+
+		// 3. 次回の同期でリモートの変更を検出できるよう、ローカルのupdated_atなどを更新する
+		if _, err := ticket.RefreshReadonly(cfg.Directory, cacheDir); err != nil {
+			verbose.Printf(verbose.General, "警告: ローカルのreadonly項目の更新に失敗しました: %v\n", err)
+		}
+
+		fmt.Printf("✅ 同期しました: %d 件取り込み, %d 件反映\n", len(incoming), len(outgoingDiffs))
+		exitIfConflicts(conflicts)
+		return nil
+	},
+}
+
+// syncOutgoingDiffs は反映する変更について、pushと同じ方法でキャッシュとの差分 (変更の種類と変更した項目) を求めます
+// 差分として送る内容がない (diff.ignore_fields の項目のみの変更など) チケットは含めません。
+func syncOutgoingDiffs(localDir, cacheDir string, outgoing []ticket.SyncItem, opts ...ticket.CompareOption) ([]ticket.DiffResult, error) {
+	diffs, err := ticket.CompareDirs(localDir, cacheDir, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("差分の検出に失敗しました: %v", err)
+	}
+	byPath := make(map[string]ticket.DiffResult, len(diffs))
+	for _, diff := range diffs {
+		byPath[filepath.Clean(diff.FilePath)] = diff
+	}
+	var result []ticket.DiffResult
+	for _, item := range outgoing {
+		diff, ok := byPath[filepath.Clean(item.LocalPath)]
+		if !ok || !diff.HasDiff {
+			verbose.Printf(verbose.Diff, "反映する差分がないためスキップ: %s\n", item.LocalPath)
+			continue
+		}
+		result = append(result, diff)
+	}
+	return result, nil
+}
+
+// printSyncPlan は同期計画を方向ごとに表示します
+func printSyncPlan(incoming, outgoing, conflicts []ticket.SyncItem) {
+	printGroup := func(header string, items []ticket.SyncItem) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Printf("%s (%d件)\n", header, len(items))
+		for _, item := range items {
+			key := item.Key
+			if key == "" {
+				key = "(新規)"
+			}
+			fmt.Printf("  %-10s %s [%s]\n", key, item.Title, item.Reason)
+		}
+		fmt.Println()
+	}
+	printGroup("⬇️  取り込む変更 (リモート → ローカル)", incoming)
+	printGroup("⬆️  反映する変更 (ローカル → リモート)", outgoing)
+	printGroup("⚠️  競合 (同期しません)", conflicts)
+}
+
+// exitIfConflicts は競合が残っている場合に終了コード2で終了します
+func exitIfConflicts(conflicts []ticket.SyncItem) {
+	if len(conflicts) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d 件の競合が残っています。'tkt diff' で確認し、手動で解消してください\n", len(conflicts))
+	os.Exit(exitCodeSyncConflict)
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "同期計画のみを表示する")
+	syncCmd.Flags().BoolVar(&syncPushOnly, "push-only", false, "ローカルの変更の反映のみを行う")
+	syncCmd.Flags().BoolVar(&syncPullOnly, "pull-only", false, "リモートの変更の取り込みのみを行う")
+	syncCmd.Flags().BoolVarP(&syncForce, "force", "f", false, "確認なしで同期する")
+}
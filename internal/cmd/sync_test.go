@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncOutgoingDiffs(t *testing.T) {
+	localDir, cacheDir := t.TempDir(), t.TempDir()
+	updated := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	for _, key := range []string{"PRJ-1", "PRJ-2", "PRJ-3"} {
+		cached := &ticket.Ticket{Key: key, Title: "タイトル", Type: "task", Status: "To Do", UpdatedAt: updated, Body: "本文\n"}
+		_, err := cached.SaveToCache(cacheDir)
+		assert.NoError(t, err)
+	}
+	save := func(tkt *ticket.Ticket) string {
+		path, err := tkt.SaveToFile(localDir)
+		assert.NoError(t, err)
+		return path
+	}
+	edited := save(&ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Status: "Done", UpdatedAt: updated, Body: "本文を変更\n"})
+	save(&ticket.Ticket{Key: "PRJ-2", Title: "タイトル", Type: "task", Status: "To Do", UpdatedAt: updated, Body: "本文\n"})
+	draft := save(&ticket.Ticket{Title: "下書き", Type: "task", Body: "本文\n"})
+	deleted := filepath.Join(localDir, ".PRJ-3.md")
+	assert.NoError(t, os.Rename(save(&ticket.Ticket{Key: "PRJ-3", Title: "タイトル", Type: "task", Status: "To Do", UpdatedAt: updated, Body: "本文\n"}), deleted))
+
+	base, err := ticket.LoadDir(cacheDir)
+	assert.NoError(t, err)
+	items, err := ticket.PlanSync(localDir, cacheDir, base)
+	assert.NoError(t, err)
+	var outgoing []ticket.SyncItem
+	for _, item := range items {
+		if item.Direction == ticket.SyncOutgoing {
+			outgoing = append(outgoing, item)
+		}
+	}
+	assert.Len(t, outgoing, 3)
+
+	// pushと同じく、変更の種類と変更した項目が分かる (以前は空のまま送って何も更新されなかった)
+	diffs, err := syncOutgoingDiffs(localDir, cacheDir, outgoing)
+	assert.NoError(t, err)
+	got := make(map[string]ticket.DiffResult)
+	for _, diff := range diffs {
+		got[diff.FilePath] = diff
+	}
+	assert.Len(t, got, 3)
+	assert.Equal(t, ticket.ChangeUpdate, got[edited].Change)
+	assert.Equal(t, []string{"status", "body"}, got[edited].ChangedFields)
+	assert.Equal(t, ticket.ChangeCreate, got[draft].Change)
+	assert.Equal(t, ticket.ChangeDelete, got[deleted].Change)
+}
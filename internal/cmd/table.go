@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// displayWidth は端末での表示幅の計算に使う条件です
+// 全角文字 (漢字やかな) は幅2として数えます。曖昧な幅の文字はlipglossに合わせて幅1とし、環境変数によって変わらないようにします。
+var displayWidth = &runewidth.Condition{EastAsianWidth: false}
+
+// minKeyColumnWidth はキーの列の最小の幅です (DRAFT や PRJ-123 が収まる幅)
+const minKeyColumnWidth = 8
+
+// padRight は表示幅がwidthになるように右に空白を詰めます
+// fmt の %-8s は文字数で数えるため、全角文字を含むとそろわなくなります。
+func padRight(s string, width int) string {
+	return displayWidth.FillRight(s, width)
+}
+
+// keyColumnWidth は一覧のキーの列の幅 (最も長いキーの表示幅、最小 minKeyColumnWidth) を返します
+// PROJECT-12345 のような長いキーがあっても、タイトルの開始位置がそろうようにします。
+func keyColumnWidth(keys []string) int {
+	width := minKeyColumnWidth
+	for _, key := range keys {
+		width = max(width, displayWidth.StringWidth(key))
+	}
+	return width
+}
+
+// writeTable は表示幅で列をそろえた表を出力します
+// 列の間は2文字空け、最後の列は詰めません。text/tabwriter と異なり全角文字を幅2として数えます。
+func writeTable(w io.Writer, rows [][]string) error {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth.StringWidth(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(padRight(cell, widths[i]))
+			b.WriteString("  ")
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
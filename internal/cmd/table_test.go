@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "testdata のゴールデンファイルを更新する")
+
+// assertGolden は testdata のゴールデンファイルと一致するかを確認します (-update で更新します)
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		assert.NoError(t, os.WriteFile(path, []byte(got), 0644))
+	}
+	want, err := os.ReadFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, string(want), got)
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{name: "半角", s: "PRJ-1", width: 8, want: "PRJ-1   "},
+		{name: "全角は幅2", s: "(新規)", width: 8, want: "(新規)  "},
+		{name: "幅を超える場合はそのまま", s: "PROJECT-12345", width: 8, want: "PROJECT-12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, padRight(tt.s, tt.width))
+		})
+	}
+}
+
+func TestKeyColumnWidth(t *testing.T) {
+	assert.Equal(t, minKeyColumnWidth, keyColumnWidth(nil))
+	assert.Equal(t, minKeyColumnWidth, keyColumnWidth([]string{"PRJ-1", "DRAFT"}))
+	assert.Equal(t, 13, keyColumnWidth([]string{"PRJ-1", "PROJECT-12345"}))
+}
+
+// mixedWidthTickets は全角のタイトルや担当者名と長いキーが混ざったチケットです
+func mixedWidthTickets() []*ticket.Ticket {
+	updated := time.Date(2025, 6, 5, 12, 0, 0, 0, time.Local)
+	return []*ticket.Ticket{
+		{Key: "PRJ-1", Type: "bug", Status: "In Progress", Assignee: "佐藤 花子", Title: "ログイン画面でエラーが表示される", UpdatedAt: updated},
+		{Key: "PROJECT-12345", Type: "task", Status: "To Do", Assignee: "Alice", Title: "Update README", UpdatedAt: updated},
+		{Key: "PRJ-22", Type: "ストーリー", Status: "完了", Assignee: "山田", Title: "検索APIのレスポンスを高速化する", UpdatedAt: updated},
+		{Title: "下書きのチケット", Type: "task"},
+	}
+}
+
+func TestListTableGolden(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, printTickets(&buf, mixedWidthTickets(), "table"))
+	assertGolden(t, "list_table.golden", buf.String())
+}
+
+func TestGrepLeftPaneGolden(t *testing.T) {
+	var items []ticketItem
+	for _, tk := range mixedWidthTickets() {
+		items = append(items, ticketItem{key: displayTicketKey(tk), title: tk.Title, ticket: tk})
+	}
+	m := &grepModel{tickets: items, filteredItems: items, cursor: len(items)}
+
+	// 端末の幅を固定して描画する (カーソルは範囲外にして選択行のスタイルを付けない)
+	assertGolden(t, "grep_left_pane.golden", m.renderLeftPane(36, 10)+"\n")
+}
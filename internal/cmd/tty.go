@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"errors"
+
+	tty "github.com/mattn/go-tty"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// errNoTTY は制御端末(TTY)が利用できないことを表します
+var errNoTTY = errors.New("TTYが利用できません")
+
+// openTTY は制御端末を開きます
+// テストでTTYがない環境を再現できるように変数にしています。
+var openTTY = tty.Open
+
+// openInteractiveTTY はインタラクティブな画面の表示に使う制御端末を開きます
+// ssh -T やCIなど制御端末がない環境では errNoTTY を返します。
+func openInteractiveTTY() (*tty.TTY, error) {
+	t, err := openTTY()
+	if err != nil {
+		verbose.Printf(verbose.General, "TTYのオープンに失敗しました: %v\n", err)
+		return nil, errNoTTY
+	}
+	return t, nil
+}
@@ -0,0 +1,68 @@
+//go:build unix
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tty "github.com/mattn/go-tty"
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubNoTTY はTTYが存在しない環境 (ssh -T やCIなど) を再現します
+func stubNoTTY(t *testing.T) {
+	t.Helper()
+	orig := openTTY
+	openTTY = func() (*tty.TTY, error) {
+		return nil, errors.New("open /dev/tty: no such device or address")
+	}
+	t.Cleanup(func() { openTTY = orig })
+}
+
+func TestRunGrepWithoutTTY(t *testing.T) {
+	stubNoTTY(t)
+
+	tickets := []*ticket.Ticket{
+		{Key: "PRJ-2", Title: "second"},
+		{Key: "PRJ-1", Title: "first"},
+	}
+
+	var buf bytes.Buffer
+	err := runGrep(&buf, tickets, t.TempDir(), nil)
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		var dto ticketDTO
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &dto))
+		assert.Equal(t, "PRJ-1", dto.Key)
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &dto))
+		assert.Equal(t, "PRJ-2", dto.Key)
+	}
+}
+
+func TestRunInteractiveRMWithoutTTY(t *testing.T) {
+	stubNoTTY(t)
+
+	dir := t.TempDir()
+	content := "---\nkey: PRJ-1\ntitle: first\n---\nbody\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "PRJ-1.md"), []byte(content), 0644))
+
+	err := runInteractiveRM(&config.Config{Directory: dir})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "tkt rm PRJ-123")
+		assert.NotContains(t, err.Error(), "/dev/tty")
+	}
+
+	// ファイルは削除されていない
+	_, err = os.Stat(filepath.Join(dir, "PRJ-1.md"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo TICKET-KEY",
+	Short: "最後のpushの前の内容をワークスペースに復元します",
+	Long: `pushの履歴 (tkt history --local) から、チケットを最後にpushする前のキャッシュの内容をワークスペースのファイルに復元します。
+JIRAは変更しないので、'tkt diff' で確認してから 'tkt push' で反映してください。
+
+削除したチケットは同じキーでは作り直せないため、キーのない下書きとして復元します (pushすると新しいチケットとして作成されます)。
+ワークスペースのファイルにpushしていない変更がある場合は、上書きする前に確認します。`,
+	Example: `  tkt undo PRJ-123`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := ticket.CanonicalKey(args[0])
+		if !utils.IsValidJIRAKey(key) {
+			return fmt.Errorf("無効なチケットキーです: %s", args[0])
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+		if cfg.Directory == "" {
+			return fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+		}
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+
+		entry, err := lastUndoableEntry(cacheDir, key)
+		if err != nil {
+			return err
+		}
+		previous, err := ticket.FromFile(ticket.HistoryBackupPath(cacheDir, *entry))
+		if err != nil {
+			return fmt.Errorf("push前の内容の読み込みに失敗しました: %v", err)
+		}
+		when := entry.Time.Local().Format("2006-01-02 15:04:05")
+
+		if entry.Action == ticket.ChangeDelete {
+			previous.Key = ""
+			previous.FilePath = ""
+			path, err := previous.SaveToFile(cfg.Directory)
+			if err != nil {
+				return fmt.Errorf("下書きの保存に失敗しました: %v", err)
+			}
+			fmt.Printf("%s で削除した %s の内容を下書き %s に復元しました。'tkt push' で新しいチケットとして作成できます\n", when, key, path)
+			return nil
+		}
+
+		filePath, err := ticket.FindFile(cfg.Directory, key)
+		if err != nil {
+			return err
+		}
+		dir := cfg.Directory
+		if filePath != "" {
+			ok, err := confirmOverwriteLocalEdits(cacheDir, filePath)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("復元をキャンセルしました")
+				return nil
+			}
+			dir = filepath.Dir(filePath)
+		}
+		previous.FilePath = filePath
+		path, err := previous.SaveToFile(dir, ticket.WithFilenameTemplate(filenameTemplate(cfg)))
+		if err != nil {
+			return fmt.Errorf("ローカルファイルの更新に失敗しました: %v", err)
+		}
+		fmt.Printf("%s を %s のpushの前の内容に戻しました: %s\n'tkt diff' で確認してから 'tkt push' で反映してください\n", key, when, path)
+		return nil
+	},
+}
+
+// lastUndoableEntry はチケットの履歴のうち、push前の内容を復元できる最後の操作 (更新または削除) を返します
+func lastUndoableEntry(cacheDir, key string) (*ticket.HistoryEntry, error) {
+	entries, err := ticket.ReadHistory(cacheDir, key)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Backup != "" {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("チケット %s には復元できるpushの履歴がありません ('tkt history --local %s' で確認できます)", key, key)
+}
+
+// confirmOverwriteLocalEdits はワークスペースのファイルにpushしていない変更がある場合に、上書きしてよいかを確認します
+func confirmOverwriteLocalEdits(cacheDir, filePath string) (bool, error) {
+	local, err := ticket.FromFile(filePath)
+	if err != nil {
+		return false, fmt.Errorf("%s の読み込みに失敗しました: %v", filePath, err)
+	}
+	cached, err := ticket.FromFile(filepath.Join(cacheDir, local.Key+".md"))
+	if err == nil && local.SameContent(cached) {
+		return true, nil
+	}
+	return utils.PromptForConfirmation(fmt.Sprintf("%s にはpushしていない変更があります。上書きしますか？", filePath)), nil
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
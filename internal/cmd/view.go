@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/derrors"
+	"github.com/qawatake/tkt/internal/jira"
+	"github.com/qawatake/tkt/internal/pkg/utils"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	viewRemote bool
+	viewJSON   bool
+)
+
+// viewWidth は本文を折り返す幅です
+const viewWidth = 100
+
+var viewCmd = &cobra.Command{
+	Use:   "view TICKET-KEY",
+	Short: "チケットを表示します",
+	Long: `チケットのフロントマターと本文を表示します。
+ワークスペース、キャッシュの順にチケットを探します。
+--remote を指定すると、どちらにもない場合にJIRAから取得します。
+--json を指定すると grep と同じ形式のJSONを出力します。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		defer derrors.Wrap(&err)
+
+		key := ticket.CanonicalKey(args[0])
+		if !utils.IsValidJIRAKey(key) {
+			return fmt.Errorf("無効なチケットキーです: %s", args[0])
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+		}
+
+		t, err := findTicket(cfg, key)
+		if err != nil {
+			if !viewRemote {
+				return err
+			}
+			t, err = ui.WithSpinnerValue(fmt.Sprintf("%s を取得中...", key), func() (*ticket.Ticket, error) {
+				jiraClient, err := jira.NewClient(cfg)
+				if err != nil {
+					return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %v", err)
+				}
+				return jiraClient.FetchIssue(key)
+			})
+			if err != nil {
+				return fmt.Errorf("チケット %s の取得に失敗しました: %v", key, err)
+			}
+		}
+
+		parents := newParentTitleIndex(nil)
+		if viewJSON {
+			b, err := json.Marshal(newTicketDTO(t, parents))
+			if err != nil {
+				return fmt.Errorf("JSON出力の生成に失敗しました: %v", err)
+			}
+			fmt.Println(string(b))
+			return nil
+		}
+
+		output := termenv.NewOutput(os.Stdout)
+		return renderTicketView(os.Stdout, t, parents, output.Profile != termenv.Ascii)
+	},
+}
+
+// renderTicketView はチケットのフロントマターの表と本文を出力します
+// 親チケットは parents からタイトルを引いて表示します。styled が false の場合 (パイプに出力する場合) は色を付けません。
+func renderTicketView(w io.Writer, t *ticket.Ticket, parents *parentTitleIndex, styled bool) error {
+	style := &styles.NoTTYStyleConfig
+	if styled {
+		s, err := customAutoStyle()
+		if err != nil {
+			return err
+		}
+		style = s
+	}
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStyles(*style),
+		glamour.WithEmoji(),
+		glamour.WithWordWrap(viewWidth),
+	)
+	if err != nil {
+		return err
+	}
+	body, err := renderer.Render(t.Body)
+	if err != nil {
+		return fmt.Errorf("本文の表示に失敗しました: %v", err)
+	}
+
+	titleStyle := lipgloss.NewStyle()
+	labelStyle := lipgloss.NewStyle()
+	if styled {
+		titleStyle = titleStyle.Bold(true)
+		labelStyle = labelStyle.Bold(true).Foreground(lipgloss.Color("33"))
+	}
+
+	fmt.Fprintln(w, titleStyle.Render(fmt.Sprintf("%s %s", t.Key, t.Title)))
+	for _, row := range viewRows(t, parents) {
+		fmt.Fprintf(w, "%s %s\n", labelStyle.Render(fmt.Sprintf("%-9s", row[0])), row[1])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.TrimRight(body, "\n"))
+	return nil
+}
+
+// viewRows はフロントマターの表の行 (項目名と値) を返します。値のない項目は含めません。
+func viewRows(t *ticket.Ticket, parents *parentTitleIndex) [][2]string {
+	var rows [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{label, value})
+		}
+	}
+	add("Type", t.Type)
+	add("Status", t.Status)
+	if t.IsFlagged() {
+		add("Flagged", flaggedIndicator+" Impediment")
+	}
+	add("Priority", t.Priority)
+	add("Assignee", t.Assignee)
+	add("Reporter", t.Reporter)
+	add("Creator", t.Creator)
+	add("Parent", parentLabel(t, parents))
+	add("Epic", t.Epic)
+	add("Sprint", t.SprintName)
+	add("Team", t.Team)
+	add("Labels", strings.Join(t.Labels, ", "))
+	if t.OriginalEstimate > 0 {
+		add("Estimate", fmt.Sprintf("%.1fh", float64(t.OriginalEstimate)))
+	}
+	if !t.UpdatedAt.IsZero() {
+		add("Updated", t.UpdatedAt.Local().Format(time.DateTime))
+	}
+	add("URL", t.URL)
+	return rows
+}
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+
+	viewCmd.Flags().BoolVar(&viewRemote, "remote", false, "ワークスペースにもキャッシュにもない場合にJIRAから取得する")
+	viewCmd.Flags().BoolVar(&viewJSON, "json", false, "JSON形式で出力する")
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTicketView(t *testing.T) {
+	tk := &ticket.Ticket{
+		Key:      "PRJ-1",
+		Title:    "ログインのタイムアウト",
+		Type:     "task",
+		Status:   "In Progress",
+		Assignee: "alice",
+		Labels:   []string{"backend", "auth"},
+		Body:     "## 再現手順\n\n- ログインする\n",
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, renderTicketView(&out, tk, nil, false))
+	got := out.String()
+	assert.Contains(t, got, "PRJ-1 ログインのタイムアウト\n")
+	assert.Contains(t, got, "Status    In Progress\n")
+	assert.Contains(t, got, "Labels    backend, auth\n")
+	assert.NotContains(t, got, "Reporter")
+	assert.Contains(t, got, "再現手順")
+	assert.Contains(t, got, "ログインする")
+	// パイプに出力する場合は色を付けない
+	assert.NotContains(t, got, "\x1b[")
+}
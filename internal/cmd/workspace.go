@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/spf13/cobra"
+)
+
+// loadWorkspaceTicket はワークスペースから指定したキーのチケットを読み込みます
+// ワークスペースにファイルがない場合はキャッシュからコピーします。
+// 削除マークが付いたチケットはエラーになります。
+func loadWorkspaceTicket(cfg *config.Config, key string) (*ticket.Ticket, error) {
+	if cfg.Directory == "" {
+		return nil, fmt.Errorf("設定ファイルにdirectoryが設定されていません。tkt initで設定してください")
+	}
+
+	deletedPath, err := ticket.FindDeletedFile(cfg.Directory, key)
+	if err != nil {
+		return nil, err
+	}
+	if deletedPath != "" {
+		return nil, fmt.Errorf("チケット %s は削除マークが付いているため操作できません: %s", key, deletedPath)
+	}
+
+	filePath, err := ticket.FindFile(cfg.Directory, key)
+	if err != nil {
+		return nil, err
+	}
+	if filePath == "" {
+		cacheDir, err := config.EnsureCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		cachePath := filepath.Join(cacheDir, key+".md")
+		if _, err := os.Stat(cachePath); err != nil {
+			return nil, fmt.Errorf("チケット %s がワークスペースにもキャッシュにも見つかりません", key)
+		}
+		if err := os.MkdirAll(cfg.Directory, 0755); err != nil {
+			return nil, fmt.Errorf("ディレクトリの作成に失敗しました: %v", err)
+		}
+		filePath, err = copyToWorkspace(cfg, cachePath, ticket.NewFileIndex(cfg.Directory))
+		if err != nil {
+			return nil, fmt.Errorf("キャッシュからのコピーに失敗しました: %v", err)
+		}
+		verbose.Printf(verbose.Cache, "キャッシュからコピー: %s -> %s\n", cachePath, filePath)
+	}
+
+	t, err := ticket.FromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("チケット %s の読み込みに失敗しました: %v", key, err)
+	}
+	return t, nil
+}
+
+// filenameTemplate は設定ファイルの filename_template と filename_style からワークスペースのファイル名のテンプレートを返します
+func filenameTemplate(cfg *config.Config) string {
+	return ticket.ResolveFilenameTemplate(cfg.FilenameTemplate, cfg.FilenameStyle)
+}
+
+// compareOptions は設定ファイルの diff.ignore_fields, diff.context と strict_ticket_detection に対応する差分検出のオプションを返します
+func compareOptions(cfg *config.Config) []ticket.CompareOption {
+	opts := []ticket.CompareOption{
+		ticket.WithIgnoreFields(cfg.Diff.IgnoreFields),
+		ticket.WithStrictDetection(cfg.StrictTicketDetection),
+	}
+	if cfg.Diff.Context != nil {
+		opts = append(opts, ticket.WithContextLines(*cfg.Diff.Context))
+	}
+	return opts
+}
+
+// diffContextLines は --context と --full から差分の前後に表示する行数を返します
+// どちらも指定しない場合は設定ファイルの diff.context、それもなければ3行です。--full の場合は ticket.FullContext を返します。
+func diffContextLines(cmd *cobra.Command, cfg *config.Config, n int, full bool) (int, error) {
+	changed := cmd.Flags().Changed("context")
+	switch {
+	case full && changed:
+		return 0, fmt.Errorf("--context と --full は同時に指定できません")
+	case full:
+		return ticket.FullContext, nil
+	case changed:
+		if n < 0 {
+			return 0, fmt.Errorf("--context には0以上の値を指定してください: %d", n)
+		}
+		return n, nil
+	case cfg.Diff.Context != nil:
+		return *cfg.Diff.Context, nil
+	default:
+		return ticket.DefaultContextLines, nil
+	}
+}
+
+// warnNonTicketFiles は strict_ticket_detection でチケットとして扱わなかったワークスペースのファイルを警告します
+// typeを書き忘れたチケットが黙って無視されないようにするためです。
+func warnNonTicketFiles(cfg *config.Config) {
+	if !cfg.StrictTicketDetection || cfg.Directory == "" {
+		return
+	}
+	files, err := ticket.FindNonTicketFiles(cfg.Directory)
+	if err != nil {
+		verbose.Printf(verbose.General, "チケット以外のファイルの検索に失敗しました: %v\n", err)
+		return
+	}
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  チケットの形式 (type と、key または tkt: true) を満たさないため %d 件のファイルをスキップしました:\n", len(files))
+	for _, file := range files {
+		fmt.Fprintf(os.Stderr, "  %s\n", file)
+	}
+}
+
+// copyToWorkspace はキャッシュのチケットのファイルを索引 files のディレクトリにコピーし、コピー先のパスを返します
+// コピー先の名前は filename_template (filename_style) に従い、同じキーの既存のファイルの名前が異なる場合 (タイトルの変更や形式の変更) は置き換えます。
+// 既存のファイルがサブディレクトリにある場合はそのディレクトリにコピーします。
+// 複数のチケットをコピーする場合は同じ索引を渡し、既存のファイルを探すためにワークスペースを何度も読み込まないようにします。
+func copyToWorkspace(cfg *config.Config, srcPath string, files *ticket.FileIndex) (string, error) {
+	t, err := ticket.FromFile(srcPath)
+	if err != nil {
+		return "", err
+	}
+	existing, err := files.Find(t.Key)
+	if err != nil {
+		return "", err
+	}
+	dir := files.Dir()
+	name, err := t.FileName(filenameTemplate(cfg))
+	if err != nil {
+		return "", err
+	}
+	// サブディレクトリにある既存のファイルはそのディレクトリで置き換える
+	dstDir := dir
+	if existing != "" {
+		dstDir = filepath.Dir(existing)
+	}
+	dstPath := filepath.Join(dstDir, name)
+	if err := copyFile(srcPath, dstPath); err != nil {
+		return "", err
+	}
+	if existing != "" && existing != dstPath {
+		if err := os.Remove(existing); err != nil {
+			return "", fmt.Errorf("古いファイル %s の削除に失敗しました: %v", existing, err)
+		}
+		verbose.Printf(verbose.General, "ファイル名を変更: %s -> %s\n", existing, dstPath)
+	}
+	return dstPath, nil
+}
+
+// findTicket はワークスペース、キャッシュの順に指定したキーのチケットを探します
+// loadWorkspaceTicket と異なり、ワークスペースへのコピーは行いません。
+func findTicket(cfg *config.Config, key string) (*ticket.Ticket, error) {
+	var dirs []string
+	if cfg.Directory != "" {
+		dirs = append(dirs, cfg.Directory)
+	}
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	dirs = append(dirs, cacheDir)
+
+	for _, dir := range dirs {
+		filePath, err := ticket.FindFile(dir, key)
+		if err != nil {
+			return nil, err
+		}
+		if filePath == "" {
+			continue
+		}
+		t, err := ticket.FromFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("チケット %s の読み込みに失敗しました: %v", key, err)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("チケット %s がワークスペースにもキャッシュにも見つかりません", key)
+}
+
+// updateCachedTicket はpush後にキャッシュ側のチケットにも同じ変更を反映し、diffが出ないようにします
+func updateCachedTicket(key string, update func(t *ticket.Ticket)) error {
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	cachePath := filepath.Join(cacheDir, key+".md")
+	if _, err := os.Stat(cachePath); os.IsNotExist(err) {
+		return nil
+	}
+	t, err := ticket.FromFile(cachePath)
+	if err != nil {
+		return fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+	}
+	update(t)
+	if _, err := t.SaveToCache(cacheDir); err != nil {
+		return fmt.Errorf("キャッシュの更新に失敗しました: %v", err)
+	}
+	return nil
+}
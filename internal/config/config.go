@@ -2,9 +2,12 @@ package config
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/qawatake/tkt/internal/derrors"
@@ -20,20 +23,57 @@ type IssueType struct {
 	Subtask          bool   `mapstructure:"subtask" yaml:"subtask"`
 }
 
+// CustomField はフロントマターに対応付けるJIRAのカスタムフィールドです
+// Keyを省略した場合はNameからフィールドを探します。
+type CustomField struct {
+	Name string `mapstructure:"name" yaml:"name"`
+	Key  string `mapstructure:"key" yaml:"key"`
+	// Frontmatter はフロントマターでのキーです。省略した場合はNameをスネークケースにしたものを使います (例: Story Points → story_points)
+	Frontmatter string `mapstructure:"frontmatter" yaml:"frontmatter,omitempty"`
+	Schema      struct {
+		Datatype string `mapstructure:"datatype" yaml:"datatype"`
+		Items    string `mapstructure:"items" yaml:"items"`
+	} `mapstructure:"schema" yaml:"schema"`
+}
+
+// FrontmatterKey はカスタムフィールドのフロントマターでのキーを返します
+func (f CustomField) FrontmatterKey() string {
+	if f.Frontmatter != "" {
+		return f.Frontmatter
+	}
+	return strings.Join(strings.Fields(strings.ToLower(f.Name)), "_")
+}
+
+// IsNumber はカスタムフィールドが数値型かどうかを返します
+// datatypeを省略した場合は数値型として扱います。
+func (f CustomField) IsNumber() bool {
+	return f.Schema.Datatype == "" || f.Schema.Datatype == "number"
+}
+
 // Config は設定ファイルの構造体です
 type Config struct {
 	AuthType string `mapstructure:"auth_type" yaml:"auth_type"`
 	Login    string `mapstructure:"login" yaml:"login"`
 	Server   string `mapstructure:"server" yaml:"server"`
-	Project  struct {
+	// TokenCommand はAPIトークンを標準出力に出力するコマンドです (例: op read op://vault/jira/token)
+	TokenCommand string `mapstructure:"token_command" yaml:"token_command,omitempty"`
+	// TokenFile はAPIトークンを保存したファイルのパスです
+	TokenFile string `mapstructure:"token_file" yaml:"token_file,omitempty"`
+	Project   struct {
 		Key  string `mapstructure:"key" yaml:"key"`
 		ID   string `mapstructure:"id" yaml:"id"`
 		Type string `mapstructure:"type" yaml:"type"`
+		// Components はプロジェクトのコンポーネント名の一覧です (tkt initで取得)
+		// 設定されている場合、push前にコンポーネント名を検証します。
+		Components []string `mapstructure:"components" yaml:"components,omitempty"`
 	} `mapstructure:"project" yaml:"project"`
 	Board struct {
 		ID   int    `mapstructure:"id" yaml:"id"`
 		Name string `mapstructure:"name" yaml:"name"`
 		Type string `mapstructure:"type" yaml:"type"`
+		// IDs はスプリントを探すボードのIDの一覧です
+		// 1つのプロジェクトを複数のボードで共有している場合に指定します。
+		IDs []int `mapstructure:"ids" yaml:"ids,omitempty"`
 	} `mapstructure:"board" yaml:"board"`
 	Epic struct {
 		Name string `mapstructure:"name" yaml:"name"`
@@ -41,14 +81,7 @@ type Config struct {
 	} `mapstructure:"epic" yaml:"epic"`
 	Issue struct {
 		Fields struct {
-			Custom []struct {
-				Name   string `mapstructure:"name" yaml:"name"`
-				Key    string `mapstructure:"key" yaml:"key"`
-				Schema struct {
-					Datatype string `mapstructure:"datatype" yaml:"datatype"`
-					Items    string `mapstructure:"items" yaml:"items"`
-				} `mapstructure:"schema" yaml:"schema"`
-			} `mapstructure:"custom" yaml:"custom"`
+			Custom []CustomField `mapstructure:"custom" yaml:"custom"`
 		} `mapstructure:"fields" yaml:"fields"`
 		// プロジェクトで利用可能なIssue Typeのリスト
 		// チケットを作成するときはこの中から選択する必要があります。
@@ -57,6 +90,217 @@ type Config struct {
 	JQL       string `mapstructure:"jql" yaml:"jql"`
 	Timezone  string `mapstructure:"timezone" yaml:"timezone"`
 	Directory string `mapstructure:"directory" yaml:"directory"`
+	// FilenameStyle はワークスペースのファイル名の形式です (key: PRJ-123.md, key-slug: PRJ-123-fix-login-timeout.md)
+	// 空の場合はkeyとして扱います。キャッシュのファイル名は常にkeyです。
+	FilenameStyle string `mapstructure:"filename_style" yaml:"filename_style,omitempty"`
+	// FilenameTemplate はワークスペースのファイル名のテンプレートです (Ticketのフィールドを参照するGoのテンプレート、例: {{.Key}}.md)
+	// 指定した場合は FilenameStyle より優先します。slug 関数でタイトルなどをファイル名に使える形に変換できます。
+	FilenameTemplate string `mapstructure:"filename_template" yaml:"filename_template,omitempty"`
+	// AllowExternalDirectory がtrueの場合、directoryにプロジェクトの外のパスを指定できます
+	AllowExternalDirectory bool `mapstructure:"allow_external_directory" yaml:"allow_external_directory,omitempty"`
+	// NormalizeOnFetch がtrueの場合、取得したチケットの本文を差分検出と同じ形式に正規化して保存します
+	NormalizeOnFetch bool `mapstructure:"normalize_on_fetch" yaml:"normalize_on_fetch"`
+	// StrictTicketDetection がtrueの場合、typeがあり、keyまたは tkt: true のマーカーがあるファイルのみをチケットとして扱います
+	// ワークスペースをObsidianのvaultなどと兼ねていて、チケット以外のノートにもフロントマターがある場合に使います。
+	StrictTicketDetection bool `mapstructure:"strict_ticket_detection" yaml:"strict_ticket_detection,omitempty"`
+	// ConfluenceLinks がtrueの場合、fetch時に本文のConfluenceのリンクのページタイトルを取得してreferencesに記録します
+	ConfluenceLinks bool `mapstructure:"confluence_links" yaml:"confluence_links,omitempty"`
+	Push            struct {
+		// MaxCacheAge はpush時に許容するキャッシュの古さです (例: 24h, 30m)
+		MaxCacheAge string `mapstructure:"max_cache_age" yaml:"max_cache_age,omitempty"`
+		// AutoFetch がtrueの場合、キャッシュが古いときは確認せずに増分フェッチしてからpushします
+		AutoFetch bool `mapstructure:"auto_fetch" yaml:"auto_fetch,omitempty"`
+		// StrictTransitions がtrueの場合、ステータスの遷移に失敗したチケットのpushを失敗にします
+		// falseの場合は警告を表示し、他の項目の更新は成功として扱います。
+		StrictTransitions bool `mapstructure:"strict_transitions" yaml:"strict_transitions,omitempty"`
+		// StrictReadonly がtrueの場合、読み取り専用の項目 (created_at, reporter, url など) が編集されているとpushを中止します
+		// falseの場合は反映されない項目を警告として表示します。
+		StrictReadonly bool `mapstructure:"strict_readonly" yaml:"strict_readonly,omitempty"`
+		// SyncBack がfalseの場合、push後にJIRAから取得し直した内容をワークスペースのファイルに書き戻しません (省略した場合はtrue)
+		SyncBack *bool `mapstructure:"sync_back" yaml:"sync_back,omitempty"`
+	} `mapstructure:"push" yaml:"push,omitempty"`
+	Diff struct {
+		// IgnoreFields は差分の検出とpushの対象から外す項目です (例: [type, parentKey])
+		IgnoreFields []string `mapstructure:"ignore_fields" yaml:"ignore_fields,omitempty"`
+		// Context は差分の前後に表示する行数です (省略した場合は3行)
+		Context *int `mapstructure:"context" yaml:"context,omitempty"`
+	} `mapstructure:"diff" yaml:"diff,omitempty"`
+	Cache struct {
+		// DurableWrites がfalseの場合、fetchでキャッシュに書き込むときにfsyncしません (省略した場合はtrue)
+		// NFSやDropboxなどのfsyncが遅いファイルシステムで、多数のチケットをフェッチする場合に使います。
+		DurableWrites *bool `mapstructure:"durable_writes" yaml:"durable_writes,omitempty"`
+	} `mapstructure:"cache" yaml:"cache,omitempty"`
+	// HTTPTimeout はJIRA APIへの1回のリクエストのタイムアウトです (例: 30s、省略した場合は60s)
+	HTTPTimeout string `mapstructure:"http_timeout" yaml:"http_timeout,omitempty"`
+	// CABundle はJIRAのサーバー証明書の検証で追加で信頼するCA証明書 (PEM形式) のパスです
+	// 社内のプロキシがTLSを中継する環境で使います。
+	CABundle string `mapstructure:"ca_bundle" yaml:"ca_bundle,omitempty"`
+	Retry    struct {
+		// MaxAttempts はJIRA APIへのリクエストを試行する最大の回数です (省略した場合は4回、1の場合は再試行しない)
+		MaxAttempts int `mapstructure:"max_attempts" yaml:"max_attempts,omitempty"`
+	} `mapstructure:"retry" yaml:"retry,omitempty"`
+	// RateLimit はJIRA APIへのリクエストの上限 (1秒あたりの回数) です (省略した場合や0の場合は制限しない)
+	// 同じサーバーへのリクエストはコマンド全体で1つの上限を共有します。
+	RateLimit float64 `mapstructure:"rate_limit" yaml:"rate_limit,omitempty"`
+	// Concurrency はチケットの検索やスプリントの取得などで並列に送るリクエストの数です (省略した場合は5)
+	Concurrency int `mapstructure:"concurrency" yaml:"concurrency,omitempty"`
+	// ExtraSources は読み取り専用でチケットを取得する別のJIRAサイトの一覧です
+	ExtraSources []ExtraSource `mapstructure:"extra_sources" yaml:"extra_sources,omitempty"`
+	Branch       struct {
+		// Template は tkt branch で作成するブランチ名のテンプレートです (text/template形式)
+		Template string `mapstructure:"template" yaml:"template,omitempty"`
+	} `mapstructure:"branch" yaml:"branch,omitempty"`
+
+	// ignoreTokenEnv がtrueの場合、APIトークンを環境変数から取得しません (追加ソース用)
+	ignoreTokenEnv bool
+}
+
+// BoardIDs はスプリントを探すボードのIDを重複なしで返します
+// board.idとboard.idsの両方が指定されている場合はboard.idを先頭にします。
+func (c *Config) BoardIDs() []int {
+	var ids []int
+	for _, id := range append([]int{c.Board.ID}, c.Board.IDs...) {
+		if id != 0 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// DefaultBranchTemplate はbranch.templateが未設定の場合のブランチ名のテンプレートです
+const DefaultBranchTemplate = "{{.Key}}-{{slug .Title}}"
+
+// BranchTemplate は tkt branch で使用するブランチ名のテンプレートを返します
+func (c *Config) BranchTemplate() string {
+	if c.Branch.Template == "" {
+		return DefaultBranchTemplate
+	}
+	return c.Branch.Template
+}
+
+// DefaultPushMaxCacheAge はpush.max_cache_ageが未設定の場合のデフォルト値です
+const DefaultPushMaxCacheAge = 24 * time.Hour
+
+// PushSyncBack はpush後にJIRAが正規化した内容をワークスペースのファイルに書き戻すかを返します
+func (c *Config) PushSyncBack() bool {
+	return c.Push.SyncBack == nil || *c.Push.SyncBack
+}
+
+// DurableWrites はfetchでキャッシュに書き込むときにfsyncするかを返します
+func (c *Config) DurableWrites() bool {
+	return c.Cache.DurableWrites == nil || *c.Cache.DurableWrites
+}
+
+// DefaultHTTPTimeout はhttp_timeoutが未設定の場合のデフォルト値です
+const DefaultHTTPTimeout = 60 * time.Second
+
+// HTTPTimeoutDuration はJIRA APIへの1回のリクエストのタイムアウトを返します
+func (c *Config) HTTPTimeoutDuration() (time.Duration, error) {
+	if c.HTTPTimeout == "" {
+		return DefaultHTTPTimeout, nil
+	}
+	d, err := time.ParseDuration(c.HTTPTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("http_timeout の形式が不正です (例: 30s): %q", c.HTTPTimeout)
+	}
+	return d, nil
+}
+
+// DefaultRetryMaxAttempts はretry.max_attemptsが未設定の場合のデフォルト値です
+const DefaultRetryMaxAttempts = 4
+
+// RetryMaxAttempts はJIRA APIへのリクエストを試行する最大の回数を返します
+func (c *Config) RetryMaxAttempts() int {
+	if c.Retry.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return c.Retry.MaxAttempts
+}
+
+// DefaultConcurrency はconcurrencyが未設定の場合のデフォルト値です
+const DefaultConcurrency = 5
+
+// RequestConcurrency は並列に送るJIRA APIへのリクエストの数を返します
+func (c *Config) RequestConcurrency() int {
+	if c.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return c.Concurrency
+}
+
+// PushMaxCacheAge はpush時に許容するキャッシュの古さを返します
+func (c *Config) PushMaxCacheAge() (time.Duration, error) {
+	if c.Push.MaxCacheAge == "" {
+		return DefaultPushMaxCacheAge, nil
+	}
+	d, err := time.ParseDuration(c.Push.MaxCacheAge)
+	if err != nil {
+		return 0, fmt.Errorf("push.max_cache_age の形式が不正です (例: 24h): %v", err)
+	}
+	return d, nil
+}
+
+// Location はtimezoneのタイムゾーンを返します (未設定の場合はローカルのタイムゾーン)
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone の形式が不正です (例: Asia/Tokyo): %v", err)
+	}
+	return loc, nil
+}
+
+// Issue Typeの階層レベル
+const (
+	HierarchySubtask  = -1
+	HierarchyStandard = 0
+	HierarchyEpic     = 1
+)
+
+// HierarchyLevel はIssue Typeの階層レベルを返します
+// issue.typesでsubtaskのものはサブタスク、名前がEpic (エピック) のものはエピック、それ以外は標準として扱います。
+func (c *Config) HierarchyLevel(typeName string) int {
+	for _, it := range c.Issue.Types {
+		if !strings.EqualFold(it.Name, typeName) && !strings.EqualFold(it.UntranslatedName, typeName) {
+			continue
+		}
+		if it.Subtask {
+			return HierarchySubtask
+		}
+		if isEpicName(it.Name) || isEpicName(it.UntranslatedName) {
+			return HierarchyEpic
+		}
+		return HierarchyStandard
+	}
+	if isEpicName(typeName) {
+		return HierarchyEpic
+	}
+	return HierarchyStandard
+}
+
+func isEpicName(name string) bool {
+	name = strings.ToLower(name)
+	return name == "epic" || name == "エピック"
+}
+
+// CheckParent は子と親のIssue Typeの組み合わせが階層のルールに合うかを検証します
+// サブタスクの親は標準のIssue Type、標準のIssue Typeの親はエピックである必要があります。
+func (c *Config) CheckParent(childType, parentType string) error {
+	child := c.HierarchyLevel(childType)
+	parent := c.HierarchyLevel(parentType)
+	if parent == child+1 {
+		return nil
+	}
+	switch child {
+	case HierarchySubtask:
+		return fmt.Errorf("サブタスク (%s) の親は標準のチケットである必要があります (親: %s)", childType, parentType)
+	case HierarchyStandard:
+		return fmt.Errorf("%s の親はエピックである必要があります (親: %s)", childType, parentType)
+	default:
+		return fmt.Errorf("エピック (%s) には親を設定できません (親: %s)", childType, parentType)
+	}
 }
 
 // LoadConfig は設定ファイルを読み込みます
@@ -84,9 +328,55 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("設定ファイルのパースに失敗しました: %v", err)
 	}
 
+	// directoryを設定ファイルのあるディレクトリからの相対パスに正規化
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("作業ディレクトリの取得に失敗しました: %v", err)
+	}
+	config.Directory, err = normalizeDirectory(config.Directory, root, config.AllowExternalDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.FilenameStyle {
+	case "", "key", "key-slug":
+	default:
+		return nil, fmt.Errorf("filename_style の値が不正です: %q (key または key-slug を指定してください)", config.FilenameStyle)
+	}
+	if config.Diff.Context != nil && *config.Diff.Context < 0 {
+		return nil, fmt.Errorf("diff.context には0以上の値を指定してください: %d", *config.Diff.Context)
+	}
+	if err := validateExtraSources(config.ExtraSources); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// normalizeDirectory はdirectoryの設定値を正規化します
+// プロジェクト (root) 内のパスは "./tmp/" や "tmp" などの表記の違いをなくした相対パスにします。
+// プロジェクトの外を指す場合は、allowExternalがtrueのときのみ絶対パスとして許可します。
+func normalizeDirectory(dir, root string, allowExternal bool) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+
+	abs := dir
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, abs)
+	}
+	abs = filepath.Clean(abs)
+
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if !allowExternal {
+			return "", fmt.Errorf("directory (%s) がプロジェクト (%s) の外を指しています。外部のディレクトリを使う場合は allow_external_directory: true を設定してください", dir, root)
+		}
+		return abs, nil
+	}
+	return rel, nil
+}
+
 // EnsureCacheDir はキャッシュディレクトリを確保します
 func EnsureCacheDir() (string, error) {
 	config, err := LoadConfig()
@@ -104,38 +394,167 @@ func EnsureCacheDir() (string, error) {
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return "", fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
 	}
+	if err := writeCacheReadme(cacheDir); err != nil {
+		return "", err
+	}
 	return cacheDir, nil
 }
 
-// ClearCacheDir はキャッシュディレクトリを削除し、再作成します
-func ClearCacheDir() (_ string, err error) {
+// cacheReadmeFile はキャッシュディレクトリがtktの管理下にあることを説明するファイルです
+const cacheReadmeFile = "README.txt"
+
+const cacheReadme = `This directory is managed by tkt.
+
+It holds the tickets as last fetched from JIRA and is used as the base for
+tkt diff and tkt push. The files are read-only on purpose: editing them
+corrupts the diff base. Edit the files in your workspace directory instead.
+
+If a file here was edited by mistake, run 'tkt fetch --clean' to restore it.
+`
+
+// writeCacheReadme はキャッシュディレクトリにREADME.txtがなければ作成します
+func writeCacheReadme(cacheDir string) error {
+	path := filepath.Join(cacheDir, cacheReadmeFile)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(cacheReadme), 0444); err != nil {
+		return fmt.Errorf("キャッシュのREADMEの作成に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// CreateCacheStagingDir はクリーンフェッチでチケットを書き込む一時ディレクトリを、キャッシュディレクトリの隣に作成します
+// 書き込みが終わったら ReplaceCacheDir で既存のキャッシュと入れ替えます。
+// 途中で失敗した場合は一時ディレクトリを削除すれば、既存のキャッシュはそのまま残ります。
+func CreateCacheStagingDir() (_ string, err error) {
 	defer derrors.Wrap(&err)
 
 	config, err := LoadConfig()
 	if err != nil {
 		return "", err
 	}
+	cacheDir, err := CacheDir(config)
+	if err != nil {
+		return "", err
+	}
 
-	workDir, err := os.Getwd()
+	// リネームで入れ替えられるように、同じファイルシステムのキャッシュディレクトリの隣に作成する
+	parent := filepath.Dir(cacheDir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %v", err)
+	}
+	stagingDir, err := os.MkdirTemp(parent, filepath.Base(cacheDir)+".staging-*")
 	if err != nil {
+		return "", fmt.Errorf("一時ディレクトリの作成に失敗しました: %v", err)
+	}
+	if err := writeCacheReadme(stagingDir); err != nil {
+		os.RemoveAll(stagingDir)
 		return "", err
 	}
+	return stagingDir, nil
+}
 
-	cacheDir := getCacheDir(config, workDir)
+// ReplaceCacheDir は CreateCacheStagingDir で作成した一時ディレクトリを既存のキャッシュディレクトリと入れ替え、
+// キャッシュディレクトリのパスを返します
+func ReplaceCacheDir(stagingDir string) (_ string, err error) {
+	defer derrors.Wrap(&err)
 
-	// キャッシュディレクトリを削除
-	if err := os.RemoveAll(cacheDir); err != nil {
+	config, err := LoadConfig()
+	if err != nil {
 		return "", err
 	}
-
-	// 再度ディレクトリを作成
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	cacheDir, err := CacheDir(config)
+	if err != nil {
+		return "", err
+	}
+	if err := replaceDir(stagingDir, cacheDir); err != nil {
 		return "", err
 	}
-
 	return cacheDir, nil
 }
 
+// preservedCacheDirs はクリーンフェッチでキャッシュを入れ替えても引き継ぐディレクトリです
+// pushの履歴 (.history)、作成中の下書きの記録 (.pending)、追加ソースのチケット (.sources) は
+// フェッチした内容ではないので、入れ替えで削除しないようにします。
+var preservedCacheDirs = []string{".history", ".pending", ExtraSourcesDir}
+
+// replaceDir は stagingDir を cacheDir にリネームし、既存の cacheDir を削除します
+func replaceDir(stagingDir, cacheDir string) error {
+	// ピン留めはフェッチした内容ではないので入れ替え後も残す
+	pins, err := ReadPins(cacheDir)
+	if err != nil {
+		return err
+	}
+	if len(pins) > 0 {
+		if err := SavePins(stagingDir, pins); err != nil {
+			return err
+		}
+	}
+
+	moved, err := moveCacheDirs(cacheDir, stagingDir)
+	if err != nil {
+		return err
+	}
+	// 入れ替えに失敗した場合は、一時ディレクトリは呼び出し元で削除されるので引き継ぐディレクトリを元に戻す
+	restore := func() {
+		for _, name := range moved {
+			os.Rename(filepath.Join(stagingDir, name), filepath.Join(cacheDir, name))
+		}
+	}
+
+	// 既存のキャッシュを退避してから入れ替え、入れ替えに失敗した場合は元に戻す
+	oldDir := stagingDir + ".old"
+	if err := os.Rename(cacheDir, oldDir); err != nil && !os.IsNotExist(err) {
+		restore()
+		return fmt.Errorf("既存のキャッシュディレクトリの退避に失敗しました: %v", err)
+	}
+	if err := os.Rename(stagingDir, cacheDir); err != nil {
+		os.Rename(oldDir, cacheDir)
+		restore()
+		return fmt.Errorf("キャッシュディレクトリの入れ替えに失敗しました: %v", err)
+	}
+	if err := os.RemoveAll(oldDir); err != nil {
+		return fmt.Errorf("古いキャッシュディレクトリの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// moveCacheDirs は preservedCacheDirs のうち cacheDir にあるものを stagingDir に移動し、移動したディレクトリ名を返します
+// 途中で失敗した場合は移動したものを元に戻します。
+func moveCacheDirs(cacheDir, stagingDir string) ([]string, error) {
+	var moved []string
+	for _, name := range preservedCacheDirs {
+		src := filepath.Join(cacheDir, name)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(stagingDir, name)
+		// リネームできるように、一時ディレクトリに同名のディレクトリがあれば削除する
+		if err := os.RemoveAll(dst); err != nil {
+			return nil, fmt.Errorf("%s の引き継ぎに失敗しました: %v", name, err)
+		}
+		if err := os.Rename(src, dst); err != nil {
+			for _, m := range moved {
+				os.Rename(filepath.Join(stagingDir, m), filepath.Join(cacheDir, m))
+			}
+			return nil, fmt.Errorf("%s の引き継ぎに失敗しました: %v", name, err)
+		}
+		moved = append(moved, name)
+	}
+	return moved, nil
+}
+
+// CacheDir はカレントディレクトリと設定から算出したキャッシュディレクトリのパスを返します
+// EnsureCacheDir と異なり、ディレクトリの作成は行いません。
+func CacheDir(config *Config) (string, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("作業ディレクトリの取得に失敗しました: %v", err)
+	}
+	return getCacheDir(config, workDir), nil
+}
+
 // getCacheDir はプロジェクト固有のキャッシュディレクトリパスを生成します
 func getCacheDir(config *Config, workDir string) string {
 	// ハッシュ値を生成するための文字列を作成
@@ -159,6 +578,12 @@ func GetLastFetchTime() (time.Time, error) {
 		return time.Time{}, fmt.Errorf("キャッシュディレクトリの確保に失敗しました: %v", err)
 	}
 
+	return ReadLastFetchTime(cacheDir)
+}
+
+// ReadLastFetchTime は指定したキャッシュディレクトリから最終フェッチ時刻を読み込みます
+// まだフェッチしていない場合はゼロ値を返します。
+func ReadLastFetchTime(cacheDir string) (time.Time, error) {
 	timestampFile := filepath.Join(cacheDir, "last_fetch.txt")
 	data, err := os.ReadFile(timestampFile)
 	if err != nil {
@@ -183,13 +608,141 @@ func SaveLastFetchTime(timestamp time.Time) error {
 		return fmt.Errorf("キャッシュディレクトリの確保に失敗しました: %v", err)
 	}
 
+	return WriteLastFetchTime(cacheDir, timestamp)
+}
+
+// WriteLastFetchTime は指定したキャッシュディレクトリに最終フェッチ時刻を保存します
+func WriteLastFetchTime(cacheDir string, timestamp time.Time) error {
 	timestampFile := filepath.Join(cacheDir, "last_fetch.txt")
 	data := timestamp.Format(time.RFC3339)
 
-	err = os.WriteFile(timestampFile, []byte(data), 0644)
-	if err != nil {
+	if err := os.WriteFile(timestampFile, []byte(data), 0644); err != nil {
 		return fmt.Errorf("最終フェッチ時刻の保存に失敗しました: %v", err)
 	}
 
 	return nil
 }
+
+// FetchMode はフェッチの種類を表します
+type FetchMode string
+
+const (
+	FetchModeFull        FetchMode = "full"
+	FetchModeIncremental FetchMode = "incremental"
+)
+
+// CacheMetadata はキャッシュがどの設定・JQLで取得されたかを表します
+// フェッチのたびにキャッシュディレクトリの metadata.json に保存されます。
+type CacheMetadata struct {
+	Server      string    `json:"server"`
+	ProjectKey  string    `json:"project_key"`
+	JQL         string    `json:"jql"`
+	ExpandedJQL string    `json:"expanded_jql"`
+	FetchMode   FetchMode `json:"fetch_mode"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	TicketCount int       `json:"ticket_count"`
+}
+
+const cacheMetadataFile = "metadata.json"
+
+// MatchesConfig はキャッシュ取得時の設定が現在の設定と一致するかを返します
+func (m *CacheMetadata) MatchesConfig(config *Config) bool {
+	return m.Server == config.Server && m.JQL == config.JQL && m.ProjectKey == config.Project.Key
+}
+
+// workspaceMetadataDir はワークスペースごとに最後のフェッチのメタデータを保存するディレクトリです
+// キャッシュディレクトリはサーバーとJQLから決まるため、設定を変えると別のディレクトリになり、
+// キャッシュディレクトリ内の metadata.json では設定の変更に気づけません。
+func workspaceMetadataDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".cache", "tkt", "workspaces")
+}
+
+// workspaceMetadataPath はカレントディレクトリのワークスペースのメタデータのパスを返します
+func workspaceMetadataPath() (string, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("作業ディレクトリの取得に失敗しました: %v", err)
+	}
+	hash := sha256.Sum256([]byte(workDir))
+	hashStr := fmt.Sprintf("%x", hash)[:16]
+	return filepath.Join(workspaceMetadataDir(), hashStr+".json"), nil
+}
+
+// SaveWorkspaceMetadata はカレントディレクトリのワークスペースで最後にフェッチしたときのメタデータを保存します
+// キャッシュディレクトリの外に保存するので、サーバーやJQLを変えた後も前回の設定と比較できます。
+func SaveWorkspaceMetadata(meta CacheMetadata) error {
+	path, err := workspaceMetadataPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("ワークスペースのメタデータのディレクトリの作成に失敗しました: %v", err)
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ワークスペースのメタデータの生成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("ワークスペースのメタデータの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ReadWorkspaceMetadata はカレントディレクトリのワークスペースで最後にフェッチしたときのメタデータを読み込みます
+// まだフェッチしていない場合は nil を返します。
+func ReadWorkspaceMetadata() (*CacheMetadata, error) {
+	path, err := workspaceMetadataPath()
+	if err != nil {
+		return nil, err
+	}
+	return readMetadataFile(path)
+}
+
+// SaveCacheMetadata はキャッシュのメタデータを保存します
+// TicketCount はキャッシュディレクトリ内のチケット数で上書きされます。
+func SaveCacheMetadata(cacheDir string, meta CacheMetadata) error {
+	files, err := filepath.Glob(filepath.Join(cacheDir, "*.md"))
+	if err != nil {
+		return fmt.Errorf("キャッシュ内のチケットの列挙に失敗しました: %v", err)
+	}
+	meta.TicketCount = 0
+	for _, file := range files {
+		// ドットで始まるファイル（削除マークされたもの）は数えない
+		if !strings.HasPrefix(filepath.Base(file), ".") {
+			meta.TicketCount++
+		}
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("キャッシュメタデータの生成に失敗しました: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cacheDir, cacheMetadataFile), data, 0644); err != nil {
+		return fmt.Errorf("キャッシュメタデータの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ReadCacheMetadata はキャッシュのメタデータを読み込みます
+// メタデータが存在しない場合は nil を返します。
+func ReadCacheMetadata(cacheDir string) (*CacheMetadata, error) {
+	return readMetadataFile(filepath.Join(cacheDir, cacheMetadataFile))
+}
+
+// readMetadataFile はメタデータのファイルを読み込みます (存在しない場合は nil)
+func readMetadataFile(path string) (*CacheMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("キャッシュメタデータの読み込みに失敗しました: %v", err)
+	}
+
+	var meta CacheMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("キャッシュメタデータのパースに失敗しました: %v", err)
+	}
+	return &meta, nil
+}
@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
 )
 
 func TestGetCacheDir(t *testing.T) {
@@ -99,3 +101,515 @@ func TestGetCacheDir(t *testing.T) {
 		})
 	}
 }
+
+func TestReadLastFetchTime(t *testing.T) {
+	t.Run("returns zero time when not fetched yet", func(t *testing.T) {
+		got, err := ReadLastFetchTime(t.TempDir())
+		assert.NoError(t, err)
+		assert.True(t, got.IsZero())
+	})
+
+	t.Run("reads saved timestamp", func(t *testing.T) {
+		dir := t.TempDir()
+		want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		err := os.WriteFile(filepath.Join(dir, "last_fetch.txt"), []byte(want.Format(time.RFC3339)), 0644)
+		assert.NoError(t, err)
+
+		got, err := ReadLastFetchTime(dir)
+		assert.NoError(t, err)
+		assert.True(t, want.Equal(got))
+	})
+}
+
+func TestCacheMetadata(t *testing.T) {
+	t.Run("returns nil when metadata does not exist", func(t *testing.T) {
+		meta, err := ReadCacheMetadata(t.TempDir())
+		assert.NoError(t, err)
+		assert.Nil(t, meta)
+	})
+
+	t.Run("saves and reads metadata with ticket count", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"PRJ-1.md", "PRJ-2.md", ".PRJ-3.md"} {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("---\n---\n"), 0644))
+		}
+		fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		err := SaveCacheMetadata(dir, CacheMetadata{
+			Server:      "https://company.atlassian.net",
+			ProjectKey:  "PRJ",
+			JQL:         "project = PRJ",
+			ExpandedJQL: "project = PRJ",
+			FetchMode:   FetchModeFull,
+			FetchedAt:   fetchedAt,
+		})
+		assert.NoError(t, err)
+
+		meta, err := ReadCacheMetadata(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, meta.TicketCount)
+		assert.Equal(t, FetchModeFull, meta.FetchMode)
+		assert.True(t, fetchedAt.Equal(meta.FetchedAt))
+
+		cfg := &Config{Server: "https://company.atlassian.net", JQL: "project = PRJ"}
+		cfg.Project.Key = "PRJ"
+		assert.True(t, meta.MatchesConfig(cfg))
+		assert.False(t, meta.MatchesConfig(&Config{Server: "https://company.atlassian.net", JQL: "project = OTHER"}))
+		// プロジェクトはキャッシュディレクトリのハッシュに含まれないので、メタデータで比較する
+		cfg.Project.Key = "OTHER"
+		assert.False(t, meta.MatchesConfig(cfg))
+	})
+}
+
+func TestWorkspaceMetadata(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	meta, err := ReadWorkspaceMetadata()
+	assert.NoError(t, err)
+	assert.Nil(t, meta)
+
+	assert.NoError(t, SaveWorkspaceMetadata(CacheMetadata{Server: "https://company.atlassian.net", ProjectKey: "PRJ", JQL: "project = PRJ"}))
+	meta, err = ReadWorkspaceMetadata()
+	assert.NoError(t, err)
+	assert.Equal(t, "project = PRJ", meta.JQL)
+
+	// 別のワークスペースの記録とは分ける
+	t.Chdir(t.TempDir())
+	meta, err = ReadWorkspaceMetadata()
+	assert.NoError(t, err)
+	assert.Nil(t, meta)
+}
+
+func TestPushMaxCacheAge(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "未設定の場合はデフォルト", value: "", want: DefaultPushMaxCacheAge},
+		{name: "時間で指定", value: "12h", want: 12 * time.Hour},
+		{name: "分で指定", value: "30m", want: 30 * time.Minute},
+		{name: "不正な形式", value: "1day", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Push.MaxCacheAge = tt.value
+			got, err := cfg.PushMaxCacheAge()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHTTPTimeoutDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "未設定", want: DefaultHTTPTimeout},
+		{name: "秒で指定", value: "30s", want: 30 * time.Second},
+		{name: "不正な形式", value: "30", wantErr: true},
+		{name: "0以下", value: "0s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{HTTPTimeout: tt.value}
+			got, err := cfg.HTTPTimeoutDuration()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequestConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		want        int
+	}{
+		{name: "未設定の場合はデフォルト", concurrency: 0, want: DefaultConcurrency},
+		{name: "設定した値", concurrency: 2, want: 2},
+		{name: "負の値はデフォルト", concurrency: -1, want: DefaultConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Concurrency: tt.concurrency}
+			assert.Equal(t, tt.want, cfg.RequestConcurrency())
+		})
+	}
+}
+
+func TestBoardIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		id   int
+		ids  []int
+		want []int
+	}{
+		{name: "未設定", want: nil},
+		{name: "board.idのみ", id: 12, want: []int{12}},
+		{name: "board.idsのみ", ids: []int{12, 34}, want: []int{12, 34}},
+		{name: "両方指定した場合は重複を除く", id: 34, ids: []int{12, 34}, want: []int{34, 12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.Board.ID = tt.id
+			cfg.Board.IDs = tt.ids
+			assert.Equal(t, tt.want, cfg.BoardIDs())
+		})
+	}
+}
+
+func TestCheckParent(t *testing.T) {
+	cfg := &Config{}
+	cfg.Issue.Types = []IssueType{
+		{Name: "エピック", UntranslatedName: "Epic"},
+		{Name: "ストーリー", UntranslatedName: "Story"},
+		{Name: "タスク", UntranslatedName: "Task"},
+		{Name: "サブタスク", UntranslatedName: "Subtask", Subtask: true},
+	}
+
+	tests := []struct {
+		name    string
+		child   string
+		parent  string
+		wantErr bool
+	}{
+		{name: "ストーリーの親がエピック", child: "story", parent: "epic"},
+		{name: "サブタスクの親がタスク", child: "サブタスク", parent: "タスク"},
+		{name: "設定にないタイプは標準として扱う", child: "bug", parent: "epic"},
+		{name: "タスクの親がストーリー", child: "task", parent: "story", wantErr: true},
+		{name: "サブタスクの親がエピック", child: "subtask", parent: "epic", wantErr: true},
+		{name: "エピックの親がエピック", child: "epic", parent: "エピック", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.CheckParent(tt.child, tt.parent)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeDirectory(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "home", "user", "project")
+
+	tests := []struct {
+		name          string
+		dir           string
+		allowExternal bool
+		want          string
+		wantErr       bool
+	}{
+		{name: "未設定", dir: "", want: ""},
+		{name: "相対パス", dir: "tmp", want: "tmp"},
+		{name: "末尾のスラッシュ", dir: "tmp/", want: "tmp"},
+		{name: "先頭の./", dir: "./tmp/", want: "tmp"},
+		{name: "重複したスラッシュ", dir: "tickets//jira", want: filepath.Join("tickets", "jira")},
+		{name: "プロジェクト内の絶対パス", dir: filepath.Join(root, "tmp"), want: "tmp"},
+		{name: "プロジェクトの外の相対パス", dir: "../other", wantErr: true},
+		{name: "プロジェクトの外の絶対パス", dir: filepath.Join(string(filepath.Separator), "var", "tickets"), wantErr: true},
+		{name: "外部ディレクトリを許可", dir: filepath.Join(string(filepath.Separator), "var", "tickets") + "/", allowExternal: true, want: filepath.Join(string(filepath.Separator), "var", "tickets")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeDirectory(tt.dir, root, tt.allowExternal)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadConfigNormalizesDirectory(t *testing.T) {
+	for _, dir := range []string{"./tmp/", "tmp", "tmp/"} {
+		t.Run(dir, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			content := "server: https://example.atlassian.net\nproject:\n  key: PRJ\ndirectory: " + dir + "\n"
+			assert.NoError(t, os.WriteFile("tkt.yml", []byte(content), 0644))
+
+			cfg, err := LoadConfig()
+			assert.NoError(t, err)
+			// diff・push・rmはいずれもcfg.Directoryを基準にファイルを参照するため、
+			// 表記によらず同じ値になっていれば同じファイルを扱う
+			assert.Equal(t, "tmp", cfg.Directory)
+		})
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0600))
+
+	tests := []struct {
+		name       string
+		env        string
+		keyring    string
+		cfg        Config
+		wantToken  string
+		wantSource string
+		wantErr    bool
+	}{
+		{name: "環境変数を優先", env: "env-token", cfg: Config{TokenFile: tokenFile}, wantToken: "env-token", wantSource: "環境変数 JIRA_API_TOKEN"},
+		{name: "token_command", cfg: Config{TokenCommand: "echo command-token", TokenFile: tokenFile}, wantToken: "command-token", wantSource: "token_command (echo command-token)"},
+		{name: "token_file", cfg: Config{TokenFile: tokenFile}, wantToken: "file-token", wantSource: "token_file (" + tokenFile + ")"},
+		{name: "token_fileが存在しない", cfg: Config{TokenFile: filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "token_commandが失敗", cfg: Config{TokenCommand: "exit 1"}, wantErr: true},
+		{name: "未設定", wantToken: "", wantSource: ""},
+		{name: "追加ソースは環境変数を使わない", env: "env-token", cfg: Config{TokenCommand: "echo source-token", ignoreTokenEnv: true}, wantToken: "source-token", wantSource: "token_command (echo source-token)"},
+		{name: "キーリングを優先", env: "env-token", keyring: "keyring-token", cfg: Config{Server: "https://example.atlassian.net/", TokenFile: tokenFile}, wantToken: "keyring-token", wantSource: "キーリング (https://example.atlassian.net)"},
+		{name: "キーリングは他のサーバーのトークンを使わない", env: "env-token", keyring: "keyring-token", cfg: Config{Server: "https://other.atlassian.net"}, wantToken: "env-token", wantSource: "環境変数 JIRA_API_TOKEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring.MockInit()
+			if tt.keyring != "" {
+				assert.NoError(t, SaveKeyringToken("https://example.atlassian.net", tt.keyring))
+			}
+			t.Setenv(APITokenEnv, tt.env)
+			token, source, err := tt.cfg.APIToken()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantToken, token)
+			assert.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestReplaceIssueTypes(t *testing.T) {
+	data := []byte(`# コメント
+server: https://example.atlassian.net
+issue:
+    types:
+        - id: "10001"
+          description: ""
+          name: Task
+          untranslated_name: Task
+          subtask: false
+jql: project = PRJ
+`)
+	updated, err := replaceIssueTypes(data, []IssueType{{ID: "10001", Name: "作業", UntranslatedName: "Task"}})
+	assert.NoError(t, err)
+	out := string(updated)
+	assert.Contains(t, out, "# コメント")
+	assert.Contains(t, out, "jql: project = PRJ")
+	assert.Contains(t, out, "name: 作業")
+	assert.NotContains(t, out, " name: Task")
+
+	// issueがない場合は追加する
+	updated, err = replaceIssueTypes([]byte("server: https://example.atlassian.net\n"), []IssueType{{ID: "10002", Name: "Bug"}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(updated), "issue:\n    types:\n        - id: \"10002\"")
+}
+
+func TestReplaceJQL(t *testing.T) {
+	data := []byte("# コメント\nserver: https://example.atlassian.net\njql: project = PRJ\ndirectory: tickets\n")
+	updated, err := replaceJQL(data, "project = PRJ AND statusCategory != Done")
+	assert.NoError(t, err)
+	out := string(updated)
+	assert.Contains(t, out, "# コメント")
+	assert.Contains(t, out, "jql: project = PRJ AND statusCategory != Done\ndirectory: tickets")
+
+	// jqlがない場合は追加する
+	updated, err = replaceJQL([]byte("server: https://example.atlassian.net\n"), "project = PRJ")
+	assert.NoError(t, err)
+	assert.Contains(t, string(updated), "jql: project = PRJ")
+}
+
+func TestPins(t *testing.T) {
+	dir := t.TempDir()
+
+	// ピン留めがなければ空
+	pins, err := ReadPins(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, pins)
+
+	assert.NoError(t, SavePins(dir, []string{"PRJ-2", "PRJ-1"}))
+	pins, err = ReadPins(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PRJ-2", "PRJ-1"}, pins)
+
+	assert.NoError(t, SavePins(dir, nil))
+	pins, err = ReadPins(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, pins)
+}
+
+func TestReplaceDir(t *testing.T) {
+	parent := t.TempDir()
+	cacheDir := filepath.Join(parent, "cache")
+	assert.NoError(t, os.MkdirAll(cacheDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, "PRJ-1.md"), []byte("古い内容"), 0444))
+	assert.NoError(t, SavePins(cacheDir, []string{"PRJ-1"}))
+
+	stagingDir := filepath.Join(parent, "cache.staging")
+	assert.NoError(t, os.MkdirAll(stagingDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(stagingDir, "PRJ-2.md"), []byte("新しい内容"), 0444))
+
+	assert.NoError(t, replaceDir(stagingDir, cacheDir))
+
+	// 一時ディレクトリの内容に入れ替わり、ピン留めは引き継ぐ
+	_, err := os.Stat(filepath.Join(cacheDir, "PRJ-1.md"))
+	assert.True(t, os.IsNotExist(err))
+	data, err := os.ReadFile(filepath.Join(cacheDir, "PRJ-2.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "新しい内容", string(data))
+	pins, err := ReadPins(cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PRJ-1"}, pins)
+
+	// 一時ディレクトリと退避したディレクトリは残らない
+	entries, err := os.ReadDir(parent)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestReplaceDirPreservesDirs(t *testing.T) {
+	parent := t.TempDir()
+	cacheDir := filepath.Join(parent, "cache")
+	for _, name := range []string{".history", ".pending", filepath.Join(ExtraSourcesDir, "OTHER-0a1b2c3d")} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(cacheDir, name), 0755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, ".history", "history.jsonl"), []byte("{}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, ".pending", "draft.json"), []byte("{}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(cacheDir, ExtraSourcesDir, "OTHER-0a1b2c3d", "OTHER-1.md"), []byte("追加ソース"), 0444))
+
+	stagingDir := filepath.Join(parent, "cache.staging")
+	assert.NoError(t, os.MkdirAll(stagingDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(stagingDir, "PRJ-2.md"), []byte("新しい内容"), 0444))
+
+	assert.NoError(t, replaceDir(stagingDir, cacheDir))
+
+	// pushの履歴、作成中の下書きの記録、追加ソースのチケットは入れ替え後も残る
+	for _, path := range []string{
+		filepath.Join(".history", "history.jsonl"),
+		filepath.Join(".pending", "draft.json"),
+		filepath.Join(ExtraSourcesDir, "OTHER-0a1b2c3d", "OTHER-1.md"),
+		"PRJ-2.md",
+	} {
+		_, err := os.Stat(filepath.Join(cacheDir, path))
+		assert.NoError(t, err, path)
+	}
+	entries, err := os.ReadDir(parent)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestReplaceDirWithoutCache(t *testing.T) {
+	parent := t.TempDir()
+	cacheDir := filepath.Join(parent, "cache")
+	stagingDir := filepath.Join(parent, "cache.staging")
+	assert.NoError(t, os.MkdirAll(stagingDir, 0755))
+
+	// 初回のクリーンフェッチでキャッシュディレクトリがない場合
+	assert.NoError(t, replaceDir(stagingDir, cacheDir))
+	info, err := os.Stat(cacheDir)
+	assert.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestExtraSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		sources    []ExtraSource
+		wantPrefix []string
+		wantErr    string
+	}{
+		{
+			name: "ホスト名の最初のラベルをプレフィックスにする",
+			sources: []ExtraSource{
+				{Server: "https://Other.atlassian.net/", JQL: "project = ABC", TokenCommand: "echo t"},
+				{Server: "https://platform.example.com", JQL: "project = PLT", TokenCommand: "echo t"},
+			},
+			wantPrefix: []string{"other", "platform"},
+		},
+		{
+			name:    "jqlがない",
+			sources: []ExtraSource{{Server: "https://other.atlassian.net", TokenCommand: "echo t"}},
+			wantErr: "extra_sources[0] には server, jql, token_command を指定してください",
+		},
+		{
+			name: "プレフィックスが重複",
+			sources: []ExtraSource{
+				{Server: "https://other.atlassian.net", JQL: "project = A", TokenCommand: "echo t"},
+				{Server: "https://other.example.com", JQL: "project = B", TokenCommand: "echo t"},
+			},
+			wantErr: "extra_sources のサイトのプレフィックスが重複しています: other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtraSources(tt.sources)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var prefixes []string
+			for _, s := range tt.sources {
+				prefixes = append(prefixes, s.Prefix())
+			}
+			assert.Equal(t, tt.wantPrefix, prefixes)
+		})
+	}
+}
+
+func TestExtraSourceConfig(t *testing.T) {
+	main := &Config{Login: "me@example.com", Server: "https://main.atlassian.net", JQL: "project = PRJ", Timezone: "Asia/Tokyo"}
+	main.Project.Key = "PRJ"
+	main.Retry.MaxAttempts = 6
+
+	cfg := ExtraSource{Server: "https://other.atlassian.net/", JQL: "project = ABC", TokenCommand: "echo t"}.Config(main)
+	assert.Equal(t, "https://other.atlassian.net", cfg.Server)
+	assert.Equal(t, "project = ABC", cfg.JQL)
+	assert.Equal(t, "me@example.com", cfg.Login)
+	assert.Equal(t, "Asia/Tokyo", cfg.Timezone)
+	assert.Equal(t, 6, cfg.RetryMaxAttempts())
+	assert.Empty(t, cfg.Project.Key)
+}
+
+func TestPruneExtraSourceCacheDirs(t *testing.T) {
+	cacheDir := t.TempDir()
+	current := ExtraSource{Server: "https://other.atlassian.net", JQL: "project = ABC"}
+	changed := ExtraSource{Server: "https://other.atlassian.net", JQL: "project = OLD"}
+	removed := ExtraSource{Server: "https://gone.atlassian.net", JQL: "project = GONE"}
+	for _, s := range []ExtraSource{current, changed, removed} {
+		assert.NoError(t, os.MkdirAll(ExtraSourceCacheDir(cacheDir, s), 0755))
+	}
+	// JQLを変えると別のディレクトリになる
+	assert.NotEqual(t, ExtraSourceCacheDir(cacheDir, current), ExtraSourceCacheDir(cacheDir, changed))
+
+	assert.NoError(t, PruneExtraSourceCacheDirs(cacheDir, []ExtraSource{current}))
+
+	entries, err := os.ReadDir(filepath.Join(cacheDir, ExtraSourcesDir))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, filepath.Base(ExtraSourceCacheDir(cacheDir, current)), entries[0].Name())
+
+	// 追加ソースのディレクトリがない場合
+	assert.NoError(t, PruneExtraSourceCacheDirs(t.TempDir(), nil))
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FindIssueType は名前 (表示名または翻訳前の名前、大文字小文字は区別しない) でissue.typesからIssue Typeを探します
+func (c *Config) FindIssueType(name string) (IssueType, bool) {
+	for _, it := range c.Issue.Types {
+		if strings.EqualFold(it.Name, name) || (it.UntranslatedName != "" && strings.EqualFold(it.UntranslatedName, name)) {
+			return it, true
+		}
+	}
+	return IssueType{}, false
+}
+
+// IssueTypeByID はIDでissue.typesからIssue Typeを探します
+func (c *Config) IssueTypeByID(id string) (IssueType, bool) {
+	for _, it := range c.Issue.Types {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	return IssueType{}, false
+}
+
+// SaveIssueTypes は設定ファイルのissue.typesを置き換えます
+// 他の設定やコメントはそのまま残します。
+func SaveIssueTypes(types []IssueType) error {
+	const configFile = "tkt.yml"
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	updated, err := replaceIssueTypes(data, types)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(configFile, updated, 0644); err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗しました: %v", err)
+	}
+	return nil
+}
+
+// replaceIssueTypes はYAMLのissue.typesを置き換えます (issueやtypesがなければ追加します)
+func replaceIssueTypes(data []byte, types []IssueType) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("設定ファイルのパースに失敗しました: %v", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("設定ファイルの形式が不正です")
+	}
+
+	var typesNode yaml.Node
+	if err := typesNode.Encode(types); err != nil {
+		return nil, fmt.Errorf("issue.typesの生成に失敗しました: %v", err)
+	}
+	issue := mappingValue(doc.Content[0], "issue")
+	setMappingValue(issue, "types", &typesNode)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("設定ファイルの生成に失敗しました: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue はマッピングのキーの値 (マッピング) を返します。キーがないか値が空の場合は空のマッピングを設定します。
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key && m.Content[i+1].Kind == yaml.MappingNode {
+			return m.Content[i+1]
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(m, key, value)
+	return value
+}
+
+// setMappingValue はマッピングのキーの値を置き換えます。キーがなければ末尾に追加します。
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
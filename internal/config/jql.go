@@ -0,0 +1,48 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SaveJQL は設定ファイルのjqlを置き換えます
+// 他の設定やコメントはそのまま残します。
+func SaveJQL(jql string) error {
+	const configFile = "tkt.yml"
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	updated, err := replaceJQL(data, jql)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(configFile, updated, 0644); err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗しました: %v", err)
+	}
+	return nil
+}
+
+// replaceJQL はYAMLのjqlを置き換えます (jqlがなければ追加します)
+func replaceJQL(data []byte, jql string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("設定ファイルのパースに失敗しました: %v", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("設定ファイルの形式が不正です")
+	}
+
+	setMappingValue(doc.Content[0], "jql", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: jql})
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("設定ファイルの生成に失敗しました: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService はOSのキーリングにAPIトークンを保存するときのサービス名です
+// キーリングのユーザー名にはサーバーのURLを使うので、サイトごとに別のトークンを保存できます。
+const keyringService = "tkt"
+
+// keyringUser はサーバーのURLからキーリングのユーザー名を返します
+// 末尾の / の有無でトークンが見つからなくならないようにそろえます。
+func keyringUser(server string) string {
+	return strings.TrimRight(server, "/")
+}
+
+// SaveKeyringToken はサーバーのAPIトークンをOSのキーリングに保存します
+func SaveKeyringToken(server, token string) error {
+	if server == "" {
+		return fmt.Errorf("サーバーのURLが設定されていません")
+	}
+	if err := keyring.Set(keyringService, keyringUser(server), token); err != nil {
+		return fmt.Errorf("キーリングへのAPIトークンの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// KeyringToken はOSのキーリングからサーバーのAPIトークンを取得します
+// 保存していない場合は空文字を返します。キーリングを使えない環境 (Secret Serviceのないサーバーなど) ではエラーを返します。
+func KeyringToken(server string) (string, error) {
+	if server == "" {
+		return "", nil
+	}
+	token, err := keyring.Get(keyringService, keyringUser(server))
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("キーリングの読み込みに失敗しました: %v", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// parentTitlesFile はJQLの対象外の親チケットのタイトルを保存するファイルです
+// 親チケットの表示 (PRJ-100 (タイトル)) のために、フェッチ時にまとめて取得してキャッシュディレクトリに保存します。
+const parentTitlesFile = "parents.json"
+
+// ReadParentTitles は保存した親チケットのタイトル (キー -> タイトル) を読み込みます
+// 保存していない場合は空のマップを返します。
+func ReadParentTitles(cacheDir string) (map[string]string, error) {
+	titles := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(cacheDir, parentTitlesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return titles, nil
+		}
+		return nil, fmt.Errorf("親チケットのタイトルの読み込みに失敗しました: %v", err)
+	}
+	if err := json.Unmarshal(data, &titles); err != nil {
+		return nil, fmt.Errorf("親チケットのタイトルのパースに失敗しました: %v", err)
+	}
+	return titles, nil
+}
+
+// SaveParentTitles は親チケットのタイトル (キー -> タイトル) を保存します
+func SaveParentTitles(cacheDir string, titles map[string]string) error {
+	if titles == nil {
+		titles = map[string]string{}
+	}
+	data, err := json.MarshalIndent(titles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("親チケットのタイトルの生成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, parentTitlesFile), data, 0644); err != nil {
+		return fmt.Errorf("親チケットのタイトルの保存に失敗しました: %v", err)
+	}
+	return nil
+}
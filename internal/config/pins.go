@@ -0,0 +1,43 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pinsFile はピン留めしたチケットのキーを保存するファイルです (キャッシュディレクトリに保存するのでワークスペースごとです)
+const pinsFile = "pins.json"
+
+// ReadPins はピン留めしたチケットのキーをピン留めした順に読み込みます
+// ピン留めしたチケットがない場合は nil を返します。
+func ReadPins(cacheDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, pinsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ピン留めの読み込みに失敗しました: %v", err)
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("ピン留めのパースに失敗しました: %v", err)
+	}
+	return keys, nil
+}
+
+// SavePins はピン留めしたチケットのキーを保存します
+func SavePins(cacheDir string, keys []string) error {
+	if keys == nil {
+		keys = []string{}
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ピン留めの生成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, pinsFile), data, 0644); err != nil {
+		return fmt.Errorf("ピン留めの保存に失敗しました: %v", err)
+	}
+	return nil
+}
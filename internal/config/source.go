@@ -0,0 +1,107 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtraSource は extra_sources に指定した、読み取り専用でチケットを取得する別のJIRAサイトです
+// 取得したチケットはキャッシュの .sources/ 以下に保存し、grepやlsには表示しますが、pushやdiffの対象にはしません。
+type ExtraSource struct {
+	Server string `mapstructure:"server" yaml:"server"`
+	// Login はサイトのログインユーザーです (省略した場合はメインのサイトのlogin)
+	Login string `mapstructure:"login" yaml:"login,omitempty"`
+	JQL   string `mapstructure:"jql" yaml:"jql"`
+	// TokenCommand はサイトのAPIトークンを標準出力に出力するコマンドです
+	TokenCommand string `mapstructure:"token_command" yaml:"token_command"`
+}
+
+// Prefix はサイトを区別するプレフィックス (ホスト名の最初のラベル) を返します
+// 例: https://other.atlassian.net → other
+func (s ExtraSource) Prefix() string {
+	host := s.Server
+	if u, err := url.Parse(s.Server); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	label, _, _ := strings.Cut(host, ".")
+	return strings.ToLower(label)
+}
+
+// Config は追加ソースからチケットを取得するための設定を返します
+// APIトークンは token_command からのみ取得し、メインのサイト用の環境変数 JIRA_API_TOKEN は使いません。
+func (s ExtraSource) Config(main *Config) *Config {
+	cfg := &Config{
+		AuthType:         "basic",
+		Login:            s.Login,
+		Server:           strings.TrimRight(s.Server, "/"),
+		TokenCommand:     s.TokenCommand,
+		JQL:              s.JQL,
+		Timezone:         main.Timezone,
+		NormalizeOnFetch: main.NormalizeOnFetch,
+		ignoreTokenEnv:   true,
+	}
+	if cfg.Login == "" {
+		cfg.Login = main.Login
+	}
+	cfg.HTTPTimeout = main.HTTPTimeout
+	cfg.CABundle = main.CABundle
+	cfg.Retry = main.Retry
+	cfg.RateLimit = main.RateLimit
+	cfg.Concurrency = main.Concurrency
+	return cfg
+}
+
+// validateExtraSources は extra_sources の設定を検証します
+func validateExtraSources(sources []ExtraSource) error {
+	seen := make(map[string]bool)
+	for i, s := range sources {
+		if s.Server == "" || s.JQL == "" || s.TokenCommand == "" {
+			return fmt.Errorf("extra_sources[%d] には server, jql, token_command を指定してください", i)
+		}
+		prefix := s.Prefix()
+		if seen[prefix] {
+			return fmt.Errorf("extra_sources のサイトのプレフィックスが重複しています: %s", prefix)
+		}
+		seen[prefix] = true
+	}
+	return nil
+}
+
+// ExtraSourcesDir は追加ソースのチケットを保存するキャッシュ内のディレクトリ名です
+// ドットで始まるため、diffやpushでキャッシュを走査するときは対象になりません。
+const ExtraSourcesDir = ".sources"
+
+// ExtraSourceCacheDir は追加ソースのチケットを保存するディレクトリのパスを返します
+// サーバーやJQLを変更した場合は別のディレクトリになり、全件取得し直します。
+func ExtraSourceCacheDir(cacheDir string, s ExtraSource) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s", s.Server, s.JQL)))
+	return filepath.Join(cacheDir, ExtraSourcesDir, fmt.Sprintf("%s-%x", s.Prefix(), hash[:4]))
+}
+
+// PruneExtraSourceCacheDirs は設定から外した追加ソース (サーバーやJQLを変更したものを含む) のディレクトリを削除します
+func PruneExtraSourceCacheDirs(cacheDir string, sources []ExtraSource) error {
+	keep := make(map[string]bool)
+	for _, s := range sources {
+		keep[filepath.Base(ExtraSourceCacheDir(cacheDir, s))] = true
+	}
+	entries, err := os.ReadDir(filepath.Join(cacheDir, ExtraSourcesDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("追加ソースのキャッシュの読み込みに失敗しました: %v", err)
+	}
+	for _, entry := range entries {
+		if keep[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cacheDir, ExtraSourcesDir, entry.Name())); err != nil {
+			return fmt.Errorf("追加ソースのキャッシュの削除に失敗しました: %v", err)
+		}
+	}
+	return nil
+}
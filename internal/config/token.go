@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// APITokenEnv はAPIトークンを設定する環境変数の名前です
+const APITokenEnv = "JIRA_API_TOKEN"
+
+// APIToken はAPIトークンとその取得元を返します
+// OSのキーリング、環境変数 JIRA_API_TOKEN、token_command、token_file の順に探し、見つからない場合は空文字を返します。
+// キーリングを使えない環境でも他の取得元を使えるように、キーリングの読み込みの失敗は無視します。
+func (c *Config) APIToken() (token string, source string, err error) {
+	if token, err := KeyringToken(c.Server); err == nil && token != "" {
+		return token, "キーリング (" + keyringUser(c.Server) + ")", nil
+	}
+	if token := os.Getenv(APITokenEnv); token != "" && !c.ignoreTokenEnv {
+		return token, "環境変数 " + APITokenEnv, nil
+	}
+	if c.TokenCommand != "" {
+		source := fmt.Sprintf("token_command (%s)", c.TokenCommand)
+		out, err := exec.Command("sh", "-c", c.TokenCommand).Output()
+		if err != nil {
+			return "", source, fmt.Errorf("token_commandの実行に失敗しました: %v", err)
+		}
+		return strings.TrimSpace(string(out)), source, nil
+	}
+	if c.TokenFile != "" {
+		path := ExpandHome(c.TokenFile)
+		source := fmt.Sprintf("token_file (%s)", path)
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", source, fmt.Errorf("token_fileの読み込みに失敗しました: %v", err)
+		}
+		return strings.TrimSpace(string(b)), source, nil
+	}
+	return "", "", nil
+}
+
+// ExpandHome は先頭の ~/ をホームディレクトリに展開します
+func ExpandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
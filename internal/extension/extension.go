@@ -100,7 +100,7 @@ type Extension struct {
 
 // Execute runs the extension with the given arguments
 func (e Extension) Execute(args []string) error {
-	if verbose.Enabled {
+	if verbose.Enabled(verbose.General) {
 		fmt.Fprintf(os.Stderr, "Executing extension: %s %v\n", e.Path, args)
 	}
 
@@ -0,0 +1,305 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/adf"
+	"github.com/qawatake/tkt/internal/md"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// attachment はJIRAのAPIが返す添付ファイルです
+type attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	// Content は添付ファイルをダウンロードするURLです
+	Content string `json:"content"`
+}
+
+// EnableAttachments はチケットの取得時に添付ファイルの情報も要求するようにします
+// 有効にすると本文のメディアは assets/<KEY>/ の画像へのリンクになります。
+func (c *Client) EnableAttachments() {
+	c.withAttachments = true
+}
+
+// convertAttachments は添付ファイルの保存先を決めます
+// 同じ名前のファイルが複数ある場合、2つ目以降はIDを先頭に付けて区別します。
+func convertAttachments(key string, attachments []attachment) []ticket.Attachment {
+	var result []ticket.Attachment
+	used := make(map[string]bool)
+	for _, a := range attachments {
+		p := ticket.AttachmentPath(key, a.Filename)
+		if used[p] {
+			p = ticket.AttachmentPath(key, a.ID+"-"+a.Filename)
+		}
+		used[p] = true
+		result = append(result, ticket.Attachment{ID: a.ID, Filename: a.Filename, Size: a.Size, Path: p})
+	}
+	return result
+}
+
+// mediaResolver は本文のメディアを添付ファイルへの相対リンクに変換します
+// ADFのメディアのIDは添付ファイルのIDと異なるため、altのファイル名で対応付けます。
+func mediaResolver(attachments []ticket.Attachment) adf.MediaResolver {
+	return func(attrs map[string]any) (string, bool) {
+		alt, _ := attrs["alt"].(string)
+		if alt == "" {
+			return "", false
+		}
+		for _, a := range attachments {
+			if a.Filename == alt {
+				return escapeLinkPath(a.Path), true
+			}
+		}
+		return "", false
+	}
+}
+
+// escapeLinkPath はパスの各要素をエスケープしてMarkdownのリンクに使えるようにします
+func escapeLinkPath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return path.Join(parts...)
+}
+
+// DownloadAttachment は添付ファイルをdestに保存します
+// 途中で失敗しても不完全なファイルが残らないよう、一時ファイルに書いてから置き換えます。
+func (c *Client) DownloadAttachment(id, dest string) error {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/attachment/content/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("添付ファイル %s のダウンロードに失敗しました (status: %d)", id, resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".download-*")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("添付ファイル %s の書き込みに失敗しました: %v", id, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("添付ファイル %s の書き込みに失敗しました: %v", id, err)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("添付ファイル %s の保存に失敗しました: %v", id, err)
+	}
+	verbose.Printf(verbose.API, "添付ファイルを保存しました: %s\n", dest)
+	return nil
+}
+
+// DownloadAttachments はチケットの添付ファイルをdir配下に保存し、ダウンロードした件数を返します
+// 同じサイズのファイルが既にある場合はダウンロードをスキップします。
+func (c *Client) DownloadAttachments(dir string, tickets []*ticket.Ticket) (int, error) {
+	downloaded := 0
+	for _, t := range tickets {
+		for _, a := range t.Attachments {
+			dest := filepath.Join(dir, filepath.FromSlash(a.Path))
+			if info, err := os.Stat(dest); err == nil && info.Size() == a.Size {
+				verbose.Printf(verbose.API, "添付ファイルは取得済みのためスキップします: %s\n", dest)
+				continue
+			}
+			if err := c.DownloadAttachment(a.ID, dest); err != nil {
+				return downloaded, err
+			}
+			downloaded++
+		}
+	}
+	return downloaded, nil
+}
+
+// imageLinkPattern は本文のMarkdownの画像リンク (![alt](target)) です
+var imageLinkPattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// isRelativeImageTarget はリンク先がローカルの相対パスかどうかを返します
+func isRelativeImageTarget(target string) bool {
+	return !strings.Contains(target, "://") && !strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "data:")
+}
+
+// relativeImageLinks は本文の画像リンクのうち相対パスのものを重複なく返します
+func relativeImageLinks(body string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for _, m := range imageLinkPattern.FindAllStringSubmatch(body, -1) {
+		target := m[2]
+		if !isRelativeImageTarget(target) || seen[target] {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// rewriteImageLinks は画像リンクのリンク先をurlsに従って置き換えます
+func rewriteImageLinks(body string, urls map[string]string) string {
+	return imageLinkPattern.ReplaceAllStringFunc(body, func(link string) string {
+		m := imageLinkPattern.FindStringSubmatch(link)
+		u, ok := urls[m[2]]
+		if !ok {
+			return link
+		}
+		return fmt.Sprintf("![%s](%s)", m[1], u)
+	})
+}
+
+// fetchAttachments はチケットの添付ファイルの一覧を取得します
+func (c *Client) fetchAttachments(key string) ([]attachment, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/issue/"+key+"?fields=attachment", nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("チケット %s の添付ファイルの取得に失敗しました (status: %d)", key, resp.StatusCode)
+	}
+
+	var body struct {
+		Fields struct {
+			Attachment []attachment `json:"attachment"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return body.Fields.Attachment, nil
+}
+
+// UploadAttachment はファイルをチケットに添付し、作成された添付ファイルを返します
+func (c *Client) UploadAttachment(key, filePath string) (*attachment, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル %s を開けませんでした: %v", filePath, err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, fmt.Errorf("ファイル %s の読み込みに失敗しました: %v", filePath, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.Server+"/rest/api/3/issue/"+key+"/attachments", &buf)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	// 添付ファイルのAPIはXSRF対策のためこのヘッダーが必須
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ファイル %s の添付に失敗しました (status: %d): %s", filePath, resp.StatusCode, string(bodyBytes))
+	}
+
+	var created []attachment
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	if len(created) == 0 {
+		return nil, fmt.Errorf("ファイル %s の添付結果が空です", filePath)
+	}
+	verbose.Printf(verbose.API, "ファイルを添付しました: %s -> %s (ID: %s)\n", filePath, key, created[0].ID)
+	return &created[0], nil
+}
+
+// UploadImages は本文で相対パスで参照している画像をチケットに添付し、リンク先を添付ファイルのURLにした本文を返します
+// baseDirはチケットのファイルがあるディレクトリです。同じ名前とサイズの添付ファイルが既にあればアップロードしません。
+func (c *Client) UploadImages(key, body, baseDir string) (string, error) {
+	targets := relativeImageLinks(body)
+	if len(targets) == 0 {
+		return body, nil
+	}
+
+	existing, err := c.fetchAttachments(key)
+	if err != nil {
+		return "", err
+	}
+
+	urls := make(map[string]string)
+	for _, target := range targets {
+		rel, err := url.PathUnescape(target)
+		if err != nil {
+			rel = target
+		}
+		filePath := filepath.Join(baseDir, filepath.FromSlash(rel))
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return "", fmt.Errorf("本文で参照している画像 %s が見つかりません: %v", target, err)
+		}
+
+		if a := findAttachment(existing, filepath.Base(filePath), info.Size()); a != nil {
+			verbose.Printf(verbose.API, "添付済みのためアップロードをスキップします: %s (ID: %s)\n", filePath, a.ID)
+			urls[target] = a.Content
+			continue
+		}
+		uploaded, err := c.UploadAttachment(key, filePath)
+		if err != nil {
+			return "", err
+		}
+		existing = append(existing, *uploaded)
+		urls[target] = uploaded.Content
+	}
+	return rewriteImageLinks(body, urls), nil
+}
+
+// findAttachment は同じ名前とサイズの添付ファイルを探します
+func findAttachment(attachments []attachment, filename string, size int64) *attachment {
+	for i, a := range attachments {
+		if a.Filename == filename && a.Size == size {
+			return &attachments[i]
+		}
+	}
+	return nil
+}
+
+// UpdateDescription はチケットの本文だけを更新します
+func (c *Client) UpdateDescription(key, body string) error {
+	return c.UpdateIssueFields(key, map[string]interface{}{"description": md.ToJiraMD(body)})
+}
@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertAttachments(t *testing.T) {
+	t.Parallel()
+
+	got := convertAttachments("PRJ-1", []attachment{
+		{ID: "100", Filename: "screenshot.png", Size: 10},
+		{ID: "101", Filename: "screenshot.png", Size: 20},
+		{ID: "102", Filename: "../../etc/passwd", Size: 30},
+	})
+	want := []ticket.Attachment{
+		{ID: "100", Filename: "screenshot.png", Size: 10, Path: "assets/PRJ-1/screenshot.png"},
+		{ID: "101", Filename: "screenshot.png", Size: 20, Path: "assets/PRJ-1/101-screenshot.png"},
+		{ID: "102", Filename: "../../etc/passwd", Size: 30, Path: "assets/PRJ-1/passwd"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestMediaResolver(t *testing.T) {
+	t.Parallel()
+
+	resolve := mediaResolver(convertAttachments("PRJ-1", []attachment{
+		{ID: "100", Filename: "画面 1.png"},
+	}))
+
+	tests := []struct {
+		name   string
+		attrs  map[string]any
+		want   string
+		wantOK bool
+	}{
+		{name: "ファイル名が一致", attrs: map[string]any{"alt": "画面 1.png"}, want: "assets/PRJ-1/%E7%94%BB%E9%9D%A2%201.png", wantOK: true},
+		{name: "ファイル名が一致しない", attrs: map[string]any{"alt": "other.png"}},
+		{name: "altがない", attrs: map[string]any{"id": "abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := resolve(tt.attrs)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRelativeImageLinks(t *testing.T) {
+	t.Parallel()
+
+	body := "![画面](./assets/foo.png)\n![外部](https://example.com/a.png)\n![絶対](/tmp/b.png)\n![再掲](./assets/foo.png)\n![](assets/PRJ-1/bar%20baz.png)\n"
+	assert.Equal(t, []string{"./assets/foo.png", "assets/PRJ-1/bar%20baz.png"}, relativeImageLinks(body))
+}
+
+func TestRewriteImageLinks(t *testing.T) {
+	t.Parallel()
+
+	body := "前\n![画面](./assets/foo.png)\n![外部](https://example.com/a.png)\n![再掲](./assets/foo.png)\n"
+	urls := map[string]string{"./assets/foo.png": "https://example.atlassian.net/rest/api/3/attachment/content/100"}
+	want := "前\n![画面](https://example.atlassian.net/rest/api/3/attachment/content/100)\n![外部](https://example.com/a.png)\n![再掲](https://example.atlassian.net/rest/api/3/attachment/content/100)\n"
+	assert.Equal(t, want, rewriteImageLinks(body, urls))
+}
+
+func TestFindAttachment(t *testing.T) {
+	t.Parallel()
+
+	attachments := []attachment{
+		{ID: "100", Filename: "foo.png", Size: 10},
+		{ID: "101", Filename: "foo.png", Size: 20},
+	}
+	assert.Equal(t, "101", findAttachment(attachments, "foo.png", 20).ID)
+	assert.Nil(t, findAttachment(attachments, "foo.png", 30))
+	assert.Nil(t, findAttachment(attachments, "bar.png", 10))
+}
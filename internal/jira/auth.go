@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+)
+
+// ErrMissingToken はAPIトークンがどこにも設定されていないことを表します
+// トークンなしでリクエストを送ると分かりにくい認証エラーになるため、クライアントの作成時に返します。
+var ErrMissingToken = errors.New("APIトークンが見つかりません。tkt auth set-token でキーリングに保存するか、環境変数 " + config.APITokenEnv + " を設定してください")
+
+// AuthError はJIRAがリクエストの認証を拒否したことを表します
+// 401 (と、ログインの失敗を示す403) はどのAPIでも同じ原因なので、レスポンスボディではなくこのエラーで知らせます。
+type AuthError struct {
+	Server string
+	Login  string
+}
+
+func (e *AuthError) Error() string {
+	who := e.Server + " で"
+	if e.Login != "" {
+		who = fmt.Sprintf("%s に %s として", e.Server, e.Login)
+	}
+	return who + "認証できませんでした。APIトークンとログインメールアドレスを確認してください (トークンの取得元は tkt auth status で確認できます)"
+}
+
+// isAuthFailure はレスポンスが認証の失敗を表すかを判定します
+// 403は権限不足の場合にも返るため、JIRAがログインの失敗を示すヘッダーを付けた場合のみ認証の失敗とします。
+func isAuthFailure(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		reason := resp.Header.Get("X-Seraph-LoginReason")
+		return strings.Contains(reason, "AUTHENTICATED_FAILED") || strings.Contains(reason, "AUTHENTICATION_DENIED")
+	}
+	return false
+}
+
+// checkAuth は認証の失敗を表すレスポンスを閉じて AuthError にします
+func checkAuth(resp *http.Response, server, login string) (*http.Response, error) {
+	if !isAuthFailure(resp) {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil, &AuthError{Server: server, Login: login}
+}
+
+// authCheckTransport は go-jira 経由のリクエストの認証の失敗を AuthError にするRoundTripperです
+type authCheckTransport struct {
+	base   http.RoundTripper
+	server string
+	login  string
+}
+
+func (t *authCheckTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	traceRequest(req)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return checkAuth(resp, t.server, t.login)
+}
@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestNewClientMissingToken(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(config.APITokenEnv, "")
+
+	_, err := NewClient(&config.Config{AuthType: "basic", Server: "https://example.atlassian.net", Login: "me@example.com"})
+	assert.ErrorIs(t, err, ErrMissingToken)
+}
+
+func TestAuthError(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(config.APITokenEnv, "wrong-token")
+
+	tests := []struct {
+		name     string
+		status   int
+		header   string
+		wantAuth bool
+	}{
+		{name: "401は認証の失敗", status: http.StatusUnauthorized, wantAuth: true},
+		{name: "ログインの失敗を示す403は認証の失敗", status: http.StatusForbidden, header: "AUTHENTICATED_FAILED", wantAuth: true},
+		{name: "権限不足の403は認証の失敗ではない", status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("X-Seraph-LoginReason", tt.header)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"errorMessages": ["You do not have permission"]}`))
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(&config.Config{AuthType: "basic", Server: srv.URL, Login: "me@example.com"})
+			assert.NoError(t, err)
+
+			// 直接送るリクエストと go-jira 経由のリクエストのどちらも同じエラーになる
+			_, err = c.Search(context.Background(), "project = PRJ", 0, 1)
+			assert.Error(t, err)
+			_, _, jiraErr := c.jiraClient.Project.Get("PRJ")
+			assert.Error(t, jiraErr)
+
+			for _, err := range []error{err, jiraErr} {
+				var authErr *AuthError
+				assert.Equal(t, tt.wantAuth, errors.As(err, &authErr), err.Error())
+				if tt.wantAuth {
+					assert.Equal(t, &AuthError{Server: srv.URL, Login: "me@example.com"}, authErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// GetActiveAndFutureSprintsOfBoards は複数のボードのアクティブと未来のスプリントを取得します
+// 複数のボードに表示されるスプリントは1つにまとめます。
+func (c *Client) GetActiveAndFutureSprintsOfBoards(boardIDs []int) ([]Sprint, error) {
+	return c.getSprintsOfBoards(context.Background(), boardIDs, []string{"active", "future"})
+}
+
+// getSprintsOfBoards は複数のボードのスプリントを取得し、スプリントIDで重複を除きます
+func (c *Client) getSprintsOfBoards(ctx context.Context, boardIDs []int, states []string) ([]Sprint, error) {
+	var all []Sprint
+	for _, boardID := range boardIDs {
+		sprints, err := c.getSprintsWithPagination(ctx, boardID, states)
+		if err != nil {
+			return nil, fmt.Errorf("ボード %d のスプリント取得に失敗しました: %w", boardID, err)
+		}
+		all = mergeSprints(all, sprints)
+	}
+	return all, nil
+}
+
+// mergeSprints はスプリントIDが重複しないようにスプリントを追加します
+func mergeSprints(sprints []Sprint, others []Sprint) []Sprint {
+	seen := make(map[int]bool, len(sprints))
+	for _, s := range sprints {
+		seen[s.ID] = true
+	}
+	for _, s := range others {
+		if seen[s.ID] {
+			continue
+		}
+		seen[s.ID] = true
+		sprints = append(sprints, s)
+	}
+	return sprints
+}
+
+// findSprintsByName はスプリント名に一致するスプリントをすべて返します
+// 大文字と小文字、前後の空白の違いは無視します。
+// 同じ名前のスプリントが複数ある場合は、アクティブ、未来、完了の順に並べます。
+func findSprintsByName(sprints []Sprint, name string) []Sprint {
+	name = normalizeSprintName(name)
+	var matched []Sprint
+	for _, s := range sprints {
+		if normalizeSprintName(s.Name) == name {
+			matched = append(matched, s)
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b Sprint) int {
+		return sprintStateRank(a.State) - sprintStateRank(b.State)
+	})
+	return matched
+}
+
+func normalizeSprintName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// sprintStateRank は同じ名前のスプリントから選ぶときの優先順位です (小さいほど優先)
+func sprintStateRank(state string) int {
+	switch state {
+	case "active":
+		return 0
+	case "future":
+		return 1
+	case "closed":
+		return 2
+	}
+	return 3
+}
+
+// maxSprintSuggestions はスプリントが見つからない場合に候補として表示する最大の数です
+const maxSprintSuggestions = 3
+
+// nearestSprintNames はスプリント名に近い名前のスプリントを近い順に返します
+// 指定した名前で始まる名前と、編集距離が名前の長さの1/3 (最低2) 以内の名前を候補にします。
+func nearestSprintNames(sprints []Sprint, name string) []string {
+	name = normalizeSprintName(name)
+	if name == "" {
+		return nil
+	}
+	maxDistance := max(2, len([]rune(name))/3)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	for _, s := range sprints {
+		if seen[s.Name] {
+			continue
+		}
+		seen[s.Name] = true
+		normalized := normalizeSprintName(s.Name)
+		distance := levenshtein(name, normalized)
+		if strings.HasPrefix(normalized, name) || distance <= maxDistance {
+			candidates = append(candidates, candidate{name: s.Name, distance: distance})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return a.distance - b.distance
+	})
+
+	var names []string
+	for _, c := range candidates[:min(len(candidates), maxSprintSuggestions)] {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// levenshtein は2つの文字列の編集距離 (文字の挿入、削除、置換の回数) を返します
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// FetchBoardNames はボードIDからボード名への対応を取得します
+// 取得に失敗したボードは結果に含めません。
+func (c *Client) FetchBoardNames(boardIDs []int) map[int]string {
+	names := make(map[int]string, len(boardIDs))
+	for _, boardID := range boardIDs {
+		name, err := c.fetchBoardName(boardID)
+		if err != nil {
+			verbose.Printf(verbose.API, "ボード %d の取得に失敗しました: %v\n", boardID, err)
+			continue
+		}
+		names[boardID] = name
+	}
+	return names
+}
+
+// fetchBoardName はボード名を取得します
+func (c *Client) fetchBoardName(boardID int) (string, error) {
+	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d", c.config.Server, boardID)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ボードの取得に失敗しました (status: %d)", resp.StatusCode)
+	}
+
+	var board struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&board); err != nil {
+		return "", fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return board.Name, nil
+}
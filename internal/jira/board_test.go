@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSprints(t *testing.T) {
+	t.Parallel()
+
+	board12 := []Sprint{{ID: 1, Name: "Sprint 1", BoardID: 12}, {ID: 2, Name: "Sprint 2", BoardID: 12}}
+	board34 := []Sprint{{ID: 2, Name: "Sprint 2", BoardID: 12}, {ID: 3, Name: "Sprint 2", BoardID: 34}}
+
+	got := mergeSprints(mergeSprints(nil, board12), board34)
+	assert.Equal(t, []Sprint{
+		{ID: 1, Name: "Sprint 1", BoardID: 12},
+		{ID: 2, Name: "Sprint 2", BoardID: 12},
+		{ID: 3, Name: "Sprint 2", BoardID: 34},
+	}, got)
+
+	// 同じ名前のスプリントは設定したボードの順に返す
+	assert.Equal(t, []Sprint{
+		{ID: 2, Name: "Sprint 2", BoardID: 12},
+		{ID: 3, Name: "Sprint 2", BoardID: 34},
+	}, findSprintsByName(got, "Sprint 2"))
+	assert.Empty(t, findSprintsByName(got, "Sprint 9"))
+}
+
+func TestFindSprintsByName(t *testing.T) {
+	t.Parallel()
+
+	sprints := []Sprint{
+		{ID: 40, Name: "Sprint 42", State: "closed"},
+		{ID: 41, Name: "Sprint 42", State: "future"},
+		{ID: 42, Name: "Sprint 42", State: "active"},
+		{ID: 43, Name: "Sprint 43", State: "future"},
+		{ID: 50, Name: "Team A Sprint 1", State: "closed"},
+		{ID: 51, Name: "Team A Sprint 2", State: "active"},
+	}
+
+	tests := []struct {
+		name    string
+		sprint  string
+		wantIDs []int
+		wantErr string
+	}{
+		{name: "同じ名前はアクティブ、未来、完了の順", sprint: "Sprint 42", wantIDs: []int{42, 41, 40}},
+		{name: "大文字と小文字を区別しない", sprint: "sprint 43", wantIDs: []int{43}},
+		{name: "前後の空白を無視する", sprint: "  SPRINT 43 ", wantIDs: []int{43}},
+		{name: "見つからない場合は近い名前を候補にする", sprint: "Sprint 44", wantErr: "スプリント 'Sprint 44' が見つかりません。近い名前のスプリント: Sprint 42, Sprint 43"},
+		{name: "前方一致も候補にする", sprint: "team a", wantErr: "スプリント 'team a' が見つかりません。近い名前のスプリント: Team A Sprint 1, Team A Sprint 2"},
+		{name: "近い名前がない", sprint: "Release", wantErr: "スプリント 'Release' が見つかりません"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config.Config{}
+			cfg.Board.ID = 7
+			// 取得済みの一覧だけで解決する (HTTPリクエストはしない)
+			c := &Client{config: cfg, sprints: sprints, sprintsLoaded: true, openSprintsRefreshed: true}
+
+			got, err := c.FindSprintByName(context.Background(), tt.sprint)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantIDs[0], got.ID)
+
+			var ids []int
+			for _, s := range findSprintsByName(sprints, tt.sprint) {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
-	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	jiralib "github.com/andygrunwald/go-jira"
@@ -36,42 +39,83 @@ type Sprint struct {
 
 // Client はJIRA APIクライアントのラッパーです
 type Client struct {
-	jiraClient    *jiralib.Client
-	config        *config.Config
-	sprintFieldID string // 動的に発見されたスプリントフィールドID
+	jiraClient      *jiralib.Client
+	httpClient      *http.Client // 全てのリクエストで共有するHTTPクライアント (nilの場合はDefaultHTTPClient)
+	config          *config.Config
+	sprintFieldID   string               // 動的に発見されたスプリントフィールドID
+	flaggedFieldID  string               // 動的に発見されたFlaggedフィールドID (存在しない場合は空)
+	customFields    []customFieldMapping // 設定ファイルで対応付けた数値のカスタムフィールド
+	apiToken        string               // NewClientで取得したAPIトークン
+	withAttachments bool                 // 添付ファイルの情報も取得するかどうか
+
+	decodersOnce sync.Once           // 組み込みのデコーダーを一度だけ登録する
+	decoders     []namedFieldDecoder // フェッチしたチケットに適用するフィールドのデコーダー
+
+	confluenceMu     sync.Mutex        // confluenceTitlesを保護する
+	confluenceTitles map[string]string // 取得したConfluenceのページのタイトル (キーはURL)
+
+	fieldNames map[string]string // フィールドIDと表示名の対応 (エラーの表示に使用)
+	noticesMu  sync.Mutex        // noticesを保護する
+	notices    []string          // コマンドの最後に表示するお知らせ
+
+	noMultiHop bool // ステータスの遷移で他のステータスを経由しない
+
+	unsupportedMu  sync.Mutex     // unsupportedADF を保護する
+	unsupportedADF map[string]int // 変換したチケットで見つかった未対応のADFのノードの種類ごとの数
+
+	legacySearch atomic.Bool // 新しい検索API (/search/jql) が使えないため従来の検索APIを使う
+
+	retryBaseDelay time.Duration // 再試行の待ち時間の初期値 (0の場合はdefaultRetryBaseDelay)
+
+	sprintsMu            sync.Mutex // 以下のスプリントの一覧を保護する
+	sprintCacheDir       string     // スプリントの一覧を保存するキャッシュディレクトリ (空の場合は保存しない)
+	sprints              []Sprint   // スプリント名の解決に使うボードのスプリントの一覧
+	sprintsLoaded        bool       // sprints を読み込んだか
+	openSprintsRefreshed bool       // 見つからないスプリントのためにアクティブと未来のスプリントを取得し直したか
+	activeSprints        []Sprint   // @active の解決に使うアクティブなスプリント
+	activeSprintsLoaded  bool       // activeSprints を取得したか
+
+	teamFieldID  string     // 動的に発見されたTeamフィールドID (存在しない場合は空)
+	teamsMu      sync.Mutex // 以下のチームの一覧を保護する
+	teamCacheDir string     // チームの一覧を保存するキャッシュディレクトリ (空の場合は config.EnsureCacheDir)
+	teams        []Team     // チーム名の解決に使うチームの一覧
+	teamsLoaded  bool       // teams を読み込んだか
+	seenTeams    []Team     // 取得したチケットに設定されていたチーム
 }
 
 // NewClient は新しいJIRA APIクライアントを作成します
 func NewClient(cfg *config.Config) (*Client, error) {
-	var jiraClient *jiralib.Client
-	var err error
+	// APIトークンを取得 (token_commandの実行はここで1回だけ行う)
+	apiToken, err := getAPIToken(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 全てのリクエストで共有するHTTPクライアントを作成 (タイムアウト、プロキシ、証明書の設定)
+	httpClient, err := NewHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// 認証タイプに応じたクライアントを作成
+	// go-jira 経由のリクエストも、認証の失敗は doRequest と同じ AuthError にする
+	transport := &authCheckTransport{base: httpClient.Transport, server: cfg.Server, login: cfg.Login}
+	var jiraClient *jiralib.Client
 	switch cfg.AuthType {
 	case "basic":
-		// 環境変数からAPIトークンを取得
-		apiToken := getAPIToken()
-		if apiToken == "" {
-			return nil, fmt.Errorf("JIRA_API_TOKEN環境変数が設定されていません")
-		}
-
 		tp := jiralib.BasicAuthTransport{
-			Username: cfg.Login,
-			Password: apiToken,
+			Username:  cfg.Login,
+			Password:  apiToken,
+			Transport: transport,
 		}
-		jiraClient, err = jiralib.NewClient(tp.Client(), cfg.Server)
+		jiraClient, err = jiralib.NewClient(&http.Client{Transport: &tp, Timeout: httpClient.Timeout}, cfg.Server)
 
 	case "bearer":
-		// 環境変数からAPIトークンを取得
-		apiToken := getAPIToken()
-		if apiToken == "" {
-			return nil, fmt.Errorf("JIRA_API_TOKEN環境変数が設定されていません")
-		}
-
 		tp := jiralib.BearerAuthTransport{
-			Token: apiToken,
+			Token:     apiToken,
+			Transport: transport,
 		}
-		jiraClient, err = jiralib.NewClient(tp.Client(), cfg.Server)
+		jiraClient, err = jiralib.NewClient(&http.Client{Transport: &tp, Timeout: httpClient.Timeout}, cfg.Server)
 
 	default:
 		return nil, fmt.Errorf("サポートされていない認証タイプです: %s", cfg.AuthType)
@@ -83,27 +127,42 @@ func NewClient(cfg *config.Config) (*Client, error) {
 
 	client := &Client{
 		jiraClient: jiraClient,
+		httpClient: httpClient,
 		config:     cfg,
+		apiToken:   apiToken,
 	}
 
-	// スプリントフィールドを動的に発見
-	if err := client.discoverSprintField(); err != nil {
-		verbose.Printf("スプリントフィールドの発見に失敗しました: %v\n", err)
-		verbose.Printf("スプリント機能は無効になります\n")
+	// カスタムフィールドを動的に発見
+	fields, err := client.fetchFieldDefinitions()
+	if err != nil {
+		verbose.Printf(verbose.API, "フィールド情報の取得に失敗しました: %v\n", err)
+		verbose.Printf(verbose.API, "スプリント機能、Flagged機能とTeam機能は無効になります\n")
 		// エラーでもクライアント作成は続行（スプリント機能が使えないだけ）
+		return client, nil
 	}
+	if err := client.discoverSprintField(fields); err != nil {
+		verbose.Printf(verbose.API, "スプリントフィールドの発見に失敗しました: %v\n", err)
+		verbose.Printf(verbose.API, "スプリント機能は無効になります\n")
+	}
+	client.discoverFlaggedField(fields)
+	client.discoverTeamField(fields)
+	client.fieldNames = fieldNameMap(fields)
+	client.customFields = resolveCustomFields(cfg.Issue.Fields.Custom, fields)
 
 	return client, nil
 }
 
-// getAPIToken は環境変数からAPIトークンを取得します
-func getAPIToken() string {
-	token := os.Getenv("JIRA_API_TOKEN")
+// getAPIToken はキーリング・環境変数・token_command・token_fileからAPIトークンを取得します
+// 見つからない場合は、認証エラーで分かりにくく失敗しないようにリクエストの前に ErrMissingToken を返します。
+func getAPIToken(cfg *config.Config) (string, error) {
+	token, _, err := cfg.APIToken()
+	if err != nil {
+		return "", err
+	}
 	if token == "" {
-		// 開発用のダミートークン（実際の環境では設定してください）
-		return "dummy_token"
+		return "", ErrMissingToken
 	}
-	return token
+	return token, nil
 }
 
 func (c *Client) FetchIssue(key string) (*ticket.Ticket, error) {
@@ -115,129 +174,275 @@ func (c *Client) FetchIssue(key string) (*ticket.Ticket, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.convertWithSprint(issue)
+	tkt, err := c.convertIssue(issue)
+	if err != nil {
+		return nil, err
+	}
+	c.addReferences([]*ticket.Ticket{tkt})
+	return tkt, nil
 }
 
 // FetchIssues はJQLに基づいてJIRAチケットを取得します
-func (c *Client) FetchIssues() (_ []*ticket.Ticket, err error) {
+func (c *Client) FetchIssues(ctx context.Context) (_ []*ticket.Ticket, err error) {
 	defer derrors.Wrap(&err)
 	// まずプロジェクトが存在するか確認
 	if err := c.validateProject(); err != nil {
 		return nil, err
 	}
 
-	// JQLクエリを作成
-	jql := JQL(c.config.JQL)
-	if jql == "" {
-		jql = JQL(fmt.Sprintf("project = %s", c.config.Project.Key))
+	return c.fetchIssuesWithJQL(ctx, c.BaseJQL())
+}
+
+// BaseJQL は全件取得で使用するJQLを返します
+// 設定にJQLがない場合はプロジェクト全体を対象にします。
+func (c *Client) BaseJQL() JQL {
+	if c.config.JQL == "" {
+		return JQL(fmt.Sprintf("project = %s", c.config.Project.Key))
 	}
+	return JQL(c.config.JQL)
+}
 
-	return c.fetchIssuesWithJQL(jql)
+// IncrementalJQL は最終フェッチ時刻以降に更新されたチケットを取得するJQLを返します
+func (c *Client) IncrementalJQL(lastFetch time.Time) JQL {
+	// JIRAのJQLでは yyyy/MM/dd HH:mm 形式を使用（分単位）
+	lastFetchJQL := lastFetch.Format("2006/01/02 15:04")
+	return JQL(fmt.Sprintf("(%s) AND updated >= \"%s\"", c.BaseJQL(), lastFetchJQL))
 }
 
 // FetchIssuesIncremental は最終フェッチ時刻以降に更新されたチケットのみを取得します
-func (c *Client) FetchIssuesIncremental(lastFetch time.Time) (_ []*ticket.Ticket, err error) {
+func (c *Client) FetchIssuesIncremental(ctx context.Context, lastFetch time.Time) (_ []*ticket.Ticket, err error) {
 	defer derrors.Wrap(&err)
 	// まずプロジェクトが存在するか確認
 	if err := c.validateProject(); err != nil {
 		return nil, err
 	}
 
-	// 基本のJQLクエリを作成
-	baseJQL := c.config.JQL
-	if baseJQL == "" {
-		baseJQL = fmt.Sprintf("project = %s", c.config.Project.Key)
-	}
-
 	// 最終フェッチ時刻以降の更新条件を追加
-	// JIRAのJQLでは yyyy/MM/dd HH:mm 形式を使用（分単位）
-	lastFetchJQL := lastFetch.Format("2006/01/02 15:04")
-	incrementalJQL := fmt.Sprintf("(%s) AND updated >= \"%s\"", baseJQL, lastFetchJQL)
+	incrementalJQL := c.IncrementalJQL(lastFetch)
 
-	verbose.Printf("増分フェッチ用JQL: %s\n", incrementalJQL)
+	verbose.Printf(verbose.API, "増分フェッチ用JQL: %s\n", incrementalJQL)
 
-	return c.fetchIssuesWithJQL(JQL(incrementalJQL))
+	return c.fetchIssuesWithJQL(ctx, incrementalJQL)
 }
 
-// fetchIssuesWithJQL は指定されたJQLでチケットを取得する共通処理です
-func (c *Client) fetchIssuesWithJQL(jql JQL) (_ []*ticket.Ticket, err error) {
+// FetchSprintIssues は指定されたスプリントに含まれるチケットを設定のJQLに関係なく取得します
+func (c *Client) FetchSprintIssues(ctx context.Context, sprintID int) (_ []*ticket.Ticket, err error) {
 	defer derrors.Wrap(&err)
+	jql := JQL(fmt.Sprintf("sprint = %d", sprintID))
+	verbose.Printf(verbose.API, "スプリント用JQL: %s\n", jql)
+	return c.fetchIssuesWithJQL(ctx, jql)
+}
 
-	fetchIssues := func() (_ []*Issue, err error) {
-		defer derrors.Wrap(&err)
-		issues := make([]*Issue, 0, 10000)
-		const limitRequestCount = 100 // 安全のための上限
-		const bigNumber = 1000
-		ctx := context.Background()
-		result, err := c.Search(ctx, jql, 0, bigNumber)
-		if err != nil {
-			return nil, err
-		}
-		if result.Total <= len(result.Issues) {
-			// 1回のリクエストで全て取得できる場合
-			return result.Issues, nil
+// FetchEpics はプロジェクトのエピックを更新日時の新しい順に取得します
+// 設定のJQLに関係なく、プロジェクトの全てのエピックが対象です。
+func (c *Client) FetchEpics(ctx context.Context) (_ []*ticket.Ticket, err error) {
+	defer derrors.Wrap(&err)
+	jql := JQL(fmt.Sprintf("project = %s AND %s ORDER BY updated DESC", c.config.Project.Key, epicTypeJQL(c.config)))
+	verbose.Printf(verbose.API, "エピック用JQL: %s\n", jql)
+	epics, err := c.fetchIssuesWithJQL(ctx, jql)
+	if err != nil {
+		return nil, err
+	}
+	// ページごとに並列で変換するため、取得した順に並んでいるとは限らない
+	slices.SortStableFunc(epics, func(a, b *ticket.Ticket) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return epics, nil
+}
+
+// epicTypeJQL はエピックのIssue Typeの条件を返します
+// issue.types にエピックがあればIDで指定し、Issue Typeの名前が翻訳されていても一致するようにします。
+func epicTypeJQL(cfg *config.Config) string {
+	var ids []string
+	for _, it := range cfg.Issue.Types {
+		if cfg.HierarchyLevel(it.Name) == config.HierarchyEpic && it.ID != "" {
+			ids = append(ids, it.ID)
 		}
-		issues = append(issues, result.Issues...)
+	}
+	if len(ids) == 0 {
+		return "issuetype = Epic"
+	}
+	return fmt.Sprintf("issuetype in (%s)", strings.Join(ids, ", "))
+}
 
-		// > To find the maximum number of items that an operation could return, set maxResults to a large number—for example, over 1000—and if the returned value of maxResults is less than the requested value, the returned value is the maximum.
-		// https://developer.atlassian.com/cloud/jira/platform/rest/v3/intro/#pagination
-		maxResults := result.MaxResults // 上限の実際の値を取得すうる。(500にしても100でcapされた。)
+// FindRecentIssuesByMe は自分が報告者として since 以降に作成したチケットを取得します
+// summary を指定した場合は、タイトルにその語句を含むチケットに絞り込みます。
+// pushでの作成がタイムアウトした場合に、作成済みのチケットを探すために使います。
+// JQLの日時はJIRAのユーザーのタイムゾーンで解釈されるため、1日前から検索します。呼び出し側で作成日時を確認してください。
+func (c *Client) FindRecentIssuesByMe(ctx context.Context, since time.Time, summary string) (_ []*ticket.Ticket, err error) {
+	defer derrors.Wrap(&err)
+	jql := fmt.Sprintf("project = %s AND reporter = currentUser() AND created >= \"%s\"", c.config.Project.Key, since.Add(-24*time.Hour).Format("2006/01/02 15:04"))
+	if summary != "" {
+		// 語句として検索するため、引用符で囲んだ文字列をさらにJQLの文字列にする
+		jql += " AND summary ~ " + strconv.Quote(strconv.Quote(summary))
+	}
+	verbose.Printf(verbose.API, "作成済みのチケットを探すJQL: %s\n", jql)
+	return c.fetchIssuesWithJQL(ctx, JQL(jql))
+}
 
-		p := pool.NewWithResults[[]*Issue]().WithContext(ctx).WithMaxGoroutines(5)
-		requestCount := 0
-		for startAt := len(result.Issues); startAt < result.Total; startAt += maxResults {
-			if requestCount >= limitRequestCount {
-				break // 安全のため、リクエスト数の上限を設定
-			}
-			requestCount++
-			p.Go(func(ctx context.Context) ([]*Issue, error) {
-				verbose.Println(startAt, maxResults, jql)
-				// ここでJQLを使ってJIRA APIに問い合わせる。
-				result, err := c.Search(ctx, jql, startAt, maxResults)
+// fetchIssuesWithJQL は指定されたJQLでチケットを取得する共通処理です
+func (c *Client) fetchIssuesWithJQL(ctx context.Context, jql JQL) (_ []*ticket.Ticket, err error) {
+	defer derrors.Wrap(&err)
+
+	// ページを受け取るたびにチケットへの変換を始め、次のページの取得と並行して進める
+	p := pool.NewWithResults[[]*ticket.Ticket]().WithContext(ctx).WithMaxGoroutines(c.config.RequestConcurrency())
+	handlePage := func(issues []*Issue) {
+		p.Go(func(ctx context.Context) ([]*ticket.Ticket, error) {
+			tickets := make([]*ticket.Ticket, 0, len(issues))
+			for _, issue := range issues {
+				ticket, err := c.convertIssue(issue)
 				if err != nil {
 					return nil, err
 				}
-				return result.Issues, nil
-			})
-		}
-		listOfIssues, err := p.Wait()
-		if err != nil {
-			return nil, err
-		}
-		issues = append(issues, slices.Concat(listOfIssues...)...)
-		return issues, nil
+				tickets = append(tickets, ticket)
+			}
+			return tickets, nil
+		})
 	}
 
-	issues, err := fetchIssues()
+	searchErr := c.searchPages(ctx, jql, handlePage)
+	pages, err := p.Wait()
+	if searchErr != nil {
+		return nil, searchErr
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	tickets := make([]*ticket.Ticket, 0, len(issues))
-	for _, issue := range issues {
-		ticket, err := c.convertWithSprint(issue)
+	tickets := slices.Concat(pages...)
+	c.addReferences(tickets)
+
+	return tickets, nil
+}
+
+// searchPages はJQLに一致するチケットをページごとに取得し、ページを受け取るたびに handle を呼びます。
+// 新しい検索API (/rest/api/3/search/jql) に対応していないサーバーでは従来の検索APIを使います。
+func (c *Client) searchPages(ctx context.Context, jql JQL, handle func([]*Issue)) (err error) {
+	defer derrors.Wrap(&err)
+	if !c.legacySearch.Load() {
+		err := c.searchPagesByToken(ctx, jql, handle)
+		if !errors.Is(err, errSearchJQLUnsupported) {
+			return err
+		}
+		verbose.Printf(verbose.API, "新しい検索API (/rest/api/3/search/jql) が使えないため、従来の検索APIを使用します\n")
+		c.legacySearch.Store(true)
+	}
+	return c.searchPagesByOffset(ctx, jql, handle)
+}
+
+// searchPagesByToken は nextPageToken をたどってページを取得します。
+// 次のページのトークンは前のページのレスポンスで初めて分かるため、ページの取得自体は順番に行います。
+func (c *Client) searchPagesByToken(ctx context.Context, jql JQL, handle func([]*Issue)) (err error) {
+	defer derrors.Wrap(&err)
+	const limitRequestCount = 100 // 安全のための上限
+	const bigNumber = 1000
+	token := ""
+	for range limitRequestCount {
+		result, err := c.SearchJQL(ctx, jql, token, bigNumber)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		tickets = append(tickets, ticket)
+		if len(result.Issues) > 0 {
+			handle(result.Issues)
+		}
+		if result.IsLast || result.NextPageToken == "" {
+			return nil
+		}
+		token = result.NextPageToken
 	}
+	return nil // 安全のため、リクエスト数の上限を設定
+}
 
-	return tickets, nil
+// searchPagesByOffset は startAt と total を使って残りのページを並列に取得します (Server/DC向け)
+func (c *Client) searchPagesByOffset(ctx context.Context, jql JQL, handle func([]*Issue)) (err error) {
+	defer derrors.Wrap(&err)
+	const limitRequestCount = 100 // 安全のための上限
+	const bigNumber = 1000
+	result, err := c.Search(ctx, jql, 0, bigNumber)
+	if err != nil {
+		return err
+	}
+	handle(result.Issues)
+	if result.Total <= len(result.Issues) {
+		// 1回のリクエストで全て取得できる場合
+		return nil
+	}
+
+	// > To find the maximum number of items that an operation could return, set maxResults to a large number—for example, over 1000—and if the returned value of maxResults is less than the requested value, the returned value is the maximum.
+	// https://developer.atlassian.com/cloud/jira/platform/rest/v3/intro/#pagination
+	maxResults := result.MaxResults // 上限の実際の値を取得すうる。(500にしても100でcapされた。)
+	if maxResults <= 0 {
+		maxResults = len(result.Issues)
+	}
+
+	p := pool.NewWithResults[[]*Issue]().WithContext(ctx).WithMaxGoroutines(c.config.RequestConcurrency())
+	requestCount := 0
+	for startAt := len(result.Issues); startAt < result.Total; startAt += maxResults {
+		if requestCount >= limitRequestCount {
+			break // 安全のため、リクエスト数の上限を設定
+		}
+		requestCount++
+		p.Go(func(ctx context.Context) ([]*Issue, error) {
+			verbose.Println(verbose.API, startAt, maxResults, jql)
+			// ここでJQLを使ってJIRA APIに問い合わせる。
+			result, err := c.Search(ctx, jql, startAt, maxResults)
+			if err != nil {
+				return nil, err
+			}
+			return result.Issues, nil
+		})
+	}
+	listOfIssues, err := p.Wait()
+	if err != nil {
+		return err
+	}
+	for _, issues := range listOfIssues {
+		handle(issues)
+	}
+	return nil
 }
 
 func convert(issue *Issue, cfg *config.Config) (*ticket.Ticket, error) {
+	key := ticket.CanonicalKey(issue.Key)
 	tkt := &ticket.Ticket{
-		Key:    issue.Key,
-		Title:  issue.Fields.Summary,
-		Type:   strings.ToLower(issue.Fields.IssueType.Name),
-		Status: issue.Fields.Status.Name,
-		URL:    fmt.Sprintf("%s/browse/%s", cfg.Server, issue.Key),
+		Key:            key,
+		Title:          issue.Fields.Summary,
+		Type:           strings.ToLower(issue.Fields.IssueType.Name),
+		TypeID:         issue.Fields.IssueType.ID,
+		Status:         issue.Fields.Status.Name,
+		StatusCategory: issue.Fields.Status.StatusCategory.Key,
+		URL:            fmt.Sprintf("%s/browse/%s", cfg.Server, key),
+	}
+
+	// 添付ファイルを取得する場合は本文のメディアを保存先への相対リンクにする
+	var translatorOpts []adf.MarkdownTranslatorOption
+	if len(issue.Fields.Attachment) > 0 {
+		tkt.Attachments = convertAttachments(issue.Key, issue.Fields.Attachment)
+		translatorOpts = append(translatorOpts, adf.WithMediaResolver(mediaResolver(tkt.Attachments)))
+	}
+	tkt.Body = adf.NewTranslator(issue.Fields.Description, adf.NewJiraMarkdownTranslator(translatorOpts...)).Translate()
+	// 環境も本文と同じ変換をする。値がない場合はフロントマターに出力しない
+	if issue.Fields.Environment != nil {
+		environment := strings.TrimRight(adf.NewTranslator(issue.Fields.Environment, adf.NewJiraMarkdownTranslator(translatorOpts...)).Translate(), "\n")
+		if environment != "" {
+			tkt.Environment = &environment
+		}
+	}
+	if cfg.NormalizeOnFetch {
+		tkt.Body = ticket.NormalizeBody(tkt.Body)
 	}
-
-	tkt.Body = adf.NewTranslator(issue.Fields.Description, adf.NewJiraMarkdownTranslator()).Translate()
 
 	if issue.Fields.Parent != nil {
 		tkt.ParentKey = issue.Fields.Parent.Key
+		// 親がエピックの場合はエピックの名前も保存する
+		if f := issue.Fields.Parent.Fields; f != nil && (f.IssueType.HierarchyLevel == config.HierarchyEpic || cfg.HierarchyLevel(f.IssueType.Name) == config.HierarchyEpic) {
+			tkt.Epic = f.Summary
+		}
+	} else if cfg.Epic.Link != "" {
+		// company-managedプロジェクトではエピックをEpic Linkのカスタムフィールドで持つ
+		if key, ok := issue.Fields.CustomFields[cfg.Epic.Link].(string); ok && key != "" {
+			tkt.ParentKey = key
+		}
 	}
 	if issue.Fields.Assignee != nil {
 		tkt.Assignee = issue.Fields.Assignee.Name
@@ -245,9 +450,32 @@ func convert(issue *Issue, cfg *config.Config) (*ticket.Ticket, error) {
 	if issue.Fields.Reporter != nil {
 		tkt.Reporter = issue.Fields.Reporter.Name
 	}
+	if issue.Fields.Creator != nil {
+		tkt.Creator = issue.Fields.Creator.Name
+	}
+	if len(issue.Fields.Labels) > 0 {
+		tkt.Labels = issue.Fields.Labels
+	}
+	for _, component := range issue.Fields.Components {
+		tkt.Components = append(tkt.Components, component.Name)
+	}
+	for _, version := range issue.Fields.FixVersions {
+		tkt.FixVersions = append(tkt.FixVersions, version.Name)
+	}
+	tkt.Links = convertLinks(issue.Fields.IssueLinks)
+	if issue.Fields.Priority != nil {
+		tkt.Priority = issue.Fields.Priority.Name
+	}
+	if issue.Fields.Resolution != nil {
+		tkt.Resolution = issue.Fields.Resolution.Name
+	}
 	if issue.Fields.TimeOriginalEstimate != nil {
 		tkt.OriginalEstimate = ticket.NewHour(time.Duration(*issue.Fields.TimeOriginalEstimate) * time.Second)
 	}
+	if tt := issue.Fields.TimeTracking; tt != nil {
+		tkt.RemainingEstimate = ticket.NewHour(time.Duration(tt.RemainingEstimateSeconds) * time.Second)
+		tkt.TimeSpent = ticket.NewHour(time.Duration(tt.TimeSpentSeconds) * time.Second)
+	}
 
 	// スプリント情報は呼び出し元で設定される
 
@@ -262,92 +490,145 @@ func convert(issue *Issue, cfg *config.Config) (*ticket.Ticket, error) {
 	}
 	tkt.CreatedAt = createdAt
 	tkt.UpdatedAt = updatedAt
+
+	if issue.Fields.Comment != nil {
+		for _, c := range issue.Fields.Comment.Comments {
+			commentCreatedAt, err := time.Parse(jiraTimestampLayout, c.Created)
+			if err != nil {
+				return nil, fmt.Errorf("コメントの作成日時のパースに失敗しました: %v", err)
+			}
+			comment := ticket.Comment{
+				Author:    "不明",
+				CreatedAt: commentCreatedAt,
+				Body:      adf.NewTranslator(c.Body, adf.NewJiraMarkdownTranslator()).Translate(),
+			}
+			if c.Author != nil {
+				comment.Author = c.Author.Name
+			}
+			tkt.Comments = append(tkt.Comments, comment)
+		}
+	}
 	return tkt, nil
 }
 
-// convertWithSprint はIssueをTicketに変換し、スプリント情報も設定します
-func (c *Client) convertWithSprint(issue *Issue) (*ticket.Ticket, error) {
+// countUnsupportedADF は本文などの変換で目印に置き換えた未対応のADFのノードを種類ごとに数えます
+func (c *Client) countUnsupportedADF(docs ...*adf.ADF) {
+	c.unsupportedMu.Lock()
+	defer c.unsupportedMu.Unlock()
+	for _, doc := range docs {
+		for nt, n := range adf.UnsupportedNodes(doc) {
+			if c.unsupportedADF == nil {
+				c.unsupportedADF = make(map[string]int)
+			}
+			c.unsupportedADF[string(nt)] += n
+		}
+	}
+}
+
+// UnsupportedADFCounts は変換したチケットで見つかった未対応のADFのノードの種類ごとの数を返します
+func (c *Client) UnsupportedADFCounts() map[string]int {
+	c.unsupportedMu.Lock()
+	defer c.unsupportedMu.Unlock()
+	return maps.Clone(c.unsupportedADF)
+}
+
+// convertIssue はIssueをTicketに変換し、登録されたデコーダーでスプリントやカスタムフィールドも設定します
+func (c *Client) convertIssue(issue *Issue) (*ticket.Ticket, error) {
 	tkt, err := convert(issue, c.config)
 	if err != nil {
 		return nil, err
 	}
-
-	// スプリント情報を動的に設定
-	if c.sprintFieldID != "" {
-		verbose.Printf("スプリントフィールドID: %s でデータ抽出を試行中...\n", c.sprintFieldID)
-		sprintName := c.extractSprintNameFromIssue(issue)
-		if sprintName != "" {
-			verbose.Printf("スプリント名を発見: %s\n", sprintName)
-		} else {
-			verbose.Printf("スプリント名が見つかりませんでした\n")
-		}
-		tkt.SprintName = sprintName
-	} else {
-		verbose.Printf("スプリントフィールドIDが設定されていません\n")
+	c.countUnsupportedADF(issue.Fields.Description, issue.Fields.Environment)
+	for _, d := range c.fieldDecoders() {
+		d.decode(&issue.Fields, tkt)
 	}
-
 	return tkt, nil
 }
 
-// extractSprintNameFromIssue は動的にスプリント名を抽出します
-func (c *Client) extractSprintNameFromIssue(issue *Issue) string {
+// extractSprintName は動的にスプリント名を抽出します
+func (c *Client) extractSprintName(fields *IssueFields) string {
 	if c.sprintFieldID == "" {
-		verbose.Printf("スプリントフィールドIDが空です\n")
+		verbose.Printf(verbose.API, "スプリントフィールドIDが空です\n")
 		return ""
 	}
 
 	// CustomFieldsからスプリントフィールドを取得
-	verbose.Printf("利用可能なカスタムフィールド数: %d\n", len(issue.Fields.CustomFields))
+	verbose.Printf(verbose.API, "利用可能なカスタムフィールド数: %d\n", len(fields.CustomFields))
 
-	sprintFieldValue, exists := issue.Fields.CustomFields[c.sprintFieldID]
+	sprintFieldValue, exists := fields.CustomFields[c.sprintFieldID]
 	if !exists {
-		verbose.Printf("スプリントフィールド %s が見つかりません\n", c.sprintFieldID)
+		verbose.Printf(verbose.API, "スプリントフィールド %s が見つかりません\n", c.sprintFieldID)
 		// デバッグ: 利用可能なカスタムフィールドを表示
-		for key := range issue.Fields.CustomFields {
+		for key := range fields.CustomFields {
 			if strings.HasPrefix(key, "customfield_") {
-				verbose.Printf("  利用可能なカスタムフィールド: %s\n", key)
+				verbose.Printf(verbose.API, "  利用可能なカスタムフィールド: %s\n", key)
 			}
 		}
 		return ""
 	}
 
-	verbose.Printf("スプリントフィールド値の型: %T, 値: %v\n", sprintFieldValue, sprintFieldValue)
+	verbose.Tracef(verbose.API, "スプリントフィールド値の型: %T, 値: %v\n", sprintFieldValue, sprintFieldValue)
 
 	// nilの場合
 	if sprintFieldValue == nil {
-		verbose.Printf("スプリントフィールドがnullです\n")
+		verbose.Printf(verbose.API, "スプリントフィールドがnullです\n")
 		return ""
 	}
 
 	sprintField, ok := sprintFieldValue.([]interface{})
 	if !ok {
-		verbose.Printf("スプリントフィールドが配列ではありません\n")
+		verbose.Printf(verbose.API, "スプリントフィールドが配列ではありません\n")
 		return ""
 	}
 
 	if len(sprintField) == 0 {
-		verbose.Printf("スプリントフィールドが空です\n")
+		verbose.Printf(verbose.API, "スプリントフィールドが空です\n")
 		return ""
 	}
 
-	verbose.Printf("スプリント数: %d\n", len(sprintField))
+	verbose.Printf(verbose.API, "スプリント数: %d\n", len(sprintField))
 
-	// 最後のスプリント（現在のスプリント）を取得
-	lastSprint, ok := sprintField[len(sprintField)-1].(map[string]interface{})
-	if !ok {
-		verbose.Printf("最後のスプリントがマップではありません。型: %T, 値: %v\n", sprintField[len(sprintField)-1], sprintField[len(sprintField)-1])
+	name, all := pickSprintName(sprintField)
+	verbose.Printf(verbose.API, "チケットのスプリント: %s\n", strings.Join(all, ", "))
+	if name == "" {
+		verbose.Printf(verbose.API, "名前のあるスプリントが見つかりませんでした\n")
 		return ""
 	}
+	verbose.Printf(verbose.API, "スプリント名抽出成功: %s\n", name)
+	return name
+}
 
-	verbose.Printf("最後のスプリント内容: %+v\n", lastSprint)
-
-	if name, ok := lastSprint["name"].(string); ok {
-		verbose.Printf("スプリント名抽出成功: %s\n", name)
-		return name
+// pickSprintName はスプリントフィールドの値から、チケットの現在のスプリント名を選びます
+// JIRAはチケットを移動した順にスプリントを追加するため、最後の要素が現在のスプリントとは限りません。
+// アクティブなスプリント、未来のスプリント、最後の完了したスプリントの順に選び、状態が分からない場合は最後のスプリントにします。
+// 2つ目の戻り値はフィールドに含まれる全てのスプリント名 (状態付き) です。
+func pickSprintName(sprintField []interface{}) (string, []string) {
+	var all []string
+	latest := make(map[string]string) // 状態ごとの最後のスプリント名
+	last := ""
+	for _, v := range sprintField {
+		sprint, ok := v.(map[string]interface{})
+		if !ok {
+			verbose.Tracef(verbose.API, "スプリントがマップではありません。型: %T, 値: %v\n", v, v)
+			continue
+		}
+		name, ok := sprint["name"].(string)
+		if !ok || name == "" {
+			verbose.Printf(verbose.API, "nameフィールドが見つからないか型が不正です。利用可能なキー: %v\n", getKeys(sprint))
+			continue
+		}
+		state, _ := sprint["state"].(string)
+		state = strings.ToLower(state)
+		all = append(all, fmt.Sprintf("%s (%s)", name, state))
+		latest[state] = name
+		last = name
+	}
+	for _, state := range []string{"active", "future", "closed"} {
+		if name, ok := latest[state]; ok {
+			return name, all
+		}
 	}
-
-	verbose.Printf("nameフィールドが見つからないか型が不正です。利用可能なキー: %v\n", getKeys(lastSprint))
-	return ""
+	return last, all
 }
 
 // getKeys はマップのキー一覧を取得します
@@ -361,46 +642,208 @@ func getKeys(m map[string]interface{}) []string {
 
 // validateProject はプロジェクトが存在するか確認します
 func (c *Client) validateProject() error {
+	if c.config.Project.Key == "" {
+		// extra_sources のようにプロジェクトではなくJQLで対象を指定する場合
+		return nil
+	}
 	project, _, err := c.jiraClient.Project.Get(c.config.Project.Key)
 	if err != nil {
 		return fmt.Errorf("プロジェクト '%s' が見つかりません。設定ファイルのproject.keyを確認してください: %v", c.config.Project.Key, err)
 	}
 
-	verbose.Printf("プロジェクト確認: %s (%s)\n", project.Name, project.Key)
+	verbose.Printf(verbose.API, "プロジェクト確認: %s (%s)\n", project.Name, project.Key)
 	return nil
 }
 
-// UpdateIssue はJIRAチケットを更新します
-func (c *Client) UpdateIssue(ticket ticket.Ticket) error {
+// UpdateIssue はJIRAチケットのうち、changed に含まれる項目 (キャッシュと比べて変更された項目、DiffResult.ChangedFields) のみを更新します
+// 変更していない項目は送信しないので、他の人がJIRAで編集した値を上書きせず、変更していない本文をJIRA記法に変換し直すこともありません。
+func (c *Client) UpdateIssue(ticket ticket.Ticket, changed []string) error {
+	has := func(name string) bool { return slices.Contains(changed, name) }
+
 	// 更新用のフィールドを構築
 	fields := make(map[string]interface{})
 
 	// 基本フィールド
-	if ticket.Title != "" {
+	if has("title") && ticket.Title != "" {
 		fields["summary"] = ticket.Title
 	}
-	if ticket.Body != "" {
-		fields["description"] = md.ToJiraMD(ticket.Body)
+	// 本文を空にした場合は説明を消去する
+	if has("body") {
+		if ticket.Body != "" {
+			fields["description"] = md.ToJiraMD(ticket.Body)
+		} else {
+			fields["description"] = nil
+		}
+	}
+	// 環境は書かれている場合のみ送信し、空文字の場合はリモートの値を消去する
+	if has("environment") && ticket.Environment != nil {
+		fields["environment"] = md.ToJiraMD(*ticket.Environment)
 	}
-	if ticket.ParentKey != "" {
-		fields["parent"] = map[string]string{"key": ticket.ParentKey}
+	// 親の行を削除した場合は、他のフィールドの更新の後に clearParent で親を外す
+	clearParent := has("parentKey") && ticket.ParentKey == ""
+	if has("parentKey") && ticket.ParentKey != "" {
+		name, value := c.parentField(ticket.Type, ticket.ParentKey)
+		fields[name] = value
+	}
+	// Issue Typeはtypeが書き換えられた場合 (type_idと解決したIDが異なる場合) のみ送信する
+	// type_idのないファイル (手書きや古いファイル) はissue.typesから名前で解決して送信する
+	if has("type") {
+		typeID, err := issueTypeID(c.config, &ticket)
+		if err != nil {
+			return err
+		}
+		if typeID != ticket.TypeID {
+			fields["issuetype"] = map[string]string{"id": typeID}
+		}
+	}
+	// 担当者は書かれている場合のみaccountIdに解決して送信する
+	if has("assignee") && ticket.Assignee != "" {
+		accountID, err := c.ResolveAssignee(ticket.Assignee)
+		if err != nil {
+			return err
+		}
+		fields["assignee"] = map[string]string{"accountId": accountID}
 	}
-	if ticket.OriginalEstimate != 0 {
-		fields["timetracking"] = map[string]interface{}{
-			"originalEstimate": fmt.Sprintf("%.1fh", float64(ticket.OriginalEstimate)),
+	timetracking := map[string]interface{}{}
+	// 見積もりを削除または0にした場合は0hを送信する
+	if has("original_estimate") {
+		timetracking["originalEstimate"] = formatEstimate(ticket.OriginalEstimate)
+	}
+	if has("remaining_estimate") {
+		timetracking["remainingEstimate"] = formatEstimate(ticket.RemainingEstimate)
+	}
+	if len(timetracking) > 0 {
+		fields["timetracking"] = timetracking
+	}
+
+	if has("priority") && ticket.Priority != "" {
+		fields["priority"] = map[string]string{"name": ticket.Priority}
+	}
+
+	// ラベルは空の場合も送信してリモートのラベルを外す
+	if has("labels") {
+		labels := ticket.Labels
+		if labels == nil {
+			labels = []string{}
 		}
+		fields["labels"] = labels
+	}
+
+	// コンポーネントもラベルと同様に、空の場合も送信してリモートのコンポーネントを外す
+	if has("components") {
+		if err := validateComponents(ticket.Components, c.config.Project.Components); err != nil {
+			return err
+		}
+		fields["components"] = namesUpdateValue(ticket.Components)
+	}
+	if has("fix_versions") {
+		fields["fixVersions"] = namesUpdateValue(ticket.FixVersions)
 	}
 
 	// スプリントフィールドの更新
-	if err := c.addSprintFieldToUpdate(fields, ticket); err != nil {
-		verbose.Printf("スプリントフィールドの設定に失敗しました: %v\n", err)
-		// エラーでも他のフィールドの更新は続行
+	if has("sprint") {
+		if err := c.addSprintFieldToUpdate(fields, ticket); err != nil {
+			// @active が解決できない場合は、意図しないスプリントのままにならないようエラーにする
+			if ticket.SprintName == SprintActive {
+				return err
+			}
+			verbose.Printf(verbose.API, "スプリントフィールドの設定に失敗しました: %v\n", err)
+			// エラーでも他のフィールドの更新は続行
+		}
 	}
 
-	updateData := map[string]interface{}{
-		"fields": fields,
+	// Flaggedフィールドの更新
+	if has("flagged") {
+		c.addFlaggedFieldToUpdate(fields, ticket)
+	}
+
+	// Teamフィールドの更新 (チーム名の打ち間違いは他の項目も更新せずにエラーにする)
+	if has("team") {
+		if err := c.addTeamFieldToUpdate(fields, ticket); err != nil {
+			return err
+		}
 	}
 
+	// カスタムフィールドの更新 (変更したもののみ)
+	customFields := make(map[string]*float64)
+	for key, value := range ticket.CustomFields {
+		if has(key) {
+			customFields[key] = value
+		}
+	}
+	ticket.CustomFields = customFields
+	c.addCustomFieldsToUpdate(fields, ticket)
+
+	// diff.ignore_fieldsの項目は送信しない
+	c.removeIgnoredFields(fields)
+
+	if len(fields) > 0 {
+		verbose.Printf(verbose.API, "チケット %s の更新フィールド: %s\n", ticket.Key, strings.Join(slices.Sorted(maps.Keys(fields)), ", "))
+		if err := c.UpdateIssueFields(ticket.Key, fields); err != nil {
+			return c.withFixVersionsHint(c.withPriorityHint(err, ticket.Priority), ticket.FixVersions)
+		}
+	}
+
+	if clearParent && !slices.Contains(c.config.Diff.IgnoreFields, "parentKey") {
+		if err := c.clearParent(ticket.Key, ticket.Type); err != nil {
+			return fmt.Errorf("親チケットの解除に失敗しました: %v", err)
+		}
+	}
+
+	// statusの更新（transition APIを使用）
+	// ステータスを変更していない場合は、トランジションの取得も行わない
+	if has("status") && ticket.Status != "" && !slices.Contains(c.config.Diff.IgnoreFields, "status") {
+		err := c.updateIssueStatus(ticket.Key, ticket.Status, ticket.Resolution)
+		if err != nil {
+			if c.config.Push.StrictTransitions {
+				return fmt.Errorf("ステータスの更新に失敗しました: %v", err)
+			}
+			// 他の項目の更新は成功しているので、警告にとどめる
+			c.addNotice("%s: ステータスを '%s' に変更できませんでした (他の項目は更新済みです): %v", ticket.Key, ticket.Status, err)
+		}
+	}
+
+	return nil
+}
+
+// formatEstimate は見積もりを更新リクエストの形式 (例: 1.5h) にします。0の場合は 0h です。
+func formatEstimate(h ticket.Hour) string {
+	if h == 0 {
+		return "0h"
+	}
+	return fmt.Sprintf("%.1fh", float64(h))
+}
+
+// clearParent はチケットの親を外します
+// "parent": null を受け付けないインスタンスがあるため、parent のフィールドエラーになった場合は
+// update の set 操作 ({"parent": [{"set": {"none": true}}]}) で外し直します。
+func (c *Client) clearParent(issueKey, issueType string) error {
+	if c.usesEpicLink(issueType) {
+		return c.UpdateIssueFields(issueKey, map[string]interface{}{c.config.Epic.Link: nil})
+	}
+	err := c.UpdateIssueFields(issueKey, map[string]interface{}{"parent": nil})
+	var apiErr *apiError
+	if err == nil || !errors.As(err, &apiErr) || !hasFieldError(apiErr.body, "parent") {
+		return err
+	}
+	verbose.Printf(verbose.API, "parent: null で親を外せなかったため、updateの操作で外します: %v\n", err)
+	return c.putIssue(issueKey, map[string]interface{}{
+		"update": map[string]interface{}{
+			"parent": []map[string]interface{}{{"set": map[string]bool{"none": true}}},
+		},
+	})
+}
+
+// UpdateIssueFields は指定したフィールドのみJIRAチケットを更新します
+// fieldsに含まれないフィールドは変更されません。
+func (c *Client) UpdateIssueFields(issueKey string, fields map[string]interface{}) error {
+	return c.putIssue(issueKey, map[string]interface{}{
+		"fields": fields,
+	})
+}
+
+// putIssue はチケットの編集APIにリクエストボディ (fields や update) を送信します
+func (c *Client) putIssue(issueKey string, updateData map[string]interface{}) error {
 	// JSON形式でリクエストボディを作成
 	jsonBody, err := json.Marshal(updateData)
 	if err != nil {
@@ -408,19 +851,16 @@ func (c *Client) UpdateIssue(ticket ticket.Ticket) error {
 	}
 	// JIRA API v2を使用（JIRA記法をサポート）
 	req, err := http.NewRequest(http.MethodPut,
-		fmt.Sprintf("%s/rest/api/2/issue/%s", c.config.Server, ticket.Key),
+		fmt.Sprintf("%s/rest/api/2/issue/%s", c.config.Server, issueKey),
 		bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -429,53 +869,269 @@ func (c *Client) UpdateIssue(ticket ticket.Ticket) error {
 		errorMsg := string(bodyBytes)
 
 		// エラーの詳細をログに出力
-		verbose.Printf("JIRA更新エラー: %s\n", errorMsg)
+		verbose.Printf(verbose.API, "JIRA更新エラー: %s\n", errorMsg)
 
-		return fmt.Errorf("JIRAチケットの更新に失敗しました (status: %d): %s", resp.StatusCode, errorMsg)
+		return &apiError{message: "JIRAチケットの更新に失敗しました", statusCode: resp.StatusCode, body: errorMsg}
 	}
 
-	// statusの更新（transition APIを使用）
-	if ticket.Status != "" {
-		err = c.updateIssueStatus(ticket.Key, ticket.Status)
-		if err != nil {
-			return fmt.Errorf("ステータスの更新に失敗しました: %v", err)
+	return nil
+}
+
+// AddComment はJIRAチケットにコメントを追加し、作成されたコメントのIDを返します
+// bodyはMarkdownで指定し、JIRA記法に変換して送信します。
+func (c *Client) AddComment(issueKey, body string) (string, error) {
+	jsonBody, err := json.Marshal(map[string]string{
+		"body": md.ToJiraMD(body),
+	})
+	if err != nil {
+		return "", fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+	}
+	verbose.Tracef(verbose.API, "コメント追加リクエスト (%s): %s\n", issueKey, string(jsonBody))
+
+	// JIRA API v2を使用（JIRA記法をサポート）
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/rest/api/2/issue/%s/comment", c.config.Server, issueKey),
+		bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("レスポンスの読み込みに失敗しました: %v", err)
+	}
+	verbose.Tracef(verbose.API, "コメント追加レスポンス (%s): %s\n", resp.Status, string(bodyBytes))
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("チケット %s が見つかりません", issueKey)
+	case http.StatusForbidden:
+		return "", fmt.Errorf("チケット %s にコメントする権限がありません", issueKey)
+	default:
+		return "", fmt.Errorf("コメントの追加に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return "", fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return result.ID, nil
+}
+
+// usesEpicLink は親をEpic Linkのカスタムフィールドで設定するかどうかを返します
+// 設定でEpic Linkフィールドが指定されている場合 (company-managedプロジェクト) は、標準のIssue Typeのエピックを Epic Link で設定します。
+// サブタスクの親は常に parent フィールドです。
+func (c *Client) usesEpicLink(issueType string) bool {
+	return c.config.Epic.Link != "" && c.config.HierarchyLevel(issueType) == config.HierarchyStandard
+}
+
+// parentField は親を設定するフィールドの名前と値を返します
+func (c *Client) parentField(issueType, parentKey string) (string, interface{}) {
+	if c.usesEpicLink(issueType) {
+		return c.config.Epic.Link, parentKey
+	}
+	return "parent", map[string]string{"key": parentKey}
+}
+
+// RemoveFromSprint はチケットをスプリントから外してバックログに戻します
+func (c *Client) RemoveFromSprint(issueKey string) error {
+	if c.sprintFieldID == "" {
+		return fmt.Errorf("スプリントフィールドが見つからないため、スプリントから外せません")
+	}
+	verbose.Printf(verbose.API, "スプリントから外す: %s\n", issueKey)
+	return c.UpdateIssueFields(issueKey, map[string]interface{}{c.sprintFieldID: nil})
+}
+
+// UpdateParent はJIRAチケットの親を変更します
+// 設定でEpic Linkフィールドが指定されている場合 (company-managedプロジェクト) はEpic Linkを、
+// それ以外 (team-managedプロジェクト) はparentフィールドを更新します。
+// parentKeyが空の場合は親を解除します。
+func (c *Client) UpdateParent(issueKey, parentKey string) error {
+	var value interface{}
+	fields := make(map[string]interface{})
+	if c.config.Epic.Link != "" {
+		if parentKey != "" {
+			value = parentKey
 		}
+		fields[c.config.Epic.Link] = value
+	} else {
+		if parentKey != "" {
+			value = map[string]string{"key": parentKey}
+		}
+		fields["parent"] = value
 	}
+	verbose.Printf(verbose.API, "親の更新: %s -> %v\n", issueKey, fields)
+	return c.UpdateIssueFields(issueKey, fields)
+}
 
-	return nil
+// maxTransitionHops は目標のステータスに直接遷移できない場合に経由するステータスの上限です
+const maxTransitionHops = 3
+
+// Concurrency は並列に送るリクエストの数 (設定の concurrency) を返します
+func (c *Client) Concurrency() int {
+	return c.config.RequestConcurrency()
+}
+
+// DisableMultiHopTransitions は目標のステータスに直接遷移できない場合に、他のステータスを経由しないようにします
+func (c *Client) DisableMultiHopTransitions() {
+	c.noMultiHop = true
 }
 
 // updateIssueStatus はJIRAチケットのステータスを更新します
-func (c *Client) updateIssueStatus(issueKey, targetStatus string) error {
+// 目標のステータスに直接遷移できない場合は、遷移先のトランジションの一覧をたどりながら
+// maxTransitionHops 個までのステータスを経由して遷移します。
+// resolution は目標のステータスへのトランジションの画面に解決状況がある場合に送信します (空の場合は必須のときのみ Done)。
+func (c *Client) updateIssueStatus(issueKey, targetStatus, resolution string) error {
 	// まず利用可能なトランジションを取得
 	transitions, err := c.getAvailableTransitions(issueKey)
 	if err != nil {
 		return fmt.Errorf("利用可能なトランジション取得に失敗しました: %v", err)
 	}
+	if transition, ok := findTransition(transitions, targetStatus); ok {
+		return c.doTransition(issueKey, transition, resolution)
+	}
+	if c.noMultiHop {
+		// 目標ステータスが見つからない場合はエラーとして返す
+		return fmt.Errorf("ステータス '%s' への遷移が見つかりません。利用可能なステータス: %s",
+			targetStatus, strings.Join(transitionStatuses(transitions), ", "))
+	}
+
+	// 同じステータスに戻らないように、現在のステータスから経由したステータスを記録する
+	current, err := c.getIssueStatus(issueKey)
+	if err != nil {
+		return err
+	}
+	path := []string{current}
+	visited := map[string]bool{current: true}
+	for hop := 1; hop <= maxTransitionHops; hop++ {
+		next, ok := nextTransitionHop(transitions, visited)
+		if !ok {
+			break
+		}
+		verbose.Printf(verbose.API, "ステータスの遷移 (経由 %d/%d): %s: %s -> %s\n", hop, maxTransitionHops, issueKey, path[len(path)-1], next.To.Name)
+		if err := c.doTransition(issueKey, next, ""); err != nil {
+			return fmt.Errorf("'%s' を経由する遷移に失敗しました (経路: %s): %v", next.To.Name, strings.Join(path, " -> "), err)
+		}
+		path = append(path, next.To.Name)
+		visited[next.To.Name] = true
+
+		transitions, err = c.getAvailableTransitions(issueKey)
+		if err != nil {
+			return fmt.Errorf("利用可能なトランジション取得に失敗しました: %v", err)
+		}
+		if transition, ok := findTransition(transitions, targetStatus); ok {
+			verbose.Printf(verbose.API, "ステータスの遷移: %s: %s -> %s\n", issueKey, next.To.Name, targetStatus)
+			return c.doTransition(issueKey, transition, resolution)
+		}
+	}
 
-	// 目標ステータスに対応するトランジションIDを見つける
-	var transitionID string
-	var availableStatuses []string
+	// 経由した遷移は取り消せないので、チケットは最後に経由したステータスのままになる
+	return fmt.Errorf("ステータス '%s' への遷移が見つかりません (経路: %s)。利用可能なステータス: %s",
+		targetStatus, strings.Join(path, " -> "), strings.Join(transitionStatuses(transitions), ", "))
+}
+
+// findTransition は目標のステータスに遷移するトランジションを返します
+func findTransition(transitions []Transition, targetStatus string) (Transition, bool) {
 	for _, transition := range transitions {
-		availableStatuses = append(availableStatuses, transition.To.Name)
 		if transition.To.Name == targetStatus {
-			transitionID = transition.ID
-			break
+			return transition, true
 		}
 	}
+	return Transition{}, false
+}
 
-	if transitionID == "" {
-		// 目標ステータスが見つからない場合はエラーとして返す
-		return fmt.Errorf("ステータス '%s' への遷移が見つかりません。利用可能なステータス: %s",
-			targetStatus, strings.Join(availableStatuses, ", "))
+// nextTransitionHop は目標のステータスに直接遷移できない場合に経由するトランジションを選びます
+// まだ経由していないステータスのうち、進行中のカテゴリのステータスを優先します。
+func nextTransitionHop(transitions []Transition, visited map[string]bool) (Transition, bool) {
+	var candidates []Transition
+	for _, transition := range transitions {
+		if !visited[transition.To.Name] {
+			candidates = append(candidates, transition)
+		}
+	}
+	if len(candidates) == 0 {
+		return Transition{}, false
+	}
+	for _, transition := range candidates {
+		if transition.To.StatusCategory.Key == "indeterminate" {
+			return transition, true
+		}
+	}
+	return candidates[0], true
+}
+
+// transitionStatuses はトランジションの遷移先のステータスの名前を返します
+func transitionStatuses(transitions []Transition) []string {
+	var statuses []string
+	for _, transition := range transitions {
+		statuses = append(statuses, transition.To.Name)
+	}
+	return statuses
+}
+
+// defaultResolution は解決状況が必須のトランジションで、ローカルで指定されていない場合に送信する解決状況です
+const defaultResolution = "Done"
+
+// transitionFields はトランジションで送信する項目を返します
+// 画面に解決状況がある場合は、ローカルで指定した解決状況か、必須の場合は Done (選択肢にない場合は最初の選択肢) を送信します。
+// 解決状況以外の必須の項目は設定できないので、項目の名前を並べたエラーを返します。
+func transitionFields(transition Transition, resolution string) (map[string]any, error) {
+	fields := make(map[string]any)
+	if f, ok := transition.Fields["resolution"]; ok {
+		if resolution == "" && f.Required {
+			resolution = defaultResolution
+			if len(f.AllowedValues) > 0 && !slices.ContainsFunc(f.AllowedValues, func(v TransitionFieldValue) bool { return v.Name == defaultResolution }) {
+				resolution = f.AllowedValues[0].Name
+			}
+		}
+		if resolution != "" {
+			fields["resolution"] = map[string]string{"name": resolution}
+		}
+	}
+
+	var missing []string
+	for id, f := range transition.Fields {
+		if id == "resolution" || !f.Required || f.HasDefaultValue {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = id
+		}
+		missing = append(missing, name)
 	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("'%s' へのトランジションには tkt から設定できない必須の項目があります: %s。JIRAの画面でステータスを変更してください",
+			transition.To.Name, strings.Join(missing, ", "))
+	}
+	return fields, nil
+}
 
-	// トランジションを実行
+// doTransition はトランジションを実行します
+func (c *Client) doTransition(issueKey string, transition Transition, resolution string) error {
+	fields, err := transitionFields(transition, resolution)
+	if err != nil {
+		return err
+	}
 	transitionData := map[string]interface{}{
 		"transition": map[string]string{
-			"id": transitionID,
+			"id": transition.ID,
 		},
 	}
+	if len(fields) > 0 {
+		transitionData["fields"] = fields
+		verbose.Printf(verbose.API, "トランジションの項目: %s -> %v\n", issueKey, fields)
+	}
 
 	jsonBody, err := json.Marshal(transitionData)
 	if err != nil {
@@ -490,47 +1146,101 @@ func (c *Client) updateIssueStatus(issueKey, targetStatus string) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		// 項目ごとのエラーがあれば、レスポンスをそのまま表示せずに不足している項目を示す
+		if errs := c.fieldErrors(string(bodyBytes)); len(errs) > 0 {
+			var lines []string
+			for _, e := range errs {
+				lines = append(lines, fmt.Sprintf("%s: %s", e.Name, e.Message))
+			}
+			return fmt.Errorf("'%s' へのステータス更新に失敗しました (status: %d)。トランジションの項目を確認してください: %s",
+				transition.To.Name, resp.StatusCode, strings.Join(lines, ", "))
+		}
 		return fmt.Errorf("ステータス更新に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	return nil
 }
 
+// getIssueStatus はチケットの現在のステータスの名前を取得します
+func (c *Client) getIssueStatus(issueKey string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/rest/api/2/issue/%s?fields=status", c.config.Server, issueKey),
+		nil)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ステータスの取得に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var response struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return response.Fields.Status.Name, nil
+}
+
 type Transition struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	To   struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
+		ID             string `json:"id"`
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key string `json:"key"` // new, indeterminate, done
+		} `json:"statusCategory"`
 	} `json:"to"`
+	// Fields はトランジションの画面の項目です (キーはフィールドID)
+	Fields map[string]TransitionField `json:"fields"`
+}
+
+// TransitionField はトランジションの画面の項目です
+type TransitionField struct {
+	Name            string                 `json:"name"`
+	Required        bool                   `json:"required"`
+	HasDefaultValue bool                   `json:"hasDefaultValue"`
+	AllowedValues   []TransitionFieldValue `json:"allowedValues"`
+}
+
+// TransitionFieldValue はトランジションの項目の選択肢です
+type TransitionFieldValue struct {
+	Name string `json:"name"`
 }
 
 // getAvailableTransitions は指定されたチケットで利用可能なトランジションを取得します
 func (c *Client) getAvailableTransitions(issueKey string) ([]Transition, error) {
 	req, err := http.NewRequest(http.MethodGet,
-		fmt.Sprintf("%s/rest/api/2/issue/%s/transitions", c.config.Server, issueKey),
+		fmt.Sprintf("%s/rest/api/2/issue/%s/transitions?expand=transitions.fields", c.config.Server, issueKey),
 		nil)
 	if err != nil {
 		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
 
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -552,35 +1262,17 @@ func (c *Client) getAvailableTransitions(issueKey string) ([]Transition, error)
 
 // CreateIssue は新しいJIRAチケットを作成します
 func (c *Client) CreateIssue(ticket *ticket.Ticket) (*ticket.Ticket, error) {
-	// チケットタイプIDを取得し、プロジェクトの妥当性も確認
-	// createコマンドと同じフィルタリングロジックを使用
-	typeID := ""
-	var selectedType *config.IssueType
-
-	verbose.Printf("チケットタイプ '%s' を検索中 (プロジェクト: %s, ID: %s)\n", ticket.Type, c.config.Project.Key, c.config.Project.ID)
-
-	// プロジェクト固有のAPIから取得したすべてのIssue Typeを使用
-	typeMap := make(map[string]config.IssueType)
-	for _, issueType := range c.config.Issue.Types {
-		verbose.Printf("  候補: %s (ID: %s)\n", issueType.Name, issueType.ID)
-		typeMap[issueType.Name] = issueType
-		verbose.Printf("    -> 追加\n")
-	}
-
-	// 指定されたタイプが見つかるかチェック
-	if selectedIssueType, exists := typeMap[ticket.Type]; exists {
-		selectedType = &selectedIssueType
-		typeID = selectedType.ID
-		verbose.Printf("選択されたタイプ: %s (ID: %s)\n", selectedType.Name, selectedType.ID)
-	}
-
-	if typeID == "" {
-		verbose.Printf("利用可能なタイプ一覧:\n")
-		for name, t := range typeMap {
-			verbose.Printf("  - %s (ID: %s)\n", name, t.ID)
+	// チケットタイプIDを取得 (type_idを優先し、なければissue.typesから名前で探す)
+	verbose.Printf(verbose.API, "チケットタイプ '%s' を検索中 (プロジェクト: %s, ID: %s)\n", ticket.Type, c.config.Project.Key, c.config.Project.ID)
+	typeID, err := issueTypeID(c.config, ticket)
+	if err != nil {
+		verbose.Printf(verbose.API, "利用可能なタイプ一覧:\n")
+		for _, t := range c.config.Issue.Types {
+			verbose.Printf(verbose.API, "  - %s (ID: %s)\n", t.Name, t.ID)
 		}
-		return nil, fmt.Errorf("チケットタイプが見つかりません: %s", ticket.Type)
+		return nil, err
 	}
+	verbose.Printf(verbose.API, "選択されたタイプ: %s (ID: %s)\n", ticket.Type, typeID)
 
 	// Markdown本文をJIRA記法に変換
 	jiraDescription := md.ToJiraMD(ticket.Body)
@@ -599,36 +1291,175 @@ func (c *Client) CreateIssue(ticket *ticket.Ticket) (*ticket.Ticket, error) {
 
 	// 親チケットがある場合は設定
 	if ticket.ParentKey != "" {
-		fields["parent"] = map[string]string{
-			"key": ticket.ParentKey,
+		name, value := c.parentField(ticket.Type, ticket.ParentKey)
+		fields[name] = value
+	}
+
+	// 優先度が指定されている場合は設定（未指定の場合はJIRAのデフォルト）
+	if ticket.Priority != "" {
+		fields["priority"] = map[string]string{"name": ticket.Priority}
+	}
+
+	// 担当者が指定されている場合はaccountIdに解決して設定
+	if ticket.Assignee != "" {
+		accountID, err := c.ResolveAssignee(ticket.Assignee)
+		if err != nil {
+			return nil, err
 		}
+		fields["assignee"] = map[string]string{"accountId": accountID}
+	}
+
+	// 環境が書かれている場合は設定
+	if ticket.Environment != nil && *ticket.Environment != "" {
+		fields["environment"] = md.ToJiraMD(*ticket.Environment)
+	}
+
+	// ラベルがある場合は設定
+	if len(ticket.Labels) > 0 {
+		fields["labels"] = ticket.Labels
+	}
+
+	// コンポーネントがある場合は設定
+	if len(ticket.Components) > 0 {
+		if err := validateComponents(ticket.Components, c.config.Project.Components); err != nil {
+			return nil, err
+		}
+		fields["components"] = namesUpdateValue(ticket.Components)
+	}
+
+	// 修正バージョンがある場合は設定
+	if len(ticket.FixVersions) > 0 {
+		fields["fixVersions"] = namesUpdateValue(ticket.FixVersions)
 	}
 
 	// スプリントが指定されている場合はカスタムフィールドに設定
-	if ticket.SprintName != "" && c.sprintFieldID != "" && c.config.Board.ID != 0 {
+	if !ticket.InBacklog() && c.sprintFieldID != "" && len(c.config.BoardIDs()) > 0 {
 		sprintID, err := c.findSprintIDByName(ticket.SprintName)
-		if err != nil {
-			verbose.Printf("スプリントIDの解決に失敗しました（作成時）: %v\n", err)
+		if err != nil && ticket.SprintName == SprintActive {
+			return nil, fmt.Errorf("スプリント %s の解決に失敗しました: %v", SprintActive, err)
+		} else if err != nil {
+			verbose.Printf(verbose.API, "スプリントIDの解決に失敗しました（作成時）: %v\n", err)
 		} else if sprintID != 0 {
-			verbose.Printf("作成時にスプリントフィールド %s を設定: %d\n", c.sprintFieldID, sprintID)
+			verbose.Printf(verbose.API, "作成時にスプリントフィールド %s を設定: %d\n", c.sprintFieldID, sprintID)
 			fields[c.sprintFieldID] = sprintID
 		}
 	}
 
+	// フラグが付いている場合のみ設定（作成画面にFlaggedがない場合を考慮）
+	if ticket.IsFlagged() {
+		c.addFlaggedFieldToUpdate(fields, *ticket)
+	}
+
+	// チームが指定されている場合のみ設定
+	if ticket.Team != "" {
+		if err := c.addTeamFieldToUpdate(fields, *ticket); err != nil {
+			return nil, err
+		}
+	}
+
+	// 値が指定されているカスタムフィールドのみ設定
+	for _, m := range c.customFields {
+		if value := ticket.CustomFields[m.FrontmatterKey]; value != nil {
+			fields[m.ID] = *value
+		}
+	}
+
 	// チケットを作成
+	key, err := c.postIssue(fields)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		fieldErrs := c.fieldErrors(apiErr.body)
+		deferred := deferrableFields(fields, fieldErrs)
+		if deferred == nil {
+			err = withFieldErrorHints(err, fieldErrs)
+			return nil, c.withFixVersionsHint(c.withPriorityHint(err, ticket.Priority), ticket.FixVersions)
+		}
+		// 作成画面にないフィールドだけが原因の場合は、それらを省略して作成し直し、作成後に設定する
+		for _, fe := range fieldErrs {
+			verbose.Printf(verbose.API, "%s\n", fe.Remediation())
+		}
+		key, err = c.postIssue(fields)
+		if err != nil {
+			return nil, c.withFixVersionsHint(c.withPriorityHint(err, ticket.Priority), ticket.FixVersions)
+		}
+		c.applyDeferredFields(key, deferred, fieldErrs)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// 作成されたチケットをfetchして正しいフォーマットで返す
+	createdTicket, err := c.FetchIssue(key)
+	if err != nil {
+		return nil, err
+	}
+
+	verbose.Printf(verbose.API, "チケット作成完了: %s\n", key)
+
+	return createdTicket, nil
+}
+
+// deferrableFields はエラーになったフィールドがすべて作成後に設定し直せる場合に、それらをfieldsから取り除いて返します
+// 設定し直せないエラーが含まれる場合は nil を返し、fieldsは変更しません。
+func deferrableFields(fields map[string]interface{}, fieldErrs []FieldError) map[string]interface{} {
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	deferred := make(map[string]interface{})
+	for _, fe := range fieldErrs {
+		value, ok := fields[fe.Field]
+		if !fe.deferrable() || !ok {
+			return nil
+		}
+		deferred[fe.Field] = value
+	}
+	for field := range deferred {
+		delete(fields, field)
+	}
+	return deferred
+}
+
+// applyDeferredFields は作成時に省略したフィールドを作成後のチケットに設定します
+// スプリントはスプリントのAPIで追加します。設定できなかった場合もチケットの作成は成功として扱い、お知らせに残します。
+func (c *Client) applyDeferredFields(key string, deferred map[string]interface{}, fieldErrs []FieldError) {
+	for _, fe := range fieldErrs {
+		c.addNotice("%s: %s", key, fe.Remediation())
+	}
+	if sprintID, ok := deferred[c.sprintFieldID].(int); ok && c.sprintFieldID != "" {
+		delete(deferred, c.sprintFieldID)
+		if err := c.AddIssueToSprint(key, sprintID); err != nil {
+			c.addNotice("%s: スプリントの設定に失敗しました: %v", key, err)
+		}
+	}
+	if len(deferred) == 0 {
+		return
+	}
+	if err := c.UpdateIssueFields(key, deferred); err != nil {
+		names := make([]string, 0, len(deferred))
+		for _, fe := range fieldErrs {
+			if _, ok := deferred[fe.Field]; ok {
+				names = append(names, fe.Name)
+			}
+		}
+		c.addNotice("%s: %s の設定に失敗しました: %v", key, strings.Join(names, ", "), err)
+	}
+}
+
+// postIssue はチケットの作成を要求し、作成されたチケットのキーを返します
+// JIRAが失敗のステータスを返した場合は *apiError を返します。
+func (c *Client) postIssue(fields map[string]interface{}) (string, error) {
 	issue := map[string]interface{}{
 		"fields": fields,
 	}
 
 	// デバッグ用：リクエストボディをログ出力
 	if requestBody, marshalErr := json.MarshalIndent(issue, "", "  "); marshalErr == nil {
-		verbose.Printf("JIRA Issue作成リクエスト:\n%s\n", string(requestBody))
+		verbose.Tracef(verbose.API, "JIRA Issue作成リクエスト:\n%s\n", string(requestBody))
 	}
 
 	// JSONボディを作成
 	jsonBody, err := json.Marshal(issue)
 	if err != nil {
-		return nil, fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+		return "", fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
 	}
 
 	// 直接HTTPリクエストを送信（カスタムフィールド対応のため）
@@ -636,27 +1467,24 @@ func (c *Client) CreateIssue(ticket *ticket.Ticket) (*ticket.Ticket, error) {
 		fmt.Sprintf("%s/rest/api/2/issue", c.config.Server),
 		bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return "", fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// レスポンスボディを読み取り
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("レスポンスの読み取りに失敗しました: %v", err)
+		return "", fmt.Errorf("レスポンスの読み取りに失敗しました: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("JIRAチケットの作成に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+		return "", &apiError{message: "JIRAチケットの作成に失敗しました", statusCode: resp.StatusCode, body: string(bodyBytes)}
 	}
 
 	// レスポンスを解析して作成されたチケットのキーを取得
@@ -664,19 +1492,17 @@ func (c *Client) CreateIssue(ticket *ticket.Ticket) (*ticket.Ticket, error) {
 		Key string `json:"key"`
 	}
 	if err := json.Unmarshal(bodyBytes, &createResponse); err != nil {
-		return nil, fmt.Errorf("作成レスポンスの解析に失敗しました: %v", err)
-	}
-
-	// 作成されたチケットをfetchして正しいフォーマットで返す
-	createdTicket, err := c.FetchIssue(createResponse.Key)
-	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("作成レスポンスの解析に失敗しました: %v", err)
 	}
 
-	// スプリントは作成時にカスタムフィールドで直接設定済み
-	verbose.Printf("チケット作成完了: %s (スプリント設定済み)\n", createResponse.Key)
+	return createResponse.Key, nil
+}
 
-	return createdTicket, nil
+// SearchJQLResult は nextPageToken でページングする検索API (/rest/api/3/search/jql) のレスポンスです
+type SearchJQLResult struct {
+	Issues        []*Issue `json:"issues"`
+	NextPageToken string   `json:"nextPageToken"`
+	IsLast        bool     `json:"isLast"`
 }
 
 type SearchResult struct {
@@ -700,14 +1526,47 @@ type IssueFields struct {
 	Parent *struct {
 		ID  string `json:"id"`
 		Key string `json:"key"`
+		// Fields は親の一部のフィールドで、親がエピックの場合にエピックの名前を取り出すのに使います
+		Fields *struct {
+			Summary   string `json:"summary"`
+			IssueType struct {
+				Name           string `json:"name"`
+				HierarchyLevel int    `json:"hierarchyLevel"`
+			} `json:"issuetype"`
+		} `json:"fields"`
 	}
 	Status struct {
+		ID             string `json:"id"`
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key  string `json:"key"`
+			Name string `json:"name"`
+		} `json:"statusCategory"`
+	} `json:"status"`
+	TimeOriginalEstimate *int `json:"timeoriginalestimate"`
+	TimeTracking         *struct {
+		RemainingEstimateSeconds int `json:"remainingEstimateSeconds"`
+		TimeSpentSeconds         int `json:"timeSpentSeconds"`
+	} `json:"timetracking"`
+	Labels     []string `json:"labels"`
+	Components []struct {
+		Name string `json:"name"`
+	} `json:"components"`
+	FixVersions []struct {
+		Name string `json:"name"`
+	} `json:"fixVersions"`
+	IssueLinks []issueLink  `json:"issuelinks"`
+	Attachment []attachment `json:"attachment"`
+	Priority   *struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
-	} `json:"status"`
-	TimeOriginalEstimate *int     `json:"timeoriginalestimate"`
-	Description          *adf.ADF `json:"description"`
-	Assignee             *struct {
+	} `json:"priority"`
+	Resolution *struct {
+		Name string `json:"name"`
+	} `json:"resolution"`
+	Description *adf.ADF `json:"description"`
+	Environment *adf.ADF `json:"environment"`
+	Assignee    *struct {
 		AccountID    string `json:"accountId"`
 		EmailAddress string `json:"emailAddress"`
 		Name         string `json:"displayName"`
@@ -717,6 +1576,20 @@ type IssueFields struct {
 		EmailAddress string `json:"emailAddress"`
 		Name         string `json:"displayName"`
 	} `json:"reporter"`
+	Creator *struct {
+		AccountID    string `json:"accountId"`
+		EmailAddress string `json:"emailAddress"`
+		Name         string `json:"displayName"`
+	} `json:"creator"`
+	Comment *struct {
+		Comments []struct {
+			Author *struct {
+				Name string `json:"displayName"`
+			} `json:"author"`
+			Body    *adf.ADF `json:"body"`
+			Created string   `json:"created"`
+		} `json:"comments"`
+	} `json:"comment"`
 	Created      string                 `json:"created"`
 	Updated      string                 `json:"updated"`
 	CustomFields map[string]interface{} `json:"-"` // カスタムフィールドを格納するためのマップ
@@ -746,8 +1619,10 @@ func (f *IssueFields) UnmarshalJSON(data []byte) error {
 	// 既知のフィールドを除外してカスタムフィールドのみ抽出
 	knownFields := map[string]bool{
 		"summary": true, "issuetype": true, "parent": true, "status": true,
-		"timeoriginalestimate": true, "description": true, "assignee": true,
-		"reporter": true, "created": true, "updated": true,
+		"timeoriginalestimate": true, "timetracking": true, "description": true, "environment": true, "assignee": true,
+		"reporter": true, "creator": true, "created": true, "updated": true, "comment": true,
+		"labels": true, "components": true, "fixVersions": true, "issuelinks": true, "priority": true,
+		"attachment": true, "resolution": true,
 	}
 
 	f.CustomFields = make(map[string]interface{})
@@ -781,35 +1656,66 @@ func (f *IssueFields) UpdatedAt() (_ time.Time, err error) {
 	return updatedAt, nil
 }
 
-type JQL string
-
-func (c *Client) Search(ctx context.Context, jql JQL, startAt, maxResults int) (_ *SearchResult, err error) {
-	defer derrors.Wrap(&err)
-	type Request struct {
-		JQL        JQL      `json:"jql"`
-		Fields     []string `json:"fields"`
-		StartAt    int      `json:"startAt"`
-		MaxResults int      `json:"maxResults"`
-	}
-
+// issueFields はチケットの取得時に要求するフィールドの一覧を返します
+func (c *Client) issueFields() []string {
 	fields := []string{
 		"issuetype",
 		"timeoriginalestimate",
 		"aggregatetimeoriginalestimate",
+		"timetracking",
 		"summary",
 		"created",
 		"status",
 		"updated",
 		"assignee",
 		"description",
+		"environment",
 		"reporter",
+		"creator",
 		"parent",
+		"labels",
+		"components",
+		"fixVersions",
+		"issuelinks",
+		"priority",
+		"resolution",
+		"comment",
 	}
 
 	// スプリントフィールドが発見されている場合は追加
 	if c.sprintFieldID != "" {
 		fields = append(fields, c.sprintFieldID)
 	}
+	if c.flaggedFieldID != "" {
+		fields = append(fields, c.flaggedFieldID)
+	}
+	if c.teamFieldID != "" {
+		fields = append(fields, c.teamFieldID)
+	}
+	for _, m := range c.customFields {
+		fields = append(fields, m.ID)
+	}
+	if c.withAttachments {
+		fields = append(fields, "attachment")
+	}
+	if c.config.Epic.Link != "" {
+		fields = append(fields, c.config.Epic.Link)
+	}
+	return fields
+}
+
+type JQL string
+
+func (c *Client) Search(ctx context.Context, jql JQL, startAt, maxResults int) (_ *SearchResult, err error) {
+	defer derrors.Wrap(&err)
+	type Request struct {
+		JQL        JQL      `json:"jql"`
+		Fields     []string `json:"fields"`
+		StartAt    int      `json:"startAt"`
+		MaxResults int      `json:"maxResults"`
+	}
+
+	fields := c.issueFields()
 
 	reqBody := Request{
 		JQL:        jql,
@@ -829,27 +1735,20 @@ func (c *Client) Search(ctx context.Context, jql JQL, startAt, maxResults int) (
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// レスポンスボディを読み取り、デバッグ出力
+	// レスポンスボディを読み取り、デバッグ出力 (ボディはチケットの内容を含むため -vv のみ)
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	verbose.Printf("=== JIRA Search API Response ===\n")
-	verbose.Printf("Status: %s\n", resp.Status)
-	verbose.Printf("JQL: %s\n", jql)
-	verbose.Printf("Body: %s\n", string(bodyBytes))
-	verbose.Printf("================================\n")
+	verbose.Printf(verbose.API, "JIRA Search API: %s (JQL: %s)\n", resp.Status, jql)
+	verbose.Tracef(verbose.API, "Body: %s\n", string(bodyBytes))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, errors.New("JIRA APIリクエストが失敗しました: " + resp.Status)
@@ -863,27 +1762,128 @@ func (c *Client) Search(ctx context.Context, jql JQL, startAt, maxResults int) (
 	return &result, nil
 }
 
-func (c *Client) Get(ctx context.Context, key string) (_ *Issue, err error) {
+// errSearchJQLUnsupported はサーバーが新しい検索API (/rest/api/3/search/jql) に対応していないことを表します
+var errSearchJQLUnsupported = errors.New("新しい検索APIに対応していません")
+
+// SearchJQL は nextPageToken でページングする検索API (/rest/api/3/search/jql) で1ページ分のチケットを取得します。
+// 最初のページは nextPageToken を空にして呼び出します。
+func (c *Client) SearchJQL(ctx context.Context, jql JQL, nextPageToken string, maxResults int) (_ *SearchJQLResult, err error) {
 	defer derrors.Wrap(&err)
+	type Request struct {
+		JQL           JQL      `json:"jql"`
+		Fields        []string `json:"fields"`
+		MaxResults    int      `json:"maxResults"`
+		NextPageToken string   `json:"nextPageToken,omitempty"`
+	}
+
+	jsonBody, err := json.Marshal(Request{
+		JQL:           jql,
+		Fields:        c.issueFields(),
+		MaxResults:    maxResults,
+		NextPageToken: nextPageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	fields := []string{
-		"issuetype",
-		"timeoriginalestimate",
-		"aggregatetimeoriginalestimate",
-		"summary",
-		"created",
-		"status",
-		"updated",
-		"assignee",
-		"description",
-		"reporter",
-		"parent",
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Server+"/rest/api/3/search/jql", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	// スプリントフィールドが発見されている場合は追加
-	if c.sprintFieldID != "" {
-		fields = append(fields, c.sprintFieldID)
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	verbose.Printf(verbose.API, "JIRA Search API: %s (JQL: %s, NextPageToken: %s)\n", resp.Status, jql, nextPageToken)
+	verbose.Tracef(verbose.API, "Body: %s\n", string(bodyBytes))
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		// Server/DCなど、エンドポイントが存在しない場合
+		return nil, errSearchJQLUnsupported
+	default:
+		return nil, errors.New("JIRA APIリクエストが失敗しました: " + resp.Status)
+	}
+
+	var result SearchJQLResult
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CountIssues はJQLに一致するチケットの件数を返します
+// Cloudでは件数のみを返すAPI (/rest/api/3/search/approximate-count) を使い、
+// 対応していないServer/DCでは maxResults=0 の検索結果の total を使います。
+func (c *Client) CountIssues(ctx context.Context, jql JQL) (_ int, err error) {
+	defer derrors.Wrap(&err)
+	if !c.legacySearch.Load() {
+		count, err := c.approximateCount(ctx, jql)
+		if !errors.Is(err, errSearchJQLUnsupported) {
+			return count, err
+		}
+		verbose.Printf(verbose.API, "件数のみを返す検索APIに対応していないため、従来の検索APIで件数を数えます\n")
+		c.legacySearch.Store(true)
+	}
+	result, err := c.Search(ctx, jql, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	return result.Total, nil
+}
+
+// approximateCount は /rest/api/3/search/approximate-count でJQLに一致するチケットの件数を取得します
+// エンドポイントがない場合は errSearchJQLUnsupported を返します。
+func (c *Client) approximateCount(ctx context.Context, jql JQL) (int, error) {
+	jsonBody, err := json.Marshal(struct {
+		JQL JQL `json:"jql"`
+	}{JQL: jql})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Server+"/rest/api/3/search/approximate-count", bytes.NewReader(jsonBody))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return 0, errSearchJQLUnsupported
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("JIRA APIリクエストが失敗しました: %s: %s", resp.Status, string(body))
+	}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, err
 	}
+	return result.Count, nil
+}
+
+func (c *Client) Get(ctx context.Context, key string) (_ *Issue, err error) {
+	defer derrors.Wrap(&err)
+
+	fields := c.issueFields()
 
 	url := fmt.Sprintf("%s/rest/api/3/issue/%s?fields=%s", c.config.Server, key, strings.Join(fields, ","))
 
@@ -891,11 +1891,7 @@ func (c *Client) Get(ctx context.Context, key string) (_ *Issue, err error) {
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -917,7 +1913,7 @@ func (c *Client) Get(ctx context.Context, key string) (_ *Issue, err error) {
 }
 
 // BulkFetchIssues は複数のJIRAチケットを一括で取得します
-func (c *Client) BulkFetchIssues(keys []string) (_ []*ticket.Ticket, err error) {
+func (c *Client) BulkFetchIssues(ctx context.Context, keys []string) (_ []*ticket.Ticket, err error) {
 	defer derrors.Wrap(&err)
 	if len(keys) == 0 {
 		return []*ticket.Ticket{}, nil
@@ -929,7 +1925,6 @@ func (c *Client) BulkFetchIssues(keys []string) (_ []*ticket.Ticket, err error)
 	}
 
 	const batchSize = 100 // JIRA Cloud APIの制限に基づく
-	ctx := context.Background()
 
 	// キーを適切なサイズに分割
 	batches := make([][]string, 0, (len(keys)+batchSize-1)/batchSize)
@@ -938,21 +1933,21 @@ func (c *Client) BulkFetchIssues(keys []string) (_ []*ticket.Ticket, err error)
 		batches = append(batches, keys[i:end])
 	}
 
-	verbose.Printf("BulkFetchIssues: Total %d keys split into %d batches (max %d per batch)\n", len(keys), len(batches), batchSize)
+	verbose.Printf(verbose.API, "BulkFetchIssues: Total %d keys split into %d batches (max %d per batch)\n", len(keys), len(batches), batchSize)
 
 	// 並列でバッチ処理
-	p := pool.NewWithResults[[]*Issue]().WithContext(ctx).WithMaxGoroutines(5)
+	p := pool.NewWithResults[[]*Issue]().WithContext(ctx).WithMaxGoroutines(c.config.RequestConcurrency())
 	for batchIndex, batch := range batches {
 		batch := batch // ループ変数のキャプチャ
 		batchIndex := batchIndex
 		p.Go(func(ctx context.Context) ([]*Issue, error) {
-			verbose.Printf("Starting batch %d: fetching %d issues (%v)\n", batchIndex+1, len(batch), batch)
+			verbose.Printf(verbose.API, "Starting batch %d: fetching %d issues (%v)\n", batchIndex+1, len(batch), batch)
 			issues, err := c.bulkFetchBatch(ctx, batch)
 			if err != nil {
-				verbose.Printf("Batch %d failed: %v\n", batchIndex+1, err)
+				verbose.Printf(verbose.API, "Batch %d failed: %v\n", batchIndex+1, err)
 				return nil, err
 			}
-			verbose.Printf("Batch %d completed: successfully fetched %d issues\n", batchIndex+1, len(issues))
+			verbose.Printf(verbose.API, "Batch %d completed: successfully fetched %d issues\n", batchIndex+1, len(issues))
 			return issues, nil
 		})
 	}
@@ -968,12 +1963,13 @@ func (c *Client) BulkFetchIssues(keys []string) (_ []*ticket.Ticket, err error)
 	// IssueからTicketに変換
 	tickets := make([]*ticket.Ticket, 0, len(allIssues))
 	for _, issue := range allIssues {
-		ticket, err := c.convertWithSprint(issue)
+		ticket, err := c.convertIssue(issue)
 		if err != nil {
 			return nil, err
 		}
 		tickets = append(tickets, ticket)
 	}
+	c.addReferences(tickets)
 
 	return tickets, nil
 }
@@ -981,7 +1977,7 @@ func (c *Client) BulkFetchIssues(keys []string) (_ []*ticket.Ticket, err error)
 // bulkFetchBatch は単一バッチのチケットを取得します
 func (c *Client) bulkFetchBatch(ctx context.Context, keys []string) (_ []*Issue, err error) {
 	defer derrors.Wrap(&err)
-	verbose.Printf("bulkFetchBatch: Making API call for keys: %v\n", keys)
+	verbose.Printf(verbose.API, "bulkFetchBatch: Making API call for keys: %v\n", keys)
 
 	type BulkFetchRequest struct {
 		IssueIdsOrKeys []string `json:"issueIdsOrKeys"`
@@ -997,24 +1993,7 @@ func (c *Client) bulkFetchBatch(ctx context.Context, keys []string) (_ []*Issue,
 		} `json:"errors"`
 	}
 
-	fields := []string{
-		"issuetype",
-		"timeoriginalestimate",
-		"aggregatetimeoriginalestimate",
-		"summary",
-		"created",
-		"status",
-		"updated",
-		"assignee",
-		"description",
-		"reporter",
-		"parent",
-	}
-
-	// スプリントフィールドが発見されている場合は追加
-	if c.sprintFieldID != "" {
-		fields = append(fields, c.sprintFieldID)
-	}
+	fields := c.issueFields()
 
 	reqBody := BulkFetchRequest{
 		IssueIdsOrKeys: keys,
@@ -1033,11 +2012,7 @@ func (c *Client) bulkFetchBatch(ctx context.Context, keys []string) (_ []*Issue,
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1052,12 +2027,12 @@ func (c *Client) bulkFetchBatch(ctx context.Context, keys []string) (_ []*Issue,
 		return nil, err
 	}
 
-	verbose.Printf("bulkFetchBatch: API response - got %d issues, %d errors\n", len(result.Issues), len(result.Errors))
+	verbose.Printf(verbose.API, "bulkFetchBatch: API response - got %d issues, %d errors\n", len(result.Issues), len(result.Errors))
 
 	// エラーがある場合はログに出力（部分的な成功も許可）
 	if len(result.Errors) > 0 {
 		for _, apiErr := range result.Errors {
-			verbose.Printf("Warning: Failed to fetch issue %s: %s\n", apiErr.IssueIDOrKey, apiErr.ErrorMessage)
+			verbose.Printf(verbose.API, "Warning: Failed to fetch issue %s: %s\n", apiErr.IssueIDOrKey, apiErr.ErrorMessage)
 		}
 	}
 
@@ -1085,10 +2060,10 @@ func (c *Client) GetActiveAndFutureSprintsWithContext(ctx context.Context, board
 }
 
 // getSprintsPageWithTotal はスプリントの1ページを取得します（総数情報付き）
-func (c *Client) getSprintsPageWithTotal(boardID int, startAt int, maxResults int, states []string) ([]Sprint, bool, int, error) {
+func (c *Client) getSprintsPageWithTotal(ctx context.Context, boardID int, startAt int, maxResults int, states []string) ([]Sprint, bool, int, error) {
 	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint", c.config.Server, boardID)
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, false, 0, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
@@ -1101,12 +2076,9 @@ func (c *Client) getSprintsPageWithTotal(boardID int, startAt int, maxResults in
 	}
 	req.URL.RawQuery = q.Encode()
 
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return nil, false, 0, fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return nil, false, 0, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -1115,11 +2087,8 @@ func (c *Client) getSprintsPageWithTotal(boardID int, startAt int, maxResults in
 		return nil, false, 0, fmt.Errorf("レスポンスの読み取りに失敗しました: %v", err)
 	}
 
-	// デバッグ用: APIレスポンスをダンプ
-	verbose.Printf("DEBUG: Sprint API Response (boardID=%d, startAt=%d, maxResults=%d, states=%v):\n", boardID, startAt, maxResults, states)
-	verbose.Printf("Status: %d\n", resp.StatusCode)
-	verbose.Printf("Body: %s\n", string(bodyBytes))
-	verbose.Printf("---\n")
+	verbose.Printf(verbose.API, "Sprint API: %s (boardID=%d, startAt=%d, maxResults=%d, states=%v)\n", resp.Status, boardID, startAt, maxResults, states)
+	verbose.Tracef(verbose.API, "Body: %s\n", string(bodyBytes))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, false, 0, fmt.Errorf("スプリント取得に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
@@ -1141,8 +2110,8 @@ func (c *Client) getSprintsPageWithTotal(boardID int, startAt int, maxResults in
 }
 
 // getSprintsPage はスプリントの1ページを取得します
-func (c *Client) getSprintsPage(boardID int, startAt int, maxResults int, states []string) ([]Sprint, bool, error) {
-	sprints, isLast, _, err := c.getSprintsPageWithTotal(boardID, startAt, maxResults, states)
+func (c *Client) getSprintsPage(ctx context.Context, boardID int, startAt int, maxResults int, states []string) ([]Sprint, bool, error) {
+	sprints, isLast, _, err := c.getSprintsPageWithTotal(ctx, boardID, startAt, maxResults, states)
 	return sprints, isLast, err
 }
 
@@ -1161,7 +2130,7 @@ func (c *Client) getSprintsWithPagination(ctx context.Context, boardID int, stat
 	const pageSize = 50
 
 	// 最初のページを取得して全件数を把握
-	firstPageSprints, isLast, total, err := c.getSprintsPageWithTotal(boardID, 0, pageSize, states)
+	firstPageSprints, isLast, total, err := c.getSprintsPageWithTotal(ctx, boardID, 0, pageSize, states)
 	if err != nil {
 		return nil, err
 	}
@@ -1180,12 +2149,12 @@ func (c *Client) getSprintsWithPagination(ctx context.Context, boardID int, stat
 	allSprints = append(allSprints, firstPageSprints...)
 
 	// 2ページ目以降を並列で取得
-	p := pool.NewWithResults[[]Sprint]().WithContext(ctx).WithMaxGoroutines(5)
+	p := pool.NewWithResults[[]Sprint]().WithContext(ctx).WithMaxGoroutines(c.config.RequestConcurrency())
 
 	for page := 1; page < totalPages; page++ {
 		currentStartAt := page * maxResults
 		p.Go(func(ctx context.Context) ([]Sprint, error) {
-			sprints, _, _, err := c.getSprintsPageWithTotal(boardID, currentStartAt, maxResults, states)
+			sprints, _, _, err := c.getSprintsPageWithTotal(ctx, boardID, currentStartAt, maxResults, states)
 			if err != nil {
 				return nil, err
 			}
@@ -1227,12 +2196,9 @@ func (c *Client) AddIssueToSprint(issueKey string, sprintID int) error {
 		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -1244,44 +2210,123 @@ func (c *Client) AddIssueToSprint(issueKey string, sprintID int) error {
 	return nil
 }
 
+// SprintActive はフロントマターでpush時点のアクティブなスプリントを指定する場合の値です
+const SprintActive = "@active"
+
 // findSprintIDByName はスプリント名からスプリントIDを解決します
 func (c *Client) findSprintIDByName(sprintName string) (int, error) {
-	// 設定からボードIDを取得
-	if c.config.Board.ID == 0 {
-		return 0, fmt.Errorf("ボード設定が見つかりません")
+	// @active はボードのアクティブなスプリント (1つに定まらなければエラー) に解決する
+	if sprintName == SprintActive {
+		sprint, err := c.FindActiveSprint(context.Background())
+		if err != nil {
+			return 0, err
+		}
+		return sprint.ID, nil
+	}
+	sprint, err := c.FindSprintByName(context.Background(), sprintName)
+	if err != nil {
+		return 0, err
+	}
+	return sprint.ID, nil
+}
+
+// FindSprintByName は設定されたボードからスプリント名に一致するスプリントを探します
+// board.idsで複数のボードが設定されている場合はすべてのボードを探します。
+// スプリントの一覧はクライアントごとに1回だけ取得し (SetSprintCacheDir を呼んだ場合は sprints.json を使い)、
+// 見つからない場合はアクティブと未来のスプリントだけを取得し直します。
+func (c *Client) FindSprintByName(ctx context.Context, sprintName string) (*Sprint, error) {
+	boardIDs := c.config.BoardIDs()
+	if len(boardIDs) == 0 {
+		return nil, fmt.Errorf("ボード設定が見つかりません")
+	}
+
+	sprints, err := c.boardSprints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := findSprintsByName(sprints, sprintName)
+	if len(matched) == 0 {
+		// 一覧を読み込んだ後に作られたスプリントかもしれない
+		sprints, err = c.refreshOpenSprints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		matched = findSprintsByName(sprints, sprintName)
+	}
+	if len(matched) == 0 {
+		if names := nearestSprintNames(sprints, sprintName); len(names) > 0 {
+			return nil, fmt.Errorf("スプリント '%s' が見つかりません。近い名前のスプリント: %s", sprintName, strings.Join(names, ", "))
+		}
+		return nil, fmt.Errorf("スプリント '%s' が見つかりません", sprintName)
+	}
+	if len(matched) > 1 {
+		verbose.Printf(verbose.API, "スプリント '%s' に一致するスプリントが %d 件あります。ボード %d の%sのスプリント (ID: %d) を使用します\n", sprintName, len(matched), matched[0].BoardID, matched[0].State, matched[0].ID)
+	}
+	return &matched[0], nil
+}
+
+// FindActiveSprint は設定されたボードのアクティブなスプリントを返します
+// アクティブなスプリントが1つに定まらない場合はエラーを返します
+func (c *Client) FindActiveSprint(ctx context.Context) (*Sprint, error) {
+	boardIDs := c.config.BoardIDs()
+	if len(boardIDs) == 0 {
+		return nil, fmt.Errorf("ボード設定が見つかりません")
 	}
 
-	sprints, err := c.GetBoardSprints(c.config.Board.ID)
+	sprints, err := c.fetchActiveSprints(ctx, boardIDs)
 	if err != nil {
-		return 0, fmt.Errorf("スプリント一覧の取得に失敗しました: %v", err)
+		return nil, err
 	}
 
-	for _, sprint := range sprints {
-		if sprint.Name == sprintName {
-			return sprint.ID, nil
+	switch len(sprints) {
+	case 0:
+		return nil, fmt.Errorf("アクティブなスプリントが見つかりません")
+	case 1:
+		return &sprints[0], nil
+	default:
+		names := make([]string, 0, len(sprints))
+		for _, sprint := range sprints {
+			names = append(names, sprint.Name)
 		}
+		return nil, fmt.Errorf("アクティブなスプリントが複数あります。スプリント名を指定してください: %s", strings.Join(names, ", "))
 	}
+}
 
-	return 0, fmt.Errorf("スプリント '%s' が見つかりません", sprintName)
+// fetchActiveSprints はボードのアクティブなスプリントを取得します
+// 同じpushで @active のチケットが複数あっても、取得はクライアントごとに1回だけです。
+func (c *Client) fetchActiveSprints(ctx context.Context, boardIDs []int) ([]Sprint, error) {
+	c.sprintsMu.Lock()
+	defer c.sprintsMu.Unlock()
+	if c.activeSprintsLoaded {
+		return c.activeSprints, nil
+	}
+	sprints, err := c.getSprintsOfBoards(ctx, boardIDs, []string{"active"})
+	if err != nil {
+		return nil, fmt.Errorf("アクティブなスプリントの取得に失敗しました: %w", err)
+	}
+	c.activeSprints, c.activeSprintsLoaded = sprints, true
+	return sprints, nil
 }
 
 // addSprintFieldToUpdate はスプリントフィールドを更新フィールドに追加します
 func (c *Client) addSprintFieldToUpdate(fields map[string]interface{}, ticket ticket.Ticket) error {
-	// スプリント名が指定されていない場合は何もしない
-	if ticket.SprintName == "" {
-		verbose.Printf("スプリント名が指定されていないため、スプリント更新をスキップします\n")
+	// スプリントフィールドIDが発見されていない場合は何もしない
+	if c.sprintFieldID == "" {
+		verbose.Printf(verbose.API, "スプリントフィールドIDが見つからないため、スプリント更新をスキップします\n")
 		return nil
 	}
 
-	// スプリントフィールドIDが発見されていない場合は何もしない
-	if c.sprintFieldID == "" {
-		verbose.Printf("スプリントフィールドIDが見つからないため、スプリント更新をスキップします\n")
+	// スプリントが空または backlog の場合はスプリントから外してバックログに戻す
+	if ticket.InBacklog() {
+		verbose.Printf(verbose.API, "スプリントフィールド %s を空にしてバックログに戻します\n", c.sprintFieldID)
+		fields[c.sprintFieldID] = nil
 		return nil
 	}
 
 	// ボード設定がない場合は何もしない
-	if c.config.Board.ID == 0 {
-		verbose.Printf("ボード設定が見つからないため、スプリント更新をスキップします\n")
+	if len(c.config.BoardIDs()) == 0 {
+		verbose.Printf(verbose.API, "ボード設定が見つからないため、スプリント更新をスキップします\n")
 		return nil
 	}
 
@@ -1291,7 +2336,7 @@ func (c *Client) addSprintFieldToUpdate(fields map[string]interface{}, ticket ti
 		return fmt.Errorf("目標スプリントIDの解決に失敗しました: %v", err)
 	}
 
-	verbose.Printf("スプリントフィールド %s をスプリント '%s' (ID: %d) に設定します\n", c.sprintFieldID, ticket.SprintName, targetSprintID)
+	verbose.Printf(verbose.API, "スプリントフィールド %s をスプリント '%s' (ID: %d) に設定します\n", c.sprintFieldID, ticket.SprintName, targetSprintID)
 
 	// スプリントフィールドに直接スプリントIDを設定
 	fields[c.sprintFieldID] = targetSprintID
@@ -1299,41 +2344,44 @@ func (c *Client) addSprintFieldToUpdate(fields map[string]interface{}, ticket ti
 	return nil
 }
 
-// discoverSprintField はJIRA APIからスプリントフィールドを動的に発見します
-func (c *Client) discoverSprintField() error {
+// fieldDefinition はJIRAのフィールド定義です
+type fieldDefinition struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+	Schema struct {
+		Custom   string `json:"custom"`
+		Type     string `json:"type"`
+		Items    string `json:"items"`
+		CustomID int    `json:"customId"`
+	} `json:"schema"`
+}
+
+// fetchFieldDefinitions はJIRA APIからフィールド定義の一覧を取得します
+func (c *Client) fetchFieldDefinitions() ([]fieldDefinition, error) {
 	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/field", nil)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("フィールド情報の取得に失敗しました (status: %d)", resp.StatusCode)
-	}
-
-	var fields []struct {
-		ID     string `json:"id"`
-		Name   string `json:"name"`
-		Custom bool   `json:"custom"`
-		Schema struct {
-			Custom   string `json:"custom"`
-			Type     string `json:"type"`
-			Items    string `json:"items"`
-			CustomID int    `json:"customId"`
-		} `json:"schema"`
+		return nil, fmt.Errorf("フィールド情報の取得に失敗しました (status: %d)", resp.StatusCode)
 	}
 
+	var fields []fieldDefinition
 	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
-		return fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
 	}
+	return fields, nil
+}
 
+// discoverSprintField はフィールド定義からスプリントフィールドを動的に発見します
+func (c *Client) discoverSprintField(fields []fieldDefinition) error {
 	// スプリントフィールドを検索
 	for _, field := range fields {
 		isSprintField := false
@@ -1352,15 +2400,15 @@ func (c *Client) discoverSprintField() error {
 
 		if isSprintField {
 			c.sprintFieldID = field.ID
-			verbose.Printf("スプリントフィールドを発見しました: %s (%s) - Schema: %+v\n", field.ID, field.Name, field.Schema)
+			verbose.Printf(verbose.API, "スプリントフィールドを発見しました: %s (%s) - Schema: %+v\n", field.ID, field.Name, field.Schema)
 			return nil
 		}
 	}
 
-	verbose.Printf("利用可能なカスタムフィールド:\n")
+	verbose.Printf(verbose.API, "利用可能なカスタムフィールド:\n")
 	for _, field := range fields {
 		if field.Custom {
-			verbose.Printf("  %s: %s (Schema: %+v)\n", field.ID, field.Name, field.Schema)
+			verbose.Printf(verbose.API, "  %s: %s (Schema: %+v)\n", field.ID, field.Name, field.Schema)
 		}
 	}
 
@@ -1375,12 +2423,9 @@ func (c *Client) DeleteIssue(issueKey string) error {
 		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
 	}
 
-	req.SetBasicAuth(c.config.Login, getAPIToken())
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %v", err)
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
 	}
 	defer resp.Body.Close()
 
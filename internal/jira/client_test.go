@@ -0,0 +1,681 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/md"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateIssueSendsOnlyChangedFields(t *testing.T) {
+	t.Parallel()
+
+	points := 5.0
+	local := ticket.Ticket{
+		Key:              "PRJ-1",
+		Title:            "新しいタイトル",
+		Type:             "Task",
+		Status:           "To Do",
+		Body:             "# 本文\n\n- 箇条書き\n",
+		Priority:         "High",
+		Labels:           []string{"backend"},
+		Components:       []string{"API"},
+		OriginalEstimate: 2,
+		CustomFields:     map[string]*float64{"story_points": &points},
+	}
+
+	tests := []struct {
+		name    string
+		changed []string
+		local   func(t ticket.Ticket) ticket.Ticket
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "タイトルのみ",
+			changed: []string{"title"},
+			want:    map[string]any{"fields": map[string]any{"summary": "新しいタイトル"}},
+		},
+		{
+			name:    "本文とラベル",
+			changed: []string{"labels", "body"},
+			want: map[string]any{"fields": map[string]any{
+				"description": md.ToJiraMD(local.Body),
+				"labels":      []any{"backend"},
+			}},
+		},
+		{
+			name:    "見積もりとカスタムフィールド",
+			changed: []string{"original_estimate", "story_points"},
+			want: map[string]any{"fields": map[string]any{
+				"timetracking":      map[string]any{"originalEstimate": "2.0h"},
+				"customfield_10016": 5.0,
+			}},
+		},
+		{
+			name:    "本文と見積もりを削除",
+			changed: []string{"body", "remaining_estimate"},
+			local:   func(t ticket.Ticket) ticket.Ticket { t.Body = ""; t.RemainingEstimate = 0; return t },
+			want: map[string]any{"fields": map[string]any{
+				"description":  nil,
+				"timetracking": map[string]any{"remainingEstimate": "0h"},
+			}},
+		},
+		{
+			name:    "type_idのないファイルでtypeを変更",
+			changed: []string{"type"},
+			local:   func(t ticket.Ticket) ticket.Ticket { t.Type = "bug"; return t },
+			want:    map[string]any{"fields": map[string]any{"issuetype": map[string]any{"id": "10002"}}},
+		},
+		{
+			name:    "type_idのあるファイルでtypeを変更",
+			changed: []string{"type"},
+			local:   func(t ticket.Ticket) ticket.Ticket { t.Type = "Bug"; t.TypeID = "10001"; return t },
+			want:    map[string]any{"fields": map[string]any{"issuetype": map[string]any{"id": "10002"}}},
+		},
+		{
+			name:    "typeがtype_idと同じIssue Type",
+			changed: []string{"type"},
+			local:   func(t ticket.Ticket) ticket.Ticket { t.TypeID = "10001"; return t },
+			want:    nil,
+		},
+		{
+			name:    "issue.typesにないtype",
+			changed: []string{"type"},
+			local:   func(t ticket.Ticket) ticket.Ticket { t.Type = "epic"; return t },
+			wantErr: true,
+		},
+		{
+			name:    "送信する項目がない",
+			changed: []string{"links"},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requests []map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/rest/api/2/issue/PRJ-1", r.URL.Path)
+				b, _ := io.ReadAll(r.Body)
+				var body map[string]any
+				assert.NoError(t, json.Unmarshal(b, &body))
+				mu.Lock()
+				requests = append(requests, body)
+				mu.Unlock()
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{Server: srv.URL}
+			cfg.Issue.Types = []config.IssueType{
+				{ID: "10001", Name: "作業", UntranslatedName: "Task"},
+				{ID: "10002", Name: "バグ", UntranslatedName: "Bug"},
+			}
+			c := &Client{
+				config:       cfg,
+				customFields: []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}},
+			}
+			l := local
+			if tt.local != nil {
+				l = tt.local(local)
+			}
+			err := c.UpdateIssue(l, tt.changed)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, requests)
+				return
+			}
+			assert.NoError(t, err)
+
+			if tt.want == nil {
+				assert.Empty(t, requests)
+				return
+			}
+			if assert.Len(t, requests, 1) {
+				assert.Equal(t, tt.want, requests[0])
+			}
+		})
+	}
+}
+
+func TestUpdateIssueClearsParent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		// rejectNull は parent: null をフィールドエラーで拒否するインスタンスかどうかです
+		rejectNull bool
+		want       []string
+	}{
+		{
+			name: "parent: null で外せる",
+			want: []string{`{"fields":{"parent":null}}`},
+		},
+		{
+			name:       "parent: null を拒否するインスタンスではupdateの操作で外す",
+			rejectNull: true,
+			want: []string{
+				`{"fields":{"parent":null}}`,
+				`{"update":{"parent":[{"set":{"none":true}}]}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				mu.Lock()
+				requests = append(requests, string(b))
+				mu.Unlock()
+				if tt.rejectNull && string(b) == `{"fields":{"parent":null}}` {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"errorMessages":[],"errors":{"parent":"Could not find issue by id or key."}}`))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			c := &Client{config: &config.Config{Server: srv.URL}}
+			err := c.UpdateIssue(ticket.Ticket{Key: "PRJ-1", Title: "子チケット"}, []string{"parentKey"})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, requests)
+		})
+	}
+}
+
+func TestUpdateIssueStatusTransition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		changed      []string
+		strict       bool
+		wantRequests []string
+		wantErr      bool
+		wantNotice   bool
+	}{
+		{
+			name:         "ステータスを変更していない場合はトランジションを取得しない",
+			changed:      []string{"title"},
+			wantRequests: []string{"PUT /rest/api/2/issue/PRJ-1"},
+		},
+		{
+			name:         "遷移できない場合は警告",
+			changed:      []string{"title", "status"},
+			wantRequests: []string{"PUT /rest/api/2/issue/PRJ-1", "GET /rest/api/2/issue/PRJ-1/transitions"},
+			wantNotice:   true,
+		},
+		{
+			name:         "strict_transitions の場合はエラー",
+			changed:      []string{"title", "status"},
+			strict:       true,
+			wantRequests: []string{"PUT /rest/api/2/issue/PRJ-1", "GET /rest/api/2/issue/PRJ-1/transitions"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				mu.Unlock()
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(`{"transitions": [{"id": "21", "to": {"name": "In Progress"}}]}`))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{Server: srv.URL}
+			cfg.Push.StrictTransitions = tt.strict
+			c := &Client{config: cfg}
+			// 経由する遷移は TestUpdateIssueStatusMultiHop で確認する
+			c.DisableMultiHopTransitions()
+			err := c.UpdateIssue(ticket.Ticket{Key: "PRJ-1", Title: "タイトル", Status: "Done"}, tt.changed)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRequests, requests)
+			notices := c.TakeNotices()
+			if tt.wantNotice {
+				if assert.Len(t, notices, 1) {
+					assert.Contains(t, notices[0], "PRJ-1: ステータスを 'Done' に変更できませんでした")
+				}
+			} else {
+				assert.Empty(t, notices)
+			}
+		})
+	}
+}
+
+func TestUpdateIssueStatusMultiHop(t *testing.T) {
+	t.Parallel()
+
+	// ステータスごとの遷移先 (To Do -> In Progress -> In Review -> Done)
+	workflow := map[string][]string{
+		"To Do":       {"In Progress"},
+		"In Progress": {"To Do", "In Review"},
+		"In Review":   {"In Progress", "Done"},
+		"Done":        {"To Do"},
+	}
+	categories := map[string]string{"To Do": "new", "In Progress": "indeterminate", "In Review": "indeterminate", "Done": "done"}
+
+	tests := []struct {
+		name       string
+		target     string
+		noMultiHop bool
+		wantStatus string
+		wantPosts  int
+		wantErr    bool
+	}{
+		{
+			name:       "直接遷移できる場合",
+			target:     "In Progress",
+			wantStatus: "In Progress",
+			wantPosts:  1,
+		},
+		{
+			name:       "途中のステータスを経由して遷移",
+			target:     "Done",
+			wantStatus: "Done",
+			wantPosts:  3,
+		},
+		{
+			name:       "--no-multi-hop の場合は経由しない",
+			target:     "Done",
+			noMultiHop: true,
+			wantStatus: "To Do",
+			wantErr:    true,
+		},
+		{
+			name:       "どこからも遷移できないステータス",
+			target:     "Archived",
+			wantStatus: "Done",
+			wantPosts:  3,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			status, posts := "To Do", 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PRJ-1":
+					_ = json.NewEncoder(w).Encode(map[string]any{"fields": map[string]any{"status": map[string]string{"name": status}}})
+				case r.Method == http.MethodGet:
+					var transitions []map[string]any
+					for _, to := range workflow[status] {
+						transitions = append(transitions, map[string]any{
+							"id": to,
+							"to": map[string]any{"name": to, "statusCategory": map[string]string{"key": categories[to]}},
+						})
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"transitions": transitions})
+				case r.Method == http.MethodPost:
+					var body struct {
+						Transition struct {
+							ID string `json:"id"`
+						} `json:"transition"`
+					}
+					_ = json.NewDecoder(r.Body).Decode(&body)
+					status = body.Transition.ID
+					posts++
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer srv.Close()
+
+			c := &Client{config: &config.Config{Server: srv.URL}}
+			if tt.noMultiHop {
+				c.DisableMultiHopTransitions()
+			}
+			err := c.updateIssueStatus("PRJ-1", tt.target, "")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantPosts, posts)
+		})
+	}
+}
+
+func TestTransitionFields(t *testing.T) {
+	t.Parallel()
+
+	resolutionField := func(required bool, allowed ...string) TransitionField {
+		f := TransitionField{Name: "Resolution", Required: required}
+		for _, name := range allowed {
+			f.AllowedValues = append(f.AllowedValues, TransitionFieldValue{Name: name})
+		}
+		return f
+	}
+
+	tests := []struct {
+		name       string
+		fields     map[string]TransitionField
+		resolution string
+		want       map[string]any
+		wantErr    string
+	}{
+		{
+			name: "画面に項目がない",
+			want: map[string]any{},
+		},
+		{
+			name:       "画面に解決状況がない場合はローカルの値を送らない",
+			resolution: "Won't Do",
+			want:       map[string]any{},
+		},
+		{
+			name:       "ローカルの解決状況を送る",
+			fields:     map[string]TransitionField{"resolution": resolutionField(false, "Done", "Won't Do")},
+			resolution: "Won't Do",
+			want:       map[string]any{"resolution": map[string]string{"name": "Won't Do"}},
+		},
+		{
+			name:   "必須の場合は Done",
+			fields: map[string]TransitionField{"resolution": resolutionField(true, "Won't Do", "Done")},
+			want:   map[string]any{"resolution": map[string]string{"name": "Done"}},
+		},
+		{
+			name:   "Done が選択肢にない場合は最初の選択肢",
+			fields: map[string]TransitionField{"resolution": resolutionField(true, "Fixed", "Won't Fix")},
+			want:   map[string]any{"resolution": map[string]string{"name": "Fixed"}},
+		},
+		{
+			name:   "必須でない場合は送らない",
+			fields: map[string]TransitionField{"resolution": resolutionField(false, "Done")},
+			want:   map[string]any{},
+		},
+		{
+			name: "設定できない必須の項目",
+			fields: map[string]TransitionField{
+				"resolution":        resolutionField(true),
+				"customfield_10050": {Name: "Root Cause", Required: true},
+				"comment":           {Name: "Comment"},
+				"assignee":          {Name: "Assignee", Required: true, HasDefaultValue: true},
+			},
+			wantErr: "tkt から設定できない必須の項目があります: Root Cause",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			transition := Transition{ID: "31", Fields: tt.fields}
+			transition.To.Name = "Done"
+			got, err := transitionFields(transition, tt.resolution)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateIssueStatusWithResolution(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		resolution string
+		status     int
+		response   string
+		wantBody   string
+		wantErr    string
+	}{
+		{
+			name:       "解決状況を送る",
+			resolution: "Won't Do",
+			status:     http.StatusNoContent,
+			wantBody:   `{"fields":{"resolution":{"name":"Won't Do"}},"transition":{"id":"31"}}`,
+		},
+		{
+			name:     "不足している項目をエラーに示す",
+			status:   http.StatusBadRequest,
+			response: `{"errorMessages":[],"errors":{"customfield_10050":"Root Cause is required."}}`,
+			wantBody: `{"fields":{"resolution":{"name":"Done"}},"transition":{"id":"31"}}`,
+			wantErr:  "Root Cause: Root Cause is required.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					assert.Equal(t, "transitions.fields", r.URL.Query().Get("expand"))
+					_, _ = w.Write([]byte(`{"transitions": [{"id": "31", "to": {"name": "Done"}, "fields": {"resolution": {"name": "Resolution", "required": true, "allowedValues": [{"name": "Done"}, {"name": "Won't Do"}]}}}]}`))
+					return
+				}
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			c := &Client{config: &config.Config{Server: srv.URL}, fieldNames: map[string]string{"customfield_10050": "Root Cause"}}
+			err := c.updateIssueStatus("PRJ-1", "Done", tt.resolution)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.JSONEq(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestFetchIssuesWithJQLPagination(t *testing.T) {
+	t.Parallel()
+
+	const total = 5
+	const pageSize = 2
+	issuesJSON := func(from, to int) string {
+		var issues []string
+		for i := from; i < min(to, total); i++ {
+			issues = append(issues, fmt.Sprintf(`{"key": "PRJ-%d", "fields": {"summary": "チケット%d", "issuetype": {"name": "Task"}, "status": {"name": "To Do"}, "created": "2025-06-01T19:06:22.513+0900", "updated": "2025-06-01T19:06:22.513+0900"}}`, i+1, i+1))
+		}
+		return "[" + strings.Join(issues, ",") + "]"
+	}
+
+	tests := []struct {
+		name          string
+		supportsJQL   bool
+		wantJQLCalls  int
+		wantOldCalls  int
+		wantJQLTokens []string
+	}{
+		{
+			name:          "nextPageTokenでページングする",
+			supportsJQL:   true,
+			wantJQLCalls:  6,
+			wantJQLTokens: []string{"", "2", "4", "", "2", "4"},
+		},
+		{
+			name:         "新しい検索APIがなければ従来の検索APIでページングする",
+			supportsJQL:  false,
+			wantJQLCalls: 1, // 2回目のフェッチでは新しい検索APIを試さない
+			wantOldCalls: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var jqlTokens []string
+			jqlCalls, oldCalls := 0, 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch r.URL.Path {
+				case "/rest/api/3/search/jql":
+					jqlCalls++
+					if !tt.supportsJQL {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					var req struct {
+						NextPageToken string `json:"nextPageToken"`
+					}
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+					jqlTokens = append(jqlTokens, req.NextPageToken)
+					start := 0
+					if req.NextPageToken != "" {
+						start, _ = strconv.Atoi(req.NextPageToken)
+					}
+					end := start + pageSize
+					next := ""
+					if end < total {
+						next = strconv.Itoa(end)
+					}
+					_, _ = fmt.Fprintf(w, `{"issues": %s, "nextPageToken": %q, "isLast": %t}`, issuesJSON(start, end), next, end >= total)
+				case "/rest/api/3/search":
+					oldCalls++
+					var req struct {
+						StartAt int `json:"startAt"`
+					}
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+					_, _ = fmt.Fprintf(w, `{"maxResults": %d, "total": %d, "issues": %s}`, pageSize, total, issuesJSON(req.StartAt, req.StartAt+pageSize))
+				default:
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+			}))
+			defer srv.Close()
+
+			c := &Client{config: &config.Config{Server: srv.URL}}
+			for range 2 {
+				tickets, err := c.fetchIssuesWithJQL(context.Background(), "project = PRJ")
+				assert.NoError(t, err)
+				var keys []string
+				for _, tkt := range tickets {
+					keys = append(keys, tkt.Key)
+				}
+				assert.ElementsMatch(t, []string{"PRJ-1", "PRJ-2", "PRJ-3", "PRJ-4", "PRJ-5"}, keys)
+			}
+			assert.Equal(t, tt.wantJQLCalls, jqlCalls)
+			assert.Equal(t, tt.wantOldCalls, oldCalls)
+			assert.Equal(t, tt.wantJQLTokens, jqlTokens)
+		})
+	}
+}
+
+func TestFetchIssuesWithJQLCancel(t *testing.T) {
+	t.Parallel()
+
+	// 2ページ目の取得中にキャンセルすると、取得途中の結果を返さずにキャンセルのエラーを返す
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.NextPageToken == "" {
+			_, _ = w.Write([]byte(`{"issues": [{"key": "PRJ-1", "fields": {"summary": "一つ目", "issuetype": {"name": "Task"}, "status": {"name": "To Do"}, "created": "2025-06-01T19:06:22.513+0900", "updated": "2025-06-01T19:06:22.513+0900"}}], "nextPageToken": "next"}`))
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c := &Client{config: &config.Config{Server: srv.URL}}
+	tickets, err := c.fetchIssuesWithJQL(ctx, "project = PRJ")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, tickets)
+}
+
+func TestCountIssues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		supportsCount bool
+	}{
+		{name: "件数のみを返すAPIで数える", supportsCount: true},
+		{name: "件数のみを返すAPIがなければ従来の検索APIで数える", supportsCount: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotMaxResults []int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					JQL        string `json:"jql"`
+					MaxResults int    `json:"maxResults"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				assert.Equal(t, "project = PRJ AND statusCategory != Done", body.JQL)
+				switch r.URL.Path {
+				case "/rest/api/3/search/approximate-count":
+					if !tt.supportsCount {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, `{"count": 42}`)
+				case "/rest/api/3/search":
+					gotMaxResults = append(gotMaxResults, body.MaxResults)
+					fmt.Fprint(w, `{"startAt": 0, "maxResults": 0, "total": 42, "issues": []}`)
+				default:
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			c := &Client{config: &config.Config{Server: srv.URL}}
+			n, err := c.CountIssues(context.Background(), "project = PRJ AND statusCategory != Done")
+			assert.NoError(t, err)
+			assert.Equal(t, 42, n)
+			if !tt.supportsCount {
+				assert.Equal(t, []int{0}, gotMaxResults)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// namesUpdateValue はcomponentsやfixVersionsのように名前で指定するフィールドの更新に使う値を返します
+// 空の配列を指定するとフィールドの値が外れます。
+func namesUpdateValue(names []string) []map[string]string {
+	value := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		value = append(value, map[string]string{"name": name})
+	}
+	return value
+}
+
+// validateComponents はコンポーネント名がプロジェクトに存在するかを検証します
+// tkt.ymlにコンポーネントの一覧がない場合は検証しません。
+func validateComponents(names []string, known []string) error {
+	if len(known) == 0 {
+		return nil
+	}
+	var unknown []string
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("コンポーネントが見つかりません: %s (指定できるコンポーネント: %s)", strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
@@ -0,0 +1,48 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamesUpdateValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []map[string]string{}, namesUpdateValue(nil))
+	assert.Equal(t, []map[string]string{{"name": "Backend"}, {"name": "API"}}, namesUpdateValue([]string{"Backend", "API"}))
+}
+
+func TestValidateComponents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		names   []string
+		known   []string
+		wantErr string
+	}{
+		{name: "一覧がない場合は検証しない", names: []string{"Unknown"}, known: nil},
+		{name: "すべて存在する", names: []string{"Backend"}, known: []string{"Backend", "Frontend"}},
+		{name: "コンポーネントなし", names: nil, known: []string{"Backend"}},
+		{
+			name:    "存在しないコンポーネント",
+			names:   []string{"Backend", "Infra"},
+			known:   []string{"Backend", "Frontend"},
+			wantErr: "コンポーネントが見つかりません: Infra (指定できるコンポーネント: Backend, Frontend)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateComponents(tt.names, tt.known)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
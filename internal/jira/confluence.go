@@ -0,0 +1,120 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// confluenceURLPattern は本文中のConfluenceのページのURLです
+var confluenceURLPattern = regexp.MustCompile(`https?://[^\s<>()\[\]"'|]+/wiki/[^\s<>()\[\]"'|]+`)
+
+// confluencePageIDPattern はURLのパスに含まれるページIDです (例: /wiki/spaces/DEV/pages/123456/Title)
+var confluencePageIDPattern = regexp.MustCompile(`/pages/(\d+)`)
+
+// extractConfluenceURLs は本文からConfluenceのURLを出現順に重複なく取り出します
+func extractConfluenceURLs(body string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range confluenceURLPattern.FindAllString(body, -1) {
+		u = strings.TrimRight(u, ".,;:")
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// confluencePageID はConfluenceのURLからページIDを取り出します
+// JIRAと同じサーバーのURLでない場合は、同じ認証情報で取得できないため空文字を返します。
+func confluencePageID(rawURL, server string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	s, err := url.Parse(server)
+	if err != nil || !strings.EqualFold(u.Host, s.Host) {
+		return ""
+	}
+	if id := u.Query().Get("pageId"); id != "" {
+		return id
+	}
+	if m := confluencePageIDPattern.FindStringSubmatch(u.Path); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// addReferences はconfluence_linksが有効な場合に、本文のConfluenceのリンクをreferencesに記録します
+// タイトルを取得できなかったページはURLのみを記録します。
+func (c *Client) addReferences(tickets []*ticket.Ticket) {
+	if !c.config.ConfluenceLinks {
+		return
+	}
+	p := pool.New().WithMaxGoroutines(c.config.RequestConcurrency())
+	for _, t := range tickets {
+		urls := extractConfluenceURLs(t.Body)
+		if len(urls) == 0 {
+			continue
+		}
+		p.Go(func() {
+			refs := make([]ticket.Reference, 0, len(urls))
+			for _, u := range urls {
+				refs = append(refs, ticket.Reference{Title: c.confluenceTitle(u), URL: u})
+			}
+			t.References = refs
+		})
+	}
+	p.Wait()
+}
+
+// confluenceTitle はConfluenceのページのタイトルを返します (取得できない場合は空文字)
+// 同じURLは一度だけ取得します。
+func (c *Client) confluenceTitle(rawURL string) string {
+	c.confluenceMu.Lock()
+	title, ok := c.confluenceTitles[rawURL]
+	c.confluenceMu.Unlock()
+	if ok {
+		return title
+	}
+
+	if id := confluencePageID(rawURL, c.config.Server); id != "" {
+		var err error
+		title, err = c.fetchConfluenceTitle(id)
+		if err != nil {
+			verbose.Printf(verbose.API, "Confluenceのページ %s のタイトルを取得できませんでした: %v\n", rawURL, err)
+		}
+	}
+
+	c.confluenceMu.Lock()
+	if c.confluenceTitles == nil {
+		c.confluenceTitles = make(map[string]string)
+	}
+	c.confluenceTitles[rawURL] = title
+	c.confluenceMu.Unlock()
+	return title
+}
+
+// fetchConfluenceTitle はConfluence REST APIでページのタイトルを取得します
+func (c *Client) fetchConfluenceTitle(pageID string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/wiki/rest/api/content/%s", strings.TrimRight(c.config.Server, "/"), pageID), nil)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	var page struct {
+		Title string `json:"title"`
+	}
+	if err := c.getJSON(req, &page); err != nil {
+		return "", err
+	}
+	return page.Title, nil
+}
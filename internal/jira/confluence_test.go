@@ -0,0 +1,77 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractConfluenceURLs(t *testing.T) {
+	t.Parallel()
+
+	body := `設計は [設計書](https://example.atlassian.net/wiki/spaces/DEV/pages/123/Design) を参照。
+旧ページ: https://example.atlassian.net/wiki/pages/viewpage.action?pageId=456.
+再掲: https://example.atlassian.net/wiki/spaces/DEV/pages/123/Design
+JIRA: https://example.atlassian.net/browse/PRJ-1`
+
+	assert.Equal(t, []string{
+		"https://example.atlassian.net/wiki/spaces/DEV/pages/123/Design",
+		"https://example.atlassian.net/wiki/pages/viewpage.action?pageId=456",
+	}, extractConfluenceURLs(body))
+}
+
+func TestConfluencePageID(t *testing.T) {
+	t.Parallel()
+
+	const server = "https://example.atlassian.net"
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "スペースのページ", url: "https://example.atlassian.net/wiki/spaces/DEV/pages/123/Design", want: "123"},
+		{name: "pageIdのクエリ", url: "https://example.atlassian.net/wiki/pages/viewpage.action?pageId=456", want: "456"},
+		{name: "短縮リンク", url: "https://example.atlassian.net/wiki/x/AbCd", want: ""},
+		{name: "別のサーバー", url: "https://other.atlassian.net/wiki/spaces/DEV/pages/123", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, confluencePageID(tt.url, server))
+		})
+	}
+}
+
+func TestAddReferences(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wiki/rest/api/content/123" {
+			_, _ = w.Write([]byte(`{"id": "123", "title": "設計書"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Server: srv.URL, ConfluenceLinks: true}
+	c := &Client{config: cfg}
+	tkt := &ticket.Ticket{Key: "PRJ-1", Body: srv.URL + "/wiki/spaces/DEV/pages/123/Design\n" + srv.URL + "/wiki/spaces/DEV/pages/999/Gone\n"}
+	c.addReferences([]*ticket.Ticket{tkt})
+
+	// 取得できなかったページはURLのみ
+	assert.Equal(t, []ticket.Reference{
+		{Title: "設計書", URL: srv.URL + "/wiki/spaces/DEV/pages/123/Design"},
+		{URL: srv.URL + "/wiki/spaces/DEV/pages/999/Gone"},
+	}, tkt.References)
+
+	// 無効な場合は何もしない
+	disabled := &Client{config: &config.Config{Server: srv.URL}}
+	other := &ticket.Ticket{Key: "PRJ-2", Body: tkt.Body}
+	disabled.addReferences([]*ticket.Ticket{other})
+	assert.Nil(t, other.References)
+}
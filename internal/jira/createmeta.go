@@ -0,0 +1,218 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// createMetaCacheFile はIssue Typeごとの作成時の必須項目を保存するキャッシュディレクトリ内のファイル名です
+const createMetaCacheFile = "createmeta.json"
+
+// CreateMetaCacheTTL は作成時の必須項目のキャッシュの有効期間です
+const CreateMetaCacheTTL = 24 * time.Hour
+
+// createMetaCacheMu はプロセス内での createmeta.json の読み書きを直列にします
+var createMetaCacheMu sync.Mutex
+
+// createFieldMeta はcreatemeta APIが返す作成画面の項目です
+type createFieldMeta struct {
+	FieldID         string `json:"fieldId"`
+	Name            string `json:"name"`
+	Required        bool   `json:"required"`
+	HasDefaultValue bool   `json:"hasDefaultValue"`
+}
+
+// createMetaEntry はプロジェクトとIssue Typeごとのキャッシュです
+type createMetaEntry struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Fields    []createFieldMeta `json:"fields"`
+}
+
+// RequiredField はチケットの作成時に必須の項目です
+type RequiredField struct {
+	// ID はJIRAのフィールドID (例: priority, customfield_10011) です
+	ID   string
+	Name string
+	// FrontmatterKey は対応するフロントマターのキーです (本文の場合は body)
+	// tktから設定できない項目の場合は空です。
+	FrontmatterKey string
+}
+
+// Label はメッセージに表示する項目の名前を返します (例: Priority (priority))
+func (f RequiredField) Label() string {
+	key := f.FrontmatterKey
+	if key == "" {
+		key = f.ID
+	}
+	return fmt.Sprintf("%s (%s)", f.Name, key)
+}
+
+// implicitCreateFields はtktが作成時に必ず送信する、またはJIRAが設定する項目です
+var implicitCreateFields = map[string]bool{
+	"summary": true, "issuetype": true, "project": true, "reporter": true,
+}
+
+// standardFieldKeys はJIRAの標準フィールドのIDとフロントマターのキーの対応です
+var standardFieldKeys = map[string]string{
+	"description":  "body",
+	"priority":     "priority",
+	"labels":       "labels",
+	"components":   "components",
+	"fixVersions":  "fix_versions",
+	"assignee":     "assignee",
+	"environment":  "environment",
+	"timetracking": "original_estimate",
+	"parent":       "parentKey",
+}
+
+// RequiredFields はIssue Typeのチケットを作成するときに入力が必要な項目を返します
+// JIRAが既定値を設定する項目とtktが必ず送信する項目は含めません。
+// 結果はプロジェクトとIssue Typeごとに cacheDir の createmeta.json に1日保存します (cacheDir が空の場合は保存しません)。
+func (c *Client) RequiredFields(ctx context.Context, cacheDir, issueType string) ([]RequiredField, error) {
+	typeID, err := issueTypeID(c.config, &ticket.Ticket{Type: issueType})
+	if err != nil {
+		return nil, err
+	}
+	fields, err := c.cachedCreateFields(ctx, cacheDir, typeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var required []RequiredField
+	for _, f := range fields {
+		if !f.Required || f.HasDefaultValue || implicitCreateFields[f.FieldID] {
+			continue
+		}
+		required = append(required, RequiredField{ID: f.FieldID, Name: f.Name, FrontmatterKey: c.frontmatterKeyForField(f.FieldID)})
+	}
+	return required, nil
+}
+
+// frontmatterKeyForField はJIRAのフィールドIDに対応するフロントマターのキーを返します (ない場合は空)
+func (c *Client) frontmatterKeyForField(id string) string {
+	if key, ok := standardFieldKeys[id]; ok {
+		return key
+	}
+	switch id {
+	case "":
+		return ""
+	case c.sprintFieldID:
+		return "sprint"
+	case c.flaggedFieldID:
+		return "flagged"
+	case c.teamFieldID:
+		return "team"
+	case c.config.Epic.Link:
+		return "parentKey"
+	}
+	for _, m := range c.customFields {
+		if m.ID == id {
+			return m.FrontmatterKey
+		}
+	}
+	return ""
+}
+
+// cachedCreateFields はIssue Typeの作成画面の項目を、有効期間内であればキャッシュから返します
+func (c *Client) cachedCreateFields(ctx context.Context, cacheDir, typeID string) ([]createFieldMeta, error) {
+	createMetaCacheMu.Lock()
+	defer createMetaCacheMu.Unlock()
+
+	cacheKey := c.config.Project.Key + "/" + typeID
+	entries := readCreateMetaCache(cacheDir)
+	if entry, ok := entries[cacheKey]; ok && time.Since(entry.FetchedAt) < CreateMetaCacheTTL {
+		return entry.Fields, nil
+	}
+
+	fields, err := c.fetchCreateFields(ctx, typeID)
+	if err != nil {
+		return nil, err
+	}
+	if cacheDir == "" {
+		return fields, nil
+	}
+	if entries == nil {
+		entries = make(map[string]createMetaEntry)
+	}
+	entries[cacheKey] = createMetaEntry{FetchedAt: time.Now(), Fields: fields}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("必須項目のキャッシュの作成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, createMetaCacheFile), data, 0644); err != nil {
+		verbose.Printf(verbose.Cache, "警告: 必須項目のキャッシュの保存に失敗しました: %v\n", err)
+	}
+	return fields, nil
+}
+
+// readCreateMetaCache は createmeta.json を読み込みます (ない場合や壊れている場合はnil)
+func readCreateMetaCache(cacheDir string) map[string]createMetaEntry {
+	if cacheDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(cacheDir, createMetaCacheFile))
+	if err != nil {
+		return nil
+	}
+	var entries map[string]createMetaEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		verbose.Printf(verbose.Cache, "必須項目のキャッシュを読み込めないため取得し直します: %v\n", err)
+		return nil
+	}
+	return entries
+}
+
+// fetchCreateFields はcreatemeta APIからIssue Typeの作成画面の項目を取得します
+func (c *Client) fetchCreateFields(ctx context.Context, typeID string) ([]createFieldMeta, error) {
+	var fields []createFieldMeta
+	for startAt := 0; ; {
+		endpoint := fmt.Sprintf("%s/rest/api/3/issue/createmeta/%s/issuetypes/%s?startAt=%d&maxResults=50",
+			c.config.Server, url.PathEscape(c.config.Project.Key), url.PathEscape(typeID), startAt)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, err := c.doRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("レスポンスの読み取りに失敗しました: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("作成画面の項目の取得に失敗しました (status: %d): %s", resp.StatusCode, string(body))
+		}
+
+		// 返す配列のキーはJIRAのバージョンによって fields または values
+		var page struct {
+			Fields []createFieldMeta `json:"fields"`
+			Values []createFieldMeta `json:"values"`
+			Total  int               `json:"total"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("作成画面の項目の解析に失敗しました: %v", err)
+		}
+		items := append(page.Fields, page.Values...)
+		fields = append(fields, items...)
+		startAt += len(items)
+		if len(items) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	verbose.Printf(verbose.API, "Issue Type %s の作成画面の項目: %d 件\n", typeID, len(fields))
+	return fields, nil
+}
@@ -0,0 +1,56 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/rest/api/3/issue/createmeta/PRJ/issuetypes/10004", r.URL.Path)
+		_, _ = w.Write([]byte(`{"startAt": 0, "maxResults": 50, "total": 7, "fields": [
+			{"fieldId": "summary", "name": "Summary", "required": true},
+			{"fieldId": "issuetype", "name": "Issue Type", "required": true},
+			{"fieldId": "reporter", "name": "Reporter", "required": true, "hasDefaultValue": true},
+			{"fieldId": "priority", "name": "Priority", "required": true},
+			{"fieldId": "labels", "name": "Labels", "required": false},
+			{"fieldId": "customfield_10016", "name": "Story Points", "required": true},
+			{"fieldId": "customfield_10011", "name": "Epic Name", "required": true}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Server: srv.URL}
+	cfg.Project.Key = "PRJ"
+	cfg.Issue.Types = []config.IssueType{{ID: "10004", Name: "Bug"}}
+	c := &Client{config: cfg, customFields: []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}}}
+
+	cacheDir := t.TempDir()
+	want := []RequiredField{
+		{ID: "priority", Name: "Priority", FrontmatterKey: "priority"},
+		{ID: "customfield_10016", Name: "Story Points", FrontmatterKey: "story_points"},
+		{ID: "customfield_10011", Name: "Epic Name"},
+	}
+	got, err := c.RequiredFields(context.Background(), cacheDir, "bug")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, "Epic Name (customfield_10011)", got[2].Label())
+
+	// 2回目はキャッシュを使う
+	got, err = c.RequiredFields(context.Background(), cacheDir, "Bug")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, 1, requests)
+
+	_, err = c.RequiredFields(context.Background(), cacheDir, "Story")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package jira
+
+import (
+	"strings"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// customFieldMapping はJIRAのカスタムフィールドIDとフロントマターのキーの対応です
+type customFieldMapping struct {
+	ID             string
+	FrontmatterKey string
+}
+
+// resolveCustomFields は設定ファイルのカスタムフィールドをフィールド定義と照合してIDを解決します
+// keyが指定されていればそれを使い、なければ名前 (大文字小文字は区別しない) で探します。
+// 数値型以外のフィールドと見つからないフィールドは無視します。
+func resolveCustomFields(custom []config.CustomField, fields []fieldDefinition) []customFieldMapping {
+	var mappings []customFieldMapping
+	for _, cf := range custom {
+		if !cf.IsNumber() {
+			verbose.Printf(verbose.API, "カスタムフィールド %s は数値型ではないためスキップします (datatype: %s)\n", cf.Name, cf.Schema.Datatype)
+			continue
+		}
+		id := cf.Key
+		if id == "" {
+			for _, field := range fields {
+				if field.Custom && strings.EqualFold(field.Name, cf.Name) {
+					id = field.ID
+					break
+				}
+			}
+		}
+		if id == "" {
+			verbose.Printf(verbose.API, "カスタムフィールド %s が見つからないためスキップします\n", cf.Name)
+			continue
+		}
+		mappings = append(mappings, customFieldMapping{ID: id, FrontmatterKey: cf.FrontmatterKey()})
+		verbose.Printf(verbose.API, "カスタムフィールドを対応付けました: %s → %s\n", id, cf.FrontmatterKey())
+	}
+	return mappings
+}
+
+// parseCustomFields はIssueのカスタムフィールドから数値の値を取得します
+// レスポンスにフィールドが含まれない場合はキー自体を含めず、nullの場合はnilを設定します。
+func parseCustomFields(customFields map[string]interface{}, mappings []customFieldMapping) map[string]*float64 {
+	var result map[string]*float64
+	for _, m := range mappings {
+		value, ok := customFields[m.ID]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]*float64)
+		}
+		if f, ok := value.(float64); ok {
+			result[m.FrontmatterKey] = &f
+		} else {
+			result[m.FrontmatterKey] = nil
+		}
+	}
+	return result
+}
+
+// addCustomFieldsToUpdate はフロントマターにあるカスタムフィールドを更新フィールドに追加します
+// nullのフィールドはJIRA上の値を消去します。
+func (c *Client) addCustomFieldsToUpdate(fields map[string]interface{}, t ticket.Ticket) {
+	for _, m := range c.customFields {
+		value, ok := t.CustomFields[m.FrontmatterKey]
+		if !ok {
+			continue
+		}
+		if value == nil {
+			fields[m.ID] = nil
+			continue
+		}
+		fields[m.ID] = *value
+	}
+}
@@ -0,0 +1,111 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCustomFields(t *testing.T) {
+	t.Parallel()
+
+	fields := []fieldDefinition{
+		{ID: "summary", Name: "Summary"},
+		{ID: "customfield_10016", Name: "Story Points", Custom: true},
+		{ID: "customfield_10030", Name: "Business Value", Custom: true},
+	}
+
+	var textField config.CustomField
+	textField.Name = "Business Value"
+	textField.Schema.Datatype = "string"
+
+	tests := []struct {
+		name   string
+		custom []config.CustomField
+		want   []customFieldMapping
+	}{
+		{
+			name:   "名前で解決",
+			custom: []config.CustomField{{Name: "story points"}},
+			want:   []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}},
+		},
+		{
+			name:   "キーを優先",
+			custom: []config.CustomField{{Name: "Story Points", Key: "customfield_10099", Frontmatter: "sp"}},
+			want:   []customFieldMapping{{ID: "customfield_10099", FrontmatterKey: "sp"}},
+		},
+		{
+			name:   "見つからないフィールドは無視",
+			custom: []config.CustomField{{Name: "Unknown"}},
+			want:   nil,
+		},
+		{
+			name:   "数値型以外は無視",
+			custom: []config.CustomField{textField},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, resolveCustomFields(tt.custom, fields))
+		})
+	}
+}
+
+func TestParseCustomFields(t *testing.T) {
+	t.Parallel()
+
+	mappings := []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}}
+
+	tests := []struct {
+		name   string
+		fields string
+		want   map[string]*float64
+	}{
+		{name: "レスポンスにフィールドが含まれない", fields: `{}`, want: nil},
+		{name: "未設定", fields: `{"customfield_10016": null}`, want: map[string]*float64{"story_points": nil}},
+		{name: "整数", fields: `{"customfield_10016": 3}`, want: map[string]*float64{"story_points": ptr(3.0)}},
+		{name: "小数", fields: `{"customfield_10016": 0.5}`, want: map[string]*float64{"story_points": ptr(0.5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var customFields map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.fields), &customFields))
+			assert.Equal(t, tt.want, parseCustomFields(customFields, mappings))
+		})
+	}
+}
+
+func TestAddCustomFieldsToUpdate(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{customFields: []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}}}
+
+	tests := []struct {
+		name   string
+		custom map[string]*float64
+		want   map[string]interface{}
+	}{
+		{name: "フロントマターにない", custom: nil, want: map[string]interface{}{}},
+		{name: "nullは消去", custom: map[string]*float64{"story_points": nil}, want: map[string]interface{}{"customfield_10016": nil}},
+		{name: "数値を設定", custom: map[string]*float64{"story_points": ptr(5.0)}, want: map[string]interface{}{"customfield_10016": 5.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields := map[string]interface{}{}
+			c.addCustomFieldsToUpdate(fields, ticket.Ticket{CustomFields: tt.custom})
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
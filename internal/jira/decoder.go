@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// FieldDecoder はJIRAのフィールドを読み取ってチケットに書き込みます
+// 標準のフィールドは convert で変換し、プロジェクトごとにIDが異なるフィールド (スプリントやカスタムフィールドなど) をデコーダーで変換します。
+type FieldDecoder func(fields *IssueFields, t *ticket.Ticket)
+
+type namedFieldDecoder struct {
+	name   string
+	decode FieldDecoder
+}
+
+// RegisterFieldDecoder はフェッチしたチケットに適用するデコーダーを追加します
+// 組み込みのデコーダーの後に登録順に適用します。チケットの取得を始める前に呼び出してください。
+func (c *Client) RegisterFieldDecoder(name string, decode FieldDecoder) {
+	c.fieldDecoders()
+	c.decoders = append(c.decoders, namedFieldDecoder{name: name, decode: decode})
+}
+
+// fieldDecoders は登録されたデコーダーを返します
+// 組み込みのデコーダーは初回の呼び出しで、NewClientで発見したフィールドIDをもとに登録します。
+func (c *Client) fieldDecoders() []namedFieldDecoder {
+	c.decodersOnce.Do(func() {
+		c.decoders = append(c.builtinFieldDecoders(), c.decoders...)
+	})
+	return c.decoders
+}
+
+// builtinFieldDecoders はスプリント、Flagged、Team、設定ファイルで対応付けたカスタムフィールドのデコーダーを返します
+func (c *Client) builtinFieldDecoders() []namedFieldDecoder {
+	var decoders []namedFieldDecoder
+	if c.sprintFieldID != "" {
+		decoders = append(decoders, namedFieldDecoder{name: "sprint", decode: c.decodeSprint})
+	} else {
+		verbose.Printf(verbose.API, "スプリントフィールドIDが設定されていません\n")
+	}
+	decoders = append(decoders,
+		namedFieldDecoder{name: "flagged", decode: c.decodeFlagged},
+	)
+	// Teamフィールドがないインスタンスではteamを扱わない
+	if c.teamFieldID != "" {
+		decoders = append(decoders, namedFieldDecoder{name: "team", decode: c.decodeTeam})
+	}
+	decoders = append(decoders, namedFieldDecoder{name: "custom", decode: c.decodeCustomFields})
+	return decoders
+}
+
+// decodeSprint はスプリントフィールドの最後のスプリントをチケットのスプリント名にします
+func (c *Client) decodeSprint(fields *IssueFields, t *ticket.Ticket) {
+	verbose.Printf(verbose.API, "スプリントフィールドID: %s でデータ抽出を試行中...\n", c.sprintFieldID)
+	sprintName := c.extractSprintName(fields)
+	if sprintName != "" {
+		verbose.Printf(verbose.API, "スプリント名を発見: %s\n", sprintName)
+	} else {
+		verbose.Printf(verbose.API, "スプリント名が見つかりませんでした\n")
+	}
+	t.SprintName = sprintName
+}
+
+// decodeFlagged はFlaggedフィールドをチケットに設定します
+func (c *Client) decodeFlagged(fields *IssueFields, t *ticket.Ticket) {
+	t.Flagged = parseFlagged(fields.CustomFields, c.flaggedFieldID)
+}
+
+// decodeCustomFields は設定ファイルで対応付けた数値のカスタムフィールド (ストーリーポイントなど) をチケットに設定します
+func (c *Client) decodeCustomFields(fields *IssueFields, t *ticket.Ticket) {
+	t.CustomFields = parseCustomFields(fields.CustomFields, c.customFields)
+}
@@ -0,0 +1,103 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+const decoderFixture = `{"key": "prj-1", "fields": {
+	"summary": "タイトル",
+	"issuetype": {"id": "10001", "name": "Story"},
+	"status": {"name": "To Do", "statusCategory": {"key": "new"}},
+	"created": "2025-06-02T10:00:00.000+0900",
+	"updated": "2025-06-03T10:00:00.000+0900",
+	"customfield_10020": [{"id": 40, "name": "Sprint 40"}, {"id": 41, "name": "Sprint 41"}],
+	"customfield_10021": [{"value": "Impediment"}],
+	"customfield_10016": 3
+}}`
+
+func TestConvertIssueDecoders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		client *Client
+		check  func(t *testing.T, got *ticket.Ticket)
+	}{
+		{
+			name: "すべてのフィールドIDが分かっている",
+			client: &Client{
+				sprintFieldID:  "customfield_10020",
+				flaggedFieldID: "customfield_10021",
+				customFields:   []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}},
+			},
+			check: func(t *testing.T, got *ticket.Ticket) {
+				assert.Equal(t, "Sprint 41", got.SprintName)
+				assert.True(t, got.IsFlagged())
+				if assert.Contains(t, got.CustomFields, "story_points") {
+					assert.Equal(t, 3.0, *got.CustomFields["story_points"])
+				}
+			},
+		},
+		{
+			name:   "フィールドIDが分からない場合は設定しない",
+			client: &Client{},
+			check: func(t *testing.T, got *ticket.Ticket) {
+				assert.Empty(t, got.SprintName)
+				assert.Nil(t, got.Flagged)
+				assert.Nil(t, got.CustomFields)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.client.config = &config.Config{Server: "https://example.atlassian.net"}
+			var issue Issue
+			if !assert.NoError(t, json.Unmarshal([]byte(decoderFixture), &issue)) {
+				return
+			}
+			got, err := tt.client.convertIssue(&issue)
+			if !assert.NoError(t, err) {
+				return
+			}
+			// 標準のフィールドはデコーダーの有無に関わらず同じ
+			assert.Equal(t, "PRJ-1", got.Key)
+			assert.Equal(t, "タイトル", got.Title)
+			assert.Equal(t, "story", got.Type)
+			assert.Equal(t, "To Do", got.Status)
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestRegisterFieldDecoder(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{config: &config.Config{}, sprintFieldID: "customfield_10020"}
+	// 追加のデコーダーは組み込みのデコーダーの後に適用される
+	c.RegisterFieldDecoder("sprint-override", func(fields *IssueFields, t *ticket.Ticket) {
+		t.SprintName = "上書き: " + t.SprintName
+	})
+
+	var names []string
+	for _, d := range c.fieldDecoders() {
+		names = append(names, d.name)
+	}
+	assert.Equal(t, []string{"sprint", "flagged", "custom", "sprint-override"}, names)
+
+	var issue Issue
+	if !assert.NoError(t, json.Unmarshal([]byte(decoderFixture), &issue)) {
+		return
+	}
+	got, err := c.convertIssue(&issue)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "上書き: Sprint 41", got.SprintName)
+}
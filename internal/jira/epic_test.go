@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertEpic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		fields     string
+		epicLink   string
+		wantParent string
+		wantEpic   string
+	}{
+		{
+			name:       "親がエピックの場合はエピックの名前を保存する",
+			fields:     `"parent": {"id": "10100", "key": "PRJ-100", "fields": {"summary": "決済基盤刷新", "issuetype": {"name": "Epic", "hierarchyLevel": 1}}}`,
+			wantParent: "PRJ-100",
+			wantEpic:   "決済基盤刷新",
+		},
+		{
+			name:       "親がエピック以外の場合はエピックの名前を保存しない",
+			fields:     `"parent": {"id": "10101", "key": "PRJ-101", "fields": {"summary": "親のストーリー", "issuetype": {"name": "Story", "hierarchyLevel": 0}}}`,
+			wantParent: "PRJ-101",
+		},
+		{
+			name:       "Epic Linkのカスタムフィールドから親を読み込む",
+			fields:     `"customfield_10014": "PRJ-100"`,
+			epicLink:   "customfield_10014",
+			wantParent: "PRJ-100",
+		},
+		{
+			name:   "Epic Linkを設定していない場合はカスタムフィールドを読まない",
+			fields: `"customfield_10014": "PRJ-100"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var issue Issue
+			data := `{"key": "PRJ-1", "fields": {"summary": "タイトル", "issuetype": {"name": "Task"}, "status": {"name": "To Do"},
+				"created": "2025-06-01T19:06:22.513+0900", "updated": "2025-06-01T19:06:22.513+0900", ` + tt.fields + `}}`
+			assert.NoError(t, json.Unmarshal([]byte(data), &issue))
+
+			cfg := &config.Config{Server: "https://example.atlassian.net"}
+			cfg.Epic.Link = tt.epicLink
+			got, err := convert(&issue, cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantParent, got.ParentKey)
+			assert.Equal(t, tt.wantEpic, got.Epic)
+		})
+	}
+}
+
+func TestParentField(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Issue.Types = []config.IssueType{{ID: "1", Name: "Task"}, {ID: "2", Name: "Sub-task", Subtask: true}, {ID: "3", Name: "Epic"}}
+	assert.Equal(t, "issuetype in (3)", epicTypeJQL(cfg))
+
+	c := &Client{config: cfg}
+	name, value := c.parentField("Task", "PRJ-100")
+	assert.Equal(t, "parent", name)
+	assert.Equal(t, map[string]string{"key": "PRJ-100"}, value)
+
+	// company-managedプロジェクトではエピックをEpic Linkで設定し、サブタスクの親はparentのまま
+	cfg.Epic.Link = "customfield_10014"
+	name, value = c.parentField("Task", "PRJ-100")
+	assert.Equal(t, "customfield_10014", name)
+	assert.Equal(t, "PRJ-100", value)
+	name, _ = c.parentField("Sub-task", "PRJ-1")
+	assert.Equal(t, "parent", name)
+}
@@ -0,0 +1,109 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError はJIRAがフィールドごとに返したエラーです
+type FieldError struct {
+	// Field はフィールドID (例: customfield_10020) です
+	Field string
+	// Name はフィールドの表示名 (例: Sprint) です。分からない場合はフィールドIDです。
+	Name    string
+	Message string
+}
+
+// requiredCreateFields は作成時に省略できないフィールドです
+var requiredCreateFields = map[string]bool{"project": true, "issuetype": true, "summary": true}
+
+// parseFieldErrors はエラーレスポンスのフィールドごとのエラー (errors) を返します
+func parseFieldErrors(body string) map[string]string {
+	var resp struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil
+	}
+	return resp.Errors
+}
+
+// fieldErrors はエラーレスポンスのフィールドごとのエラーを、フィールドの表示名付きでフィールドID順に返します
+func (c *Client) fieldErrors(body string) []FieldError {
+	errs := parseFieldErrors(body)
+	result := make([]FieldError, 0, len(errs))
+	for field, message := range errs {
+		name := c.fieldNames[field]
+		if name == "" {
+			name = field
+		}
+		result = append(result, FieldError{Field: field, Name: name, Message: message})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Field < result[j].Field })
+	return result
+}
+
+// cannotBeSet はプロジェクトの作成画面にないためにフィールドを設定できないというエラーかどうかを返します
+// (例: "Field 'Sprint' cannot be set. It is not on the appropriate screen, or unknown.")
+func (e FieldError) cannotBeSet() bool {
+	return strings.Contains(e.Message, "cannot be set") || strings.Contains(e.Message, "not on the appropriate screen")
+}
+
+// deferrable は作成時に省略し、作成後に設定し直せるエラーかどうかを返します
+// 値が不正な場合などは作成後に設定しても失敗するので、作成画面にないために設定できない場合のみです。
+func (e FieldError) deferrable() bool {
+	return !requiredCreateFields[e.Field] && e.cannotBeSet()
+}
+
+// Remediation はエラーへの対処方法を1行で返します
+func (e FieldError) Remediation() string {
+	switch {
+	case e.deferrable():
+		return fmt.Sprintf("%s はこのプロジェクトでは作成時に設定できません — 作成後に設定します", e.Name)
+	case e.cannotBeSet():
+		return fmt.Sprintf("%s はこのプロジェクトでは設定できません — JIRAの作成画面にフィールドを追加するよう管理者に依頼してください", e.Name)
+	case strings.Contains(strings.ToLower(e.Message), "required"):
+		return fmt.Sprintf("%s は必須です — フロントマターに値を指定してください (%s)", e.Name, e.Message)
+	default:
+		return fmt.Sprintf("%s: %s", e.Name, e.Message)
+	}
+}
+
+// withFieldErrorHints はフィールドごとのエラーの対処方法をエラーに追加します
+func withFieldErrorHints(err error, fieldErrs []FieldError) error {
+	if len(fieldErrs) == 0 {
+		return err
+	}
+	lines := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		lines = append(lines, "  - "+fe.Remediation())
+	}
+	return fmt.Errorf("%w\n%s", err, strings.Join(lines, "\n"))
+}
+
+// fieldNameMap はフィールド定義からフィールドIDと表示名の対応を作ります
+func fieldNameMap(fields []fieldDefinition) map[string]string {
+	names := make(map[string]string, len(fields))
+	for _, f := range fields {
+		names[f.ID] = f.Name
+	}
+	return names
+}
+
+// addNotice はコマンドの最後に表示するお知らせを追加します (並列に呼ばれても安全です)
+func (c *Client) addNotice(format string, args ...any) {
+	c.noticesMu.Lock()
+	defer c.noticesMu.Unlock()
+	c.notices = append(c.notices, fmt.Sprintf(format, args...))
+}
+
+// TakeNotices はこれまでのお知らせ (作成時に省略したフィールドなど) を返し、空にします
+func (c *Client) TakeNotices() []string {
+	c.noticesMu.Lock()
+	defer c.noticesMu.Unlock()
+	notices := c.notices
+	c.notices = nil
+	return notices
+}
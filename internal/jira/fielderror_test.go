@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{fieldNames: map[string]string{"customfield_10020": "Sprint", "priority": "Priority"}}
+
+	tests := []struct {
+		name        string
+		body        string
+		want        []string
+		deferrable  bool
+		wantDropped []string
+	}{
+		{
+			name:        "作成画面にないスプリント",
+			body:        `{"errorMessages":[],"errors":{"customfield_10020":"Field 'Sprint' cannot be set. It is not on the appropriate screen, or unknown."}}`,
+			want:        []string{"Sprint はこのプロジェクトでは作成時に設定できません — 作成後に設定します"},
+			deferrable:  true,
+			wantDropped: []string{"customfield_10020"},
+		},
+		{
+			name:       "値が不正なフィールドが含まれる",
+			body:       `{"errorMessages":[],"errors":{"customfield_10020":"Field 'Sprint' cannot be set.","priority":"Priority name 'Urgent' is not valid"}}`,
+			want:       []string{"Sprint はこのプロジェクトでは作成時に設定できません — 作成後に設定します", "Priority: Priority name 'Urgent' is not valid"},
+			deferrable: false,
+		},
+		{
+			name:       "必須フィールドは省略できない",
+			body:       `{"errorMessages":[],"errors":{"summary":"Field 'summary' cannot be set. It is not on the appropriate screen, or unknown."}}`,
+			want:       []string{"summary はこのプロジェクトでは設定できません — JIRAの作成画面にフィールドを追加するよう管理者に依頼してください"},
+			deferrable: false,
+		},
+		{
+			name:       "名前の分からない必須フィールド",
+			body:       `{"errorMessages":[],"errors":{"customfield_10099":"Team is required."}}`,
+			want:       []string{"customfield_10099 は必須です — フロントマターに値を指定してください (Team is required.)"},
+			deferrable: false,
+		},
+		{
+			name:       "JSONではない",
+			body:       `Internal Server Error`,
+			want:       []string{},
+			deferrable: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fieldErrs := c.fieldErrors(tt.body)
+			got := make([]string, 0, len(fieldErrs))
+			for _, fe := range fieldErrs {
+				got = append(got, fe.Remediation())
+			}
+			assert.Equal(t, tt.want, got)
+
+			fields := map[string]interface{}{
+				"summary":           "タイトル",
+				"priority":          map[string]string{"name": "Urgent"},
+				"customfield_10020": 42,
+			}
+			deferred := deferrableFields(fields, fieldErrs)
+			assert.Equal(t, tt.deferrable, deferred != nil)
+			for _, field := range tt.wantDropped {
+				assert.Contains(t, deferred, field)
+				assert.NotContains(t, fields, field)
+			}
+			if !tt.deferrable {
+				assert.Len(t, fields, 3)
+			}
+		})
+	}
+}
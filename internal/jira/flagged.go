@@ -0,0 +1,79 @@
+package jira
+
+import (
+	"strings"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+const (
+	// flaggedFieldSchema はFlaggedフィールドのスキーマです
+	flaggedFieldSchema = "com.atlassian.jira.plugin.system.customfieldtypes:multicheckboxes"
+	// impedimentOption はFlaggedフィールドの唯一の選択肢です
+	impedimentOption = "Impediment"
+)
+
+// findFlaggedFieldID はフィールド定義からFlaggedフィールドのIDを探します
+// 見つからない場合は空文字を返します。
+func findFlaggedFieldID(fields []fieldDefinition) string {
+	for _, field := range fields {
+		if field.Custom && field.Schema.Custom == flaggedFieldSchema && strings.EqualFold(field.Name, "flagged") {
+			return field.ID
+		}
+	}
+	return ""
+}
+
+// discoverFlaggedField はフィールド定義からFlaggedフィールドを動的に発見します
+// 見つからない場合はflaggedの取得・更新を行いません。
+func (c *Client) discoverFlaggedField(fields []fieldDefinition) {
+	c.flaggedFieldID = findFlaggedFieldID(fields)
+	if c.flaggedFieldID == "" {
+		verbose.Printf(verbose.API, "Flaggedフィールドが見つからないため、flaggedは無効になります\n")
+		return
+	}
+	verbose.Printf(verbose.API, "Flaggedフィールドを発見しました: %s\n", c.flaggedFieldID)
+}
+
+// parseFlagged はIssueのカスタムフィールドからflaggedの値を取得します
+// Flaggedフィールドがないインスタンスや、レスポンスにフィールドが含まれない課題ではnilを返します。
+func parseFlagged(customFields map[string]interface{}, fieldID string) *bool {
+	if fieldID == "" {
+		return nil
+	}
+	value, ok := customFields[fieldID]
+	if !ok {
+		return nil
+	}
+	flagged := false
+	if options, ok := value.([]interface{}); ok {
+		for _, option := range options {
+			if m, ok := option.(map[string]interface{}); ok && m["value"] == impedimentOption {
+				flagged = true
+			}
+		}
+	}
+	return &flagged
+}
+
+// flaggedUpdateValue はFlaggedフィールドの更新に使う値を返します
+// 空の配列を指定するとフラグが外れます。
+func flaggedUpdateValue(flagged bool) []map[string]string {
+	if !flagged {
+		return []map[string]string{}
+	}
+	return []map[string]string{{"value": impedimentOption}}
+}
+
+// addFlaggedFieldToUpdate はflaggedが指定されている場合に更新フィールドに追加します
+func (c *Client) addFlaggedFieldToUpdate(fields map[string]interface{}, t ticket.Ticket) {
+	if t.Flagged == nil {
+		return
+	}
+	if c.flaggedFieldID == "" {
+		verbose.Printf(verbose.API, "Flaggedフィールドが見つからないため、flaggedの更新をスキップします\n")
+		return
+	}
+	fields[c.flaggedFieldID] = flaggedUpdateValue(*t.Flagged)
+}
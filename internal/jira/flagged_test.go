@@ -0,0 +1,119 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlagged(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fieldID string
+		fields  string
+		want    *bool
+	}{
+		{
+			name:    "インスタンスにFlaggedフィールドがない",
+			fieldID: "",
+			fields:  `{"customfield_10021": [{"value": "Impediment"}]}`,
+			want:    nil,
+		},
+		{
+			name:    "レスポンスにフィールドが含まれない",
+			fieldID: "customfield_10021",
+			fields:  `{}`,
+			want:    nil,
+		},
+		{
+			name:    "フラグなし",
+			fieldID: "customfield_10021",
+			fields:  `{"customfield_10021": null}`,
+			want:    ptr(false),
+		},
+		{
+			name:    "フラグあり",
+			fieldID: "customfield_10021",
+			fields:  `{"customfield_10021": [{"self": "https://example.atlassian.net/rest/api/2/customFieldOption/10019", "value": "Impediment", "id": "10019"}]}`,
+			want:    ptr(true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var customFields map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.fields), &customFields))
+			assert.Equal(t, tt.want, parseFlagged(customFields, tt.fieldID))
+		})
+	}
+}
+
+func TestAddFlaggedFieldToUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fieldID string
+		flagged *bool
+		want    map[string]interface{}
+	}{
+		{
+			name:    "フラグを付ける",
+			fieldID: "customfield_10021",
+			flagged: ptr(true),
+			want:    map[string]interface{}{"customfield_10021": []map[string]string{{"value": "Impediment"}}},
+		},
+		{
+			name:    "フラグを外す",
+			fieldID: "customfield_10021",
+			flagged: ptr(false),
+			want:    map[string]interface{}{"customfield_10021": []map[string]string{}},
+		},
+		{
+			name:    "flaggedが未指定",
+			fieldID: "customfield_10021",
+			flagged: nil,
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "インスタンスにFlaggedフィールドがない",
+			fieldID: "",
+			flagged: ptr(true),
+			want:    map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &Client{flaggedFieldID: tt.fieldID}
+			fields := map[string]interface{}{}
+			c.addFlaggedFieldToUpdate(fields, ticket.Ticket{Key: "PRJ-1", Flagged: tt.flagged})
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestFindFlaggedFieldID(t *testing.T) {
+	t.Parallel()
+
+	var fields []fieldDefinition
+	assert.NoError(t, json.Unmarshal([]byte(`[
+		{"id": "summary", "name": "Summary", "custom": false, "schema": {"type": "string"}},
+		{"id": "customfield_10020", "name": "Sprint", "custom": true, "schema": {"type": "array", "items": "json", "custom": "com.pyxis.greenhopper.jira:gh-sprint"}},
+		{"id": "customfield_10021", "name": "Flagged", "custom": true, "schema": {"type": "array", "items": "option", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:multicheckboxes"}}
+	]`), &fields))
+	assert.Equal(t, "customfield_10021", findFlaggedFieldID(fields))
+	assert.Equal(t, "", findFlaggedFieldID(fields[:2]))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
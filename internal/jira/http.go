@@ -0,0 +1,385 @@
+package jira
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// doRequest はJIRA APIへのHTTPリクエストを送信します
+// 認証情報の付与とAPI呼び出し回数の記録を行う共通処理です。
+// 冪等なリクエストは 429/502/503 のときに指数バックオフ (Retry-Afterがあればそれに従う) で再試行し、
+// 冪等でないリクエストは接続に失敗したときのみ再試行します。
+// 認証に失敗した場合は、APIごとのエラーの代わりに AuthError を返します。
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	req.SetBasicAuth(c.config.Login, c.apiToken)
+	traceRequest(req)
+
+	resp, err := c.sendWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	return checkAuth(resp, c.config.Server, c.config.Login)
+}
+
+// traceRequest は -vv のときにリクエストの行とヘッダーを表示します (Authorization ヘッダーの値は伏せます)
+func traceRequest(req *http.Request) {
+	verbose.Tracef(verbose.API, "%s %s\n%s", req.Method, req.URL, verbose.Headers(req.Header))
+}
+
+// sendWithRetry はリクエストを送信し、一時的な失敗であれば再試行します
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	client := c.client()
+	maxAttempts := c.config.RetryMaxAttempts()
+	idempotent := isIdempotentRequest(req)
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= maxAttempts || !canReplayBody(req) {
+			return resp, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if !isRetryableError(err, idempotent) {
+				return nil, err
+			}
+			wait = c.backoff(attempt)
+			verbose.Printf(verbose.API, "%s %s の送信に失敗したため %s後に再試行します (%d/%d): %v\n", req.Method, req.URL.Path, wait, attempt, maxAttempts, err)
+		case idempotent && isRetryableStatus(resp.StatusCode):
+			wait = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if wait <= 0 {
+				wait = c.backoff(attempt)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			verbose.Printf(verbose.API, "%s %s が %s を返したため %s後に再試行します (%d/%d)\n", req.Method, req.URL.Path, resp.Status, wait, attempt, maxAttempts)
+		default:
+			return resp, nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// client はリクエストの送信に使う http.Client を返します
+// NewClient を通さずに作ったClient (テストなど) では共有のデフォルトのクライアントを使います。
+func (c *Client) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return DefaultHTTPClient()
+}
+
+var (
+	defaultHTTPClientOnce sync.Once
+	defaultHTTPClient     *http.Client
+)
+
+// DefaultHTTPClient は設定ファイルがない場合 (tkt init など) に使う共有の http.Client を返します
+// タイムアウトはデフォルト値で、プロキシは環境変数 (HTTP_PROXY, HTTPS_PROXY, NO_PROXY) に従います。
+func DefaultHTTPClient() *http.Client {
+	defaultHTTPClientOnce.Do(func() {
+		defaultHTTPClient = &http.Client{
+			Transport: &metricsTransport{base: newBaseTransport(nil)},
+			Timeout:   config.DefaultHTTPTimeout,
+		}
+	})
+	return defaultHTTPClient
+}
+
+// NewHTTPClient は設定に従った http.Client を作成します
+// http_timeout をタイムアウトにし、ca_bundle が指定されていればシステムの証明書に加えて信頼します。
+// コネクションを使い回せるように、Clientごとに1つだけ作成して全てのリクエストで共有します。
+func NewHTTPClient(cfg *config.Config) (*http.Client, error) {
+	timeout, err := cfg.HTTPTimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// newTransport はAPI呼び出し回数を記録し、設定の証明書を信頼するRoundTripperを作成します
+// rate_limit が設定されていれば、同じサーバーへのリクエストの送信間隔も制限します。
+func newTransport(cfg *config.Config) (http.RoundTripper, error) {
+	var tlsConfig *tls.Config
+	if cfg.CABundle != "" {
+		path := config.ExpandHome(cfg.CABundle)
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("ca_bundleの読み込みに失敗しました: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_bundleに証明書が含まれていません: %s", path)
+		}
+		tlsConfig = &tls.Config{RootCAs: pool}
+	}
+	var base http.RoundTripper = newBaseTransport(tlsConfig)
+	if limiter := sharedRateLimiter(cfg.Server, cfg.RateLimit); limiter != nil {
+		base = &rateLimitTransport{base: base, limiter: limiter}
+	}
+	return &metricsTransport{base: base}, nil
+}
+
+// newBaseTransport はデフォルトの設定 (プロキシは環境変数に従う) をもとにTransportを作成します
+func newBaseTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport
+}
+
+// defaultRetryBaseDelay は再試行の待ち時間の初期値です (再試行のたびに2倍にする)
+const defaultRetryBaseDelay = 500 * time.Millisecond
+
+// maxRetryWait は再試行の待ち時間の上限です (Retry-Afterが長すぎる場合も含む)
+const maxRetryWait = time.Minute
+
+// backoff は attempt 回目の失敗の後に待つ時間を返します
+// 並列に送ったリクエストが同時に再試行しないように、最大で半分の揺らぎを加えます。
+func (c *Client) backoff(attempt int) time.Duration {
+	base := c.retryBaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	d := min(base<<(attempt-1), maxRetryWait)
+	return d + rand.N(d/2+1)
+}
+
+// parseRetryAfter はRetry-Afterヘッダー (秒数またはHTTP日付) を待ち時間に変換します
+// ヘッダーがないか解釈できない場合は0を返します。
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	var d time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(value); err == nil {
+		d = t.Sub(now)
+	}
+	return max(min(d, maxRetryWait), 0)
+}
+
+// isIdempotentRequest は再試行しても結果が変わらないリクエストかを判定します
+// 検索やbulkfetchはPOSTですが読み取りのみなので冪等として扱います。
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	switch classifyEndpoint(req.Method, req.URL.Path) {
+	case EndpointSearch, EndpointGet:
+		return true
+	}
+	return false
+}
+
+// isRetryableStatus は一時的な失敗を表すステータスかを判定します
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// isRetryableError は送信の失敗が再試行できるものかを判定します
+// 冪等でないリクエストは、サーバーに届いていないことが確かな接続の失敗のみ再試行します。
+func isRetryableError(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if idempotent {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// canReplayBody はリクエストボディを再送できるかを返します
+func canReplayBody(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// apiError はJIRA APIが失敗のステータスを返したことを表します
+// レスポンスボディを保持し、フィールドごとのエラーを後から調べられるようにします。
+type apiError struct {
+	message    string
+	statusCode int
+	body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (status: %d): %s", e.message, e.statusCode, e.body)
+}
+
+// EndpointClass はAPI呼び出し回数を集計するためのエンドポイントの分類です
+type EndpointClass string
+
+const (
+	EndpointSearch EndpointClass = "search"
+	EndpointGet    EndpointClass = "get"
+	EndpointUpdate EndpointClass = "update"
+	EndpointAgile  EndpointClass = "agile"
+	EndpointOther  EndpointClass = "other"
+)
+
+// endpointClassOrder は集計結果の表示順です
+var endpointClassOrder = []EndpointClass{EndpointSearch, EndpointGet, EndpointUpdate, EndpointAgile, EndpointOther}
+
+// apiCallStats はAPI呼び出し回数の集計です
+// first と last は最初のリクエストを送った時刻と最後のレスポンスを受け取った時刻で、実効スループットの計算に使います。
+type apiCallStats struct {
+	mu          sync.Mutex
+	counts      map[EndpointClass]int
+	first, last time.Time
+}
+
+func newAPICallStats() *apiCallStats {
+	return &apiCallStats{counts: make(map[EndpointClass]int)}
+}
+
+// apiCalls はプロセス全体でのAPI呼び出し回数です
+var apiCalls = newAPICallStats()
+
+// searchEndpoints はチケットの検索APIのパスです
+// /rest/api/3/user/search などチケット以外の検索は含めません。
+var searchEndpoints = []string{"/rest/api/2/search", "/rest/api/3/search", "/rest/api/3/search/jql"}
+
+// classifyEndpoint はリクエストのメソッドとパスからエンドポイントの分類を判定します
+func classifyEndpoint(method, path string) EndpointClass {
+	switch {
+	case strings.Contains(path, "/rest/agile/"):
+		return EndpointAgile
+	case slices.ContainsFunc(searchEndpoints, func(endpoint string) bool { return strings.HasSuffix(path, endpoint) }):
+		return EndpointSearch
+	case strings.Contains(path, "/issue"):
+		if method == http.MethodGet || strings.HasSuffix(path, "/bulkfetch") {
+			return EndpointGet
+		}
+		return EndpointUpdate
+	default:
+		return EndpointOther
+	}
+}
+
+// metricsTransport はAPI呼び出し回数を記録するRoundTripperです
+// 再試行もそれぞれ1回の呼び出しとして数えます。
+type metricsTransport struct {
+	base  http.RoundTripper
+	stats *apiCallStats // nilの場合は apiCalls に記録します
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stats := t.stats
+	if stats == nil {
+		stats = apiCalls
+	}
+	class := classifyEndpoint(req.Method, req.URL.Path)
+	stats.mu.Lock()
+	stats.counts[class]++
+	if stats.first.IsZero() {
+		stats.first = time.Now()
+	}
+	stats.mu.Unlock()
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+
+	stats.mu.Lock()
+	stats.last = time.Now()
+	stats.mu.Unlock()
+	return resp, err
+}
+
+// APICallCounts はこれまでのAPI呼び出し回数を分類ごとに返します
+func APICallCounts() map[EndpointClass]int {
+	return apiCalls.snapshot()
+}
+
+// snapshot はAPI呼び出し回数を分類ごとに返します
+func (s *apiCallStats) snapshot() map[EndpointClass]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make(map[EndpointClass]int, len(s.counts))
+	for class, n := range s.counts {
+		counts[class] = n
+	}
+	return counts
+}
+
+// APICallSummary はAPI呼び出し回数の1行サマリーを返します
+// 例: "API calls: 37 search, 12 update, 3 agile (4.2 req/s)"
+// 括弧内は最初のリクエストから最後のレスポンスまでの実効スループットです。
+// APIを呼び出していない場合は空文字列を返します。
+func APICallSummary() string {
+	return apiCalls.summary()
+}
+
+// summary は APICallSummary と同じ形式の1行サマリーを返します
+func (s *apiCallStats) summary() string {
+	counts := s.snapshot()
+	var parts []string
+	total := 0
+	for _, class := range endpointClassOrder {
+		if n := counts[class]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, class))
+			total += n
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	summary := "API calls: " + strings.Join(parts, ", ")
+
+	s.mu.Lock()
+	elapsed := s.last.Sub(s.first)
+	s.mu.Unlock()
+	if total > 1 && elapsed > 0 {
+		summary += fmt.Sprintf(" (%.1f req/s)", float64(total)/elapsed.Seconds())
+	}
+	return summary
+}
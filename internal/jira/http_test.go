@@ -0,0 +1,304 @@
+package jira
+
+import (
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoRequestRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		statuses    []int // 各試行で返すステータス (足りない分は200)
+		maxAttempts int
+		wantStatus  int
+		wantCalls   int32
+	}{
+		{
+			name:       "GETは429の後に再試行する",
+			method:     http.MethodGet,
+			path:       "/rest/api/3/issue/PRJ-1",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "検索のPOSTは冪等として再試行する",
+			method:     http.MethodPost,
+			path:       "/rest/api/3/search/jql",
+			statuses:   []int{http.StatusBadGateway},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "チケット作成のPOSTはステータスでは再試行しない",
+			method:     http.MethodPost,
+			path:       "/rest/api/3/issue",
+			statuses:   []int{http.StatusServiceUnavailable},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:        "最大の試行回数で諦める",
+			method:      http.MethodPut,
+			path:        "/rest/api/3/issue/PRJ-1",
+			statuses:    []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			maxAttempts: 2,
+			wantStatus:  http.StatusTooManyRequests,
+			wantCalls:   2,
+		},
+		{
+			name:       "再試行しないステータスはそのまま返す",
+			method:     http.MethodGet,
+			path:       "/rest/api/3/issue/PRJ-1",
+			statuses:   []int{http.StatusInternalServerError},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				body := make([]byte, 64)
+				m, _ := r.Body.Read(body)
+				if r.Method == http.MethodPost {
+					// 再試行でもボディを送り直す
+					assert.Equal(t, `{"jql":"project = PRJ"}`, string(body[:m]))
+				}
+				if n <= len(tt.statuses) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.statuses[n-1])
+				}
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{Server: srv.URL}
+			cfg.Retry.MaxAttempts = tt.maxAttempts
+			c := &Client{config: cfg, retryBaseDelay: time.Millisecond}
+			var body *strings.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader(`{"jql":"project = PRJ"}`)
+			} else {
+				body = strings.NewReader("")
+			}
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, body)
+			assert.NoError(t, err)
+			resp, err := c.doRequest(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestDoRequestRetryConnectionError(t *testing.T) {
+	t.Parallel()
+
+	// 閉じたポートへの接続は冪等でないPOSTでも再試行する
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+
+	c := &Client{config: &config.Config{Server: "http://" + addr}, retryBaseDelay: time.Millisecond}
+	req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/rest/api/3/issue", strings.NewReader(`{}`))
+	assert.NoError(t, err)
+	start := time.Now()
+	_, err = c.doRequest(req)
+	assert.Error(t, err)
+	// 3回の再試行の待ち時間 (1ms, 2ms, 4ms) 以上かかる
+	assert.GreaterOrEqual(t, time.Since(start), 7*time.Millisecond)
+}
+
+func TestClassifyEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   EndpointClass
+	}{
+		{name: "JQL検索", method: http.MethodPost, path: "/rest/api/3/search/jql", want: EndpointSearch},
+		{name: "旧検索API", method: http.MethodGet, path: "/rest/api/2/search", want: EndpointSearch},
+		{name: "v3の旧検索API", method: http.MethodPost, path: "/rest/api/3/search", want: EndpointSearch},
+		{name: "ユーザーの検索", method: http.MethodGet, path: "/rest/api/3/user/search", want: EndpointOther},
+		{name: "ユーザーの検索 (POST)", method: http.MethodPost, path: "/rest/api/3/user/search", want: EndpointOther},
+		{name: "ユーザーとグループの検索", method: http.MethodGet, path: "/rest/api/2/groupuserpicker/search", want: EndpointOther},
+		{name: "チケットの取得", method: http.MethodGet, path: "/rest/api/3/issue/PRJ-1", want: EndpointGet},
+		{name: "一括取得", method: http.MethodPost, path: "/rest/api/3/issue/bulkfetch", want: EndpointGet},
+		{name: "チケットの作成", method: http.MethodPost, path: "/rest/api/3/issue", want: EndpointUpdate},
+		{name: "チケットの更新", method: http.MethodPut, path: "/rest/api/3/issue/PRJ-1", want: EndpointUpdate},
+		{name: "遷移の取得", method: http.MethodGet, path: "/rest/api/3/issue/PRJ-1/transitions", want: EndpointGet},
+		{name: "遷移の実行", method: http.MethodPost, path: "/rest/api/3/issue/PRJ-1/transitions", want: EndpointUpdate},
+		{name: "createmeta", method: http.MethodGet, path: "/rest/api/3/issue/createmeta/PRJ/issuetypes/10001", want: EndpointGet},
+		{name: "ボードのスプリント", method: http.MethodGet, path: "/rest/agile/1.0/board/1/sprint", want: EndpointAgile},
+		{name: "フィールド定義", method: http.MethodGet, path: "/rest/api/3/field", want: EndpointOther},
+		{name: "不明なパス", method: http.MethodGet, path: "/rest/api/3/myself", want: EndpointOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, classifyEndpoint(tt.method, tt.path))
+		})
+	}
+}
+
+func TestIsIdempotentRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   bool
+	}{
+		{name: "GET", method: http.MethodGet, path: "/rest/api/3/issue/PRJ-1", want: true},
+		{name: "JQL検索のPOST", method: http.MethodPost, path: "/rest/api/3/search/jql", want: true},
+		{name: "一括取得のPOST", method: http.MethodPost, path: "/rest/api/3/issue/bulkfetch", want: true},
+		{name: "チケットの作成", method: http.MethodPost, path: "/rest/api/3/issue", want: false},
+		{name: "チケット以外の検索のPOST", method: http.MethodPost, path: "/rest/api/3/user/search", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tt.method, "https://example.atlassian.net"+tt.path, nil)
+			assert.Equal(t, tt.want, isIdempotentRequest(req))
+		})
+	}
+}
+
+func TestAPICallSummary(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 検索の最初の2回は429を返して再試行させる
+		if r.URL.Path == "/rest/api/3/search/jql" && calls.Add(1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}))
+	defer srv.Close()
+
+	stats := newAPICallStats()
+	assert.Equal(t, "", stats.summary())
+
+	c := &Client{
+		config:         &config.Config{Server: srv.URL},
+		httpClient:     &http.Client{Transport: &metricsTransport{stats: stats}},
+		retryBaseDelay: time.Millisecond,
+	}
+	send := func(method, path string) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(`{}`))
+		assert.NoError(t, err)
+		resp, err := c.doRequest(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+	send(http.MethodPost, "/rest/api/3/search/jql")
+	send(http.MethodGet, "/rest/api/3/issue/PRJ-1")
+	send(http.MethodPut, "/rest/api/3/issue/PRJ-1")
+	send(http.MethodGet, "/rest/api/3/myself")
+
+	// 再試行もそれぞれ1回の呼び出しとして数える
+	assert.Equal(t, map[EndpointClass]int{EndpointSearch: 3, EndpointGet: 1, EndpointUpdate: 1, EndpointOther: 1}, stats.snapshot())
+	summary := stats.summary()
+	assert.True(t, strings.HasPrefix(summary, "API calls: 3 search, 1 get, 1 update, 1 other ("), summary)
+	assert.True(t, strings.HasSuffix(summary, " req/s)"), summary)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "秒数", value: "3", want: 3 * time.Second},
+		{name: "HTTP日付", value: "Sun, 01 Jun 2025 12:00:10 GMT", want: 10 * time.Second},
+		{name: "過去の日付", value: "Sun, 01 Jun 2025 11:00:00 GMT", want: 0},
+		{name: "上限を超える", value: "3600", want: maxRetryWait},
+		{name: "空", value: "", want: 0},
+		{name: "不正な値", value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parseRetryAfter(tt.value, now))
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+
+	tests := []struct {
+		name    string
+		cfg     config.Config
+		path    string
+		wantErr string
+	}{
+		{name: "ca_bundleの証明書を信頼する", cfg: config.Config{CABundle: caBundle}, path: "/"},
+		{name: "ca_bundleがなければ検証に失敗する", path: "/", wantErr: "certificate"},
+		{name: "http_timeoutを超えると失敗する", cfg: config.Config{CABundle: caBundle, HTTPTimeout: "50ms"}, path: "/slow", wantErr: "Client.Timeout exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewHTTPClient(&tt.cfg)
+			assert.NoError(t, err)
+			resp, err := client.Get(srv.URL + tt.path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			resp.Body.Close()
+		})
+	}
+
+	// 証明書を含まないファイルはエラーにする
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	assert.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0600))
+	_, err := NewHTTPClient(&config.Config{CABundle: invalid})
+	assert.ErrorContains(t, err, "ca_bundleに証明書が含まれていません")
+}
@@ -0,0 +1,51 @@
+package jira
+
+// ignoredPayloadFields はフロントマターの項目名と更新リクエストのフィールド名の対応です
+var ignoredPayloadFields = map[string]string{
+	"title":        "summary",
+	"type":         "issuetype",
+	"body":         "description",
+	"parentKey":    "parent",
+	"assignee":     "assignee",
+	"priority":     "priority",
+	"labels":       "labels",
+	"components":   "components",
+	"fix_versions": "fixVersions",
+	"environment":  "environment",
+}
+
+// removeIgnoredFields は diff.ignore_fields に指定された項目を更新フィールドから取り除きます
+// 差分にならない項目をpushでローカルの値に戻してしまわないようにします。
+func (c *Client) removeIgnoredFields(fields map[string]interface{}) {
+	for _, name := range c.config.Diff.IgnoreFields {
+		switch name {
+		case "original_estimate", "remaining_estimate":
+			if tt, ok := fields["timetracking"].(map[string]interface{}); ok {
+				if name == "original_estimate" {
+					delete(tt, "originalEstimate")
+				} else {
+					delete(tt, "remainingEstimate")
+				}
+				if len(tt) == 0 {
+					delete(fields, "timetracking")
+				}
+			}
+		case "sprint":
+			delete(fields, c.sprintFieldID)
+		case "flagged":
+			delete(fields, c.flaggedFieldID)
+		case "team":
+			delete(fields, c.teamFieldID)
+		default:
+			if key, ok := ignoredPayloadFields[name]; ok {
+				delete(fields, key)
+				continue
+			}
+			for _, m := range c.customFields {
+				if m.FrontmatterKey == name {
+					delete(fields, m.ID)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveIgnoredFields(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Diff.IgnoreFields = []string{"parentKey", "original_estimate", "sprint", "story_points"}
+	c := &Client{
+		config:        cfg,
+		sprintFieldID: "customfield_10020",
+		customFields:  []customFieldMapping{{ID: "customfield_10016", FrontmatterKey: "story_points"}},
+	}
+
+	fields := map[string]interface{}{
+		"summary":           "タイトル",
+		"parent":            map[string]string{"key": "PRJ-2"},
+		"timetracking":      map[string]interface{}{"originalEstimate": "2.0h", "remainingEstimate": "1.0h"},
+		"customfield_10020": 42,
+		"customfield_10016": 3.0,
+	}
+	c.removeIgnoredFields(fields)
+
+	assert.Equal(t, map[string]interface{}{
+		"summary":      "タイトル",
+		"timetracking": map[string]interface{}{"remainingEstimate": "1.0h"},
+	}, fields)
+}
@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+)
+
+// FetchIssueTypes はプロジェクトで利用可能なIssue Typeの一覧を取得します
+func (c *Client) FetchIssueTypes() ([]config.IssueType, error) {
+	v := url.Values{}
+	v.Add("projectId", c.config.Project.ID)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/api/3/issuetype/project?%s", c.config.Server, v.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	var body []struct {
+		ID               string `json:"id"`
+		Description      string `json:"description"`
+		Name             string `json:"name"`
+		UntranslatedName string `json:"untranslatedName"`
+		Subtask          bool   `json:"subtask"`
+	}
+	if err := c.getJSON(req, &body); err != nil {
+		return nil, fmt.Errorf("Issue Typeの取得に失敗しました: %v", err)
+	}
+	types := make([]config.IssueType, 0, len(body))
+	for _, it := range body {
+		types = append(types, config.IssueType{
+			ID:               it.ID,
+			Description:      it.Description,
+			Name:             it.Name,
+			UntranslatedName: it.UntranslatedName,
+			Subtask:          it.Subtask,
+		})
+	}
+	return types, nil
+}
+
+// issueTypeID はチケットのIssue TypeのIDを返します
+// フェッチしたチケットのtype_idを優先し、Issue Typeの名前が変更されていてもpushできるようにします。
+// ただし、typeが別のIssue Typeの名前に書き換えられている場合はtypeを優先します。
+// type_idがない場合 (手で書いた下書き) はissue.typesから名前で探します。
+func issueTypeID(cfg *config.Config, t *ticket.Ticket) (string, error) {
+	byName, nameFound := cfg.FindIssueType(t.Type)
+	if t.TypeID != "" {
+		_, idFound := cfg.IssueTypeByID(t.TypeID)
+		if idFound && (!nameFound || byName.ID == t.TypeID) {
+			return t.TypeID, nil
+		}
+		// issue.typesが古くtype_idが見つからない場合も、名前で見つからなければtype_idを使う
+		if !idFound && !nameFound {
+			return t.TypeID, nil
+		}
+	}
+	if nameFound {
+		return byName.ID, nil
+	}
+	return "", fmt.Errorf("チケットタイプが見つかりません: %s", t.Type)
+}
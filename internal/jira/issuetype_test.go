@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueTypeID(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{}
+	cfg.Issue.Types = []config.IssueType{
+		{ID: "10001", Name: "作業", UntranslatedName: "Task"},
+		{ID: "10002", Name: "Bug", UntranslatedName: "Bug"},
+	}
+
+	tests := []struct {
+		name    string
+		typ     string
+		typeID  string
+		want    string
+		wantErr bool
+	}{
+		{name: "名前が変更されてもtype_idで解決する", typ: "story", typeID: "10001", want: "10001"},
+		{name: "typeとtype_idが一致する", typ: "作業", typeID: "10001", want: "10001"},
+		{name: "typeを別のIssue Typeに書き換えた場合はtypeを優先", typ: "bug", typeID: "10001", want: "10002"},
+		{name: "type_idがない下書きは名前で解決する", typ: "Bug", want: "10002"},
+		{name: "翻訳前の名前でも解決する", typ: "task", want: "10001"},
+		{name: "issue.typesにないtype_idもそのまま使う", typ: "epic", typeID: "10000", want: "10000"},
+		{name: "見つからない", typ: "epic", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := issueTypeID(cfg, &ticket.Ticket{Type: tt.typ, TypeID: tt.typeID})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
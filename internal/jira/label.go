@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// labelUpdateOps はラベルを追加・削除する update の操作 ([{"add": "x"}, {"remove": "y"}]) を返します
+func labelUpdateOps(add, remove []string) []map[string]string {
+	ops := make([]map[string]string, 0, len(add)+len(remove))
+	for _, label := range add {
+		ops = append(ops, map[string]string{"add": label})
+	}
+	for _, label := range remove {
+		ops = append(ops, map[string]string{"remove": label})
+	}
+	return ops
+}
+
+// UpdateLabels はチケットのラベルを追加・削除します
+// labels を丸ごと置き換えずに update の add/remove で送るため、同時に他の人が編集したラベルは残ります。
+func (c *Client) UpdateLabels(issueKey string, add, remove []string) error {
+	for _, label := range append(append([]string{}, add...), remove...) {
+		if err := ValidateLabel(label); err != nil {
+			return err
+		}
+	}
+	return c.putIssue(issueKey, map[string]interface{}{
+		"update": map[string]interface{}{
+			"labels": labelUpdateOps(add, remove),
+		},
+	})
+}
+
+// ValidateLabel はJIRAのラベルとして使える文字列かを検証します (JIRAのラベルには空白を含められません)
+func ValidateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("ラベルが空です")
+	}
+	if strings.ContainsAny(label, " \t\n") {
+		return fmt.Errorf("ラベルに空白は使えません: %q", label)
+	}
+	return nil
+}
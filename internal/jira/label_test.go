@@ -0,0 +1,67 @@
+package jira
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateLabels(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(b, &gotBody))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := &Client{config: &config.Config{Server: srv.URL}}
+	err := c.UpdateLabels("PRJ-1", []string{"backend"}, []string{"old"})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT /rest/api/2/issue/PRJ-1", gotPath)
+	assert.Equal(t, map[string]any{
+		"update": map[string]any{
+			"labels": []any{
+				map[string]any{"add": "backend"},
+				map[string]any{"remove": "old"},
+			},
+		},
+	}, gotBody)
+	_, ok := gotBody["fields"]
+	assert.False(t, ok, "labels全体は送らない")
+}
+
+func TestValidateLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		label   string
+		wantErr bool
+	}{
+		{name: "通常のラベル", label: "backend"},
+		{name: "記号を含む", label: "team-api_v2"},
+		{name: "空白を含む", label: "back end", wantErr: true},
+		{name: "空", label: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateLabel(tt.label)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
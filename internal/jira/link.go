@@ -0,0 +1,257 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// issueLinkType はJIRAのリンクの種類です (例: name: Blocks, inward: is blocked by, outward: blocks)
+type issueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// linkedIssue はリンク先のチケットです
+type linkedIssue struct {
+	Key string `json:"key"`
+}
+
+// issueLink はJIRAのAPIが返すチケットのリンクです
+// outwardIssueがある場合は「このチケット <outward> outwardIssue」、
+// inwardIssueがある場合は「このチケット <inward> inwardIssue」を表します。
+type issueLink struct {
+	ID           string        `json:"id"`
+	Type         issueLinkType `json:"type"`
+	InwardIssue  *linkedIssue  `json:"inwardIssue"`
+	OutwardIssue *linkedIssue  `json:"outwardIssue"`
+}
+
+// toLink はリンクをチケットから見た説明とキーに変換します
+func (l issueLink) toLink() (ticket.Link, bool) {
+	switch {
+	case l.OutwardIssue != nil:
+		return ticket.Link{Type: l.Type.Outward, Key: l.OutwardIssue.Key}, true
+	case l.InwardIssue != nil:
+		return ticket.Link{Type: l.Type.Inward, Key: l.InwardIssue.Key}, true
+	}
+	return ticket.Link{}, false
+}
+
+// convertLinks はJIRAのリンクをフロントマターのリンクに変換します
+func convertLinks(issueLinks []issueLink) []ticket.Link {
+	var links []ticket.Link
+	for _, l := range issueLinks {
+		if link, ok := l.toLink(); ok {
+			links = append(links, link)
+		}
+	}
+	ticket.SortLinks(links)
+	return links
+}
+
+// resolveLinkType はリンクの説明 (blocks, is blocked by など) または種類の名前からリンクの種類を探します
+// outwardはこのチケットがリンクの外向き (例: blocks) 側かどうかです。大文字小文字は区別しません。
+func resolveLinkType(types []issueLinkType, relation string) (linkType issueLinkType, outward bool, err error) {
+	for _, t := range types {
+		if strings.EqualFold(t.Outward, relation) || strings.EqualFold(t.Name, relation) {
+			return t, true, nil
+		}
+		if strings.EqualFold(t.Inward, relation) {
+			return t, false, nil
+		}
+	}
+	var candidates []string
+	for _, t := range types {
+		candidates = append(candidates, t.Outward)
+		if t.Inward != t.Outward {
+			candidates = append(candidates, t.Inward)
+		}
+	}
+	return issueLinkType{}, false, fmt.Errorf("リンクの種類 %q が見つかりません (指定できる種類: %s)", relation, strings.Join(candidates, ", "))
+}
+
+// fetchLinkTypes はリンクの種類の一覧を取得します
+func (c *Client) fetchLinkTypes() ([]issueLinkType, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/issueLinkType", nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("リンクの種類の取得に失敗しました (status: %d)", resp.StatusCode)
+	}
+
+	var body struct {
+		IssueLinkTypes []issueLinkType `json:"issueLinkTypes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return body.IssueLinkTypes, nil
+}
+
+// LinkIssues はチケット間にリンクを作成します (例: LinkIssues("PRJ-1", "blocks", "PRJ-2"))
+func (c *Client) LinkIssues(from, relation, to string) error {
+	types, err := c.fetchLinkTypes()
+	if err != nil {
+		return err
+	}
+	return c.createLink(types, from, relation, to)
+}
+
+// createLink はリンクを作成します
+func (c *Client) createLink(types []issueLinkType, from, relation, to string) error {
+	linkType, outward, err := resolveLinkType(types, relation)
+	if err != nil {
+		return err
+	}
+
+	// 作成APIでは inwardIssue <outward> outwardIssue の関係になる
+	// (例: inwardIssue=PRJ-1, outwardIssue=PRJ-2, type=Blocks は「PRJ-1 blocks PRJ-2」)
+	inward, outwardKey := from, to
+	if !outward {
+		inward, outwardKey = to, from
+	}
+	reqBody := map[string]interface{}{
+		"type":         map[string]string{"name": linkType.Name},
+		"inwardIssue":  map[string]string{"key": inward},
+		"outwardIssue": map[string]string{"key": outwardKey},
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.Server+"/rest/api/3/issueLink", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+		verbose.Printf(verbose.API, "リンクを作成しました: %s %s %s\n", from, relation, to)
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("チケット %s または %s が見つかりません", from, to)
+	default:
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("リンクの作成に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+}
+
+// fetchIssueLinks はチケットのリンクをIDつきで取得します
+func (c *Client) fetchIssueLinks(key string) ([]issueLink, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/issue/"+key+"?fields=issuelinks", nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("チケット %s のリンクの取得に失敗しました (status: %d)", key, resp.StatusCode)
+	}
+
+	var body struct {
+		Fields struct {
+			IssueLinks []issueLink `json:"issuelinks"`
+		} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return body.Fields.IssueLinks, nil
+}
+
+// deleteIssueLink はリンクを削除します
+func (c *Client) deleteIssueLink(id string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.config.Server+"/rest/api/3/issueLink/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("リンクの削除に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+	return nil
+}
+
+// diffLinks はリモートのリンクとローカルのリンクを比較し、追加するリンクと削除するリンクを返します
+func diffLinks(remote []issueLink, local []ticket.Link) (added []ticket.Link, removed []issueLink) {
+	var remoteLinks []ticket.Link
+	for _, l := range remote {
+		link, ok := l.toLink()
+		if !ok {
+			continue
+		}
+		remoteLinks = append(remoteLinks, link)
+		if !slices.Contains(local, link) {
+			removed = append(removed, l)
+		}
+	}
+	for _, link := range local {
+		if !slices.Contains(remoteLinks, link) {
+			added = append(added, link)
+		}
+	}
+	return added, removed
+}
+
+// SyncLinks はチケットのリンクをフロントマターのlinksと同じ状態にします
+// フロントマターにないリンクは削除し、リモートにないリンクは作成します。
+func (c *Client) SyncLinks(key string, links []ticket.Link) error {
+	remote, err := c.fetchIssueLinks(key)
+	if err != nil {
+		return err
+	}
+	added, removed := diffLinks(remote, links)
+	for _, l := range removed {
+		verbose.Printf(verbose.API, "リンクを削除中: %s (ID: %s)\n", key, l.ID)
+		if err := c.deleteIssueLink(l.ID); err != nil {
+			return err
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	types, err := c.fetchLinkTypes()
+	if err != nil {
+		return err
+	}
+	for _, link := range added {
+		if err := c.createLink(types, key, link.Type, link.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+var blocksType = issueLinkType{ID: "1", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+var relatesType = issueLinkType{ID: "2", Name: "Relates", Inward: "relates to", Outward: "relates to"}
+
+func TestConvertLinks(t *testing.T) {
+	t.Parallel()
+
+	issueLinks := []issueLink{
+		{ID: "10", Type: blocksType, OutwardIssue: &linkedIssue{Key: "PRJ-45"}},
+		{ID: "11", Type: blocksType, InwardIssue: &linkedIssue{Key: "PRJ-3"}},
+		{ID: "12", Type: relatesType, InwardIssue: &linkedIssue{Key: "PRJ-7"}},
+		{ID: "13", Type: blocksType},
+	}
+	want := []ticket.Link{
+		{Type: "blocks", Key: "PRJ-45"},
+		{Type: "is blocked by", Key: "PRJ-3"},
+		{Type: "relates to", Key: "PRJ-7"},
+	}
+	assert.Equal(t, want, convertLinks(issueLinks))
+	assert.Nil(t, convertLinks(nil))
+}
+
+func TestResolveLinkType(t *testing.T) {
+	t.Parallel()
+
+	types := []issueLinkType{blocksType, relatesType}
+	tests := []struct {
+		name        string
+		relation    string
+		want        issueLinkType
+		wantOutward bool
+		wantErr     bool
+	}{
+		{name: "外向きの説明", relation: "blocks", want: blocksType, wantOutward: true},
+		{name: "内向きの説明", relation: "is blocked by", want: blocksType, wantOutward: false},
+		{name: "種類の名前", relation: "Blocks", want: blocksType, wantOutward: true},
+		{name: "大文字小文字を区別しない", relation: "Relates To", want: relatesType, wantOutward: true},
+		{name: "存在しない種類", relation: "duplicates", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, outward, err := resolveLinkType(types, tt.relation)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "is blocked by")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOutward, outward)
+		})
+	}
+}
+
+func TestDiffLinks(t *testing.T) {
+	t.Parallel()
+
+	remote := []issueLink{
+		{ID: "10", Type: blocksType, OutwardIssue: &linkedIssue{Key: "PRJ-45"}},
+		{ID: "11", Type: relatesType, InwardIssue: &linkedIssue{Key: "PRJ-7"}},
+	}
+	tests := []struct {
+		name        string
+		local       []ticket.Link
+		wantAdded   []ticket.Link
+		wantRemoved []string
+	}{
+		{
+			name:  "変更なし",
+			local: []ticket.Link{{Type: "blocks", Key: "PRJ-45"}, {Type: "relates to", Key: "PRJ-7"}},
+		},
+		{
+			name:        "追加と削除",
+			local:       []ticket.Link{{Type: "blocks", Key: "PRJ-45"}, {Type: "is blocked by", Key: "PRJ-3"}},
+			wantAdded:   []ticket.Link{{Type: "is blocked by", Key: "PRJ-3"}},
+			wantRemoved: []string{"11"},
+		},
+		{
+			name:        "すべて削除",
+			local:       nil,
+			wantRemoved: []string{"10", "11"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			added, removed := diffLinks(remote, tt.local)
+			assert.Equal(t, tt.wantAdded, added)
+			var removedIDs []string
+			for _, l := range removed {
+				removedIDs = append(removedIDs, l.ID)
+			}
+			assert.Equal(t, tt.wantRemoved, removedIDs)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/k1LoW/errors"
+)
+
+// FetchPriorities は利用可能な優先度の名前を取得します
+func (c *Client) FetchPriorities() ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/priority", nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("優先度の取得に失敗しました (status: %d)", resp.StatusCode)
+	}
+
+	var priorities []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&priorities); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+
+	names := make([]string, 0, len(priorities))
+	for _, p := range priorities {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// hasFieldError はJIRAのエラーレスポンスに指定したフィールドのエラーが含まれるかを返します
+func hasFieldError(body string, field string) bool {
+	_, ok := parseFieldErrors(body)[field]
+	return ok
+}
+
+// withPriorityHint は優先度が原因のエラーの場合に、指定できる優先度の一覧をエラーに追加します
+func (c *Client) withPriorityHint(err error, priority string) error {
+	var apiErr *apiError
+	if priority == "" || !errors.As(err, &apiErr) || !hasFieldError(apiErr.body, "priority") {
+		return err
+	}
+	names, fetchErr := c.FetchPriorities()
+	if fetchErr != nil || len(names) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n優先度 %q は使用できません。指定できる優先度: %s", err, priority, strings.Join(names, ", "))
+}
@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasFieldError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		want  bool
+	}{
+		{
+			name:  "優先度のエラー",
+			body:  `{"errorMessages":[],"errors":{"priority":"Specify the Priority (id or name) in the string format"}}`,
+			field: "priority",
+			want:  true,
+		},
+		{
+			name:  "別のフィールドのエラー",
+			body:  `{"errorMessages":[],"errors":{"summary":"You must specify a summary of the issue."}}`,
+			field: "priority",
+			want:  false,
+		},
+		{
+			name:  "JSONではない",
+			body:  `Internal Server Error`,
+			field: "priority",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, hasFieldError(tt.body, tt.field))
+		})
+	}
+}
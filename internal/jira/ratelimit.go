@@ -0,0 +1,92 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter はリクエストの送信間隔を一定に保つトークンバケットです
+// 並列に取得してもリクエストが一度に集中しないよう、バケットの容量は1にしています。
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // 1秒あたりに補充するトークンの数
+	tokens float64   // 残りのトークン (負の値は待っているリクエストの分)
+	last   time.Time // 最後にトークンを補充した時刻
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, tokens: 1}
+}
+
+// reserve はトークンを1つ取り、リクエストを送れるまでの待ち時間を返します
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens = min(1, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// wait はリクエストを送れるまで待ちます。ctx がキャンセルされた場合はそのエラーを返します。
+func (l *rateLimiter) wait(ctx context.Context) error {
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = make(map[string]*rateLimiter)
+)
+
+// sharedRateLimiter はサーバーごとに共有する rateLimiter を返します
+// 同じコマンドの中で複数のClientを作っても、同じサーバーへのリクエスト全体で上限を守ります。
+// rate が0以下の場合は制限しないので nil を返します。
+func sharedRateLimiter(server string, rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	l, ok := rateLimiters[server]
+	if !ok || l.rate != rate {
+		l = newRateLimiter(rate)
+		rateLimiters[server] = l
+	}
+	return l
+}
+
+// rateLimitTransport は rate_limit に従ってリクエストの送信を待たせるRoundTripperです
+// 再試行や go-jira 経由のリクエストも含め、全てのリクエストが対象になります。
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
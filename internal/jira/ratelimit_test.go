@@ -0,0 +1,49 @@
+package jira
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	l := newRateLimiter(4) // 250msに1回
+
+	// 最初のリクエストはすぐに送れ、同時に来たリクエストは間隔をあけて順に待つ
+	assert.Equal(t, time.Duration(0), l.reserve(now))
+	assert.Equal(t, 250*time.Millisecond, l.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(now))
+
+	// 十分に時間がたてばすぐに送れる (待たなかった分をまとめて送ることはできない)
+	later := now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve(later))
+	assert.Equal(t, 250*time.Millisecond, l.reserve(later))
+}
+
+func TestRateLimiterWaitCancel(t *testing.T) {
+	t.Parallel()
+
+	l := newRateLimiter(0.1) // 10秒に1回
+	assert.NoError(t, l.wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.wait(ctx), context.Canceled)
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	// rate_limitが未設定の場合は制限しない
+	assert.Nil(t, sharedRateLimiter("https://unlimited.example.com", 0))
+
+	// 同じサーバーでは同じ上限を共有する
+	a := sharedRateLimiter("https://shared.example.com", 5)
+	assert.Same(t, a, sharedRateLimiter("https://shared.example.com", 5))
+	assert.NotSame(t, a, sharedRateLimiter("https://other.example.com", 5))
+}
@@ -0,0 +1,252 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rest/agile/1.0/board/7/sprint", r.URL.Path)
+		_, _ = w.Write([]byte(`{"isLast": true, "total": 2, "values": [
+			{"id": 41, "name": "Sprint 41", "state": "active"},
+			{"id": 42, "name": "Sprint 42", "state": "future"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Server: srv.URL}
+	cfg.Board.ID = 7
+	c := &Client{config: cfg, sprintFieldID: "customfield_10020"}
+
+	// fetch: 状態が分からない場合は最後のスプリントがフロントマターのsprintになる
+	var issue Issue
+	assert.NoError(t, json.Unmarshal([]byte(`{"key": "PRJ-1", "fields": {
+		"summary": "タイトル",
+		"issuetype": {"name": "Task"},
+		"created": "2025-06-02T10:00:00.000+0900",
+		"updated": "2025-06-03T10:00:00.000+0900",
+		"customfield_10020": [{"id": 40, "name": "Sprint 40"}, {"id": 41, "name": "Sprint 41"}]
+	}}`), &issue))
+	fetched, err := c.convertIssue(&issue)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Sprint 41", fetched.SprintName)
+
+	// file: 書き出して読み込んでもスプリント名が残る
+	dir := t.TempDir()
+	path, err := fetched.SaveToFile(dir)
+	assert.NoError(t, err)
+	loaded, err := ticket.FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sprint 41", loaded.SprintName)
+	assert.False(t, loaded.HasNonReadonlyDiff(fetched))
+
+	// push: スプリントを変更すると差分になり、スプリントIDで更新する
+	loaded.SprintName = "Sprint 42"
+	assert.True(t, loaded.HasNonReadonlyDiff(fetched))
+	fields := make(map[string]interface{})
+	assert.NoError(t, c.addSprintFieldToUpdate(fields, *loaded))
+	assert.Equal(t, 42, fields["customfield_10020"])
+}
+
+func TestPickSprintName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sprints string
+		want    string
+		wantAll []string
+	}{
+		{
+			name:    "アクティブなスプリントが最後の要素でない",
+			sprints: `[{"id": 42, "name": "Sprint 42", "state": "active"}, {"id": 40, "name": "Sprint 40", "state": "closed"}]`,
+			want:    "Sprint 42",
+			wantAll: []string{"Sprint 42 (active)", "Sprint 40 (closed)"},
+		},
+		{
+			name:    "バックログから未来のスプリントに戻した",
+			sprints: `[{"id": 43, "name": "Sprint 43", "state": "future"}, {"id": 41, "name": "Sprint 41", "state": "closed"}, {"id": 40, "name": "Sprint 40", "state": "closed"}]`,
+			want:    "Sprint 43",
+			wantAll: []string{"Sprint 43 (future)", "Sprint 41 (closed)", "Sprint 40 (closed)"},
+		},
+		{
+			name:    "アクティブなスプリントを未来のスプリントより優先する",
+			sprints: `[{"id": 43, "name": "Sprint 43", "state": "future"}, {"id": 42, "name": "Sprint 42", "state": "ACTIVE"}]`,
+			want:    "Sprint 42",
+			wantAll: []string{"Sprint 43 (future)", "Sprint 42 (active)"},
+		},
+		{
+			name:    "完了したスプリントのみの場合は最後のもの",
+			sprints: `[{"id": 40, "name": "Sprint 40", "state": "closed"}, {"id": 41, "name": "Sprint 41", "state": "closed"}]`,
+			want:    "Sprint 41",
+			wantAll: []string{"Sprint 40 (closed)", "Sprint 41 (closed)"},
+		},
+		{
+			name:    "名前のない要素は無視する",
+			sprints: `[{"id": 40, "name": "Sprint 40", "state": "active"}, {"id": 41}, "invalid"]`,
+			want:    "Sprint 40",
+			wantAll: []string{"Sprint 40 (active)"},
+		},
+		{
+			name:    "空",
+			sprints: `[]`,
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sprints []interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.sprints), &sprints))
+			got, all := pickSprintName(sprints)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantAll, all)
+		})
+	}
+}
+
+func TestAddSprintFieldActive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sprints string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "アクティブなスプリントが1つ", sprints: `{"id": 41, "name": "Sprint 41", "state": "active"}`, want: 41},
+		{name: "アクティブなスプリントがない", sprints: ``, wantErr: true},
+		{name: "アクティブなスプリントが複数", sprints: `{"id": 41, "name": "A", "state": "active"}, {"id": 51, "name": "B", "state": "active"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "active", r.URL.Query().Get("state"))
+				_, _ = w.Write([]byte(`{"isLast": true, "values": [` + tt.sprints + `]}`))
+			}))
+			defer srv.Close()
+
+			cfg := &config.Config{Server: srv.URL}
+			cfg.Board.ID = 7
+			c := &Client{config: cfg, sprintFieldID: "customfield_10020"}
+
+			fields := make(map[string]interface{})
+			err := c.addSprintFieldToUpdate(fields, ticket.Ticket{Key: "PRJ-1", SprintName: SprintActive})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, fields["customfield_10020"])
+		})
+	}
+}
+
+func TestAddSprintFieldBacklog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fieldID string
+		sprint  string
+		want    map[string]interface{}
+	}{
+		{name: "sprintを空にするとスプリントから外す", fieldID: "customfield_10020", sprint: "", want: map[string]interface{}{"customfield_10020": nil}},
+		{name: "backlogを指定するとスプリントから外す", fieldID: "customfield_10020", sprint: "Backlog", want: map[string]interface{}{"customfield_10020": nil}},
+		{name: "スプリントフィールドがない", fieldID: "", sprint: "", want: map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// ボードやスプリントの一覧は参照しない
+			c := &Client{config: &config.Config{}, sprintFieldID: tt.fieldID}
+			fields := make(map[string]interface{})
+			assert.NoError(t, c.addSprintFieldToUpdate(fields, ticket.Ticket{Key: "PRJ-1", SprintName: tt.sprint}))
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestFindSprintByNameCachesSprints(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Query().Get("state"))
+		mu.Unlock()
+		if r.URL.Query().Get("state") == "" {
+			_, _ = w.Write([]byte(`{"isLast": true, "values": [{"id": 40, "name": "Sprint 40", "state": "closed"}, {"id": 41, "name": "Sprint 41", "state": "active"}]}`))
+			return
+		}
+		// キャッシュした後に作られたスプリント
+		_, _ = w.Write([]byte(`{"isLast": true, "values": [{"id": 41, "name": "Sprint 41", "state": "active"}, {"id": 42, "name": "Sprint 42", "state": "future"}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Server: srv.URL}
+	cfg.Board.ID = 7
+	c := &Client{config: cfg}
+	ctx := context.Background()
+
+	// 何件解決しても一覧の取得は1回
+	for _, name := range []string{"Sprint 41", "Sprint 40", "Sprint 41"} {
+		_, err := c.FindSprintByName(ctx, name)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{""}, requests)
+
+	// 一覧にないスプリントはアクティブと未来のスプリントのみ取得し直す
+	sprint, err := c.FindSprintByName(ctx, "Sprint 42")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, sprint.ID)
+	assert.Equal(t, []string{"", "active,future"}, requests)
+
+	// 取得し直すのは1回だけ
+	_, err = c.FindSprintByName(ctx, "Sprint 99")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"", "active,future"}, requests)
+}
+
+func TestFindSprintByNameUsesSprintCacheFile(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	data, err := json.Marshal(sprintCache{FetchedAt: time.Now(), Sprints: []Sprint{{ID: 41, Name: "Sprint 41", State: "active"}}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, sprintCacheFile), data, 0644))
+
+	cfg := &config.Config{Server: srv.URL}
+	cfg.Board.ID = 7
+	c := &Client{config: cfg}
+	c.SetSprintCacheDir(dir)
+
+	sprint, err := c.FindSprintByName(context.Background(), "Sprint 41")
+	assert.NoError(t, err)
+	assert.Equal(t, 41, sprint.ID)
+}
@@ -0,0 +1,189 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// sprintCacheFile はスプリントの一覧を保存するキャッシュディレクトリ内のファイル名です
+const sprintCacheFile = "sprints.json"
+
+// SprintCacheTTL はスプリントの一覧のキャッシュの有効期間です
+const SprintCacheTTL = 24 * time.Hour
+
+// sprintCache はキャッシュに保存するスプリントの一覧です
+type sprintCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Sprints   []Sprint  `json:"sprints"`
+}
+
+// ReadSprintCache はキャッシュに保存されたスプリントの一覧と取得時刻を返します
+// キャッシュがない場合はnilを返します。有効期間は確認しません。
+func ReadSprintCache(cacheDir string) ([]Sprint, time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, sprintCacheFile))
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("スプリントのキャッシュの読み込みに失敗しました: %v", err)
+	}
+	var cache sprintCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, time.Time{}, fmt.Errorf("スプリントのキャッシュの解析に失敗しました: %v", err)
+	}
+	return cache.Sprints, cache.FetchedAt, nil
+}
+
+// SetSprintCacheDir はスプリント名の解決に使うスプリントの一覧を、キャッシュディレクトリの sprints.json から読むようにします
+// 設定しない場合も、一覧の取得はクライアントごとに1回だけです。
+func (c *Client) SetSprintCacheDir(cacheDir string) {
+	c.sprintsMu.Lock()
+	defer c.sprintsMu.Unlock()
+	c.sprintCacheDir = cacheDir
+}
+
+// CachedSprints は設定されたボードのスプリントの一覧を返します
+// キャッシュが有効期間内であればキャッシュを使い、古い場合は取得し直して保存します。
+// 一度読み込んだ一覧はクライアントに保持し、同じコマンドの中では読み込み直しません。
+func (c *Client) CachedSprints(ctx context.Context, cacheDir string) ([]Sprint, error) {
+	c.sprintsMu.Lock()
+	defer c.sprintsMu.Unlock()
+	if c.sprintsLoaded {
+		return c.sprints, nil
+	}
+
+	sprints, fetchedAt, err := ReadSprintCache(cacheDir)
+	if err != nil {
+		verbose.Printf(verbose.API, "%v\n", err)
+	} else if sprints != nil && time.Since(fetchedAt) < SprintCacheTTL {
+		c.sprints, c.sprintsLoaded = sprints, true
+		return sprints, nil
+	}
+	return c.refreshSprintCache(ctx, cacheDir)
+}
+
+// RefreshSprintCache は設定されたボードのスプリントの一覧を取得してキャッシュに保存します
+func (c *Client) RefreshSprintCache(ctx context.Context, cacheDir string) ([]Sprint, error) {
+	c.sprintsMu.Lock()
+	defer c.sprintsMu.Unlock()
+	return c.refreshSprintCache(ctx, cacheDir)
+}
+
+// refreshSprintCache はスプリントの一覧を取得してクライアントに保持し、cacheDir が空でなければ保存します
+// sprintsMu を保持して呼び出します。
+func (c *Client) refreshSprintCache(ctx context.Context, cacheDir string) ([]Sprint, error) {
+	boardIDs := c.config.BoardIDs()
+	if len(boardIDs) == 0 {
+		return nil, nil
+	}
+	sprints, err := c.getSprintsOfBoards(ctx, boardIDs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("スプリント一覧の取得に失敗しました: %w", err)
+	}
+	c.sprints, c.sprintsLoaded = sprints, true
+	if cacheDir == "" {
+		return sprints, nil
+	}
+
+	data, err := json.MarshalIndent(sprintCache{FetchedAt: time.Now(), Sprints: sprints}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("スプリントのキャッシュの作成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, sprintCacheFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("スプリントのキャッシュの保存に失敗しました: %v", err)
+	}
+	verbose.Printf(verbose.API, "%d 件のスプリントをキャッシュしました\n", len(sprints))
+	return sprints, nil
+}
+
+// boardSprints はスプリント名の解決に使うスプリントの一覧を返します
+func (c *Client) boardSprints(ctx context.Context) ([]Sprint, error) {
+	c.sprintsMu.Lock()
+	cacheDir := c.sprintCacheDir
+	c.sprintsMu.Unlock()
+	return c.CachedSprints(ctx, cacheDir)
+}
+
+// refreshOpenSprints はアクティブと未来のスプリントを取得し直して、保持している一覧に反映します
+// 一覧を読み込んだ後に作られたスプリントを解決するためのもので、クライアントごとに1回だけ取得します。
+func (c *Client) refreshOpenSprints(ctx context.Context) ([]Sprint, error) {
+	c.sprintsMu.Lock()
+	defer c.sprintsMu.Unlock()
+	if c.openSprintsRefreshed {
+		return c.sprints, nil
+	}
+	open, err := c.getSprintsOfBoards(ctx, c.config.BoardIDs(), []string{"active", "future"})
+	if err != nil {
+		return nil, fmt.Errorf("スプリント一覧の取得に失敗しました: %w", err)
+	}
+	c.openSprintsRefreshed = true
+	// 取得し直したスプリントの状態を優先する
+	c.sprints = mergeSprints(open, c.sprints)
+	verbose.Printf(verbose.API, "アクティブと未来のスプリント %d 件を取得し直しました\n", len(open))
+	return c.sprints, nil
+}
+
+// LookupSprint はスプリント名に一致するスプリントを返します
+// 大文字と小文字、前後の空白の違いは無視し、同じ名前のスプリントが複数ある場合はアクティブ、未来、完了の順に優先します。
+func LookupSprint(sprints []Sprint, name string) *Sprint {
+	matched := findSprintsByName(sprints, name)
+	if len(matched) == 0 {
+		return nil
+	}
+	return &matched[0]
+}
+
+// OpenSprints はアクティブと未来のスプリントを返します
+func OpenSprints(sprints []Sprint) []Sprint {
+	var open []Sprint
+	for _, s := range sprints {
+		if s.State == "active" || s.State == "future" {
+			open = append(open, s)
+		}
+	}
+	return open
+}
+
+// Label はスプリントの状態と日付を含む表示用の文字列を返します (例: Sprint 42 (active, ends 6/20))
+func (s Sprint) Label() string {
+	switch s.State {
+	case "active":
+		if end := shortSprintDate(s.EndDate); end != "" {
+			return fmt.Sprintf("%s (active, ends %s)", s.Name, end)
+		}
+	case "future":
+		if start := shortSprintDate(s.StartDate); start != "" {
+			return fmt.Sprintf("%s (future, starts %s)", s.Name, start)
+		}
+	case "closed":
+		date := s.CompleteDate
+		if date == "" {
+			date = s.EndDate
+		}
+		if end := shortSprintDate(date); end != "" {
+			return fmt.Sprintf("%s (closed, ended %s)", s.Name, end)
+		}
+	}
+	if s.State == "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%s (%s)", s.Name, s.State)
+}
+
+// shortSprintDate はスプリントの日付を月/日の形式にします (ローカルタイムゾーン)
+func shortSprintDate(date string) string {
+	if date == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return ""
+	}
+	return t.Local().Format("1/2")
+}
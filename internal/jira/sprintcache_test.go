@@ -0,0 +1,70 @@
+package jira
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		sprint Sprint
+		want   string
+	}{
+		{name: "アクティブ", sprint: Sprint{Name: "Sprint 42", State: "active", EndDate: "2025-06-20T12:00:00.000Z"}, want: "Sprint 42 (active, ends 6/20)"},
+		{name: "未来", sprint: Sprint{Name: "Sprint 43", State: "future", StartDate: "2025-06-23T12:00:00.000Z"}, want: "Sprint 43 (future, starts 6/23)"},
+		{name: "完了", sprint: Sprint{Name: "Sprint 41", State: "closed", EndDate: "2025-06-06T12:00:00.000Z", CompleteDate: "2025-06-05T12:00:00.000Z"}, want: "Sprint 41 (closed, ended 6/5)"},
+		{name: "日付なし", sprint: Sprint{Name: "Sprint 44", State: "future"}, want: "Sprint 44 (future)"},
+		{name: "状態なし", sprint: Sprint{Name: "Sprint 45"}, want: "Sprint 45"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.sprint.Label())
+		})
+	}
+}
+
+func TestLookupSprint(t *testing.T) {
+	t.Parallel()
+
+	sprints := []Sprint{
+		{ID: 1, Name: "Sprint 1", State: "closed"},
+		{ID: 2, Name: "Sprint 2", State: "closed"},
+		{ID: 3, Name: "Sprint 2", State: "active"},
+		{ID: 4, Name: "Sprint 3", State: "future"},
+	}
+
+	assert.Equal(t, 1, LookupSprint(sprints, "Sprint 1").ID)
+	// 同じ名前の場合は完了していないスプリントを優先
+	assert.Equal(t, 3, LookupSprint(sprints, "Sprint 2").ID)
+	assert.Nil(t, LookupSprint(sprints, "Sprint 9"))
+
+	assert.Equal(t, []Sprint{sprints[2], sprints[3]}, OpenSprints(sprints))
+}
+
+func TestReadSprintCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// キャッシュがない場合
+	sprints, fetchedAt, err := ReadSprintCache(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, sprints)
+	assert.True(t, fetchedAt.IsZero())
+
+	content := `{"fetched_at":"2025-06-10T09:00:00Z","sprints":[{"id":42,"name":"Sprint 42","state":"active","originBoardId":12,"startDate":"2025-06-06T12:00:00.000Z","endDate":"2025-06-20T12:00:00.000Z","completeDate":""}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, sprintCacheFile), []byte(content), 0644))
+
+	sprints, fetchedAt, err = ReadSprintCache(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-06-10T09:00:00Z", fetchedAt.Format("2006-01-02T15:04:05Z07:00"))
+	assert.Equal(t, []Sprint{{ID: 42, Name: "Sprint 42", State: "active", BoardID: 12, StartDate: "2025-06-06T12:00:00.000Z", EndDate: "2025-06-20T12:00:00.000Z"}}, sprints)
+}
@@ -0,0 +1,255 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+const (
+	// teamFieldSchemaPrefix はAtlassian Teams (Advanced Roadmaps) のTeamフィールドのスキーマの接頭辞です
+	teamFieldSchemaPrefix = "com.atlassian.teams"
+	// teamFieldSchema はJIRA CloudのTeamフィールドのスキーマです
+	teamFieldSchema = "com.atlassian.jira.plugin.system.customfieldtypes:atlassian-team"
+)
+
+// teamCacheFile はチームの一覧を保存するキャッシュディレクトリ内のファイル名です
+const teamCacheFile = "teams.json"
+
+// TeamCacheTTL はチームの一覧のキャッシュの有効期間です
+const TeamCacheTTL = 24 * time.Hour
+
+// Team はTeamフィールドで選択できるチームです
+type Team struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// teamCache はキャッシュに保存するチームの一覧です
+type teamCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Teams     []Team    `json:"teams"`
+}
+
+// findTeamFieldID はフィールド定義からTeamフィールドのIDを探します
+// 見つからない場合は空文字を返します。
+func findTeamFieldID(fields []fieldDefinition) string {
+	for _, field := range fields {
+		if field.Custom && (strings.HasPrefix(field.Schema.Custom, teamFieldSchemaPrefix) || field.Schema.Custom == teamFieldSchema) {
+			return field.ID
+		}
+	}
+	return ""
+}
+
+// discoverTeamField はフィールド定義からTeamフィールドを動的に発見します
+// 見つからない場合はteamの取得・更新を行いません。
+func (c *Client) discoverTeamField(fields []fieldDefinition) {
+	c.teamFieldID = findTeamFieldID(fields)
+	if c.teamFieldID == "" {
+		verbose.Printf(verbose.API, "Teamフィールドが見つからないため、teamは無効になります\n")
+		return
+	}
+	verbose.Printf(verbose.API, "Teamフィールドを発見しました: %s\n", c.teamFieldID)
+}
+
+// parseTeam はIssueのカスタムフィールドからチームを取得します
+// Teamフィールドがないインスタンスや、チームが設定されていない課題ではfalseを返します。
+func parseTeam(customFields map[string]interface{}, fieldID string) (Team, bool) {
+	if fieldID == "" {
+		return Team{}, false
+	}
+	m, ok := customFields[fieldID].(map[string]interface{})
+	if !ok {
+		return Team{}, false
+	}
+	var team Team
+	// Advanced RoadmapsのTeamフィールドはIDが数値
+	switch id := m["id"].(type) {
+	case string:
+		team.ID = id
+	case float64:
+		team.ID = strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	for _, key := range []string{"name", "title"} {
+		if name, ok := m[key].(string); ok && name != "" {
+			team.Name = name
+			break
+		}
+	}
+	if team.ID == "" || team.Name == "" {
+		return Team{}, false
+	}
+	return team, true
+}
+
+// decodeTeam はTeamフィールドのチーム名をチケットに設定します
+// 取得したチームは名前の解決に使えるように覚えておきます。
+func (c *Client) decodeTeam(fields *IssueFields, t *ticket.Ticket) {
+	team, ok := parseTeam(fields.CustomFields, c.teamFieldID)
+	if !ok {
+		t.Team = ""
+		return
+	}
+	t.Team = team.Name
+	c.teamsMu.Lock()
+	defer c.teamsMu.Unlock()
+	c.seenTeams = mergeTeams(c.seenTeams, []Team{team})
+}
+
+// Teams はTeamフィールドで選択できるチームの一覧を名前順に返します
+// キャッシュディレクトリの teams.json が有効期間内であればそれを使い、古い場合は取得し直して保存します。
+// Teamフィールドがないインスタンスでは空の一覧を返します。
+func (c *Client) Teams(ctx context.Context) ([]Team, error) {
+	if c.teamFieldID == "" {
+		return nil, nil
+	}
+	c.teamsMu.Lock()
+	defer c.teamsMu.Unlock()
+	if c.teamsLoaded {
+		return mergeTeams(c.teams, c.seenTeams), nil
+	}
+
+	cacheDir := c.teamCacheDir
+	if cacheDir == "" {
+		dir, err := config.EnsureCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+		}
+		cacheDir = dir
+	}
+	if cache, ok := readTeamCache(cacheDir); ok && time.Since(cache.FetchedAt) < TeamCacheTTL {
+		c.teams, c.teamsLoaded = cache.Teams, true
+		return mergeTeams(c.teams, c.seenTeams), nil
+	}
+
+	teams, err := c.fetchTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 候補に出てこないチームも、取得したチケットに設定されていれば使えるようにする
+	teams = mergeTeams(teams, c.seenTeams)
+	c.teams, c.teamsLoaded = teams, true
+	data, err := json.MarshalIndent(teamCache{FetchedAt: time.Now(), Teams: teams}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("チームのキャッシュの作成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, teamCacheFile), data, 0644); err != nil {
+		verbose.Printf(verbose.Cache, "警告: チームのキャッシュの保存に失敗しました: %v\n", err)
+	}
+	return teams, nil
+}
+
+// readTeamCache は teams.json を読み込みます (ない場合や壊れている場合はfalse)
+func readTeamCache(cacheDir string) (teamCache, bool) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, teamCacheFile))
+	if err != nil {
+		return teamCache{}, false
+	}
+	var cache teamCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		verbose.Printf(verbose.Cache, "チームのキャッシュを読み込めないため取得し直します: %v\n", err)
+		return teamCache{}, false
+	}
+	return cache, true
+}
+
+// fetchTeams はJQLの入力候補のAPIからTeamフィールドで選択できるチームを取得します
+func (c *Client) fetchTeams(ctx context.Context) ([]Team, error) {
+	fieldName := "cf[" + strings.TrimPrefix(c.teamFieldID, "customfield_") + "]"
+	endpoint := fmt.Sprintf("%s/rest/api/3/jql/autocompletedata/suggestions?fieldName=%s", c.config.Server, url.QueryEscape(fieldName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	var resp struct {
+		Results []struct {
+			Value       string `json:"value"`
+			DisplayName string `json:"displayName"`
+		} `json:"results"`
+	}
+	if err := c.getJSON(req, &resp); err != nil {
+		return nil, fmt.Errorf("チームの一覧の取得に失敗しました: %v", err)
+	}
+	teams := make([]Team, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Value == "" || r.DisplayName == "" {
+			continue
+		}
+		teams = append(teams, Team{ID: r.Value, Name: r.DisplayName})
+	}
+	verbose.Printf(verbose.API, "チームの一覧: %d 件\n", len(teams))
+	return teams, nil
+}
+
+// mergeTeams はIDの重複を除いてチームの一覧をまとめ、名前順に並べます
+func mergeTeams(lists ...[]Team) []Team {
+	seen := make(map[string]bool)
+	var merged []Team
+	for _, list := range lists {
+		for _, team := range list {
+			if seen[team.ID] {
+				continue
+			}
+			seen[team.ID] = true
+			merged = append(merged, team)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	return merged
+}
+
+// findTeamByName はチーム名 (大文字と小文字は区別しない) に一致するチームを探します
+// 見つからない場合は有効なチーム名の一覧を含むエラーを返します。
+func findTeamByName(teams []Team, name string) (Team, error) {
+	want := strings.TrimSpace(name)
+	for _, team := range teams {
+		if strings.EqualFold(team.Name, want) {
+			return team, nil
+		}
+	}
+	if len(teams) == 0 {
+		return Team{}, fmt.Errorf("チーム %q が見つかりません (選択できるチームを取得できませんでした)", name)
+	}
+	names := make([]string, len(teams))
+	for i, team := range teams {
+		names[i] = team.Name
+	}
+	return Team{}, fmt.Errorf("チーム %q が見つかりません (有効なチーム: %s)", name, strings.Join(names, ", "))
+}
+
+// addTeamFieldToUpdate はチーム名をIDに解決して更新フィールドに追加します
+// teamが空の場合はチームを外します。Teamフィールドがないインスタンスでは何もしません。
+func (c *Client) addTeamFieldToUpdate(fields map[string]interface{}, t ticket.Ticket) error {
+	if c.teamFieldID == "" {
+		verbose.Printf(verbose.API, "Teamフィールドが見つからないため、teamの更新をスキップします\n")
+		return nil
+	}
+	if t.Team == "" {
+		fields[c.teamFieldID] = nil
+		return nil
+	}
+	teams, err := c.Teams(context.Background())
+	if err != nil {
+		return err
+	}
+	team, err := findTeamByName(teams, t.Team)
+	if err != nil {
+		return err
+	}
+	fields[c.teamFieldID] = team.ID
+	return nil
+}
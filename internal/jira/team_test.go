@@ -0,0 +1,160 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTeamFieldID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		fields string
+		want   string
+	}{
+		{
+			name: "Atlassian TeamsのTeamフィールド",
+			fields: `[
+				{"id": "summary", "name": "Summary", "custom": false, "schema": {"type": "string"}},
+				{"id": "customfield_10001", "name": "Team", "custom": true, "schema": {"type": "team", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:atlassian-team"}}
+			]`,
+			want: "customfield_10001",
+		},
+		{
+			name:   "Advanced RoadmapsのTeamフィールド",
+			fields: `[{"id": "customfield_10100", "name": "Team", "custom": true, "schema": {"type": "any", "custom": "com.atlassian.teams:rm-teams-custom-field-team"}}]`,
+			want:   "customfield_10100",
+		},
+		{
+			name:   "Teamフィールドがない",
+			fields: `[{"id": "customfield_10200", "name": "Team", "custom": true, "schema": {"type": "string", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:textfield"}}]`,
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var fields []fieldDefinition
+			assert.NoError(t, json.Unmarshal([]byte(tt.fields), &fields))
+			assert.Equal(t, tt.want, findTeamFieldID(fields))
+		})
+	}
+}
+
+func TestParseTeam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fieldID string
+		fields  string
+		want    Team
+		wantOK  bool
+	}{
+		{
+			name:    "インスタンスにTeamフィールドがない",
+			fieldID: "",
+			fields:  `{"customfield_10001": {"id": "abc", "name": "Platform"}}`,
+		},
+		{
+			name:    "チームが設定されていない",
+			fieldID: "customfield_10001",
+			fields:  `{"customfield_10001": null}`,
+		},
+		{
+			name:    "Atlassian Teamsのチーム",
+			fieldID: "customfield_10001",
+			fields:  `{"customfield_10001": {"id": "36885b3c-1bf0-4f85-a357-c5b858c31de4", "name": "Platform", "isVisible": true}}`,
+			want:    Team{ID: "36885b3c-1bf0-4f85-a357-c5b858c31de4", Name: "Platform"},
+			wantOK:  true,
+		},
+		{
+			name:    "Advanced RoadmapsのチームはIDが数値で名前がtitle",
+			fieldID: "customfield_10100",
+			fields:  `{"customfield_10100": {"id": 12, "title": "Mobile", "isShared": true}}`,
+			want:    Team{ID: "12", Name: "Mobile"},
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var customFields map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.fields), &customFields))
+			got, ok := parseTeam(customFields, tt.fieldID)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAddTeamFieldToUpdate(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/rest/api/3/jql/autocompletedata/suggestions", r.URL.Path)
+		assert.Equal(t, "cf[10001]", r.URL.Query().Get("fieldName"))
+		_, _ = w.Write([]byte(`{"results": [
+			{"value": "team-platform", "displayName": "Platform"},
+			{"value": "team-mobile", "displayName": "Mobile"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	c := &Client{config: &config.Config{Server: srv.URL}, teamFieldID: "customfield_10001", teamCacheDir: dir}
+	// 候補に出てこないチームも、取得したチケットに設定されていれば使える
+	c.decodeTeam(&IssueFields{CustomFields: map[string]interface{}{"customfield_10001": map[string]interface{}{"id": "team-data", "name": "Data"}}}, &ticket.Ticket{})
+
+	tests := []struct {
+		name    string
+		team    string
+		want    map[string]interface{}
+		wantErr string
+	}{
+		{name: "チーム名をIDに解決する (大文字と小文字は区別しない)", team: "platform", want: map[string]interface{}{"customfield_10001": "team-platform"}},
+		{name: "取得したチケットのチーム", team: "Data", want: map[string]interface{}{"customfield_10001": "team-data"}},
+		{name: "チームを外す", team: "", want: map[string]interface{}{"customfield_10001": nil}},
+		{name: "打ち間違いは有効なチーム名を示す", team: "Platfrom", wantErr: `チーム "Platfrom" が見つかりません (有効なチーム: Data, Mobile, Platform)`},
+	}
+	for _, tt := range tests {
+		fields := map[string]interface{}{}
+		err := c.addTeamFieldToUpdate(fields, ticket.Ticket{Key: "PRJ-1", Team: tt.team})
+		if tt.wantErr != "" {
+			assert.EqualError(t, err, tt.wantErr, tt.name)
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, fields, tt.name)
+	}
+
+	// 一覧はクライアントごとに1回だけ取得し、キャッシュに保存する
+	assert.Equal(t, int32(1), requests.Load())
+	_, err := os.Stat(filepath.Join(dir, teamCacheFile))
+	assert.NoError(t, err)
+	fresh := &Client{config: &config.Config{Server: srv.URL}, teamFieldID: "customfield_10001", teamCacheDir: dir}
+	teams, err := fresh.Teams(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, []Team{{ID: "team-data", Name: "Data"}, {ID: "team-mobile", Name: "Mobile"}, {ID: "team-platform", Name: "Platform"}}, teams)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Teamフィールドがないインスタンスでは何もしない
+	inert := &Client{config: &config.Config{Server: srv.URL}}
+	fields := map[string]interface{}{}
+	assert.NoError(t, inert.addTeamFieldToUpdate(fields, ticket.Ticket{Key: "PRJ-1", Team: "Platform"}))
+	assert.Empty(t, fields)
+}
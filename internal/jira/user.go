@@ -0,0 +1,199 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/qawatake/tkt/internal/config"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// userCacheFile はaccountIdと表示名の対応を保存するキャッシュディレクトリ内のファイル名です
+const userCacheFile = "users.json"
+
+// AssigneeMe はフロントマターで自分を担当者にする場合の値です
+const AssigneeMe = "me"
+
+// User はJIRAのユーザーです
+type User struct {
+	AccountID    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+// userCacheMu はpushの並列実行でユーザーのキャッシュを同時に書き込まないためのロックです
+var userCacheMu sync.Mutex
+
+// SearchUsers は名前やメールアドレスでユーザーを検索します
+func (c *Client) SearchUsers(query string) ([]User, error) {
+	req, err := http.NewRequest(http.MethodGet,
+		fmt.Sprintf("%s/rest/api/3/user/search?query=%s", c.config.Server, url.QueryEscape(query)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	var users []User
+	if err := c.getJSON(req, &users); err != nil {
+		return nil, fmt.Errorf("ユーザーの検索に失敗しました: %v", err)
+	}
+	return users, nil
+}
+
+// Myself はAPIトークンのユーザーを返します
+func (c *Client) Myself() (*User, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/rest/api/3/myself", c.config.Server), nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	var user User
+	if err := c.getJSON(req, &user); err != nil {
+		return nil, fmt.Errorf("ユーザー情報の取得に失敗しました: %v", err)
+	}
+	return &user, nil
+}
+
+// getJSON はGETリクエストを送信し、レスポンスのJSONをvに読み込みます
+func (c *Client) getJSON(req *http.Request, v interface{}) error {
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ResolveAssignee はフロントマターの担当者 (表示名、メールアドレスまたはme) をaccountIdに解決します
+// 解決した対応はキャッシュディレクトリに保存し、次回からはAPIを呼ばずに使います。
+// 候補が複数ある場合は候補の一覧を含むエラーを返します。
+func (c *Client) ResolveAssignee(name string) (string, error) {
+	userCacheMu.Lock()
+	defer userCacheMu.Unlock()
+
+	cacheDir, err := config.EnsureCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %v", err)
+	}
+	cached, err := readUserCache(cacheDir)
+	if err != nil {
+		verbose.Printf(verbose.API, "%v\n", err)
+	}
+
+	if strings.EqualFold(name, AssigneeMe) {
+		me, err := c.Myself()
+		if err != nil {
+			return "", err
+		}
+		writeUserCache(cacheDir, addUser(cached, *me))
+		return me.AccountID, nil
+	}
+
+	if user, err := matchUser(cached, name); err != nil {
+		return "", err
+	} else if user != nil {
+		return user.AccountID, nil
+	}
+
+	found, err := c.SearchUsers(name)
+	if err != nil {
+		return "", err
+	}
+	user, err := matchUser(found, name)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		if len(found) != 1 {
+			return "", fmt.Errorf("担当者 %q に一致するユーザーが見つかりません%s", name, userCandidates(found))
+		}
+		user = &found[0]
+	}
+	writeUserCache(cacheDir, addUser(cached, *user))
+	verbose.Printf(verbose.API, "担当者を解決しました: %s → %s\n", name, user.AccountID)
+	return user.AccountID, nil
+}
+
+// matchUser は表示名またはメールアドレスが一致するユーザーを返します (大文字小文字は区別しない)
+// 一致するユーザーがいない場合はnil、複数いる場合は候補の一覧を含むエラーを返します。
+func matchUser(users []User, name string) (*User, error) {
+	var matched []User
+	for _, u := range users {
+		if strings.EqualFold(u.DisplayName, name) || (u.EmailAddress != "" && strings.EqualFold(u.EmailAddress, name)) {
+			matched = append(matched, u)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, fmt.Errorf("担当者 %q に一致するユーザーが複数あります%s", name, userCandidates(matched))
+	}
+}
+
+// userCandidates はエラーメッセージに添えるユーザーの候補の一覧です
+func userCandidates(users []User) string {
+	if len(users) == 0 {
+		return ""
+	}
+	var lines []string
+	for _, u := range users {
+		line := fmt.Sprintf("  - %s (accountId: %s)", u.DisplayName, u.AccountID)
+		if u.EmailAddress != "" {
+			line = fmt.Sprintf("  - %s <%s> (accountId: %s)", u.DisplayName, u.EmailAddress, u.AccountID)
+		}
+		lines = append(lines, line)
+	}
+	return "。候補:\n" + strings.Join(lines, "\n")
+}
+
+// addUser はユーザーをaccountIdで重複なく追加します
+func addUser(users []User, user User) []User {
+	for i, u := range users {
+		if u.AccountID == user.AccountID {
+			users[i] = user
+			return users
+		}
+	}
+	return append(users, user)
+}
+
+// readUserCache はキャッシュに保存されたユーザーの一覧を返します (キャッシュがない場合はnil)
+func readUserCache(cacheDir string) ([]User, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, userCacheFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーのキャッシュの読み込みに失敗しました: %v", err)
+	}
+	var users []User
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("ユーザーのキャッシュの解析に失敗しました: %v", err)
+	}
+	return users, nil
+}
+
+// writeUserCache はユーザーの一覧をキャッシュに保存します
+// キャッシュは次回の解決を速くするためのものなので、失敗しても警告のみです。
+func writeUserCache(cacheDir string, users []User) {
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(cacheDir, userCacheFile), data, 0644)
+	}
+	if err != nil {
+		verbose.Printf(verbose.API, "警告: ユーザーのキャッシュの保存に失敗しました: %v\n", err)
+	}
+}
@@ -0,0 +1,49 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchUser(t *testing.T) {
+	t.Parallel()
+
+	users := []User{
+		{AccountID: "1", DisplayName: "Taro Yamada", EmailAddress: "taro@example.com"},
+		{AccountID: "2", DisplayName: "Hanako Suzuki"},
+		{AccountID: "3", DisplayName: "Hanako Suzuki", EmailAddress: "hanako2@example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "表示名が一致", query: "Taro Yamada", want: "1"},
+		{name: "大文字小文字を区別しない", query: "taro yamada", want: "1"},
+		{name: "メールアドレスが一致", query: "hanako2@example.com", want: "3"},
+		{name: "一致しない", query: "Jiro", want: ""},
+		{name: "複数一致", query: "Hanako Suzuki", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user, err := matchUser(users, tt.query)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "accountId: 2")
+				assert.ErrorContains(t, err, "accountId: 3")
+				return
+			}
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, user)
+				return
+			}
+			assert.Equal(t, tt.want, user.AccountID)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/errors"
+)
+
+// FetchVersions はプロジェクトのバージョン名を取得します
+func (c *Client) FetchVersions() ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, c.config.Server+"/rest/api/3/project/"+c.config.Project.Key+"/versions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("バージョンの取得に失敗しました (status: %d)", resp.StatusCode)
+	}
+
+	var versions []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+
+	names := make([]string, 0, len(versions))
+	for _, v := range versions {
+		names = append(names, v.Name)
+	}
+	return names, nil
+}
+
+// unknownVersions はプロジェクトに存在しないバージョン名を返します
+func unknownVersions(names []string, known []string) []string {
+	var unknown []string
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// withFixVersionsHint は修正バージョンが原因のエラーの場合に、プロジェクトのバージョンの一覧をエラーに追加します
+func (c *Client) withFixVersionsHint(err error, fixVersions []string) error {
+	var apiErr *apiError
+	if len(fixVersions) == 0 || !errors.As(err, &apiErr) || !hasFieldError(apiErr.body, "fixVersions") {
+		return err
+	}
+	names, fetchErr := c.FetchVersions()
+	if fetchErr != nil {
+		return err
+	}
+	unknown := unknownVersions(fixVersions, names)
+	if len(unknown) == 0 {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w\n修正バージョン %s はプロジェクトに存在しません (バージョンが登録されていません)", err, strings.Join(unknown, ", "))
+	}
+	return fmt.Errorf("%w\n修正バージョン %s はプロジェクトに存在しません。指定できるバージョン: %s", err, strings.Join(unknown, ", "), strings.Join(names, ", "))
+}
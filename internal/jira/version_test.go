@@ -0,0 +1,30 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownVersions(t *testing.T) {
+	t.Parallel()
+
+	known := []string{"1.0.0", "1.1.0"}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "すべて存在する", names: []string{"1.0.0", "1.1.0"}, want: nil},
+		{name: "存在しないバージョン", names: []string{"1.0.0", "2.0.0"}, want: []string{"2.0.0"}},
+		{name: "大文字小文字は区別する", names: []string{"V1.0.0"}, want: []string{"V1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, unknownVersions(tt.names, known))
+		})
+	}
+}
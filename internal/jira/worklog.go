@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/adf"
+	"github.com/qawatake/tkt/internal/verbose"
+)
+
+// Worklog はチケットの作業ログです
+type Worklog struct {
+	ID        string
+	Author    string
+	Started   time.Time
+	TimeSpent time.Duration
+	Comment   string
+}
+
+// worklogResponse はJIRAのAPIが返す作業ログです
+type worklogResponse struct {
+	ID     string `json:"id"`
+	Author *struct {
+		Name string `json:"displayName"`
+	} `json:"author"`
+	Started          string   `json:"started"`
+	TimeSpentSeconds int64    `json:"timeSpentSeconds"`
+	Comment          *adf.ADF `json:"comment"`
+}
+
+// toWorklog は作業ログをlocのタイムゾーンに変換します
+func (w worklogResponse) toWorklog(loc *time.Location) (Worklog, error) {
+	started, err := time.Parse(jiraTimestampLayout, w.Started)
+	if err != nil {
+		return Worklog{}, fmt.Errorf("作業ログの開始日時のパースに失敗しました: %v", err)
+	}
+	worklog := Worklog{
+		ID:        w.ID,
+		Author:    "不明",
+		Started:   started.In(loc),
+		TimeSpent: time.Duration(w.TimeSpentSeconds) * time.Second,
+	}
+	if w.Author != nil {
+		worklog.Author = w.Author.Name
+	}
+	if w.Comment != nil {
+		worklog.Comment = strings.TrimSpace(adf.NewTranslator(w.Comment, adf.NewJiraMarkdownTranslator()).Translate())
+	}
+	return worklog, nil
+}
+
+// textADF はテキストを段落に分けたADFのドキュメントにします (空行のない行ごとに1段落)
+func textADF(text string) map[string]interface{} {
+	var paragraphs []interface{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		paragraphs = append(paragraphs, map[string]interface{}{
+			"type":    "paragraph",
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": line}},
+		})
+	}
+	return map[string]interface{}{"type": "doc", "version": 1, "content": paragraphs}
+}
+
+// newWorklogRequest は作業ログ作成のリクエストボディを作ります
+// startedは設定ファイルのtimezoneでの日時として送信します。
+func newWorklogRequest(started time.Time, d time.Duration, comment string) map[string]interface{} {
+	body := map[string]interface{}{
+		"started":          started.Format(jiraTimestampLayout),
+		"timeSpentSeconds": int64(d / time.Second),
+	}
+	if comment != "" {
+		body["comment"] = textADF(comment)
+	}
+	return body
+}
+
+// AddWorklog はチケットに作業ログを追加し、作成された作業ログのIDを返します
+// 開始日時は現在時刻 (設定ファイルのtimezone) です。
+func (c *Client) AddWorklog(key string, d time.Duration, comment string) (string, error) {
+	if d < time.Minute {
+		return "", fmt.Errorf("作業時間は1分以上で指定してください")
+	}
+	loc, err := c.config.Location()
+	if err != nil {
+		return "", err
+	}
+	jsonBody, err := json.Marshal(newWorklogRequest(time.Now().In(loc), d, comment))
+	if err != nil {
+		return "", fmt.Errorf("リクエストボディの作成に失敗しました: %v", err)
+	}
+	verbose.Tracef(verbose.API, "作業ログ追加リクエスト (%s): %s\n", key, string(jsonBody))
+
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/rest/api/3/issue/%s/worklog", c.config.Server, key),
+		bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("レスポンスの読み込みに失敗しました: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("チケット %s が見つかりません", key)
+	case http.StatusForbidden:
+		return "", fmt.Errorf("チケット %s に作業ログを追加する権限がありません (タイムトラッキングが無効な可能性があります)", key)
+	default:
+		return "", fmt.Errorf("作業ログの追加に失敗しました (status: %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return "", fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+	}
+	return result.ID, nil
+}
+
+// Worklogs はチケットの作業ログを古い順に取得します (日時は設定ファイルのtimezone)
+func (c *Client) Worklogs(key string) ([]Worklog, error) {
+	loc, err := c.config.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	var worklogs []Worklog
+	startAt := 0
+	for {
+		req, err := http.NewRequest(http.MethodGet,
+			fmt.Sprintf("%s/rest/api/3/issue/%s/worklog?startAt=%d", c.config.Server, key, startAt), nil)
+		if err != nil {
+			return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %v", err)
+		}
+		resp, err := c.doRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("HTTPリクエストの送信に失敗しました: %w", err)
+		}
+
+		var page struct {
+			StartAt  int               `json:"startAt"`
+			Total    int               `json:"total"`
+			Worklogs []worklogResponse `json:"worklogs"`
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			err = json.NewDecoder(resp.Body).Decode(&page)
+			resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("レスポンスの解析に失敗しました: %v", err)
+			}
+		case http.StatusNotFound:
+			resp.Body.Close()
+			return nil, fmt.Errorf("チケット %s が見つかりません", key)
+		default:
+			resp.Body.Close()
+			return nil, fmt.Errorf("作業ログの取得に失敗しました (status: %d)", resp.StatusCode)
+		}
+
+		for _, w := range page.Worklogs {
+			worklog, err := w.toWorklog(loc)
+			if err != nil {
+				return nil, err
+			}
+			worklogs = append(worklogs, worklog)
+		}
+		startAt += len(page.Worklogs)
+		if len(page.Worklogs) == 0 || startAt >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
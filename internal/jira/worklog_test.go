@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qawatake/tkt/internal/adf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorklogRequest(t *testing.T) {
+	t.Parallel()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	started := time.Date(2025, 6, 1, 19, 6, 22, 513000000, jst)
+
+	tests := []struct {
+		name    string
+		comment string
+		want    map[string]interface{}
+	}{
+		{
+			name: "コメントなし",
+			want: map[string]interface{}{
+				"started":          "2025-06-01T19:06:22.513+0900",
+				"timeSpentSeconds": int64(5400),
+			},
+		},
+		{
+			name:    "コメントあり",
+			comment: "ペアプロ\n\nレビュー",
+			want: map[string]interface{}{
+				"started":          "2025-06-01T19:06:22.513+0900",
+				"timeSpentSeconds": int64(5400),
+				"comment": map[string]interface{}{"type": "doc", "version": 1, "content": []interface{}{
+					map[string]interface{}{"type": "paragraph", "content": []interface{}{map[string]interface{}{"type": "text", "text": "ペアプロ"}}},
+					map[string]interface{}{"type": "paragraph", "content": []interface{}{map[string]interface{}{"type": "text", "text": "レビュー"}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, newWorklogRequest(started, 90*time.Minute, tt.comment))
+		})
+	}
+}
+
+func TestWorklogResponseToWorklog(t *testing.T) {
+	t.Parallel()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	res := worklogResponse{
+		ID:               "10001",
+		Started:          "2025-06-01T10:00:00.000+0000",
+		TimeSpentSeconds: 3600,
+		Comment: &adf.ADF{Version: 1, DocType: "doc", Content: []*adf.Node{
+			{NodeType: adf.NodeParagraph, Content: []*adf.Node{{NodeType: adf.ChildNodeText, NodeValue: adf.NodeValue{Text: "ペアプロ"}}}},
+		}},
+	}
+
+	got, err := res.toWorklog(jst)
+	assert.NoError(t, err)
+	assert.Equal(t, "10001", got.ID)
+	assert.Equal(t, "不明", got.Author)
+	assert.Equal(t, time.Hour, got.TimeSpent)
+	assert.Equal(t, "2025-06-01 19:00", got.Started.Format("2006-01-02 15:04"))
+	assert.Equal(t, "ペアプロ", got.Comment)
+}
@@ -18,6 +18,19 @@ func ToJiraMD(md string) string {
 	return string(renderer.Render(r.Parse([]byte(md))))
 }
 
+// ToHTML renders CommonMark as an HTML fragment.
+// Raw HTML in the input is dropped and unsafe links are not rendered as links.
+func ToHTML(md string) string {
+	if md == "" {
+		return md
+	}
+
+	renderer := bf.NewHTMLRenderer(bf.HTMLRendererParameters{
+		Flags: bf.SkipHTML | bf.Safelink | bf.NofollowLinks | bf.NoreferrerLinks,
+	})
+	return string(bf.Run([]byte(md), bf.WithRenderer(renderer), bf.WithExtensions(bf.CommonExtensions)))
+}
+
 // FromJiraMD translates Jira flavored markdown to CommonMark.
 func FromJiraMD(jfm string) string {
 	return jirawiki.Parse(jfm)
@@ -4,6 +4,7 @@ package md
 import (
 	"testing"
 
+	"github.com/qawatake/tkt/internal/pkg/markdown"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -108,3 +109,61 @@ func main\(\) {
 
 	assert.Equal(t, expected, ToJiraMD(jfm))
 }
+
+// pull/pushを繰り返しても本文が変化しないことを確認します。
+func TestRoundTripIdempotent(t *testing.T) {
+	roundTrip := func(s string) string {
+		return markdown.ConvertJiraToMarkdown(ToJiraMD(s))
+	}
+
+	corpus := map[string]string{
+		"git log": `* 3f2a1bc fix: handle nil pointer
+* 9e8d7c6 feat: add *experimental* flag
+* 1a2b3c4 Merge branch 'main'`,
+		"git log graph": "```" + `
+* 3f2a1bc (HEAD -> main) fix
+|\
+| * 9e8d7c6 feat
+|/
+* 1a2b3c4 init
+` + "```",
+		"ascii table": `+------+-------+
+| a*b  | c * d |
++------+-------+`,
+		"markdown table": `| name | expr |
+| --- | --- |
+| mul | 2 * 3 * 4 |
+| glob | *.go |`,
+		"literal asterisks": `2 * 3 * 4 = 24
+***** rating *****
+foo*bar*baz`,
+		"formatting": `**bold** and *italic* and ~~strike~~ and ` + "`code`" + `
+
+- item1
+- item2
+  - nested`,
+		"release notes": `## v1.2.0
+
+* **Breaking**: rename ` + "`--foo`" + ` to ` + "`--bar`" + `
+* fix ` + "`a*b`" + ` parsing
+* snake_case_name and 2025-01-02-release`,
+	}
+
+	for name, input := range corpus {
+		t.Run(name, func(t *testing.T) {
+			once := roundTrip(input)
+			twice := roundTrip(once)
+			assert.Equal(t, once, twice)
+		})
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	got := ToHTML("## 見出し\n\n- [x] done\n\n[link](https://example.com) ![img](assets/PRJ-1/a.png)\n\n<script>alert(1)</script>\n\n[bad](javascript:alert(1))\n")
+	assert.Contains(t, got, "<h2>見出し</h2>")
+	assert.Contains(t, got, `<a href="https://example.com" rel="nofollow noreferrer">link</a>`)
+	assert.Contains(t, got, `<img src="assets/PRJ-1/a.png" alt="img" />`)
+	assert.NotContains(t, got, "<script>")
+	assert.NotContains(t, got, `href="javascript:`)
+	assert.Equal(t, "", ToHTML(""))
+}
@@ -2,15 +2,29 @@ package markdown
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 // CreateFrontMatter はマップからYAMLフロントマターを作成します
-func CreateFrontMatter(data map[string]interface{}) string {
+// orderに含まれるキーはその順に、それ以外のキーは名前順に出力するので、同じ内容なら常に同じ出力になります。
+func CreateFrontMatter(data map[string]interface{}, order ...string) string {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range orderedKeys(data, order) {
+		var k, v yaml.Node
+		if err := k.Encode(key); err != nil {
+			return ""
+		}
+		if err := v.Encode(data[key]); err != nil {
+			return ""
+		}
+		node.Content = append(node.Content, &k, &v)
+	}
+
 	// マップをYAMLに変換
-	yamlBytes, err := yaml.Marshal(data)
+	yamlBytes, err := yaml.Marshal(node)
 	if err != nil {
 		return ""
 	}
@@ -19,6 +33,26 @@ func CreateFrontMatter(data map[string]interface{}) string {
 	return fmt.Sprintf("---\n%s---\n\n", string(yamlBytes))
 }
 
+// orderedKeys はorderにあるキーをその順に並べ、残りのキーを名前順に続けます
+func orderedKeys(data map[string]interface{}, order []string) []string {
+	keys := make([]string, 0, len(data))
+	seen := make(map[string]bool, len(order))
+	for _, key := range order {
+		if _, ok := data[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	var rest []string
+	for key := range data {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
 // ParseFrontMatter はマークダウン文字列からフロントマターと本文を抽出します
 func ParseFrontMatter(content string) (map[string]interface{}, string, error) {
 	// フロントマターの開始と終了を検出
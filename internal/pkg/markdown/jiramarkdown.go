@@ -1,7 +1,9 @@
 package markdown
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -15,8 +17,15 @@ func ConvertJiraToMarkdown(input string) string {
 	content := strings.ReplaceAll(input, "\r\n", "\n")
 	content = strings.ReplaceAll(content, "\r", "\n")
 
+	// 変換後のテキストを再度変換しても結果が変わらないように、
+	// コードやエスケープされた文字はプレースホルダに退避してから変換します。
+	p := &placeholders{}
+
 	// コードブロック変換（最初に処理してコードブロック内のマークアップを保護）
-	content = convertCodeBlocks(content)
+	content = convertCodeBlocks(content, p)
+
+	// エスケープされた文字 (\* など) は装飾として解釈しない
+	content = protectEscapes(content, p)
 
 	// 見出し変換
 	content = convertHeadings(content)
@@ -39,7 +48,41 @@ func ConvertJiraToMarkdown(input string) string {
 	// テーブル変換
 	content = convertTables(content)
 
-	return content
+	return p.restore(content)
+}
+
+// placeholders は変換対象から除外する文字列を一時的に退避します
+type placeholders struct {
+	values []string
+}
+
+// placeholderRe はプレースホルダを表す正規表現です (私用領域の文字で囲むため本文と衝突しない)
+var placeholderRe = regexp.MustCompile("\uE000(\\d+)\uE001")
+
+// add は文字列を退避し、代わりに埋め込むプレースホルダを返します
+func (p *placeholders) add(s string) string {
+	p.values = append(p.values, s)
+	return fmt.Sprintf("\uE000%d\uE001", len(p.values)-1)
+}
+
+// restore はプレースホルダを元の文字列に戻します
+func (p *placeholders) restore(content string) string {
+	return placeholderRe.ReplaceAllStringFunc(content, func(match string) string {
+		i, err := strconv.Atoi(placeholderRe.FindStringSubmatch(match)[1])
+		if err != nil || i >= len(p.values) {
+			return match
+		}
+		return p.values[i]
+	})
+}
+
+// escapeRe はJIRA記法でエスケープされた記号にマッチします
+var escapeRe = regexp.MustCompile(`\\[!-/:-@\[-` + "`" + `{-~]`)
+
+// protectEscapes はエスケープされた記号をプレースホルダに退避します
+// JIRA記法とMarkdownはどちらもバックスラッシュで記号をエスケープするため、そのまま残します。
+func protectEscapes(content string, p *placeholders) string {
+	return escapeRe.ReplaceAllStringFunc(content, p.add)
 }
 
 // convertHeadings は見出しを変換します (h1. -> #, h2. -> ## など)
@@ -64,7 +107,8 @@ func convertHeadings(content string) string {
 }
 
 // convertCodeBlocks はコードブロックを変換します
-func convertCodeBlocks(content string) string {
+// 変換後のコードブロックは以降の変換で書き換えられないようにプレースホルダに退避します。
+func convertCodeBlocks(content string, p *placeholders) string {
 	// {code:language}...{code} -> ```language...```
 	codeBlockRe := regexp.MustCompile(`(?s)\{code(?::([^}]*))?\}(.*?)\{code\}`)
 	content = codeBlockRe.ReplaceAllStringFunc(content, func(match string) string {
@@ -77,64 +121,71 @@ func convertCodeBlocks(content string) string {
 		if len(parts) > 2 {
 			code = strings.TrimSpace(parts[2])
 		}
-		return "```" + language + "\n" + code + "\n```"
+		return p.add("```" + language + "\n" + code + "\n```")
 	})
 
 	// {noformat}...{noformat} -> ```...```
 	noFormatRe := regexp.MustCompile(`(?s)\{noformat\}(.*?)\{noformat\}`)
-	content = noFormatRe.ReplaceAllString(content, "```\n$1\n```")
+	content = noFormatRe.ReplaceAllStringFunc(content, func(match string) string {
+		parts := noFormatRe.FindStringSubmatch(match)
+		return p.add("```\n" + parts[1] + "\n```")
+	})
+
+	// インラインコード: {{text}} -> `text`
+	// Markdownのコードスパン内ではエスケープが解釈されないため、エスケープを外します。
+	inlineCodeRe := regexp.MustCompile(`\{\{([^}]+)\}\}`)
+	content = inlineCodeRe.ReplaceAllStringFunc(content, func(match string) string {
+		parts := inlineCodeRe.FindStringSubmatch(match)
+		code := escapeRe.ReplaceAllStringFunc(parts[1], func(s string) string {
+			return s[1:]
+		})
+		return p.add("`" + code + "`")
+	})
 
 	return content
 }
 
 // convertTextFormatting はテキスト装飾を変換します
 func convertTextFormatting(content string) string {
-	// 太字: *text* -> **text** (ただし、リストマーカーでない場合のみ)
-	boldRe := regexp.MustCompile(`(?m)(?:^|[^*\n])\*([^*\n]+)\*(?:[^*\n]|$)`)
-	content = boldRe.ReplaceAllStringFunc(content, func(match string) string {
-		// マッチした内容を分析
-		re := regexp.MustCompile(`(.*?)\*([^*\n]+)\*(.*)`)
-		parts := re.FindStringSubmatch(match)
-		if len(parts) == 4 {
-			return parts[1] + "**" + parts[2] + "**" + parts[3]
-		}
-		return match
-	})
+	// 太字: *text* -> **text**
+	content = convertInlineMarkup(content, "*", "**")
 
 	// 斜体: _text_ -> *text*
-	italicRe := regexp.MustCompile(`_([^_\n]+)_`)
-	content = italicRe.ReplaceAllString(content, "*$1*")
+	content = convertInlineMarkup(content, "_", "*")
 
 	// 下線: +text+ -> __text__ (Markdownに下線はないので太字で代用)
-	underlineRe := regexp.MustCompile(`\+([^+\n]+)\+`)
-	content = underlineRe.ReplaceAllString(content, "__$1__")
-
-	// 取り消し線: -text- -> ~~text~~ (ただし、リストマーカーでない場合のみ)
-	strikeRe := regexp.MustCompile(`(?m)(?:^|[^-\n])-([^-\n]+)-(?:[^-\n]|$)`)
-	content = strikeRe.ReplaceAllStringFunc(content, func(match string) string {
-		re := regexp.MustCompile(`(.*?)-([^-\n]+)-(.*)`)
-		parts := re.FindStringSubmatch(match)
-		if len(parts) == 4 {
-			return parts[1] + "~~" + parts[2] + "~~" + parts[3]
-		}
-		return match
-	})
-
-	// インラインコード: {{text}} -> `text`
-	inlineCodeRe := regexp.MustCompile(`\{\{([^}]+)\}\}`)
-	content = inlineCodeRe.ReplaceAllString(content, "`$1`")
+	content = convertInlineMarkup(content, "+", "__")
 
 	// 上付き文字: ^text^ -> text (Markdownには上付きがないので削除)
-	supRe := regexp.MustCompile(`\^([^^]+)\^`)
-	content = supRe.ReplaceAllString(content, "$1")
+	content = convertInlineMarkup(content, "^", "")
 
 	// 下付き文字: ~text~ -> text (Markdownには下付きがないので削除)
-	subRe := regexp.MustCompile(`~([^~]+)~`)
-	content = subRe.ReplaceAllString(content, "$1")
+	// 取り消し線の ~~ を壊さないように、取り消し線より先に処理します。
+	content = convertInlineMarkup(content, "~", "")
+
+	// 取り消し線: -text- -> ~~text~~
+	content = convertInlineMarkup(content, "-", "~~")
 
 	return content
 }
 
+// convertInlineMarkup は記号で囲まれたテキスト装飾をMarkdownの記号に置き換えます
+// JIRAと同様に、開始記号の直後と終了記号の直前が空白でなく、
+// 記号の外側が英数字でない場合のみ装飾とみなします。
+// これにより "2 * 3 * 4" やリストマーカー、既に変換済みの "**text**" は書き換えません。
+func convertInlineMarkup(content, marker, replacement string) string {
+	m := regexp.QuoteMeta(marker)
+	re := regexp.MustCompile(`(^|[^A-Za-z0-9` + m + `])` + m + `([^\s` + m + `](?:[^` + m + `\n]*[^\s` + m + `])?)` + m + `($|[^A-Za-z0-9` + m + `])`)
+	// 隣接する装飾は前後の区切り文字を共有するため、変化がなくなるまで繰り返す
+	for {
+		replaced := re.ReplaceAllString(content, "${1}"+replacement+"${2}"+replacement+"${3}")
+		if replaced == content {
+			return content
+		}
+		content = replaced
+	}
+}
+
 // convertLists はリストを変換します
 func convertLists(content string) string {
 	lines := strings.Split(content, "\n")
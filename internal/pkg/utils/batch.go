@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+const (
+	// batchSize はBatchWriterがまとめて書き込むファイルの数です
+	batchSize = 64
+	// batchWorkers はBatchWriterが同時に書き込むファイルの数です
+	// ネットワークファイルシステムでは1回の書き込みやfsyncの待ち時間が大きいため、並列に書き込んで待ち時間を重ねます。
+	batchWorkers = 8
+)
+
+// fsyncFile と fsyncDir はテストで遅いファイルシステムを再現するために差し替えます
+var (
+	fsyncFile = func(f *os.File) error { return f.Sync() }
+	fsyncDir  = syncDir
+)
+
+// BatchWriter は多数の小さなファイルをまとめて書き込みます
+// 各ファイルは WriteFileAtomic と同じく一時ファイルからリネームしますが、ディレクトリのfsyncはまとめて1回にします。
+// durable が false の場合はfsyncを行いません (中断した場合は再度フェッチし直す前提のキャッシュ向けです)。
+type BatchWriter struct {
+	durable bool
+	pending []batchFile
+}
+
+type batchFile struct {
+	path    string
+	data    []byte
+	perm    os.FileMode
+	modTime time.Time
+}
+
+// NewBatchWriter は新しいBatchWriterを作成します
+func NewBatchWriter(durable bool) *BatchWriter {
+	return &BatchWriter{durable: durable}
+}
+
+// Add はファイルを書き込み待ちに追加し、一定数たまったら書き込みます
+// modTime がゼロでなければ、書き込んだファイルの更新日時を modTime にします。
+func (w *BatchWriter) Add(path string, data []byte, perm os.FileMode, modTime time.Time) error {
+	w.pending = append(w.pending, batchFile{path: path, data: data, perm: perm, modTime: modTime})
+	if len(w.pending) >= batchSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush は書き込み待ちのファイルをすべて書き込みます
+func (w *BatchWriter) Flush() error {
+	files := w.pending
+	w.pending = nil
+	if len(files) == 0 {
+		return nil
+	}
+
+	p := pool.New().WithErrors().WithMaxGoroutines(batchWorkers)
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		dirs[filepath.Dir(f.path)] = true
+		p.Go(func() error {
+			return f.write(w.durable)
+		})
+	}
+	if err := p.Wait(); err != nil {
+		return err
+	}
+	if !w.durable {
+		return nil
+	}
+
+	// リネームを永続化するため、書き込んだディレクトリごとに1回だけ同期する
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+	for _, dir := range sorted {
+		if err := fsyncDir(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f batchFile) write(durable bool) error {
+	err := replaceFile(f.path, f.perm, durable, func(w io.Writer) error {
+		_, err := io.Copy(w, bytes.NewReader(f.data))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s の書き込みに失敗しました: %v", f.path, err)
+	}
+	if !f.modTime.IsZero() {
+		if err := os.Chtimes(f.path, time.Now(), f.modTime); err != nil {
+			return fmt.Errorf("%s の更新日時の変更に失敗しました: %v", f.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		durable bool
+	}{
+		{name: "fsyncする", durable: true},
+		{name: "fsyncしない", durable: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+			w := NewBatchWriter(tt.durable)
+			// batchSize を超える件数を追加すると、Flushの前にも書き込まれる
+			n := batchSize + 3
+			for i := range n {
+				path := filepath.Join(dir, fmt.Sprintf("PRJ-%d.md", i))
+				assert.NoError(t, w.Add(path, []byte(fmt.Sprintf("内容 %d\n", i)), 0444, modTime))
+			}
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, batchSize)
+
+			assert.NoError(t, w.Flush())
+			entries, err = os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, n)
+
+			path := filepath.Join(dir, "PRJ-1.md")
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, "内容 1\n", string(data))
+			info, err := os.Stat(path)
+			assert.NoError(t, err)
+			assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
+			assert.True(t, info.ModTime().Equal(modTime))
+		})
+	}
+}
+
+// slowFileSystem はfsyncのたびに latency だけ待つようにして、NFSなどの遅いファイルシステムを再現します
+func slowFileSystem(b *testing.B, latency time.Duration) {
+	origFile, origDir := fsyncFile, fsyncDir
+	fsyncFile = func(f *os.File) error {
+		time.Sleep(latency)
+		return origFile(f)
+	}
+	fsyncDir = func(dir string) error {
+		time.Sleep(latency)
+		return origDir(dir)
+	}
+	b.Cleanup(func() {
+		fsyncFile, fsyncDir = origFile, origDir
+	})
+}
+
+// BenchmarkWriteCacheFiles はフェッチで多数のチケットをキャッシュに書き込む場合を比較します
+func BenchmarkWriteCacheFiles(b *testing.B) {
+	const (
+		files   = 200
+		latency = 2 * time.Millisecond
+	)
+	data := []byte("---\nkey: PRJ-1\ntitle: タイトル\n---\n本文\n")
+
+	b.Run("WriteFileAtomic", func(b *testing.B) {
+		slowFileSystem(b, latency)
+		dir := b.TempDir()
+		for b.Loop() {
+			for i := range files {
+				if err := WriteFileAtomic(filepath.Join(dir, fmt.Sprintf("PRJ-%d.md", i)), data, 0644); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	for _, durable := range []bool{true, false} {
+		b.Run(fmt.Sprintf("BatchWriter/durable=%t", durable), func(b *testing.B) {
+			slowFileSystem(b, latency)
+			dir := b.TempDir()
+			for b.Loop() {
+				w := NewBatchWriter(durable)
+				for i := range files {
+					if err := w.Add(filepath.Join(dir, fmt.Sprintf("PRJ-%d.md", i)), data, 0644, time.Time{}); err != nil {
+						b.Fatal(err)
+					}
+				}
+				if err := w.Flush(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -10,21 +13,77 @@ func EnsureDir(dir string) error {
 	return os.MkdirAll(dir, 0755)
 }
 
-// IsValidJIRAKey はJIRAキーの形式をチェックします (例: PRJ-123)
+// WriteFileAtomic はファイルを同じディレクトリの一時ファイルに書き込んでからリネームします
+// 書き込みの途中で中断されても、元のファイルが途中までの内容で壊れることはありません。
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+func writeFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	if err := replaceFile(path, perm, true, write); err != nil {
+		return err
+	}
+	// リネーム自体を永続化するためにディレクトリも同期する
+	return fsyncDir(filepath.Dir(path))
+}
+
+// replaceFile は同じディレクトリの一時ファイルに書き込んでから path にリネームします
+// durable が false の場合は一時ファイルをfsyncしません。
+func replaceFile(path string, perm os.FileMode, durable bool, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("一時ファイルの作成に失敗しました: %v", err)
+	}
+	// 失敗した場合は一時ファイルを残さない
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if durable {
+		if err := fsyncFile(tmp); err != nil {
+			return fmt.Errorf("一時ファイルの同期に失敗しました: %v", err)
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("一時ファイルのクローズに失敗しました: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return fmt.Errorf("一時ファイルの権限の変更に失敗しました: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("一時ファイルのリネームに失敗しました: %v", err)
+	}
+	return nil
+}
+
+// IsValidJIRAKey はJIRAキーの形式をチェックします (例: PRJ-123, B2B-12)
+// プロジェクトキーは英字で始まり、英数字とアンダースコアのみを含みます。小文字のキーも受け付けます。
 func IsValidJIRAKey(key string) bool {
 	// プロジェクトキー-数字の形式
 	parts := strings.Split(key, "-")
-	if len(parts) != 2 {
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return false
 	}
-
-	// プロジェクトキーが英字のみ、数字部分が数字のみかチェック
 	projectKey := parts[0]
 	issueNumber := parts[1]
 
-	// プロジェクトキーは英字のみ
-	for _, r := range projectKey {
-		if !((r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+	// プロジェクトキーは英字で始まり、2文字目以降は英数字かアンダースコア
+	for i, r := range projectKey {
+		isLetter := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		if i == 0 && !isLetter {
+			return false
+		}
+		if !isLetter && !(r >= '0' && r <= '9') && r != '_' {
 			return false
 		}
 	}
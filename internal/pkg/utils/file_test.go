@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidJIRAKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "英字のみのプロジェクトキー", key: "PRJ-123", want: true},
+		{name: "数字を含むプロジェクトキー", key: "B2B-12", want: true},
+		{name: "途中に数字を含むプロジェクトキー", key: "AB2C-3", want: true},
+		{name: "アンダースコアを含むプロジェクトキー", key: "MY_PRJ-1", want: true},
+		{name: "小文字のキー", key: "abc-1", want: true},
+		{name: "番号がない", key: "A-", want: false},
+		{name: "プロジェクトキーがない", key: "-1", want: false},
+		{name: "数字で始まるプロジェクトキー", key: "123-4", want: false},
+		{name: "番号が数字でない", key: "PRJ-1a", want: false},
+		{name: "ハイフンが複数", key: "PRJ-1-2", want: false},
+		{name: "下書きのファイル名", key: "TMP-20250101-120000", want: false},
+		{name: "空文字", key: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, IsValidJIRAKey(tt.key))
+		})
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "PRJ-1.md")
+	assert.NoError(t, WriteFileAtomic(path, []byte("元の内容\n"), 0644))
+
+	// 途中まで書き込んだところで失敗しても、元のファイルは壊れず一時ファイルも残らない
+	err := writeFileAtomic(path, 0644, func(w io.Writer) error {
+		if _, err := w.Write([]byte("途中")); err != nil {
+			return err
+		}
+		return errors.New("interrupted")
+	})
+	assert.Error(t, err)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "元の内容\n", string(data))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// 成功すれば内容と権限が置き換わる
+	assert.NoError(t, WriteFileAtomic(path, []byte("新しい内容\n"), 0444))
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "新しい内容\n", string(data))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
+}
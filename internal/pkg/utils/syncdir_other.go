@@ -0,0 +1,8 @@
+//go:build !unix
+
+package utils
+
+// syncDir はUNIX以外 (Windowsなど) ではディレクトリをfsyncできないため何もしません
+func syncDir(dir string) error {
+	return nil
+}
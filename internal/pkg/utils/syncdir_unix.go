@@ -0,0 +1,21 @@
+//go:build unix
+
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+// syncDir はディレクトリをfsyncし、直前のリネームをディスクに反映します
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("ディレクトリのオープンに失敗しました: %v", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("ディレクトリの同期に失敗しました: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package ticket
+
+import (
+	"path"
+	"path/filepath"
+)
+
+// AssetsDir は添付ファイルを保存するディレクトリ名です (チケットのディレクトリからの相対パス)
+const AssetsDir = "assets"
+
+// Attachment はチケットの添付ファイルです
+type Attachment struct {
+	ID       string
+	Filename string
+	// Size はJIRAが返すファイルサイズ (バイト) です。ダウンロード済みかどうかの判定に使います。
+	Size int64
+	// Path はチケットのディレクトリからの相対パスです (例: assets/PRJ-1/screenshot.png)
+	Path string
+}
+
+// AttachmentPath は添付ファイルの保存先をチケットのディレクトリからの相対パスで返します
+// ファイル名にディレクトリが含まれていても assets/<KEY>/ の外には出ません。
+func AttachmentPath(key, filename string) string {
+	return path.Join(AssetsDir, key, filepath.Base(filepath.FromSlash(filename)))
+}
@@ -0,0 +1,190 @@
+package ticket
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/qawatake/tkt/internal/pkg/utils"
+)
+
+// CacheFileMode はキャッシュに保存するチケットの権限です
+// キャッシュは差分の基準なので、誤って編集しないように読み取り専用にします。
+const CacheFileMode = 0444
+
+// SaveToCache はチケットをキャッシュディレクトリに読み取り専用で保存します
+// ファイルの更新日時はチケットのupdated_atにそろえ、手動での編集を検出できるようにします。
+func (t *Ticket) SaveToCache(cacheDir string) (string, error) {
+	t.Key = CanonicalKey(t.Key)
+	filePath := filepath.Join(cacheDir, t.Key+".md")
+	// 読み取り専用の既存ファイルを上書きできるように、一時的に書き込み可能にする
+	if err := os.Chmod(filePath, 0644); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("キャッシュの権限の変更に失敗しました: %v", err)
+	}
+	// キャッシュのファイル名は常に KEY.md にする
+	path, err := t.SaveToFile(cacheDir, WithFilenameTemplate(DefaultFilenameTemplate))
+	if err != nil {
+		return "", err
+	}
+	if err := os.Chmod(path, CacheFileMode); err != nil {
+		return "", fmt.Errorf("キャッシュの権限の変更に失敗しました: %v", err)
+	}
+	if !t.UpdatedAt.IsZero() {
+		if err := os.Chtimes(path, time.Now(), t.UpdatedAt); err != nil {
+			return "", fmt.Errorf("キャッシュの更新日時の変更に失敗しました: %v", err)
+		}
+	}
+	return path, nil
+}
+
+// CacheWriter はfetchで多数のチケットをキャッシュに保存するときに、書き込みをまとめて行います
+// 保存する内容・権限・更新日時は SaveToCache と同じです。最後に Flush を呼んでください。
+type CacheWriter struct {
+	dir string
+	w   *utils.BatchWriter
+}
+
+// NewCacheWriter は cacheDir に書き込むCacheWriterを作成します
+// durable が false の場合はfsyncを行いません (設定の cache.durable_writes)。
+func NewCacheWriter(cacheDir string, durable bool) *CacheWriter {
+	return &CacheWriter{dir: cacheDir, w: utils.NewBatchWriter(durable)}
+}
+
+// Add はチケットを書き込み待ちに追加し、保存先のパスを返します
+func (cw *CacheWriter) Add(t *Ticket) (string, error) {
+	t.Key = CanonicalKey(t.Key)
+	filePath := filepath.Join(cw.dir, t.Key+".md")
+	// 読み取り専用の既存ファイルを上書きできるように、一時的に書き込み可能にする
+	if err := os.Chmod(filePath, 0644); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("キャッシュの権限の変更に失敗しました: %v", err)
+	}
+	if err := cw.w.Add(filePath, []byte(t.ToMarkdown()), CacheFileMode, t.UpdatedAt); err != nil {
+		return "", err
+	}
+	t.FilePath = filePath
+	return filePath, nil
+}
+
+// Flush は書き込み待ちのチケットをすべて保存します
+func (cw *CacheWriter) Flush() error {
+	if err := cw.w.Flush(); err != nil {
+		return fmt.Errorf("キャッシュの保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// CanonicalKey はチケットのキーを正規化 (大文字) します
+// JIRAのAPIによってキーの大文字小文字が異なることがあるため、キーを扱うときは常にこの形にそろえます。
+func CanonicalKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
+
+// DuplicateCacheEntry はキーの大文字小文字の違いで重複していたキャッシュのファイルをまとめた結果です
+type DuplicateCacheEntry struct {
+	Key     string   // 正規化したキー
+	Kept    string   // 残したファイルのパス
+	Removed []string // 削除したファイルのパス
+}
+
+// MergeDuplicateCacheFiles はキャッシュ内でキーの大文字小文字だけが異なるファイル (PRJ-12.md と prj-12.md など) をまとめます
+// updated_at が最も新しいものを KEY.md として残し、それ以外のファイルを削除します。
+func MergeDuplicateCacheFiles(cacheDir string) ([]DuplicateCacheEntry, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("キャッシュディレクトリの読み込みに失敗しました: %v", err)
+	}
+
+	groups := make(map[string][]*Ticket)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".md") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		t, err := FromFile(filepath.Join(cacheDir, name))
+		if err != nil || t.Key == "" {
+			continue
+		}
+		groups[t.Key] = append(groups[t.Key], t)
+	}
+
+	var merged []DuplicateCacheEntry
+	for key, tickets := range groups {
+		if len(tickets) < 2 {
+			continue
+		}
+		newest := tickets[0]
+		for _, t := range tickets[1:] {
+			if t.UpdatedAt.After(newest.UpdatedAt) {
+				newest = t
+			}
+		}
+		// SaveToCache でFilePathが変わるため、元のパスを控えておく
+		paths := make([]string, 0, len(tickets))
+		for _, t := range tickets {
+			paths = append(paths, t.FilePath)
+		}
+		kept, err := newest.SaveToCache(cacheDir)
+		if err != nil {
+			return merged, fmt.Errorf("キャッシュの %s の統合に失敗しました: %v", key, err)
+		}
+		entry := DuplicateCacheEntry{Key: key, Kept: kept}
+		for _, path := range paths {
+			if path == kept {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return merged, fmt.Errorf("重複したキャッシュ %s の削除に失敗しました: %v", path, err)
+			}
+			entry.Removed = append(entry.Removed, path)
+		}
+		sort.Strings(entry.Removed)
+		merged = append(merged, entry)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Key < merged[j].Key })
+	return merged, nil
+}
+
+// TamperedCacheFiles はキャッシュのチケットのうち、最終フェッチより後にtkt以外で編集されたファイルのパスを返します
+// tktが保存したファイルは更新日時がチケットのupdated_atになるため、それより新しいものを編集されたとみなします。
+// 最終フェッチ時刻がない場合は何も返しません。
+func TamperedCacheFiles(cacheDir string, lastFetch time.Time) ([]string, error) {
+	if lastFetch.IsZero() {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの読み込みに失敗しました: %v", err)
+	}
+
+	var tampered []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().After(lastFetch) {
+			continue
+		}
+		path := filepath.Join(cacheDir, entry.Name())
+		t, err := FromFile(path)
+		if err != nil {
+			tampered = append(tampered, path)
+			continue
+		}
+		// 更新日時の精度の違いを吸収するため1秒の余裕を持たせる
+		if info.ModTime().After(t.UpdatedAt.Add(time.Second)) {
+			tampered = append(tampered, path)
+		}
+	}
+	sort.Strings(tampered)
+	return tampered, nil
+}
@@ -0,0 +1,134 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveToCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	updatedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	tkt := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", UpdatedAt: updatedAt}
+	path, err := tkt.SaveToCache(dir)
+	assert.NoError(t, err)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(CacheFileMode), info.Mode().Perm())
+	assert.True(t, info.ModTime().Equal(updatedAt))
+
+	// 読み取り専用のファイルも上書きできる
+	tkt.Title = "変更後"
+	_, err = tkt.SaveToCache(dir)
+	assert.NoError(t, err)
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "変更後", loaded.Title)
+}
+
+func TestCacheWriter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	updatedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	// SaveToCache で保存した既存の読み取り専用のファイルを上書きする
+	old := &Ticket{Key: "PRJ-1", Title: "変更前", Type: "task", UpdatedAt: updatedAt}
+	_, err := old.SaveToCache(dir)
+	assert.NoError(t, err)
+
+	cw := NewCacheWriter(dir, false)
+	tkt := &Ticket{Key: "prj-1", Title: "タイトル", Type: "task", Body: "本文\n", UpdatedAt: updatedAt}
+	path, err := cw.Add(tkt)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1.md"), path)
+	assert.NoError(t, cw.Flush())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, tkt.ToMarkdown(), string(data))
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(CacheFileMode), info.Mode().Perm())
+	assert.True(t, info.ModTime().Equal(updatedAt))
+}
+
+func TestTamperedCacheFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	lastFetch := time.Now().Add(-time.Hour)
+	saved := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", UpdatedAt: lastFetch.Add(-time.Hour)}
+	_, err := saved.SaveToCache(dir)
+	assert.NoError(t, err)
+	// pushでフェッチより後に更新されたチケット
+	pushed := &Ticket{Key: "PRJ-2", Title: "タイトル", Type: "task", UpdatedAt: lastFetch.Add(time.Minute)}
+	_, err = pushed.SaveToCache(dir)
+	assert.NoError(t, err)
+	// 手動で編集されたチケット
+	edited := &Ticket{Key: "PRJ-3", Title: "タイトル", Type: "task", UpdatedAt: lastFetch.Add(-time.Hour)}
+	editedPath, err := edited.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	tampered, err := TamperedCacheFiles(dir, lastFetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{editedPath}, tampered)
+
+	// フェッチしたことがなければ検出しない
+	tampered, err = TamperedCacheFiles(dir, time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, tampered)
+	assert.FileExists(t, filepath.Join(dir, "PRJ-1.md"))
+}
+
+func TestMergeDuplicateCacheFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fixtures := map[string]string{
+		"PRJ-12.md": "---\nkey: PRJ-12\ntitle: 古いタイトル\ntype: task\nupdated_at: 2025-06-01T10:00:00Z\n---\n",
+		"prj-12.md": "---\nkey: prj-12\ntitle: 新しいタイトル\ntype: task\nupdated_at: 2025-06-02T10:00:00Z\n---\n",
+		"PRJ-13.md": "---\nkey: PRJ-13\ntitle: 重複なし\ntype: task\nupdated_at: 2025-06-01T10:00:00Z\n---\n",
+	}
+	for name, content := range fixtures {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), CacheFileMode))
+	}
+
+	merged, err := MergeDuplicateCacheFiles(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, merged, 1) {
+		assert.Equal(t, "PRJ-12", merged[0].Key)
+		assert.Equal(t, filepath.Join(dir, "PRJ-12.md"), merged[0].Kept)
+		assert.Equal(t, []string{filepath.Join(dir, "prj-12.md")}, merged[0].Removed)
+	}
+
+	// updated_at が新しい方の内容を KEY.md として残す
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join(dir, "PRJ-12.md"), filepath.Join(dir, "PRJ-13.md")}, files)
+	kept, err := FromFile(filepath.Join(dir, "PRJ-12.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "PRJ-12", kept.Key)
+	assert.Equal(t, "新しいタイトル", kept.Title)
+
+	// 小文字のキーのローカルファイルもキャッシュと対応づける
+	localDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(localDir, "prj-12.md"), []byte(fixtures["prj-12.md"]), 0644))
+	results, err := CompareDirs(localDir, dir)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "PRJ-12", results[0].Key)
+		assert.Equal(t, ChangeUpdate, results[0].Change)
+		assert.False(t, results[0].HasDiff)
+	}
+
+	// 重複がなければ何もしない
+	merged, err = MergeDuplicateCacheFiles(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, merged)
+}
@@ -0,0 +1,90 @@
+package ticket
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Comment はJIRAチケットのコメントです
+// コメントはreadonlyで、ローカルで編集してもpushの差分にはなりません。
+type Comment struct {
+	Author    string
+	CreatedAt time.Time
+	Body      string
+}
+
+// commentsMarker はコメントセクションの開始を表します
+// 本文中の "## Comments" という見出しと区別するために使用します。
+const commentsMarker = "<!-- tkt:comments readonly -->"
+
+// commentHeaderRe はコメントの見出し行にマッチします (例: ### 山田太郎 (2025-01-02T15:04:05+09:00))
+var commentHeaderRe = regexp.MustCompile(`(?m)^### (.+) \((\d{4}-\d{2}-\d{2}T[^)]+)\)$`)
+
+// renderComments はコメントを本文の末尾に追加するセクションに変換します
+func renderComments(comments []Comment) string {
+	if len(comments) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(commentsMarker + "\n")
+	b.WriteString("## Comments\n")
+	for _, c := range comments {
+		b.WriteString("\n")
+		b.WriteString("### " + c.Author + " (" + c.CreatedAt.Format(time.RFC3339) + ")\n")
+		b.WriteString("\n")
+		if body := strings.Trim(c.Body, "\n"); body != "" {
+			b.WriteString(body + "\n")
+		}
+	}
+	return b.String()
+}
+
+// splitComments は本文とコメントセクションを分離します
+func splitComments(body string) (string, []Comment) {
+	var section string
+	switch {
+	case strings.HasPrefix(body, commentsMarker+"\n"):
+		section = body[len(commentsMarker)+1:]
+		body = ""
+	default:
+		i := strings.LastIndex(body, "\n"+commentsMarker+"\n")
+		if i < 0 {
+			return body, nil
+		}
+		section = body[i+len(commentsMarker)+2:]
+		body = body[:i]
+	}
+
+	var comments []Comment
+	matches := commentHeaderRe.FindAllStringSubmatchIndex(section, -1)
+	for i, m := range matches {
+		createdAt, err := time.Parse(time.RFC3339, section[m[4]:m[5]])
+		if err != nil {
+			continue
+		}
+		end := len(section)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		comments = append(comments, Comment{
+			Author:    section[m[2]:m[3]],
+			CreatedAt: createdAt,
+			Body:      strings.Trim(section[m[1]:end], "\n"),
+		})
+	}
+	return body, comments
+}
+
+// sameComments はコメントが一致するかを返します
+func sameComments(a, b []Comment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Author != b[i].Author || !a[i].CreatedAt.Equal(b[i].CreatedAt) || strings.Trim(a[i].Body, "\n") != strings.Trim(b[i].Body, "\n") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package ticket
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Conflict はローカルとリモートの両方で同じ項目が変更されたチケットです
+type Conflict struct {
+	Key string
+	// Fields はローカルとリモートの両方で変更された項目の名前です
+	Fields []string
+	// RemoteUpdatedAt はリモートの最終更新日時です
+	RemoteUpdatedAt time.Time
+}
+
+// DetectConflict はフェッチ時のキャッシュ (base) を基準に、ローカルの変更とリモートの変更が重なるかを調べます
+// リモートがbaseより新しく、ローカルと同じ項目が変更されている場合のみ Conflict を返します。
+// リモートがbaseから更新されていない場合や、変更された項目が重ならない場合は nil を返します。
+func DetectConflict(local, base, remote *Ticket) *Conflict {
+	if !remote.UpdatedAt.After(base.UpdatedAt) {
+		return nil
+	}
+	remoteChanged := changedFields(remote, base)
+	var fields []string
+	for _, field := range changedFields(local, base) {
+		if slices.Contains(remoteChanged, field) {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &Conflict{Key: remote.Key, Fields: fields, RemoteUpdatedAt: remote.UpdatedAt}
+}
+
+// Rebase はローカルのチケットに、base からリモートで変更された項目を取り込んだチケットを返します
+// ローカルで変更した項目はローカルの値のままにするため、DetectConflict で競合がないことを確認してから使ってください。
+func Rebase(local, base, remote *Ticket) *Ticket {
+	rebased := *local
+	for _, field := range changedFields(remote, base) {
+		copyField(&rebased, remote, field)
+	}
+	rebased.UpdatedAt = remote.UpdatedAt
+	return &rebased
+}
+
+// PendingRebase はリモートの変更を取り込んだが、まだワークスペースとキャッシュに書き込んでいないチケットです
+type PendingRebase struct {
+	// Rebased はローカルのチケットにリモートの変更を取り込んだものです
+	Rebased *Ticket
+	// Remote はキャッシュに保存するリモートのチケットです
+	Remote *Ticket
+}
+
+// WithPendingRebases は指定したローカルファイル (キーはパス) について、ファイルの代わりにリモートの変更を取り込んだ内容を
+// キャッシュの代わりにリモートのチケットと比較します
+// pushの確認やドライランで、ファイルを書き換えずにリモートの変更を取り込んだ後の差分を表示するために使います。
+func WithPendingRebases(rebases map[string]PendingRebase) CompareOption {
+	return func(o *compareOptions) {
+		o.rebases = rebases
+	}
+}
+
+// copyField は changedFields が返す名前の項目を src から dst にコピーします
+func copyField(dst, src *Ticket, field string) {
+	switch field {
+	case "title":
+		dst.Title = src.Title
+	case "type":
+		dst.Type = src.Type
+		dst.TypeID = src.TypeID
+	case "parentKey":
+		dst.ParentKey = src.ParentKey
+	case "status":
+		dst.Status = src.Status
+		dst.StatusCategory = src.StatusCategory
+		dst.Resolution = src.Resolution
+	case "assignee":
+		dst.Assignee = src.Assignee
+	case "sprint":
+		dst.SprintName = src.SprintName
+	case "team":
+		dst.Team = src.Team
+	case "original_estimate":
+		dst.OriginalEstimate = src.OriginalEstimate
+	case "remaining_estimate":
+		dst.RemainingEstimate = src.RemainingEstimate
+	case "priority":
+		dst.Priority = src.Priority
+	case "labels":
+		dst.Labels = slices.Clone(src.Labels)
+	case "components":
+		dst.Components = slices.Clone(src.Components)
+	case "fix_versions":
+		dst.FixVersions = slices.Clone(src.FixVersions)
+	case "links":
+		dst.Links = slices.Clone(src.Links)
+	case "flagged":
+		dst.Flagged = src.Flagged
+	case "environment":
+		dst.Environment = src.Environment
+	case "body":
+		dst.Body = src.Body
+	default:
+		// カスタムフィールド
+		dst.CustomFields = maps.Clone(dst.CustomFields)
+		if dst.CustomFields == nil {
+			dst.CustomFields = make(map[string]*float64)
+		}
+		if v, ok := src.CustomFields[field]; ok {
+			dst.CustomFields[field] = v
+		} else {
+			delete(dst.CustomFields, field)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectConflict(t *testing.T) {
+	t.Parallel()
+
+	fetchedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	base := &Ticket{Key: "PRJ-123", Title: "ログイン画面", Type: "task", Status: "To Do", Body: "本文\n", UpdatedAt: fetchedAt}
+
+	tests := []struct {
+		name          string
+		local, remote func(t Ticket) Ticket
+		want          *Conflict
+	}{
+		{
+			name:   "リモートがキャッシュと同じ",
+			local:  func(t Ticket) Ticket { t.Title = "ログイン画面の修正"; return t },
+			remote: func(t Ticket) Ticket { return t },
+			want:   nil,
+		},
+		{
+			name:  "リモートが新しいが変更した項目が重ならない",
+			local: func(t Ticket) Ticket { t.Title = "ログイン画面の修正"; return t },
+			remote: func(t Ticket) Ticket {
+				t.Body = "同僚が編集した本文\n"
+				t.UpdatedAt = fetchedAt.Add(time.Hour)
+				return t
+			},
+			want: nil,
+		},
+		{
+			name:  "同じ項目をリモートでも変更した",
+			local: func(t Ticket) Ticket { t.Title = "ログイン画面の修正"; t.Body = "自分の本文\n"; return t },
+			remote: func(t Ticket) Ticket {
+				t.Body = "同僚が編集した本文\n"
+				t.Status = "In Progress"
+				t.UpdatedAt = fetchedAt.Add(time.Hour)
+				return t
+			},
+			want: &Conflict{Key: "PRJ-123", Fields: []string{"body"}, RemoteUpdatedAt: fetchedAt.Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			local, remote := tt.local(*base), tt.remote(*base)
+			assert.Equal(t, tt.want, DetectConflict(&local, base, &remote))
+		})
+	}
+}
+
+func TestRebase(t *testing.T) {
+	t.Parallel()
+
+	fetchedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	base := &Ticket{Key: "PRJ-123", Title: "ログイン画面", Type: "task", Status: "To Do", Labels: []string{"ui"}, Body: "本文\n", UpdatedAt: fetchedAt}
+	local := *base
+	local.Title = "ログイン画面の修正"
+	local.FilePath = "/work/PRJ-123.md"
+	remote := *base
+	remote.Body = "同僚が編集した本文\n"
+	remote.Status = "In Progress"
+	remote.StatusCategory = "indeterminate"
+	remote.Labels = []string{"ui", "urgent"}
+	remote.UpdatedAt = fetchedAt.Add(time.Hour)
+
+	got := Rebase(&local, base, &remote)
+	assert.Equal(t, "ログイン画面の修正", got.Title)
+	assert.Equal(t, "同僚が編集した本文\n", got.Body)
+	assert.Equal(t, "In Progress", got.Status)
+	assert.Equal(t, "indeterminate", got.StatusCategory)
+	assert.Equal(t, []string{"ui", "urgent"}, got.Labels)
+	assert.Equal(t, remote.UpdatedAt, got.UpdatedAt)
+	assert.Equal(t, "/work/PRJ-123.md", got.FilePath)
+	assert.Equal(t, "ログイン画面", base.Title, "baseは変更しない")
+}
@@ -0,0 +1,33 @@
+package ticket
+
+import (
+	"math"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// DefaultContextLines は差分の前後に表示するデフォルトの行数です
+const DefaultContextLines = diff.DefaultContextLines
+
+// FullContext は差分にファイル全体を表示することを表す WithContextLines の値です
+const FullContext = -1
+
+// WithContextLines は差分の前後に表示する行数を指定します (省略した場合は3行)
+// FullContext を指定すると、変更されたチケットのファイル全体を表示します。
+func WithContextLines(n int) CompareOption {
+	return func(o *compareOptions) {
+		o.contextLines = &n
+	}
+}
+
+// unifiedContextLines はunified形式の差分の前後に表示する行数を返します
+func (o compareOptions) unifiedContextLines() int {
+	switch {
+	case o.contextLines == nil:
+		return DefaultContextLines
+	case *o.contextLines == FullContext:
+		return math.MaxInt32
+	default:
+		return *o.contextLines
+	}
+}
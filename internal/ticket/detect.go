@@ -0,0 +1,40 @@
+package ticket
+
+// WithStrictDetection はチケットの形式 (HasTicketSignature) を満たさないファイルを無視します
+// 設定ファイルの strict_ticket_detection に対応します。
+func WithStrictDetection(strict bool) CompareOption {
+	return func(o *compareOptions) {
+		o.strict = strict
+	}
+}
+
+// HasTicketSignature はtktのチケットの形式を満たすかどうかを返します
+// typeがあり、keyまたは tkt: true のマーカーがある場合にチケットとみなします。
+func (t *Ticket) HasTicketSignature() bool {
+	return t.Type != "" && (t.Key != "" || t.Marker)
+}
+
+// isTicket はオプションに従ってチケットとして扱うかどうかを返します
+func (o compareOptions) isTicket(t *Ticket) bool {
+	return !o.strict || t.HasTicketSignature()
+}
+
+// FindNonTicketFiles はディレクトリのMarkdownファイルのうち、チケットの形式を満たさないファイルを返します
+// 削除マークの付いたファイルも対象です。読み込めないファイルは含めません。
+func FindNonTicketFiles(dir string) ([]string, error) {
+	files, deleted, err := WalkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, file := range append(files, deleted...) {
+		t, err := FromFile(file)
+		if err != nil {
+			continue
+		}
+		if !t.HasTicketSignature() {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
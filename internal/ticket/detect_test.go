@@ -0,0 +1,82 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictDetection(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	files := map[string]string{
+		// Obsidianのノート: titleはあるがtypeがない
+		"note.md": "---\ntitle: 読書メモ\ntags: [book]\naliases: [memo]\n---\n本文\n",
+		// typeはあるがkeyもマーカーもない
+		"typed.md": "---\ntitle: 種類だけ\ntype: task\n---\n本文\n",
+		// マーカー付きの下書き
+		"TMP-1.md": "---\ntkt: true\ntitle: 下書き\ntype: task\n---\n本文\n",
+		// keyのあるチケット (キャッシュにないので新規作成扱い)
+		"PRJ-1.md": "---\nkey: PRJ-1\ntitle: チケット\ntype: task\n---\n本文\n",
+		// typeを書き忘れたチケット
+		"PRJ-2.md": "---\nkey: PRJ-2\ntitle: typeなし\n---\n本文\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(localDir, name), []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name   string
+		strict bool
+		want   []string
+		// wantDrafts はPlanSyncで新規作成になるkeyのないファイルの数です (keyのあるファイルはキャッシュにないので対象外)
+		wantDrafts int
+	}{
+		{name: "strictでなければすべてのファイルを対象にする", strict: false, want: []string{"PRJ-1.md", "PRJ-2.md", "TMP-1.md", "note.md", "typed.md"}, wantDrafts: 3},
+		{name: "strictではチケットの形式を満たすファイルのみ", strict: true, want: []string{"PRJ-1.md", "TMP-1.md"}, wantDrafts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := CompareDirs(localDir, cacheDir, WithStrictDetection(tt.strict))
+			assert.NoError(t, err)
+			var got []string
+			for _, r := range results {
+				got = append(got, filepath.Base(r.FilePath))
+			}
+			assert.ElementsMatch(t, tt.want, got)
+
+			items, err := PlanSync(localDir, cacheDir, nil, WithStrictDetection(tt.strict))
+			assert.NoError(t, err)
+			assert.Len(t, items, tt.wantDrafts)
+		})
+	}
+
+	skipped, err := FindNonTicketFiles(localDir)
+	assert.NoError(t, err)
+	var names []string
+	for _, file := range skipped {
+		names = append(names, filepath.Base(file))
+	}
+	assert.ElementsMatch(t, []string{"PRJ-2.md", "note.md", "typed.md"}, names)
+}
+
+func TestMarkerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	draft := &Ticket{Title: "下書き", Type: "task", Marker: true, Body: "本文\n"}
+	path, err := draft.SaveToFile(dir)
+	assert.NoError(t, err)
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.True(t, loaded.Marker)
+	assert.True(t, loaded.HasTicketSignature())
+	assert.Contains(t, loaded.ToMarkdown(), "tkt: true\n")
+	// マーカーは差分の対象にしない
+	assert.False(t, loaded.HasNonReadonlyDiff(&Ticket{Title: "下書き", Type: "task", Body: "本文\n"}))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,29 +16,67 @@ import (
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
+// ChangeType はpushで行う変更の種類を表します
+type ChangeType string
+
+const (
+	// ChangeCreate はJIRAにチケットを新規作成することを表します
+	ChangeCreate ChangeType = "create"
+	// ChangeUpdate はJIRAのチケットを更新することを表します
+	ChangeUpdate ChangeType = "update"
+	// ChangeDelete はJIRAのチケットを削除することを表します
+	ChangeDelete ChangeType = "delete"
+)
+
 // DiffResult は差分の結果を表します
 type DiffResult struct {
 	Key      string
+	Title    string
 	FilePath string
 	HasDiff  bool
 	DiffText string
+	Change   ChangeType
+	// ChangedFields は変更された項目 (フロントマターのキーまたはbody) です。更新の場合のみ設定されます。
+	ChangedFields []string
+	// Added と Removed は追加・削除された行数です
+	Added   int
+	Removed int
+	// OldTitle と NewTitle は更新でタイトルが変わった場合のみ、キャッシュとローカルのタイトルを持ちます
+	OldTitle string `json:"old_title,omitempty"`
+	NewTitle string `json:"new_title,omitempty"`
+	// IgnoredFields はローカルで変更されているが diff.ignore_fields により無視した項目です
+	IgnoredFields []string `json:"ignored_fields,omitempty"`
+	// ReadonlyEdits はローカルで変更されているが、読み取り専用のためpushで反映されない項目です
+	ReadonlyEdits []string `json:"readonly_edits,omitempty"`
+}
+
+// Header は差分の見出し (例: "PRJ-123: 旧タイトル → 新タイトル") を返します
+// 差分本文ではなくパースしたチケットから作るので、キーを流し読みしてもどのチケットか分かります。
+func (d DiffResult) Header() string {
+	key := d.Key
+	if key == "" {
+		key = "新規"
+	}
+	if d.OldTitle != d.NewTitle {
+		return fmt.Sprintf("%s: %s → %s", key, d.OldTitle, d.NewTitle)
+	}
+	return fmt.Sprintf("%s: %s", key, d.Title)
 }
 
 // CompareDirs はローカルディレクトリとキャッシュディレクトリの差分を検出します
-func CompareDirs(localDir, cacheDir string) ([]DiffResult, error) {
+func CompareDirs(localDir, cacheDir string, opts ...CompareOption) ([]DiffResult, error) {
+	var o compareOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	var results []DiffResult
 
-	// 通常のファイルと削除済みファイル（ドットプレフィックス）を両方検索
-	localFiles, err := filepath.Glob(filepath.Join(localDir, "*.md"))
+	// 通常のファイルと削除済みファイル（ドットプレフィックス）をサブディレクトリも含めて検索
+	localFiles, deletedFiles, err := WalkFiles(localDir)
 	if err != nil {
 		return nil, fmt.Errorf("ローカルファイルの検索に失敗しました: %v", err)
 	}
 
-	deletedFiles, err := filepath.Glob(filepath.Join(localDir, ".*.md"))
-	if err != nil {
-		return nil, fmt.Errorf("削除済みファイルの検索に失敗しました: %v", err)
-	}
-
 	// 削除済みファイルのキーを記録（重複処理を避けるため）
 	deletedKeys := make(map[string]bool)
 
@@ -47,40 +87,73 @@ func CompareDirs(localDir, cacheDir string) ([]DiffResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("削除済みファイルの読み込みに失敗しました: %v", err)
 		}
+		if !o.isTicket(deletedTicket) {
+			continue
+		}
+		if err := deletedTicket.CheckWritable(); err != nil {
+			return nil, err
+		}
 
 		deletedKeys[deletedTicket.Key] = true
 
 		results = append(results, DiffResult{
 			Key:      deletedTicket.Key,
+			Title:    deletedTicket.Title,
 			FilePath: deletedFile,
 			HasDiff:  true,
 			DiffText: fmt.Sprintf("削除されたチケット: %s", deletedTicket.Title),
+			Change:   ChangeDelete,
+			Removed:  countLines(format(deletedTicket.ToMarkdownWithoutReadonly())),
 		})
 	}
 
+	cacheFiles := NewFileIndex(cacheDir)
 	for _, localFile := range localFiles {
-		fileName := filepath.Base(localFile)
-		cacheFile := filepath.Join(cacheDir, fileName)
+		fileName, err := filepath.Rel(localDir, localFile)
+		if err != nil {
+			fileName = filepath.Base(localFile)
+		}
 
 		// ローカルファイルを読み込み
 		localTicket, err := FromFile(localFile)
 		if err != nil {
 			return nil, fmt.Errorf("ローカルファイルの読み込みに失敗しました: %v", err)
 		}
+		rebase, rebased := o.rebases[localFile]
+		if rebased {
+			rebasedTicket := *rebase.Rebased
+			localTicket = &rebasedTicket
+		}
+		// チケットの形式を満たさないファイル (チケット以外のノートなど) は対象外
+		if !o.isTicket(localTicket) {
+			continue
+		}
+		// extra_sources から取得したチケットをワークスペースにコピーしても、新規作成としてpushしない
+		if err := localTicket.CheckWritable(); err != nil {
+			return nil, err
+		}
 
 		// 削除済みファイルとして既に処理済みの場合はスキップ
 		if deletedKeys[localTicket.Key] {
 			continue
 		}
 
-		// キャッシュファイルが存在するか確認
-		if _, err := os.Stat(cacheFile); os.IsNotExist(err) {
+		// キャッシュファイルはファイル名ではなくフロントマターのキーで探す
+		// (filename_style: key-slug でタイトルを変えてファイル名が変わっても同じチケットとして比較する)
+		cacheFile, err := cacheFiles.Find(localTicket.Key)
+		if err != nil {
+			return nil, err
+		}
+		if cacheFile == "" {
 			// キャッシュにないファイルは新規作成対象
 			results = append(results, DiffResult{
 				Key:      localTicket.Key,
+				Title:    localTicket.Title,
 				FilePath: localFile,
 				HasDiff:  true,
 				DiffText: fmt.Sprintf("新規チケット: %s", localTicket.Title),
+				Change:   ChangeCreate,
+				Added:    countLines(format(localTicket.ToMarkdownWithoutReadonly())),
 			})
 			continue
 		}
@@ -90,15 +163,32 @@ func CompareDirs(localDir, cacheDir string) ([]DiffResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("キャッシュファイルの読み込みに失敗しました: %v", err)
 		}
+		if rebased {
+			remoteTicket := *rebase.Remote
+			cacheTicket = &remoteTicket
+		}
+
+		// environmentを書いていない場合はリモートの値を変更しないので比較しない
+		if localTicket.Environment == nil {
+			cacheTicket.Environment = nil
+		}
+
+		// diff.ignore_fieldsの項目はキャッシュの値にそろえて比較しない
+		ignored := applyIgnoreFields(localTicket, cacheTicket, o.ignoreFields)
+		readonly := readonlyEdits(localTicket, cacheTicket)
 
 		// readonly項目以外に差分があるかチェック
 		if !localTicket.HasNonReadonlyDiff(cacheTicket) {
 			// readonly項目のみの変更の場合は差分なしとして扱う
 			results = append(results, DiffResult{
-				Key:      localTicket.Key,
-				FilePath: localFile,
-				HasDiff:  false,
-				DiffText: "",
+				Key:           localTicket.Key,
+				Title:         localTicket.Title,
+				FilePath:      localFile,
+				HasDiff:       false,
+				DiffText:      "",
+				Change:        ChangeUpdate,
+				IgnoredFields: ignored,
+				ReadonlyEdits: readonly,
 			})
 			continue
 		}
@@ -114,7 +204,7 @@ func CompareDirs(localDir, cacheDir string) ([]DiffResult, error) {
 			chunks = append(chunks, chunk)
 		}
 		builder := strings.Builder{}
-		unifiedEncoder := diff.NewUnifiedEncoder(&builder, diff.DefaultContextLines)
+		unifiedEncoder := diff.NewUnifiedEncoder(&builder, o.unifiedContextLines())
 		unifiedEncoder.SetColor(diff.NewColorConfig())
 
 		info, err := os.Stat(cacheFile)
@@ -161,39 +251,154 @@ func CompareDirs(localDir, cacheDir string) ([]DiffResult, error) {
 
 		// 差分があるかどうか
 		hasDiff := false
+		var added, removed int
 		for _, diff := range diffs {
-			if diff.Type != diffmatchpatch.DiffEqual {
+			switch diff.Type {
+			case diffmatchpatch.DiffInsert:
+				hasDiff = true
+				added += countLines(diff.Text)
+			case diffmatchpatch.DiffDelete:
 				hasDiff = true
-				break
+				removed += countLines(diff.Text)
 			}
 		}
 
-		results = append(results, DiffResult{
-			Key:      localTicket.Key,
-			FilePath: localFile,
-			HasDiff:  hasDiff,
-			DiffText: builder.String(),
-		})
+		result := DiffResult{
+			Key:           localTicket.Key,
+			Title:         localTicket.Title,
+			FilePath:      localFile,
+			HasDiff:       hasDiff,
+			DiffText:      builder.String(),
+			Change:        ChangeUpdate,
+			ChangedFields: changedFields(localTicket, cacheTicket),
+			Added:         added,
+			Removed:       removed,
+			IgnoredFields: ignored,
+			ReadonlyEdits: readonly,
+		}
+		if localTicket.Title != cacheTicket.Title {
+			result.OldTitle = cacheTicket.Title
+			result.NewTitle = localTicket.Title
+		}
+		results = append(results, result)
 	}
 
 	return results, nil
 }
 
+// changedFields はキャッシュから変更された項目の名前を返します
+func changedFields(local, cache *Ticket) []string {
+	var fields []string
+	if local.Title != cache.Title {
+		fields = append(fields, "title")
+	}
+	if local.Type != cache.Type {
+		fields = append(fields, "type")
+	}
+	if local.ParentKey != cache.ParentKey {
+		fields = append(fields, "parentKey")
+	}
+	if local.Status != cache.Status {
+		fields = append(fields, "status")
+	}
+	if local.Assignee != cache.Assignee {
+		fields = append(fields, "assignee")
+	}
+	if local.sprintValue() != cache.sprintValue() {
+		fields = append(fields, "sprint")
+	}
+	if local.Team != cache.Team {
+		fields = append(fields, "team")
+	}
+	if local.OriginalEstimate != cache.OriginalEstimate {
+		fields = append(fields, "original_estimate")
+	}
+	if local.RemainingEstimate != cache.RemainingEstimate {
+		fields = append(fields, "remaining_estimate")
+	}
+	if local.Priority != cache.Priority {
+		fields = append(fields, "priority")
+	}
+	if !slices.Equal(local.Labels, cache.Labels) {
+		fields = append(fields, "labels")
+	}
+	if !slices.Equal(local.Components, cache.Components) {
+		fields = append(fields, "components")
+	}
+	if !slices.Equal(local.FixVersions, cache.FixVersions) {
+		fields = append(fields, "fix_versions")
+	}
+	if !slices.Equal(local.Links, cache.Links) {
+		fields = append(fields, "links")
+	}
+	if local.IsFlagged() != cache.IsFlagged() {
+		fields = append(fields, "flagged")
+	}
+	if local.Environment != nil && *local.Environment != stringValue(cache.Environment) {
+		fields = append(fields, "environment")
+	}
+	var customKeys []string
+	for key := range local.CustomFields {
+		customKeys = append(customKeys, key)
+	}
+	for key := range cache.CustomFields {
+		if _, ok := local.CustomFields[key]; !ok {
+			customKeys = append(customKeys, key)
+		}
+	}
+	sort.Strings(customKeys)
+	for _, key := range customKeys {
+		l, r := local.CustomFields[key], cache.CustomFields[key]
+		if (l == nil) != (r == nil) || (l != nil && *l != *r) {
+			fields = append(fields, key)
+		}
+	}
+	if NormalizeBody(local.Body) != NormalizeBody(cache.Body) {
+		fields = append(fields, "body")
+	}
+	return fields
+}
+
+// stringValue はnilを空文字として文字列の値を返します
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// countLines はテキストの行数を返します
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	n := strings.Count(text, "\n")
+	if !strings.HasSuffix(text, "\n") {
+		n++
+	}
+	return n
+}
+
 // CommonMarkとして正規化しないと、パース結果が同じなのに差分があると検知されてしまいノイジーなので。
 func format(body string) string {
 	// front matterとbodyを分離
 	frontMatter, content := separateFrontMatter(body)
 
 	// bodyのみにJIRAのMarkdown変換を適用
-	if content != "" {
-		jmd := md.ToJiraMD(content)
-		content = md.FromJiraMD(jmd)
-	}
+	content = NormalizeBody(content)
 
 	// front matterとbodyを結合
 	return frontMatter + content
 }
 
+// NormalizeBody は本文をJIRA記法を経由して変換し、差分検出で使う正規化された形式にします
+func NormalizeBody(body string) string {
+	if body == "" {
+		return body
+	}
+	return md.FromJiraMD(md.ToJiraMD(body))
+}
+
 // separateFrontMatter はMarkdownからfront matterとbodyを分離します
 func separateFrontMatter(markdown string) (frontMatter, body string) {
 	lines := strings.Split(markdown, "\n")
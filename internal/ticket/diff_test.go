@@ -1,8 +1,12 @@
 package ticket
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -315,3 +319,383 @@ title: 'Test'
 		})
 	}
 }
+
+func TestCompareDirsSummary(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	cached := &Ticket{Key: "PRJ-1", Title: "before", Type: "task", Body: "1行目\n\n2行目\n"}
+	_, err := cached.SaveToFile(cacheDir)
+	assert.NoError(t, err)
+	edited := &Ticket{Key: "PRJ-1", Title: "after", Type: "task", Body: "1行目\n\n2行目\n\n3行目\n"}
+	_, err = edited.SaveToFile(localDir)
+	assert.NoError(t, err)
+	draft := &Ticket{Title: "draft", Type: "task", Body: "本文\n"}
+	_, err = draft.SaveToFile(localDir)
+	assert.NoError(t, err)
+
+	results, err := CompareDirs(localDir, cacheDir)
+	assert.NoError(t, err)
+
+	byChange := make(map[ChangeType]DiffResult)
+	for _, r := range results {
+		byChange[r.Change] = r
+	}
+
+	update := byChange[ChangeUpdate]
+	assert.Equal(t, "PRJ-1", update.Key)
+	assert.Equal(t, []string{"title", "body"}, update.ChangedFields)
+	assert.Positive(t, update.Added)
+	assert.Positive(t, update.Removed)
+	assert.Equal(t, "before", update.OldTitle)
+	assert.Equal(t, "after", update.NewTitle)
+	assert.Equal(t, "PRJ-1: before → after", update.Header())
+
+	create := byChange[ChangeCreate]
+	assert.Equal(t, "draft", create.Title)
+	assert.Empty(t, create.OldTitle)
+	assert.Equal(t, "新規: draft", create.Header())
+	assert.Positive(t, create.Added)
+}
+
+func TestCompareDirsEnvironment(t *testing.T) {
+	t.Parallel()
+
+	remote := "Chrome 126"
+	empty := ""
+	changed := "Firefox 127"
+	tests := []struct {
+		name        string
+		environment *string
+		want        []string
+	}{
+		{name: "書かれていない場合は変更しない", environment: nil, want: nil},
+		{name: "空にすると消去", environment: &empty, want: []string{"environment"}},
+		{name: "値を変更", environment: &changed, want: []string{"environment"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			localDir := t.TempDir()
+			cacheDir := t.TempDir()
+			cached := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "bug", Body: "本文\n", Environment: &remote}
+			_, err := cached.SaveToFile(cacheDir)
+			assert.NoError(t, err)
+			local := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "bug", Body: "本文\n", Environment: tt.environment}
+			_, err = local.SaveToFile(localDir)
+			assert.NoError(t, err)
+
+			results, err := CompareDirs(localDir, cacheDir)
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			assert.Equal(t, tt.want != nil, results[0].HasDiff)
+			assert.Equal(t, tt.want, results[0].ChangedFields)
+		})
+	}
+}
+
+func TestCompareDirsSprintBacklog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		local  string
+		remote string
+		want   []string
+	}{
+		{name: "backlogとスプリントなしは同じ", local: "backlog", remote: "", want: nil},
+		{name: "大文字のBacklogも同じ", local: "Backlog", remote: "", want: nil},
+		{name: "スプリントなしとbacklogは同じ", local: "", remote: "backlog", want: nil},
+		{name: "backlogに移動", local: "backlog", remote: "Sprint 1", want: []string{"sprint"}},
+		{name: "スプリントに追加", local: "Sprint 1", remote: "", want: []string{"sprint"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			localDir := t.TempDir()
+			cacheDir := t.TempDir()
+			cached := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", SprintName: tt.remote}
+			_, err := cached.SaveToFile(cacheDir)
+			assert.NoError(t, err)
+			local := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", SprintName: tt.local}
+			_, err = local.SaveToFile(localDir)
+			assert.NoError(t, err)
+
+			results, err := CompareDirs(localDir, cacheDir)
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			assert.Equal(t, tt.want != nil, results[0].HasDiff)
+			assert.Equal(t, tt.want, results[0].ChangedFields)
+		})
+	}
+}
+
+func TestCompareDirsIgnoreFields(t *testing.T) {
+	t.Parallel()
+
+	three := 3.0
+	five := 5.0
+	tests := []struct {
+		name        string
+		local       *Ticket
+		wantDiff    bool
+		wantChanged []string
+		wantIgnored []string
+	}{
+		{
+			name:        "無視する項目のみの変更は差分にならない",
+			local:       &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "bug", ParentKey: "PRJ-9", Body: "本文\n", CustomFields: map[string]*float64{"story_points": &three}},
+			wantDiff:    false,
+			wantIgnored: []string{"type", "parentKey"},
+		},
+		{
+			name:        "他の項目の変更は差分になる",
+			local:       &Ticket{Key: "PRJ-1", Title: "新しいタイトル", Type: "bug", ParentKey: "PRJ-2", Body: "本文\n", CustomFields: map[string]*float64{"story_points": &three}},
+			wantDiff:    true,
+			wantChanged: []string{"title"},
+			wantIgnored: []string{"type"},
+		},
+		{
+			name:        "カスタムフィールドも無視できる",
+			local:       &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", ParentKey: "PRJ-2", Body: "本文\n", CustomFields: map[string]*float64{"story_points": &five}},
+			wantDiff:    false,
+			wantIgnored: []string{"story_points"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			localDir := t.TempDir()
+			cacheDir := t.TempDir()
+			cached := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", ParentKey: "PRJ-2", Body: "本文\n", CustomFields: map[string]*float64{"story_points": &three}}
+			_, err := cached.SaveToFile(cacheDir)
+			assert.NoError(t, err)
+			_, err = tt.local.SaveToFile(localDir)
+			assert.NoError(t, err)
+
+			results, err := CompareDirs(localDir, cacheDir, WithIgnoreFields([]string{"type", "parentKey", "story_points"}))
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			assert.Equal(t, tt.wantDiff, results[0].HasDiff)
+			assert.Equal(t, tt.wantChanged, results[0].ChangedFields)
+			assert.Equal(t, tt.wantIgnored, results[0].IgnoredFields)
+		})
+	}
+}
+
+func TestCompareDirsReadonlyEdits(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	updated := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		edit     func(local *Ticket)
+		wantDiff bool
+		want     []string
+	}{
+		{
+			name: "編集していない",
+			edit: func(local *Ticket) {},
+		},
+		{
+			name: "created_atとreporterとurlの編集",
+			edit: func(local *Ticket) {
+				local.CreatedAt = created.Add(time.Hour)
+				local.Reporter = "別の人"
+				local.URL = "https://example.atlassian.net/browse/PRJ-2"
+			},
+			want: []string{"created_at", "reporter", "url"},
+		},
+		{
+			name: "updated_atを進めた",
+			edit: func(local *Ticket) {
+				local.UpdatedAt = updated.Add(time.Hour)
+			},
+			want: []string{"updated_at"},
+		},
+		{
+			name: "古い版のファイルのreporterはリモートの変更とみなす",
+			edit: func(local *Ticket) {
+				local.UpdatedAt = updated.Add(-time.Hour)
+				local.Reporter = "以前の報告者"
+			},
+		},
+		{
+			name: "creatorのない古いファイルは編集とみなさない",
+			edit: func(local *Ticket) {
+				local.Creator = ""
+			},
+		},
+		{
+			name: "他の項目の変更と一緒に検出する",
+			edit: func(local *Ticket) {
+				local.Title = "新しいタイトル"
+				local.Reporter = "別の人"
+			},
+			wantDiff: true,
+			want:     []string{"reporter"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			localDir := t.TempDir()
+			cacheDir := t.TempDir()
+			newTicket := func() *Ticket {
+				return &Ticket{
+					Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n",
+					Reporter: "報告者", Creator: "作成者", CreatedAt: created, UpdatedAt: updated,
+					URL: "https://example.atlassian.net/browse/PRJ-1",
+				}
+			}
+			_, err := newTicket().SaveToFile(cacheDir)
+			assert.NoError(t, err)
+			local := newTicket()
+			tt.edit(local)
+			_, err = local.SaveToFile(localDir)
+			assert.NoError(t, err)
+
+			results, err := CompareDirs(localDir, cacheDir)
+			assert.NoError(t, err)
+			assert.Len(t, results, 1)
+			assert.Equal(t, tt.wantDiff, results[0].HasDiff)
+			assert.Equal(t, tt.want, results[0].ReadonlyEdits)
+		})
+	}
+}
+
+func TestCompareDirsNested(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	for _, tkt := range []*Ticket{
+		{Key: "PRJ-1", Title: "エピック", Type: "epic", Body: "本文\n"},
+		{Key: "PRJ-2", Title: "バグ", Type: "bug", Body: "本文\n"},
+		{Key: "PRJ-3", Title: "消すバグ", Type: "bug", Body: "本文\n"},
+	} {
+		_, err := tkt.SaveToFile(cacheDir)
+		assert.NoError(t, err)
+	}
+
+	// epics/PRJ-1.md, bugs/2025/PRJ-2.md, bugs/2025/.PRJ-3.md, bugs/draft.md の2階層のレイアウト
+	epicsDir := filepath.Join(localDir, "epics")
+	bugsDir := filepath.Join(localDir, "bugs", "2025")
+	assert.NoError(t, os.MkdirAll(epicsDir, 0755))
+	assert.NoError(t, os.MkdirAll(bugsDir, 0755))
+	edited := &Ticket{Key: "PRJ-1", Title: "エピック (編集)", Type: "epic", Body: "本文\n"}
+	_, err := edited.SaveToFile(epicsDir)
+	assert.NoError(t, err)
+	unchanged := &Ticket{Key: "PRJ-2", Title: "バグ", Type: "bug", Body: "本文\n"}
+	_, err = unchanged.SaveToFile(bugsDir)
+	assert.NoError(t, err)
+	deleted := &Ticket{Key: "PRJ-3", Title: "消すバグ", Type: "bug", Body: "本文\n"}
+	deletedPath, err := deleted.SaveToFile(bugsDir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Rename(deletedPath, filepath.Join(bugsDir, ".PRJ-3.md")))
+	draft := &Ticket{Title: "下書き", Type: "bug", Body: "本文\n"}
+	draftPath, err := draft.SaveToFile(filepath.Join(localDir, "bugs"))
+	assert.NoError(t, err)
+	// ドットで始まるディレクトリは探さない
+	hiddenDir := filepath.Join(localDir, ".git")
+	assert.NoError(t, os.MkdirAll(hiddenDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(hiddenDir, "PRJ-9.md"), []byte("---\nkey: PRJ-9\ntitle: x\ntype: task\n---\n"), 0644))
+
+	results, err := CompareDirs(localDir, cacheDir)
+	assert.NoError(t, err)
+
+	byKey := make(map[string]DiffResult)
+	for _, r := range results {
+		byKey[r.Key] = r
+	}
+	assert.Len(t, results, 4)
+	assert.Equal(t, ChangeUpdate, byKey["PRJ-1"].Change)
+	assert.True(t, byKey["PRJ-1"].HasDiff)
+	assert.Equal(t, filepath.Join(epicsDir, "PRJ-1.md"), byKey["PRJ-1"].FilePath)
+	assert.Equal(t, ChangeUpdate, byKey["PRJ-2"].Change)
+	assert.False(t, byKey["PRJ-2"].HasDiff)
+	assert.Equal(t, ChangeDelete, byKey["PRJ-3"].Change)
+	assert.Equal(t, filepath.Join(bugsDir, ".PRJ-3.md"), byKey["PRJ-3"].FilePath)
+	assert.Equal(t, ChangeCreate, byKey[""].Change)
+	assert.Equal(t, draftPath, byKey[""].FilePath)
+
+	// サブディレクトリのファイルもキーで見つかる
+	path, err := FindFile(localDir, "PRJ-2")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(bugsDir, "PRJ-2.md"), path)
+	path, err = FindDeletedFile(localDir, "PRJ-3")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(bugsDir, ".PRJ-3.md"), path)
+}
+
+func TestCompareDirsReadOnlySource(t *testing.T) {
+	t.Parallel()
+
+	// extra_sources のチケットをワークスペースにコピーしても、新規作成として扱わない
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	copied := &Ticket{Key: "ABC-12", Title: "別サイトのチケット", Type: "task", Source: "other", Body: "本文\n"}
+	_, err := copied.SaveToFile(localDir)
+	assert.NoError(t, err)
+
+	_, err = CompareDirs(localDir, cacheDir)
+	var sourceErr *ReadOnlySourceError
+	assert.ErrorAs(t, err, &sourceErr)
+	assert.EqualError(t, err, "ABC-12 は読み取り専用のソース (other) のチケットのため、pushやdiffの対象にできません")
+}
+
+func TestCompareDirsContextLines(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	var lines []string
+	for i := 1; i <= 12; i++ {
+		lines = append(lines, fmt.Sprintf("行%02d", i))
+	}
+	cached := &Ticket{Key: "PRJ-1", Title: "長い本文", Type: "task", Body: strings.Join(lines, "\n") + "\n"}
+	_, err := cached.SaveToFile(cacheDir)
+	assert.NoError(t, err)
+	lines[5] = "行06 (編集)"
+	local := &Ticket{Key: "PRJ-1", Title: "長い本文", Type: "task", Body: strings.Join(lines, "\n") + "\n"}
+	_, err = local.SaveToFile(localDir)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		opts     []CompareOption
+		contains []string
+		excludes []string
+	}{
+		{name: "デフォルトは3行", contains: []string{"\n 行03\n", "\n 行09\n"}, excludes: []string{"\n 行02\n", "\n 行10"}},
+		{name: "0行", opts: []CompareOption{WithContextLines(0)}, contains: []string{"+行06 (編集)"}, excludes: []string{"\n 行05\n", "\n 行07"}},
+		{name: "ファイル全体", opts: []CompareOption{WithContextLines(FullContext)}, contains: []string{"\n title: 長い本文\n", "\n 行01\n", "\n 行11"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := CompareDirs(localDir, cacheDir, tt.opts...)
+			assert.NoError(t, err)
+			if !assert.Len(t, results, 1) {
+				return
+			}
+			for _, s := range tt.contains {
+				assert.Contains(t, results[0].DiffText, s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, results[0].DiffText, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package ticket
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ワークスペースのファイル名の形式 (設定ファイルの filename_style) です
+// キャッシュは常にキーのみのファイル名で保存します。
+const (
+	// FilenameStyleKey はキーのみのファイル名 (例: PRJ-123.md) です
+	FilenameStyleKey = "key"
+	// FilenameStyleKeySlug はキーとタイトルのスラッグを繋げたファイル名 (例: PRJ-123-fix-login-timeout.md) です
+	FilenameStyleKeySlug = "key-slug"
+)
+
+// DefaultFilenameTemplate はファイル名のテンプレートの既定値です
+const DefaultFilenameTemplate = "{{.Key}}.md"
+
+// keySlugFilenameTemplate は filename_style: key-slug に相当するテンプレートです
+// タイトルから英数字のスラッグが作れない場合はキーのみのファイル名になります。
+const keySlugFilenameTemplate = "{{.Key}}{{with slug .Title}}-{{.}}{{end}}.md"
+
+// ResolveFilenameTemplate は設定ファイルの filename_template と filename_style からファイル名のテンプレートを決めます
+// filename_template が指定されていればそれを優先します。
+func ResolveFilenameTemplate(tmpl, style string) string {
+	if tmpl != "" {
+		return tmpl
+	}
+	if style == FilenameStyleKeySlug {
+		return keySlugFilenameTemplate
+	}
+	return DefaultFilenameTemplate
+}
+
+// maxSlugLength はスラッグの最大長です
+const maxSlugLength = 50
+
+var slugInvalidRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug はタイトルをファイル名やブランチ名に使える形式に変換します
+// 英数字以外はハイフンに置き換え、日本語などは除去します。
+func Slug(s string) string {
+	slug := slugInvalidRe.ReplaceAllString(strings.ToLower(s), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// FileName はテンプレート (Ticketのフィールドと slug 関数が使えるGoのテンプレート) からチケットのファイル名を生成します
+// テンプレートが空の場合は DefaultFilenameTemplate を使います。
+// 別のチケットと衝突したり削除マークと紛らわしくならないよう、キーを含み "." で始まらない .md のファイル名のみ許可します。
+func (t *Ticket) FileName(tmpl string) (string, error) {
+	if tmpl == "" {
+		tmpl = DefaultFilenameTemplate
+	}
+	tp, err := template.New("filename").Funcs(template.FuncMap{"slug": Slug}).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("filename_template の解析に失敗しました: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tp.Execute(&buf, t); err != nil {
+		return "", fmt.Errorf("ファイル名の生成に失敗しました: %v", err)
+	}
+	name := strings.TrimSpace(buf.String())
+	switch {
+	case !strings.HasSuffix(name, ".md"):
+		return "", fmt.Errorf("ファイル名は .md で終わる必要があります: %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return "", fmt.Errorf("ファイル名にパスの区切り文字は使えません: %q", name)
+	case strings.HasPrefix(name, "."):
+		return "", fmt.Errorf("ファイル名を \".\" で始めることはできません (削除マークと区別できないため): %q", name)
+	case !strings.Contains(name, t.Key):
+		return "", fmt.Errorf("ファイル名はチケットのキー %s を含む必要があります: %q", t.Key, name)
+	}
+	return name, nil
+}
+
+// SaveOption はSaveToFileの動作を変更するオプションです
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	filenameTemplate *string
+}
+
+// WithFilenameTemplate はファイル名のテンプレートを指定します (空の場合は DefaultFilenameTemplate)
+// 指定しない場合、既存のファイルがあればその名前をそのまま使います。
+func WithFilenameTemplate(tmpl string) SaveOption {
+	return func(o *saveOptions) {
+		o.filenameTemplate = &tmpl
+	}
+}
+
+// draftPrefix はキーのない下書きのファイル名の接頭辞です
+const draftPrefix = "TMP-"
+
+// draftFileName は下書きのファイル名 (例: TMP-20250601-150405-k3x9qa.md) を返します
+// 同じ秒に複数の下書きを作成しても衝突しないよう、ランダムな6文字を付けます。
+func draftFileName(dir string) string {
+	for {
+		name := fmt.Sprintf("%s%s-%s.md", draftPrefix, time.Now().Format("20060102-150405"), strings.ToLower(rand.Text()[:6]))
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			return name
+		}
+	}
+}
+
+// ownsFileName はファイル名がこのチケットのものとして扱えるかどうかを返します
+// キーのない下書きは TMP-*.md、キーのあるチケットは下書きの名前以外です。
+// (pushで作成したチケットは下書きのファイルを残したまま新しい名前で保存するため)
+func (t *Ticket) ownsFileName(name string) bool {
+	isDraft := strings.HasPrefix(name, draftPrefix) && strings.HasSuffix(name, ".md")
+	if t.Key == "" {
+		return isDraft
+	}
+	return !isDraft
+}
+
+// FindFile はディレクトリから指定したキーのチケットのファイルを探します
+// KEY.md を優先し、なければディレクトリ内のファイルのうちフロントマターのキーが一致するものを返します。
+// 見つからない場合は空文字を返します。
+// 複数のキーを探す場合は、ファイルを1回だけ読み込む FileIndex を使ってください。
+func FindFile(dir, key string) (string, error) {
+	return NewFileIndex(dir).Find(key)
+}
+
+// FindDeletedFile はディレクトリから指定したキーの削除マーク付きのファイル (ドットで始まるファイル) を探します
+func FindDeletedFile(dir, key string) (string, error) {
+	return NewFileIndex(dir).FindDeleted(key)
+}
+
+// FileIndex はディレクトリ以下のチケットのファイルをキーで探すための索引です
+// KEY.md で見つからないキーを初めて探すときに WalkFiles で1回だけ全ファイルを読み込み、以降はその結果から探します。
+// 作成後に追加・名前を変更したファイルは索引に反映されません。
+type FileIndex struct {
+	dir     string
+	loaded  bool
+	files   map[string]string // キー → 通常のファイル
+	deleted map[string]string // キー → 削除マーク付きのファイル
+}
+
+// NewFileIndex はディレクトリのファイルの索引を作成します
+func NewFileIndex(dir string) *FileIndex {
+	return &FileIndex{dir: dir}
+}
+
+// Dir は索引の対象のディレクトリを返します
+func (idx *FileIndex) Dir() string {
+	return idx.dir
+}
+
+// Find は指定したキーのチケットのファイルを返します (FindFile と同じ順で探します)
+func (idx *FileIndex) Find(key string) (string, error) {
+	return idx.find(false, key)
+}
+
+// FindDeleted は指定したキーの削除マーク付きのファイルを返します (FindDeletedFile と同じ順で探します)
+func (idx *FileIndex) FindDeleted(key string) (string, error) {
+	return idx.find(true, key)
+}
+
+func (idx *FileIndex) find(deleted bool, key string) (string, error) {
+	key = CanonicalKey(key)
+	if key == "" {
+		return "", nil
+	}
+	prefix := ""
+	if deleted {
+		prefix = "."
+	}
+	path := filepath.Join(idx.dir, prefix+key+".md")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	// filename_template で名前を変えている場合やサブディレクトリにある場合はフロントマターのキーで探す
+	if err := idx.load(); err != nil {
+		return "", err
+	}
+	if deleted {
+		return idx.deleted[key], nil
+	}
+	return idx.files[key], nil
+}
+
+// load はディレクトリ以下のファイルを読み込み、キーごとのファイルを記録します
+// 同じキーのファイルが複数ある場合は WalkFiles で先に見つかったものを使います。
+func (idx *FileIndex) load() error {
+	if idx.loaded {
+		return nil
+	}
+	files, deletedFiles, err := WalkFiles(idx.dir)
+	if err != nil {
+		return err
+	}
+	index := func(paths []string) map[string]string {
+		byKey := make(map[string]string, len(paths))
+		for _, path := range paths {
+			t, err := FromFile(path)
+			if err != nil || t.Key == "" {
+				continue
+			}
+			if _, ok := byKey[t.Key]; !ok {
+				byKey[t.Key] = path
+			}
+		}
+		return byKey
+	}
+	idx.files, idx.deleted, idx.loaded = index(files), index(deletedFiles), true
+	return nil
+}
+
+// WalkFiles はディレクトリ以下 (サブディレクトリを含む) のチケットのファイルを探します
+// 通常のファイルと削除マーク付きのファイル (ドットで始まるファイル) を分けて返します。
+// ドットで始まるディレクトリ (.git など) と .tktignore のパターンに一致するファイルは探しません。
+func WalkFiles(dir string) (files, deleted []string, err error) {
+	ignore, err := LoadIgnore(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		name := d.Name()
+		if path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(name, ".") || ignore.Match(rel, true) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".md") || ignore.Match(rel, false) {
+			return nil
+		}
+		if strings.HasPrefix(name, ".") {
+			deleted = append(deleted, path)
+		} else {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("ファイルの検索に失敗しました: %v", err)
+	}
+	return files, deleted, nil
+}
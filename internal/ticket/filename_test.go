@@ -0,0 +1,232 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		title string
+		style string
+		want  string
+	}{
+		{name: "既定はキーのみ", title: "Fix login timeout", style: "", want: "PRJ-12345.md"},
+		{name: "key", title: "Fix login timeout", style: FilenameStyleKey, want: "PRJ-12345.md"},
+		{name: "key-slug", title: "Fix login timeout", style: FilenameStyleKeySlug, want: "PRJ-12345-fix-login-timeout.md"},
+		{name: "記号はハイフンにまとめる", title: "[API] Fix: login/timeout!", style: FilenameStyleKeySlug, want: "PRJ-12345-api-fix-login-timeout.md"},
+		{name: "スラッグが空ならキーのみ", title: "ログインのタイムアウト", style: FilenameStyleKeySlug, want: "PRJ-12345.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tkt := &Ticket{Key: "PRJ-12345", Title: tt.title}
+			got, err := tkt.FileName(ResolveFilenameTemplate("", tt.style))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileNameTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{name: "空なら既定のテンプレート", tmpl: "", want: "PRJ-1.md"},
+		{name: "フィールドを参照できる", tmpl: "{{.Type}}-{{.Key}}.md", want: "task-PRJ-1.md"},
+		{name: "slug関数を使える", tmpl: "{{.Key}}_{{slug .Title}}.md", want: "PRJ-1_fix-login.md"},
+		{name: "テンプレートの構文エラー", tmpl: "{{.Key}.md", wantErr: true},
+		{name: "存在しないフィールド", tmpl: "{{.Unknown}}.md", wantErr: true},
+		{name: ".mdで終わらない", tmpl: "{{.Key}}.txt", wantErr: true},
+		{name: "パスの区切り文字を含む", tmpl: "{{.Type}}/{{.Key}}.md", wantErr: true},
+		{name: "ドットで始まる", tmpl: ".{{.Key}}.md", wantErr: true},
+		{name: "キーを含まない", tmpl: "{{slug .Title}}.md", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tkt := &Ticket{Key: "PRJ-1", Title: "Fix login", Type: "task"}
+			got, err := tkt.FileName(tt.tmpl)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSaveToFileRename(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tkt := &Ticket{Key: "PRJ-1", Title: "Fix login", Type: "task"}
+	path, err := tkt.SaveToFile(dir, WithFilenameTemplate(keySlugFilenameTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1-fix-login.md"), path)
+
+	// タイトルを変えるとファイル名も変わり、古いファイルは残らない
+	tkt.Title = "Fix login timeout"
+	path, err = tkt.SaveToFile(dir, WithFilenameTemplate(keySlugFilenameTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1-fix-login-timeout.md"), path)
+	assert.NoFileExists(t, filepath.Join(dir, "PRJ-1-fix-login.md"))
+
+	// 形式を指定しなければ既存のファイル名を維持する
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	loaded.Title = "Other title"
+	path, err = loaded.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1-fix-login-timeout.md"), path)
+
+	// 形式をkeyに戻すとキーのみのファイル名に移行する
+	path, err = loaded.SaveToFile(dir, WithFilenameTemplate(DefaultFilenameTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1.md"), path)
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{path}, files)
+}
+
+func TestFindFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, tkt := range []*Ticket{
+		{Key: "PRJ-1", Title: "Fix login", Type: "task"},
+		{Key: "PRJ-12", Title: "Other", Type: "task"},
+	} {
+		_, err := tkt.SaveToFile(dir, WithFilenameTemplate(keySlugFilenameTemplate))
+		assert.NoError(t, err)
+	}
+	// キーで始まらない名前のファイルもフロントマターのキーで見つける
+	other := &Ticket{Key: "PRJ-7", Title: "Other", Type: "task"}
+	_, err := other.SaveToFile(dir, WithFilenameTemplate("{{.Type}}-{{.Key}}.md"))
+	assert.NoError(t, err)
+	// PRJ-1 のように見えるが別のチケットのファイル
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "PRJ-1-copy.md"), []byte("---\nkey: PRJ-99\ntitle: copy\ntype: task\n---\n"), 0644))
+	assert.NoError(t, os.Rename(filepath.Join(dir, "PRJ-12-other.md"), filepath.Join(dir, ".PRJ-12-other.md")))
+
+	path, err := FindFile(dir, "PRJ-1")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-1-fix-login.md"), path)
+
+	path, err = FindFile(dir, "PRJ-12")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	path, err = FindDeletedFile(dir, "PRJ-12")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ".PRJ-12-other.md"), path)
+
+	path, err = FindFile(dir, "PRJ-7")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "task-PRJ-7.md"), path)
+}
+
+func TestFileIndex(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	subDir := filepath.Join(dir, "epic")
+	assert.NoError(t, os.MkdirAll(subDir, 0755))
+	for _, tkt := range []*Ticket{
+		{Key: "PRJ-1", Title: "Fix login", Type: "task"},
+		{Key: "PRJ-2", Title: "Other", Type: "task"},
+	} {
+		_, err := tkt.SaveToFile(subDir, WithFilenameTemplate(keySlugFilenameTemplate))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, os.Rename(filepath.Join(subDir, "PRJ-2-other.md"), filepath.Join(subDir, ".PRJ-2-other.md")))
+
+	index := NewFileIndex(dir)
+	path, err := index.Find("PRJ-1")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(subDir, "PRJ-1-fix-login.md"), path)
+	path, err = index.FindDeleted("PRJ-2")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(subDir, ".PRJ-2-other.md"), path)
+
+	// ファイルは最初に探したときに1回だけ読み込み、以降は索引から探す
+	added := &Ticket{Key: "PRJ-3", Title: "Added", Type: "task"}
+	_, err = added.SaveToFile(subDir, WithFilenameTemplate(keySlugFilenameTemplate))
+	assert.NoError(t, err)
+	path, err = index.Find("PRJ-3")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	path, err = NewFileIndex(dir).Find("PRJ-3")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(subDir, "PRJ-3-added.md"), path)
+
+	// KEY.md は索引を使わずに見つける
+	keyOnly := &Ticket{Key: "PRJ-4", Title: "Key only", Type: "task"}
+	_, err = keyOnly.SaveToFile(dir)
+	assert.NoError(t, err)
+	path, err = index.Find("PRJ-4")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "PRJ-4.md"), path)
+}
+
+func TestCompareDirsMatchesByKey(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	cached := &Ticket{Key: "PRJ-1", Title: "Fix login", Type: "task", Body: "本文\n"}
+	_, err := cached.SaveToFile(cacheDir)
+	assert.NoError(t, err)
+
+	// タイトルを変えてスラッグ付きのファイル名が変わっても、削除と新規作成ではなく更新として扱う
+	local := *cached
+	local.Title = "Fix login timeout"
+	local.FilePath = ""
+	_, err = local.SaveToFile(localDir, WithFilenameTemplate(keySlugFilenameTemplate))
+	assert.NoError(t, err)
+
+	results, err := CompareDirs(localDir, cacheDir)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, ChangeUpdate, results[0].Change)
+		assert.True(t, results[0].HasDiff)
+		assert.Equal(t, []string{"title"}, results[0].ChangedFields)
+	}
+}
+
+func TestSaveToFileDrafts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// 同じ秒に作成した下書きも上書きし合わない
+	first := &Ticket{Title: "下書き1", Type: "task"}
+	second := &Ticket{Title: "下書き2", Type: "task"}
+	firstPath, err := first.SaveToFile(dir)
+	assert.NoError(t, err)
+	secondPath, err := second.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.NotEqual(t, firstPath, secondPath)
+	assert.Regexp(t, `^TMP-\d{8}-\d{6}-[a-z0-9]{6}\.md$`, filepath.Base(firstPath))
+	assert.FileExists(t, firstPath)
+	assert.FileExists(t, secondPath)
+
+	// 保存し直しても同じファイルを使う
+	first.Title = "下書き1 (編集)"
+	path, err := first.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, firstPath, path)
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+}
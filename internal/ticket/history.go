@@ -0,0 +1,216 @@
+package ticket
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pushの履歴はキャッシュディレクトリの .history に保存します (ドットで始まるディレクトリはチケットとして読み込まれません)
+const (
+	historyDir        = ".history"
+	historyFile       = "history.jsonl"
+	historyBackupsDir = "backups"
+	// historyMaxSize は履歴ファイルの上限です。超える場合は history.jsonl.1 にローテーションします。
+	historyMaxSize = 1 << 20
+)
+
+// historyMu はpushの並列処理から同時に履歴を書き込まないようにします
+var historyMu sync.Mutex
+
+// HistoryEntry はpushで行った1件の操作の記録です
+type HistoryEntry struct {
+	Time   time.Time  `json:"time"`
+	Key    string     `json:"key"`
+	Action ChangeType `json:"action"`
+	// ChangedFields は更新で送信した項目です
+	ChangedFields []string `json:"changed_fields,omitempty"`
+	// PreviousHash はpush前のキャッシュのファイルのSHA-256です (作成の場合は空)
+	PreviousHash string `json:"previous_hash,omitempty"`
+	// Backup はpush前のキャッシュの内容を保存したファイルの .history からの相対パスです
+	Backup string `json:"backup,omitempty"`
+}
+
+// ReadCacheFile はキャッシュのチケットのファイルの内容を返します (ファイルがない場合は nil)
+// pushでキャッシュを上書き・削除する前に読み込み、AppendHistory にpush前の内容として渡します。
+func ReadCacheFile(cacheDir, key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, CanonicalKey(key)+".md"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("キャッシュの読み込みに失敗しました: %v", err)
+	}
+	return data, nil
+}
+
+// saveBackup はpush前のキャッシュの内容を .history/backups に保存し、内容のハッシュと保存先を返します
+// 同じ内容のバックアップは1つだけ保存します。
+func saveBackup(dir string, data []byte) (hash, backup string, err error) {
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+	backup = filepath.ToSlash(filepath.Join(historyBackupsDir, hash+".md"))
+
+	path := filepath.Join(dir, filepath.FromSlash(backup))
+	if _, err := os.Stat(path); err == nil {
+		return hash, backup, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", fmt.Errorf("ディレクトリの作成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", "", fmt.Errorf("バックアップの保存に失敗しました: %v", err)
+	}
+	return hash, backup, nil
+}
+
+// HistoryBackupPath は履歴のバックアップのファイルのパスを返します (バックアップがない場合は空文字)
+func HistoryBackupPath(cacheDir string, entry HistoryEntry) string {
+	if entry.Backup == "" {
+		return ""
+	}
+	return filepath.Join(cacheDir, historyDir, filepath.FromSlash(entry.Backup))
+}
+
+// AppendHistory は履歴ファイルに1件追記します
+// previous にpush前のキャッシュの内容を渡すと、バックアップとして保存し、そのハッシュと保存先を記録します。
+func AppendHistory(cacheDir string, entry HistoryEntry, previous []byte) error {
+	return appendHistory(cacheDir, entry, previous, historyMaxSize)
+}
+
+func appendHistory(cacheDir string, entry HistoryEntry, previous []byte, maxSize int64) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	dir := filepath.Join(cacheDir, historyDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("ディレクトリの作成に失敗しました: %v", err)
+	}
+	entry.Key = CanonicalKey(entry.Key)
+	if previous != nil {
+		hash, backup, err := saveBackup(dir, previous)
+		if err != nil {
+			return err
+		}
+		entry.PreviousHash, entry.Backup = hash, backup
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("履歴の生成に失敗しました: %v", err)
+	}
+	line = append(line, '\n')
+
+	path := filepath.Join(dir, historyFile)
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(line)) > maxSize {
+		if err := rotateHistory(dir, entry); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("履歴ファイルを開けませんでした: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("履歴の書き込みに失敗しました: %v", err)
+	}
+	return nil
+}
+
+// rotateHistory は履歴ファイルを history.jsonl.1 に移し (それまでの .1 は削除)、
+// 残った履歴と追記する entry から参照されないバックアップを削除します。
+func rotateHistory(dir string, entry HistoryEntry) error {
+	path := filepath.Join(dir, historyFile)
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("履歴ファイルのローテーションに失敗しました: %v", err)
+	}
+
+	kept, err := readHistoryFile(path + ".1")
+	if err != nil {
+		return err
+	}
+	referenced := map[string]bool{entry.Backup: true}
+	for _, e := range kept {
+		referenced[e.Backup] = true
+	}
+	backups, err := os.ReadDir(filepath.Join(dir, historyBackupsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("バックアップの一覧の取得に失敗しました: %v", err)
+	}
+	for _, b := range backups {
+		rel := filepath.ToSlash(filepath.Join(historyBackupsDir, b.Name()))
+		if referenced[rel] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, historyBackupsDir, b.Name())); err != nil {
+			return fmt.Errorf("古いバックアップの削除に失敗しました: %v", err)
+		}
+	}
+	return nil
+}
+
+// ReadHistory はpushの履歴を古い順に返します。keyを指定した場合はそのチケットの履歴のみ返します。
+func ReadHistory(cacheDir, key string) ([]HistoryEntry, error) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	path := filepath.Join(cacheDir, historyDir, historyFile)
+	var entries []HistoryEntry
+	for _, p := range []string{path + ".1", path} {
+		es, err := readHistoryFile(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, es...)
+	}
+	if key == "" {
+		return entries, nil
+	}
+	key = CanonicalKey(key)
+	var filtered []HistoryEntry
+	for _, e := range entries {
+		if e.Key == key {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+func readHistoryFile(path string) ([]HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("履歴ファイルを開けませんでした: %v", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// 書き込み途中で中断された行などは読み飛ばす
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("履歴ファイルの読み込みに失敗しました: %v", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,80 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	cached := &Ticket{Key: "PRJ-1", Title: "push前", Type: "task", Body: "元の本文"}
+	_, err := cached.SaveToCache(cacheDir)
+	assert.NoError(t, err)
+
+	previous, err := ReadCacheFile(cacheDir, "prj-1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, previous)
+	// キャッシュにないチケット (作成) は nil
+	missing, err := ReadCacheFile(cacheDir, "PRJ-2")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, AppendHistory(cacheDir, HistoryEntry{Time: now, Key: "prj-1", Action: ChangeUpdate, ChangedFields: []string{"title"}}, previous))
+	assert.NoError(t, AppendHistory(cacheDir, HistoryEntry{Time: now.Add(time.Minute), Key: "PRJ-2", Action: ChangeCreate}, nil))
+
+	all, err := ReadHistory(cacheDir, "")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Empty(t, all[1].Backup)
+
+	got, err := ReadHistory(cacheDir, "prj-1")
+	assert.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "PRJ-1", got[0].Key)
+		assert.Equal(t, []string{"title"}, got[0].ChangedFields)
+		assert.Len(t, got[0].PreviousHash, 64)
+		restored, err := FromFile(HistoryBackupPath(cacheDir, got[0]))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "push前", restored.Title)
+			assert.Equal(t, "元の本文", restored.Body)
+		}
+	}
+
+	// 履歴ディレクトリのファイルはチケットとして読み込まない
+	files, _, err := WalkFiles(cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(cacheDir, "PRJ-1.md")}, files)
+}
+
+func TestAppendHistoryRotation(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	const maxSize = 300 // 2件で上限を超える大きさ
+	for _, title := range []string{"1回目", "2回目", "3回目"} {
+		assert.NoError(t, appendHistory(cacheDir, HistoryEntry{Key: "PRJ-1", Action: ChangeUpdate, ChangedFields: []string{title}}, []byte(title), maxSize))
+	}
+
+	entries, err := ReadHistory(cacheDir, "PRJ-1")
+	assert.NoError(t, err)
+	if !assert.Len(t, entries, 2, "1回目の履歴はローテーションで削除される") {
+		return
+	}
+	assert.Equal(t, []string{"2回目"}, entries[0].ChangedFields)
+	assert.Equal(t, []string{"3回目"}, entries[1].ChangedFields)
+
+	backups, err := os.ReadDir(filepath.Join(cacheDir, historyDir, historyBackupsDir))
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2, "参照されなくなったバックアップは削除する")
+	for _, e := range entries {
+		_, err = os.Stat(HistoryBackupPath(cacheDir, e))
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,36 @@
+package ticket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseHour は作業時間の文字列を解析します
+// 90m, 1.5h, 1h30m のような単位つきの表記と、単位のない数値 (時間) を受け付けます。
+func ParseHour(s string) (Hour, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("作業時間が空です")
+	}
+	if hours, err := strconv.ParseFloat(s, 64); err == nil {
+		if hours <= 0 {
+			return 0, fmt.Errorf("作業時間は正の値で指定してください: %s", s)
+		}
+		return Hour(hours), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("作業時間の形式が不正です (例: 90m, 1.5h, 1h30m): %s", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("作業時間は正の値で指定してください: %s", s)
+	}
+	return NewHour(d), nil
+}
+
+// Duration は時間をtime.Durationに変換します
+func (h Hour) Duration() time.Duration {
+	return time.Duration(float64(h) * float64(time.Hour))
+}
@@ -0,0 +1,42 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHour(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "分", input: "90m", want: 90 * time.Minute},
+		{name: "小数の時間", input: "1.5h", want: 90 * time.Minute},
+		{name: "時間と分", input: "1h30m", want: 90 * time.Minute},
+		{name: "単位なしは時間", input: "2", want: 2 * time.Hour},
+		{name: "空文字", input: "", wantErr: true},
+		{name: "ゼロ", input: "0m", wantErr: true},
+		{name: "負の値", input: "-1h", wantErr: true},
+		{name: "不正な形式", input: "1day", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseHour(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Duration())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package ticket
+
+import (
+	"maps"
+	"slices"
+)
+
+// CompareOption はCompareDirsの比較方法を変更するオプションです
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignoreFields []string
+	strict       bool
+	contextLines *int
+	rebases      map[string]PendingRebase
+}
+
+// WithIgnoreFields は指定した項目 (フロントマターのキーまたはbody) の差分を無視します
+// 設定ファイルの diff.ignore_fields に対応します。
+func WithIgnoreFields(fields []string) CompareOption {
+	return func(o *compareOptions) {
+		o.ignoreFields = fields
+	}
+}
+
+// ignoreCopiers は無視する項目をキャッシュの値にそろえる関数です (キーはchangedFieldsの項目名)
+var ignoreCopiers = map[string]func(local, cache *Ticket){
+	"title":              func(l, c *Ticket) { l.Title = c.Title },
+	"type":               func(l, c *Ticket) { l.Type = c.Type },
+	"parentKey":          func(l, c *Ticket) { l.ParentKey = c.ParentKey },
+	"status":             func(l, c *Ticket) { l.Status = c.Status },
+	"assignee":           func(l, c *Ticket) { l.Assignee = c.Assignee },
+	"sprint":             func(l, c *Ticket) { l.SprintName = c.SprintName },
+	"team":               func(l, c *Ticket) { l.Team = c.Team },
+	"original_estimate":  func(l, c *Ticket) { l.OriginalEstimate = c.OriginalEstimate },
+	"remaining_estimate": func(l, c *Ticket) { l.RemainingEstimate = c.RemainingEstimate },
+	"priority":           func(l, c *Ticket) { l.Priority = c.Priority },
+	"labels":             func(l, c *Ticket) { l.Labels = c.Labels },
+	"components":         func(l, c *Ticket) { l.Components = c.Components },
+	"fix_versions":       func(l, c *Ticket) { l.FixVersions = c.FixVersions },
+	"links":              func(l, c *Ticket) { l.Links = c.Links },
+	"flagged":            func(l, c *Ticket) { l.Flagged = c.Flagged },
+	"environment":        func(l, c *Ticket) { l.Environment = c.Environment },
+	"body":               func(l, c *Ticket) { l.Body = c.Body },
+}
+
+// applyIgnoreFields は無視する項目のローカルの値をキャッシュの値にそろえ、
+// ローカルで変更されていた無視対象の項目を返します (差分にはならないが気づけるように表示するため)。
+func applyIgnoreFields(local, cache *Ticket, ignore []string) []string {
+	if len(ignore) == 0 {
+		return nil
+	}
+	var drifted []string
+	for _, field := range changedFields(local, cache) {
+		if slices.Contains(ignore, field) {
+			drifted = append(drifted, field)
+		}
+	}
+	for _, field := range ignore {
+		if copyField, ok := ignoreCopiers[field]; ok {
+			copyField(local, cache)
+			continue
+		}
+		// それ以外はカスタムフィールドのキーとして扱う
+		value, inCache := cache.CustomFields[field]
+		_, inLocal := local.CustomFields[field]
+		if !inCache && !inLocal {
+			continue
+		}
+		local.CustomFields = maps.Clone(local.CustomFields)
+		if inCache {
+			local.setCustomField(field, value)
+		} else {
+			delete(local.CustomFields, field)
+		}
+	}
+	return drifted
+}
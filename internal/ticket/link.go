@@ -0,0 +1,61 @@
+package ticket
+
+import (
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Link はチケット間のリンクです
+// Typeはこのチケットから見たリンクの説明 (例: blocks, is blocked by, relates to) です。
+type Link struct {
+	Type string `yaml:"type"`
+	Key  string `yaml:"key"`
+}
+
+// MarshalYAML はリンクを1行のフロースタイル ({type: blocks, key: PRJ-45}) で出力します
+func (l Link) MarshalYAML() (interface{}, error) {
+	scalar := func(v string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+	}
+	return &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Style:   yaml.FlowStyle,
+		Content: []*yaml.Node{scalar("type"), scalar(l.Type), scalar("key"), scalar(l.Key)},
+	}, nil
+}
+
+// SortLinks はリンクを種類・キーの順に並べ替えます
+// JIRAから取得した順序とローカルで追加した順序の違いが差分にならないようにします。
+func SortLinks(links []Link) {
+	slices.SortFunc(links, func(a, b Link) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+}
+
+// parseLinks はフロントマターのリンクの一覧を取り出します
+func parseLinks(value interface{}) []Link {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var links []Link
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		linkType, _ := m["type"].(string)
+		key, _ := m["key"].(string)
+		if linkType == "" || key == "" {
+			continue
+		}
+		links = append(links, Link{Type: linkType, Key: key})
+	}
+	SortLinks(links)
+	return links
+}
@@ -0,0 +1,95 @@
+package ticket
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergeCategory はmerge/pullでキャッシュからワークスペースに取り込むファイルの分類です
+type MergeCategory string
+
+const (
+	// MergeNew はワークスペースにないチケット (リモートにのみ存在する) です
+	MergeNew MergeCategory = "new"
+	// MergeUpdate はワークスペースで編集されていないチケットです。上書きしても失われる変更はありません。
+	MergeUpdate MergeCategory = "update"
+	// MergeOverwrite はワークスペースで編集されているチケットです。上書きするとローカルの編集が失われます。
+	MergeOverwrite MergeCategory = "overwrite"
+)
+
+// MergeItem は取り込むファイル1件分です
+type MergeItem struct {
+	Key       string        `json:"key"`
+	Title     string        `json:"title"`
+	Category  MergeCategory `json:"category"`
+	CachePath string        `json:"cache_path"`
+	// LocalPath は上書きされるワークスペースのファイルです (MergeNew の場合は空)
+	LocalPath string `json:"local_path,omitempty"`
+}
+
+// MergePlan はmerge/pullで取り込むファイルの一覧と分類ごとの件数です
+type MergePlan struct {
+	Items []MergeItem `json:"items"`
+	// Incoming は取り込むファイルの総数です
+	Incoming int `json:"incoming"`
+	// LocalEdits はそのうちローカルの編集が失われるファイルの数です
+	LocalEdits int `json:"local_edits"`
+	// New はワークスペースにないチケットの数です
+	New int `json:"new"`
+}
+
+// PlanMerge はキャッシュ→ワークスペースの向きで比較したCompareDirsの結果を分類します
+//
+// ローカルの updated_at がキャッシュと同じ (リモートが変わっていない) のに差分がある場合はローカルの編集です。
+// リモートが更新されている場合は、フェッチ前のキャッシュ (base) と比べてローカルが編集されていないことを確認できたときのみ
+// MergeUpdate とし、確認できなければ安全側に倒して MergeOverwrite とします。
+func PlanMerge(diffs []DiffResult, localDir string, base map[string]*Ticket) (MergePlan, error) {
+	plan := MergePlan{Items: []MergeItem{}}
+	localFiles := NewFileIndex(localDir)
+	for _, d := range diffs {
+		if !d.HasDiff {
+			continue
+		}
+		item := MergeItem{
+			Key:       d.Key,
+			Title:     d.Title,
+			CachePath: d.FilePath,
+		}
+		if d.Change == ChangeCreate {
+			item.Category = MergeNew
+			plan.New++
+			plan.Items = append(plan.Items, item)
+			continue
+		}
+
+		localPath, err := localFiles.Find(d.Key)
+		if err != nil {
+			return MergePlan{}, err
+		}
+		local, err := FromFile(localPath)
+		if err != nil {
+			return MergePlan{}, fmt.Errorf("%s の読み込みに失敗しました: %v", localPath, err)
+		}
+		remote, err := FromFile(d.FilePath)
+		if err != nil {
+			return MergePlan{}, fmt.Errorf("%s の読み込みに失敗しました: %v", d.FilePath, err)
+		}
+		item.LocalPath = localPath
+		item.Category = MergeOverwrite
+		if remote.UpdatedAt.After(local.UpdatedAt) {
+			if b, ok := base[d.Key]; ok && local.SameContent(b) {
+				item.Category = MergeUpdate
+			}
+		}
+		if item.Category == MergeOverwrite {
+			plan.LocalEdits++
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	plan.Incoming = len(plan.Items)
+
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		return plan.Items[i].Key < plan.Items[j].Key
+	})
+	return plan, nil
+}
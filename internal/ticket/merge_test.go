@@ -0,0 +1,64 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanMerge(t *testing.T) {
+	t.Parallel()
+
+	localDir := t.TempDir()
+	baseDir := t.TempDir()
+	cacheDir := t.TempDir()
+	fetchedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	updatedAt := fetchedAt.Add(time.Hour)
+
+	save := func(dir string, tkt Ticket) {
+		_, err := tkt.SaveToFile(dir)
+		assert.NoError(t, err)
+	}
+	for _, key := range []string{"PRJ-1", "PRJ-2", "PRJ-3"} {
+		tkt := Ticket{Key: key, Title: key, Type: "task", Body: "本文\n", UpdatedAt: fetchedAt}
+		save(baseDir, tkt)
+		save(localDir, tkt)
+	}
+	// PRJ-1: リモートで更新され、ローカルは未編集
+	save(cacheDir, Ticket{Key: "PRJ-1", Title: "PRJ-1", Type: "task", Body: "リモートの変更\n", UpdatedAt: updatedAt})
+	// PRJ-2: リモートで更新され、ローカルも編集
+	save(cacheDir, Ticket{Key: "PRJ-2", Title: "PRJ-2", Type: "task", Body: "リモートの変更\n", UpdatedAt: updatedAt})
+	save(localDir, Ticket{Key: "PRJ-2", Title: "PRJ-2", Type: "task", Body: "ローカルの変更\n", UpdatedAt: fetchedAt})
+	// PRJ-3: リモートは変わらず、ローカルのみ編集
+	save(cacheDir, Ticket{Key: "PRJ-3", Title: "PRJ-3", Type: "task", Body: "本文\n", UpdatedAt: fetchedAt})
+	save(localDir, Ticket{Key: "PRJ-3", Title: "PRJ-3", Type: "task", Body: "ローカルの変更\n", UpdatedAt: fetchedAt})
+	// PRJ-4: リモートにのみ存在
+	save(cacheDir, Ticket{Key: "PRJ-4", Title: "PRJ-4", Type: "task", Body: "本文\n", UpdatedAt: updatedAt})
+
+	base, err := LoadDir(baseDir)
+	assert.NoError(t, err)
+	diffs, err := CompareDirs(cacheDir, localDir)
+	assert.NoError(t, err)
+
+	plan, err := PlanMerge(diffs, localDir, base)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, plan.Incoming)
+	assert.Equal(t, 2, plan.LocalEdits)
+	assert.Equal(t, 1, plan.New)
+	categories := make(map[string]MergeCategory)
+	for _, item := range plan.Items {
+		categories[item.Key] = item.Category
+	}
+	assert.Equal(t, map[string]MergeCategory{
+		"PRJ-1": MergeUpdate,
+		"PRJ-2": MergeOverwrite,
+		"PRJ-3": MergeOverwrite,
+		"PRJ-4": MergeNew,
+	}, categories)
+
+	// フェッチ前のキャッシュがなければ、リモートで更新されたチケットは安全側に倒して上書き扱い
+	plan, err = PlanMerge(diffs, localDir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, plan.LocalEdits)
+}
@@ -0,0 +1,106 @@
+package ticket
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pendingCreatesDir はpushで作成中の下書きの記録を保存するキャッシュディレクトリ内のディレクトリです
+// (ドットで始まるディレクトリはチケットとして読み込まれません)
+const pendingCreatesDir = ".pending"
+
+// pendingCreateMargin は作成済みのチケットを探すときに、記録した時刻より前に広げる幅です
+// JIRAとこのマシンの時計のずれを吸収します。
+const pendingCreateMargin = time.Minute
+
+// PendingCreate はpushで作成を始めた下書きの記録です
+// 作成のリクエストがタイムアウトした場合、JIRAではチケットが作成済みのことがあります。
+// 再実行時にこの記録から作成済みのチケットを探し、同じチケットを二重に作成しないようにします。
+type PendingCreate struct {
+	// DraftID は下書きのファイル名 (拡張子を除く) です
+	DraftID string `json:"draft_id"`
+	// TitleHash は作成時のタイトルのSHA-256です
+	TitleHash string    `json:"title_hash"`
+	Time      time.Time `json:"time"`
+}
+
+// DraftID は下書きのファイルのパスから下書きを識別するIDを返します
+func DraftID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), ".md")
+}
+
+// NewPendingCreate は下書きの作成を始めたことを表す記録を返します
+func NewPendingCreate(path, title string, now time.Time) PendingCreate {
+	return PendingCreate{DraftID: DraftID(path), TitleHash: titleHash(title), Time: now}
+}
+
+func titleHash(title string) string {
+	sum := sha256.Sum256([]byte(title))
+	return hex.EncodeToString(sum[:])
+}
+
+// Since は作成済みのチケットを探すときの作成日時の下限を返します
+func (p *PendingCreate) Since() time.Time {
+	return p.Time.Add(-pendingCreateMargin)
+}
+
+// SameTitle はタイトルが記録したときと同じかどうかを返します
+func (p *PendingCreate) SameTitle(title string) bool {
+	return p.TitleHash == titleHash(title)
+}
+
+// Matches はチケットがこの記録の作成で作られたものと考えられるかどうかを返します
+// タイトルが記録と完全に一致し、記録した時刻以降に作成されたチケットが該当します。
+func (p *PendingCreate) Matches(t *Ticket) bool {
+	return p.SameTitle(t.Title) && !t.CreatedAt.Before(p.Since())
+}
+
+func pendingCreatePath(cacheDir, draftID string) string {
+	return filepath.Join(cacheDir, pendingCreatesDir, draftID+".json")
+}
+
+// SavePendingCreate は下書きの作成を始めたことを記録します
+func SavePendingCreate(cacheDir string, p PendingCreate) error {
+	path := pendingCreatePath(cacheDir, p.DraftID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("作成中の記録のディレクトリの作成に失敗しました: %v", err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("作成中の記録の生成に失敗しました: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("作成中の記録の保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ReadPendingCreate は下書きの作成中の記録を読み込みます (記録がない場合は nil)
+func ReadPendingCreate(cacheDir, draftID string) (*PendingCreate, error) {
+	data, err := os.ReadFile(pendingCreatePath(cacheDir, draftID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("作成中の記録の読み込みに失敗しました: %v", err)
+	}
+	var p PendingCreate
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("作成中の記録のパースに失敗しました: %v", err)
+	}
+	return &p, nil
+}
+
+// ClearPendingCreate は作成が完了した下書きの記録を削除します
+func ClearPendingCreate(cacheDir, draftID string) error {
+	if err := os.Remove(pendingCreatePath(cacheDir, draftID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("作成中の記録の削除に失敗しました: %v", err)
+	}
+	return nil
+}
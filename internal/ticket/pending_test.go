@@ -0,0 +1,61 @@
+package ticket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingCreateMatches(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2025, 6, 1, 15, 4, 5, 0, time.UTC)
+	pending := NewPendingCreate("tickets/TMP-20250601-150405-k3x9qa.md", "ログイン画面の修正", started)
+
+	tests := []struct {
+		name    string
+		created time.Time
+		title   string
+		want    bool
+	}{
+		{name: "記録の後に同じタイトルで作成された", created: started.Add(2 * time.Second), title: "ログイン画面の修正", want: true},
+		{name: "時計のずれの範囲内", created: started.Add(-30 * time.Second), title: "ログイン画面の修正", want: true},
+		{name: "記録より前に作成された", created: started.Add(-time.Hour), title: "ログイン画面の修正", want: false},
+		{name: "タイトルが異なる", created: started.Add(2 * time.Second), title: "ログイン画面の修正 (2)", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, pending.Matches(&Ticket{Key: "PRJ-1", Title: tt.title, CreatedAt: tt.created}))
+		})
+	}
+}
+
+func TestPendingCreateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	pending := NewPendingCreate("TMP-20250601-150405-k3x9qa.md", "タイトル", time.Date(2025, 6, 1, 15, 4, 5, 0, time.UTC))
+	assert.Equal(t, "TMP-20250601-150405-k3x9qa", pending.DraftID)
+
+	got, err := ReadPendingCreate(cacheDir, pending.DraftID)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, SavePendingCreate(cacheDir, pending))
+	got, err = ReadPendingCreate(cacheDir, pending.DraftID)
+	assert.NoError(t, err)
+	assert.Equal(t, &pending, got)
+
+	// 記録はチケットとして読み込まれない
+	files, _, err := WalkFiles(cacheDir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	assert.NoError(t, ClearPendingCreate(cacheDir, pending.DraftID))
+	got, err = ReadPendingCreate(cacheDir, pending.DraftID)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.NoError(t, ClearPendingCreate(cacheDir, pending.DraftID))
+}
@@ -0,0 +1,32 @@
+package ticket
+
+// readonlyEdits はローカルで変更されているが、pushで反映されない読み取り専用の項目を返します
+// created_at と creator はリモートでも変わらないので、キャッシュと異なればローカルの編集です。
+// reporter などはリモートでも変わるため、ワークスペースのファイルがキャッシュと同じ版 (updated_at が同じ) の場合のみ比較します。
+// 古い版のファイルで比較すると、リモートでの変更をローカルの編集と取り違えるためです。
+func readonlyEdits(local, cache *Ticket) []string {
+	var fields []string
+	if !local.CreatedAt.Equal(cache.CreatedAt) {
+		fields = append(fields, "created_at")
+	}
+	// creator のない古いファイルは編集とみなさない
+	if local.Creator != "" && local.Creator != cache.Creator {
+		fields = append(fields, "creator")
+	}
+
+	switch {
+	case local.UpdatedAt.After(cache.UpdatedAt), local.UpdatedAt.IsZero() && !cache.UpdatedAt.IsZero():
+		fields = append(fields, "updated_at")
+	case local.UpdatedAt.Equal(cache.UpdatedAt):
+		if local.Reporter != cache.Reporter {
+			fields = append(fields, "reporter")
+		}
+		if local.TimeSpent != cache.TimeSpent {
+			fields = append(fields, "time_spent")
+		}
+		if local.URL != cache.URL {
+			fields = append(fields, "url")
+		}
+	}
+	return fields
+}
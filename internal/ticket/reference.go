@@ -0,0 +1,47 @@
+package ticket
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Reference は本文から参照しているConfluenceのページです (readonly)
+// ページのタイトルを解決できなかった場合、Titleは空になります。
+type Reference struct {
+	Title string `yaml:"title" json:"title,omitempty"`
+	URL   string `yaml:"url" json:"url"`
+}
+
+// MarshalYAML は参照を1行のフロースタイル ({title: 設計書, url: https://...}) で出力します
+func (r Reference) MarshalYAML() (interface{}, error) {
+	scalar := func(v string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	if r.Title != "" {
+		node.Content = append(node.Content, scalar("title"), scalar(r.Title))
+	}
+	node.Content = append(node.Content, scalar("url"), scalar(r.URL))
+	return node, nil
+}
+
+// parseReferences はフロントマターの参照の一覧を取り出します
+func parseReferences(value interface{}) []Reference {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var refs []Reference
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		url, _ := m["url"].(string)
+		if url == "" {
+			continue
+		}
+		title, _ := m["title"].(string)
+		refs = append(refs, Reference{Title: title, URL: url})
+	}
+	return refs
+}
@@ -0,0 +1,55 @@
+package ticket
+
+import (
+	"strings"
+)
+
+// HasValue はフロントマターのキー (本文の場合は body) に値が入力されているかを返します
+// 作成時の必須項目が入力されているかの確認に使います。
+func (t *Ticket) HasValue(key string) bool {
+	switch key {
+	case "body":
+		return strings.TrimSpace(t.Body) != ""
+	case "title":
+		return t.Title != ""
+	case "parentKey":
+		return t.ParentKey != ""
+	case "assignee":
+		return t.Assignee != ""
+	case "sprint":
+		return t.SprintName != ""
+	case "team":
+		return t.Team != ""
+	case "priority":
+		return t.Priority != ""
+	case "labels":
+		return len(t.Labels) > 0
+	case "components":
+		return len(t.Components) > 0
+	case "fix_versions":
+		return len(t.FixVersions) > 0
+	case "environment":
+		return t.Environment != nil && *t.Environment != ""
+	case "original_estimate":
+		return t.OriginalEstimate != 0
+	case "remaining_estimate":
+		return t.RemainingEstimate != 0
+	case "flagged":
+		return t.Flagged != nil
+	}
+	return t.CustomFields[key] != nil
+}
+
+// InsertFrontmatterLines はマークダウンのフロントマターの末尾に行を追加します
+// 下書きに必須項目の空の値やコメントを追加するために使います。フロントマターがない場合はそのまま返します。
+func InsertFrontmatterLines(content string, lines []string) string {
+	if len(lines) == 0 || !strings.HasPrefix(content, "---\n") {
+		return content
+	}
+	end := strings.Index(content[len("---\n"):], "\n---\n")
+	if end < 0 {
+		return content
+	}
+	end += len("---\n") + 1
+	return content[:end] + strings.Join(lines, "\n") + "\n" + content[end:]
+}
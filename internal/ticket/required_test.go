@@ -0,0 +1,49 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertFrontmatterLines(t *testing.T) {
+	t.Parallel()
+
+	draft := &Ticket{Title: "タイトル", Type: "bug", Body: "本文\n"}
+	content := InsertFrontmatterLines(draft.ToMarkdown(), []string{
+		"priority: # 必須: Priority",
+		"story_points: # 必須: Story Points",
+		"# 必須: Epic Name (customfield_10011) はtktから設定できません",
+	})
+	assert.Equal(t, "---\ntitle: タイトル\ntype: bug\npriority: # 必須: Priority\nstory_points: # 必須: Story Points\n# 必須: Epic Name (customfield_10011) はtktから設定できません\n---\n\n本文\n", content)
+
+	// 空の入力欄は未入力として読み込む
+	path := filepath.Join(t.TempDir(), "TMP-20250601-150405-k3x9qa.md")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "本文\n", loaded.Body)
+	assert.False(t, loaded.HasValue("priority"))
+	assert.False(t, loaded.HasValue("story_points"))
+	assert.True(t, loaded.HasValue("body"))
+
+	// フロントマターがない場合はそのまま
+	assert.Equal(t, "本文\n", InsertFrontmatterLines("本文\n", []string{"priority:"}))
+}
+
+func TestHasValue(t *testing.T) {
+	t.Parallel()
+
+	points := 3.0
+	empty := ""
+	tk := &Ticket{Priority: "High", Labels: []string{"backend"}, Environment: &empty, CustomFields: map[string]*float64{"story_points": &points, "risk": nil}}
+	assert.True(t, tk.HasValue("priority"))
+	assert.True(t, tk.HasValue("labels"))
+	assert.False(t, tk.HasValue("components"))
+	assert.False(t, tk.HasValue("environment"))
+	assert.True(t, tk.HasValue("story_points"))
+	assert.False(t, tk.HasValue("risk"))
+	assert.False(t, tk.HasValue("body"))
+}
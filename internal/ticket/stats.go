@@ -0,0 +1,58 @@
+package ticket
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// BodyStats はチケット本文の文字数と単語数です
+type BodyStats struct {
+	// Chars は空白を除いた文字数 (rune数) です
+	Chars int
+	// Words は単語数です。日本語などのCJK文字は1文字を1語として数えます。
+	Words int
+}
+
+// CountBody は本文の文字数と単語数を数えます
+// 英語などは空白区切りで、日本語などのCJK文字は1文字ずつ数えます。
+func CountBody(body string) BodyStats {
+	var stats BodyStats
+	inWord := false
+	for _, r := range body {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		stats.Chars++
+		if isCJK(r) {
+			stats.Words++
+			inWord = false
+			continue
+		}
+		if !inWord {
+			stats.Words++
+			inWord = true
+		}
+	}
+	return stats
+}
+
+// BodyStats はチケット本文の文字数と単語数を返します (コメントは含みません)
+func (t *Ticket) BodyStats() BodyStats {
+	return CountBody(t.Body)
+}
+
+// isCJK は単語の区切りに空白を使わない文字かどうかを返します
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		// 全角の句読点・記号
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+// FormatChars は文字数を短い表記にします (例: 980 chars, 1.2k chars)
+func FormatChars(n int) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d chars", n)
+	}
+	return fmt.Sprintf("%.1fk chars", float64(n)/1000)
+}
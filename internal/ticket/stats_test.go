@@ -0,0 +1,53 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want BodyStats
+	}{
+		{name: "空", body: "", want: BodyStats{}},
+		{name: "英語", body: "Fix the login bug\n", want: BodyStats{Chars: 14, Words: 4}},
+		{name: "日本語", body: "ログイン画面の改善", want: BodyStats{Chars: 9, Words: 9}},
+		{name: "句読点を含む日本語", body: "確認しました。", want: BodyStats{Chars: 7, Words: 7}},
+		{name: "日本語と英語の混在", body: "APIの修正 (v2)", want: BodyStats{Chars: 10, Words: 5}},
+		{name: "改行とMarkdown", body: "## 概要\n\n- item one\n- item two", want: BodyStats{Chars: 20, Words: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, CountBody(tt.body))
+		})
+	}
+}
+
+func TestFormatChars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{name: "0", n: 0, want: "0 chars"},
+		{name: "1000未満", n: 999, want: "999 chars"},
+		{name: "1000以上", n: 1234, want: "1.2k chars"},
+		{name: "10000以上", n: 12345, want: "12.3k chars"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, FormatChars(tt.n))
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package ticket
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// SyncDirection は同期の方向を表します
+type SyncDirection string
+
+const (
+	// SyncIncoming はリモートの変更をローカルに取り込むことを表します
+	SyncIncoming SyncDirection = "incoming"
+	// SyncOutgoing はローカルの変更をリモートに反映することを表します
+	SyncOutgoing SyncDirection = "outgoing"
+	// SyncConflict はローカルとリモートの両方で変更されていることを表します
+	SyncConflict SyncDirection = "conflict"
+)
+
+// SyncItem は同期計画の1件分を表します
+type SyncItem struct {
+	Key       string
+	Title     string
+	Direction SyncDirection
+	// LocalPath はワークスペースのファイルパスです。リモートで新規作成されたチケットの場合は取り込み先のパスです。
+	LocalPath string
+	// CachePath はキャッシュのファイルパスです。ローカルで新規作成されたチケットの場合は空です。
+	CachePath string
+	Reason    string
+}
+
+// LoadDir はディレクトリ以下 (サブディレクトリを含む) のkeyを持つチケットをkeyごとに読み込みます
+// ドットで始まるファイル（削除マークされたもの）は含みません。
+func LoadDir(dir string) (map[string]*Ticket, error) {
+	files, _, err := WalkFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make(map[string]*Ticket)
+	for _, file := range files {
+		t, err := FromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", file, err)
+		}
+		if t.Key != "" {
+			tickets[t.Key] = t
+		}
+	}
+	return tickets, nil
+}
+
+// PlanSync はローカル・フェッチ前のキャッシュ・フェッチ後のキャッシュを比較して同期計画を作成します
+//
+// リモートが変更されたかどうかはローカルとリモートの updated_at で判断します。
+// リモートが変更されている場合、ローカルがフェッチ前のキャッシュ (base) と同じ内容であれば取り込み、
+// そうでなければどちらの変更を優先すべきか判断できないため競合として扱います。
+// opts のうち WithStrictDetection のみを使用します。
+func PlanSync(localDir, cacheDir string, base map[string]*Ticket, opts ...CompareOption) ([]SyncItem, error) {
+	var o compareOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	remote, err := LoadDir(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []SyncItem
+	seen := make(map[string]bool)
+
+	// サブディレクトリも含めて探す (.tktignore のパターンに一致するファイルは対象外)
+	localFiles, deletedFiles, err := WalkFiles(localDir)
+	if err != nil {
+		return nil, fmt.Errorf("ローカルファイルの検索に失敗しました: %v", err)
+	}
+
+	// 削除マークされたチケット
+	for _, file := range deletedFiles {
+		local, err := FromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", file, err)
+		}
+		if !o.isTicket(local) {
+			continue
+		}
+		seen[local.Key] = true
+		r, ok := remote[local.Key]
+		if !ok {
+			continue
+		}
+		item := SyncItem{
+			Key:       local.Key,
+			Title:     local.Title,
+			LocalPath: file,
+			CachePath: r.FilePath,
+		}
+		if r.UpdatedAt.After(local.UpdatedAt) {
+			item.Direction = SyncConflict
+			item.Reason = "ローカルで削除されましたが、リモートで更新されています"
+		} else {
+			item.Direction = SyncOutgoing
+			item.Reason = "削除"
+		}
+		items = append(items, item)
+	}
+
+	for _, file := range localFiles {
+		local, err := FromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", file, err)
+		}
+		if !o.isTicket(local) {
+			continue
+		}
+
+		// keyがないチケットは新規作成
+		if local.Key == "" {
+			items = append(items, SyncItem{
+				Title:     local.Title,
+				Direction: SyncOutgoing,
+				LocalPath: file,
+				Reason:    "新規作成",
+			})
+			continue
+		}
+		if seen[local.Key] {
+			continue
+		}
+		seen[local.Key] = true
+
+		r, ok := remote[local.Key]
+		if !ok {
+			// JQLの範囲外になったチケットなどは対象外
+			continue
+		}
+		if local.SameContent(r) {
+			continue
+		}
+
+		item := SyncItem{
+			Key:       local.Key,
+			Title:     local.Title,
+			LocalPath: file,
+			CachePath: r.FilePath,
+		}
+
+		if !r.UpdatedAt.After(local.UpdatedAt) {
+			// リモートはローカルの取得時点から変わっていない
+			item.Direction = SyncOutgoing
+			item.Reason = "更新"
+			items = append(items, item)
+			continue
+		}
+
+		// リモートが更新されている場合、ローカルが編集されていなければ取り込む
+		if b, ok := base[local.Key]; ok && local.SameContent(b) {
+			item.Direction = SyncIncoming
+			item.Reason = "リモートで更新"
+		} else {
+			item.Direction = SyncConflict
+			item.Reason = "ローカルとリモートの両方で変更されています"
+		}
+		items = append(items, item)
+	}
+
+	// リモートにのみ存在するチケット
+	for key, r := range remote {
+		if seen[key] {
+			continue
+		}
+		items = append(items, SyncItem{
+			Key:       key,
+			Title:     r.Title,
+			Direction: SyncIncoming,
+			LocalPath: filepath.Join(localDir, filepath.Base(r.FilePath)),
+			CachePath: r.FilePath,
+			Reason:    "リモートで新規作成",
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items, nil
+}
+
+// SameContent はreadonly項目以外の内容が一致するかを返します
+func (t *Ticket) SameContent(other *Ticket) bool {
+	if !t.HasNonReadonlyDiff(other) {
+		return true
+	}
+	return format(t.ToMarkdownWithoutReadonly()) == format(other.ToMarkdownWithoutReadonly())
+}
+
+// SyncBack はpush後にJIRAから取得し直したチケットのうち、pushした項目 (changedFields の名前) の値をローカルのチケットに反映します
+// JIRAが正規化した内容 (末尾の空白の削除、表の書式など) をローカルにも反映し、次の差分に出ないようにします。
+// pushしていない項目 (diff.ignore_fields の項目など) はローカルの値を残し、readonly項目はリモートの値にそろえます。
+func SyncBack(local, remote *Ticket, pushed []string) *Ticket {
+	synced := *local
+	for _, field := range pushed {
+		copyField(&synced, remote, field)
+	}
+	synced.copyReadonly(remote)
+	return &synced
+}
+
+// CreatedFields は新規作成でJIRAに送った項目 (changedFields の名前) を返します
+// 作成後の SyncBack に渡します。
+func CreatedFields(t *Ticket) []string {
+	return changedFields(t, &Ticket{})
+}
+
+// copyReadonly はreadonly項目 (更新日時やコメントなど) を src の値にそろえます
+// readonly項目はpushされないため、そろえてもローカルの編集は失われません。
+func (t *Ticket) copyReadonly(src *Ticket) {
+	t.StatusCategory = src.StatusCategory
+	t.Reporter = src.Reporter
+	t.Creator = src.Creator
+	t.Epic = src.Epic
+	t.TypeID = src.TypeID
+	t.CreatedAt = src.CreatedAt
+	t.UpdatedAt = src.UpdatedAt
+	t.TimeSpent = src.TimeSpent
+	t.URL = src.URL
+	t.Comments = src.Comments
+}
+
+// RefreshReadonly はリモートと内容が一致しているローカルのチケットについて、
+// updated_at などのreadonly項目をキャッシュの値に更新し、更新した件数を返します。
+// これにより、次回の同期でリモートの変更を正しく検出できるようになります。
+func RefreshReadonly(localDir, cacheDir string) (int, error) {
+	remote, err := LoadDir(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+	local, err := LoadDir(localDir)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for key, l := range local {
+		r, ok := remote[key]
+		if !ok || !l.SameContent(r) {
+			continue
+		}
+		if l.UpdatedAt.Equal(r.UpdatedAt) && l.StatusCategory == r.StatusCategory && l.Resolution == r.Resolution && l.Assignee == r.Assignee && l.Reporter == r.Reporter && l.Creator == r.Creator && l.Epic == r.Epic && l.URL == r.URL && sameComments(l.Comments, r.Comments) {
+			continue
+		}
+		l.copyReadonly(r)
+		l.Resolution = r.Resolution
+		l.Assignee = r.Assignee
+		// サブディレクトリにあるチケットはそのディレクトリに保存し直す
+		if _, err := l.SaveToFile(filepath.Dir(l.FilePath)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
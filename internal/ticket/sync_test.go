@@ -0,0 +1,232 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanSync(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	newTicket := func(key, title string, updatedAt time.Time) *Ticket {
+		return &Ticket{Key: key, Title: title, Type: "task", UpdatedAt: updatedAt, Body: "本文\n"}
+	}
+
+	tests := []struct {
+		name   string
+		local  []*Ticket
+		base   []*Ticket
+		remote []*Ticket
+		want   map[string]SyncDirection
+	}{
+		{
+			name:   "差分なし",
+			local:  []*Ticket{newTicket("PRJ-1", "a", t1)},
+			base:   []*Ticket{newTicket("PRJ-1", "a", t1)},
+			remote: []*Ticket{newTicket("PRJ-1", "a", t1)},
+			want:   map[string]SyncDirection{},
+		},
+		{
+			name:   "ローカルのみ変更",
+			local:  []*Ticket{newTicket("PRJ-1", "local", t1)},
+			base:   []*Ticket{newTicket("PRJ-1", "a", t1)},
+			remote: []*Ticket{newTicket("PRJ-1", "a", t1)},
+			want:   map[string]SyncDirection{"PRJ-1": SyncOutgoing},
+		},
+		{
+			name:   "リモートのみ変更",
+			local:  []*Ticket{newTicket("PRJ-1", "a", t1)},
+			base:   []*Ticket{newTicket("PRJ-1", "a", t1)},
+			remote: []*Ticket{newTicket("PRJ-1", "remote", t2)},
+			want:   map[string]SyncDirection{"PRJ-1": SyncIncoming},
+		},
+		{
+			name:   "両方で変更",
+			local:  []*Ticket{newTicket("PRJ-1", "local", t1)},
+			base:   []*Ticket{newTicket("PRJ-1", "a", t1)},
+			remote: []*Ticket{newTicket("PRJ-1", "remote", t2)},
+			want:   map[string]SyncDirection{"PRJ-1": SyncConflict},
+		},
+		{
+			name:   "基準がない場合は競合",
+			local:  []*Ticket{newTicket("PRJ-1", "a", t1)},
+			base:   nil,
+			remote: []*Ticket{newTicket("PRJ-1", "remote", t2)},
+			want:   map[string]SyncDirection{"PRJ-1": SyncConflict},
+		},
+		{
+			name:   "リモートで新規作成",
+			local:  nil,
+			base:   nil,
+			remote: []*Ticket{newTicket("PRJ-2", "new", t2)},
+			want:   map[string]SyncDirection{"PRJ-2": SyncIncoming},
+		},
+		{
+			name:   "ローカルで新規作成",
+			local:  []*Ticket{newTicket("", "draft", time.Time{})},
+			base:   nil,
+			remote: nil,
+			want:   map[string]SyncDirection{"": SyncOutgoing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			localDir := t.TempDir()
+			cacheDir := t.TempDir()
+			for _, lt := range tt.local {
+				_, err := lt.SaveToFile(localDir)
+				assert.NoError(t, err)
+			}
+			for _, rt := range tt.remote {
+				_, err := rt.SaveToFile(cacheDir)
+				assert.NoError(t, err)
+			}
+			base := make(map[string]*Ticket)
+			for _, bt := range tt.base {
+				base[bt.Key] = bt
+			}
+
+			items, err := PlanSync(localDir, cacheDir, base)
+			assert.NoError(t, err)
+
+			got := make(map[string]SyncDirection)
+			for _, item := range items {
+				got[item.Key] = item.Direction
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPlanSyncDeleted(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	deleted := &Ticket{Key: "PRJ-1", Title: "a", Type: "task", UpdatedAt: t1}
+	path, err := deleted.SaveToFile(localDir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Rename(path, filepath.Join(localDir, ".PRJ-1.md")))
+	_, err = deleted.SaveToFile(cacheDir)
+	assert.NoError(t, err)
+
+	items, err := PlanSync(localDir, cacheDir, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, SyncOutgoing, items[0].Direction)
+		assert.Equal(t, filepath.Join(localDir, ".PRJ-1.md"), items[0].LocalPath)
+	}
+}
+
+func TestPlanSyncNested(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	subDir := filepath.Join(localDir, "epic")
+	assert.NoError(t, os.MkdirAll(subDir, 0755))
+
+	// サブディレクトリでローカルのみ変更したチケット
+	edited := &Ticket{Key: "PRJ-1", Title: "local", Type: "task", UpdatedAt: t1, Body: "本文\n"}
+	_, err := edited.SaveToFile(subDir)
+	assert.NoError(t, err)
+	// サブディレクトリで削除マークを付けたチケット
+	deleted := &Ticket{Key: "PRJ-2", Title: "a", Type: "task", UpdatedAt: t1, Body: "本文\n"}
+	path, err := deleted.SaveToFile(subDir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Rename(path, filepath.Join(subDir, ".PRJ-2.md")))
+
+	for _, rt := range []*Ticket{
+		{Key: "PRJ-1", Title: "a", Type: "task", UpdatedAt: t1, Body: "本文\n"},
+		{Key: "PRJ-2", Title: "a", Type: "task", UpdatedAt: t1, Body: "本文\n"},
+	} {
+		_, err := rt.SaveToFile(cacheDir)
+		assert.NoError(t, err)
+	}
+
+	items, err := PlanSync(localDir, cacheDir, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "PRJ-1", items[0].Key)
+		assert.Equal(t, SyncOutgoing, items[0].Direction)
+		assert.Equal(t, filepath.Join(subDir, "PRJ-1.md"), items[0].LocalPath)
+		assert.Equal(t, "PRJ-2", items[1].Key)
+		assert.Equal(t, SyncOutgoing, items[1].Direction)
+		assert.Equal(t, filepath.Join(subDir, ".PRJ-2.md"), items[1].LocalPath)
+	}
+}
+
+func TestRefreshReadonlyNested(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	localDir := t.TempDir()
+	cacheDir := t.TempDir()
+	subDir := filepath.Join(localDir, "epic")
+	assert.NoError(t, os.MkdirAll(subDir, 0755))
+
+	local := &Ticket{Key: "PRJ-1", Title: "a", Type: "task", UpdatedAt: t1, Body: "本文\n"}
+	_, err := local.SaveToFile(subDir)
+	assert.NoError(t, err)
+	remote := &Ticket{Key: "PRJ-1", Title: "a", Type: "task", UpdatedAt: t2, Body: "本文\n"}
+	_, err = remote.SaveToFile(cacheDir)
+	assert.NoError(t, err)
+
+	count, err := RefreshReadonly(localDir, cacheDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// サブディレクトリのファイルを更新し、ルートには保存しない
+	assert.NoFileExists(t, filepath.Join(localDir, "PRJ-1.md"))
+	got, err := FromFile(filepath.Join(subDir, "PRJ-1.md"))
+	assert.NoError(t, err)
+	assert.True(t, t2.Equal(got.UpdatedAt))
+}
+
+func TestSyncBack(t *testing.T) {
+	t.Parallel()
+
+	three, five := 3.0, 5.0
+	updated := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	local := &Ticket{
+		Key: "PRJ-1", Title: "ローカルで変更したタイトル", Type: "task", Status: "To Do", Marker: true, FilePath: "tickets/PRJ-1.md",
+		Body: "本文  \n", CustomFields: map[string]*float64{"story_points": &three},
+	}
+	remote := &Ticket{
+		Key: "PRJ-1", Title: "タイトル", Type: "task", Status: "Done", StatusCategory: "done", UpdatedAt: updated,
+		Body: "本文\n", CustomFields: map[string]*float64{"story_points": &five},
+	}
+
+	// pushした項目のみリモートの値 (JIRAが正規化した本文) にする
+	synced := SyncBack(local, remote, []string{"body"})
+	assert.Equal(t, "本文\n", synced.Body)
+	assert.Equal(t, "tickets/PRJ-1.md", synced.FilePath)
+	assert.True(t, synced.Marker)
+	// pushしていない項目 (ignore_fields の項目など) はローカルの値を残す
+	assert.Equal(t, "ローカルで変更したタイトル", synced.Title)
+	assert.Equal(t, "To Do", synced.Status)
+	assert.Equal(t, 3.0, *synced.CustomFields["story_points"])
+	// readonly項目はリモートの値にそろえる
+	assert.Equal(t, updated, synced.UpdatedAt)
+	assert.Equal(t, "done", synced.StatusCategory)
+
+	// 何もpushしていない場合はローカルの編集を残す
+	untouched := SyncBack(local, remote, nil)
+	assert.Equal(t, local.Title, untouched.Title)
+	assert.Equal(t, local.Body, untouched.Body)
+	assert.Equal(t, 5.0, *remote.CustomFields["story_points"])
+}
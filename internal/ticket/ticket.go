@@ -5,33 +5,117 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	jiralib "github.com/andygrunwald/go-jira"
 	"github.com/qawatake/tkt/internal/pkg/markdown"
+	"github.com/qawatake/tkt/internal/pkg/utils"
 )
 
 // Ticket はJIRAチケットのローカル表現です
 type Ticket struct {
-	Key              string    `yaml:"key"`
-	ParentKey        string    `yaml:"parentKey"`
-	Type             string    `yaml:"type"`
-	Status           string    `yaml:"status"`
-	Assignee         string    `yaml:"assignee"`
-	Reporter         string    `yaml:"reporter"`
+	Key       string `yaml:"key"`
+	ParentKey string `yaml:"parentKey"`
+	Type      string `yaml:"type"`
+	// Epic は親のエピックの名前で、readonlyです。grepやqueryでエピックごとに絞り込むために保存します。
+	Epic string `yaml:"epic"`
+	// TypeID はIssue TypeのIDで、readonlyです。Issue Typeの名前が変更されてもpushできるように保持します。
+	TypeID         string `yaml:"type_id"`
+	Status         string `yaml:"status"`
+	StatusCategory string `yaml:"status_category"`
+	// Resolution は解決状況です。単独では変更できず、statusを変更するときのトランジションで送信します。
+	Resolution string `yaml:"resolution"`
+	Assignee   string `yaml:"assignee"`
+	Reporter   string `yaml:"reporter"`
+	// Creator はチケットを実際に作成したユーザーで、readonlyです
+	// ボットが利用者の代わりに起票した場合などはReporterと異なります。古いファイルにはありません。
+	Creator          string    `yaml:"creator"`
 	CreatedAt        time.Time `yaml:"created_at"`
 	UpdatedAt        time.Time `yaml:"updated_at"`
 	OriginalEstimate Hour      `yaml:"original_estimate"`
-	URL              string    `yaml:"url"`
-	SprintName       string    `yaml:"sprint"`
-	Title            string    `yaml:"-"`
-	Body             string    `yaml:"-"`
-	FilePath         string    `yaml:"-"`
+	// RemainingEstimate は残り見積もり (時間) で、push時に更新できます
+	RemainingEstimate Hour `yaml:"remaining_estimate"`
+	// TimeSpent は記録済みの作業時間 (時間) で、readonlyです
+	TimeSpent  Hour   `yaml:"time_spent"`
+	URL        string `yaml:"url"`
+	SprintName string `yaml:"sprint"`
+	// Team はAtlassian TeamsのTeamフィールドのチーム名です
+	// JIRAにTeamフィールドがない場合は常に空で、フロントマターにも出力しません。
+	Team        string   `yaml:"team"`
+	Priority    string   `yaml:"priority"`
+	Labels      []string `yaml:"labels"`
+	Components  []string `yaml:"components"`
+	FixVersions []string `yaml:"fix_versions"`
+	Links       []Link   `yaml:"links"`
+	// References は本文から参照しているConfluenceのページです (confluence_links: true の場合のみ、readonly)
+	References []Reference `yaml:"references"`
+	// Environment はJIRAの環境 (Environment) フィールドです
+	// nilの場合はフロントマターに出力せず、push時もリモートの値を変更しません。空文字の場合はpush時に値を消去します。
+	Environment *string `yaml:"environment"`
+	// Flagged はフラグ (Impediment) が付いているかどうかです
+	// JIRAにFlaggedフィールドがない場合はnilで、フロントマターにも出力しません。
+	Flagged *bool `yaml:"flagged"`
+	// CustomFields は設定ファイルで対応付けた数値のカスタムフィールドです (キーはフロントマターでのキー)
+	// 値がnilの場合はフロントマターにnullとして出力し、push時にリモートの値を消去します。
+	CustomFields map[string]*float64 `yaml:"-"`
+	Title        string              `yaml:"-"`
+	Body         string              `yaml:"-"`
+	// Comments は本文の末尾に ## Comments セクションとして出力されるreadonlyなコメントです
+	Comments []Comment `yaml:"-"`
+	// Attachments はfetch --with-attachmentsで取得した添付ファイルです (ファイルには出力しません)
+	Attachments []Attachment `yaml:"-"`
+	FilePath    string       `yaml:"-"`
+	// Marker はtktのチケットであることを示すマーカー (tkt: true) です
+	// strict_ticket_detection のときにkeyのない下書きをチケットとして認識するために使います。
+	Marker bool `yaml:"tkt"`
+	// Source は extra_sources から読み取り専用で取得したチケットのサイトのプレフィックスです (readonly)
+	// 空でないチケットはpushやdiffの対象にできません。
+	Source string `yaml:"source"`
+}
+
+// ReadOnlySourceError は読み取り専用のソースのチケットをpushやdiffの対象にしようとしたことを表します
+type ReadOnlySourceError struct {
+	Key    string
+	Source string
+}
+
+func (e *ReadOnlySourceError) Error() string {
+	return fmt.Sprintf("%s は読み取り専用のソース (%s) のチケットのため、pushやdiffの対象にできません", e.Key, e.Source)
+}
+
+// CheckWritable はチケットがpushやdiffの対象にできるかを確認します
+func (t *Ticket) CheckWritable() error {
+	if t.Source != "" {
+		return &ReadOnlySourceError{Key: t.Key, Source: t.Source}
+	}
+	return nil
 }
 
 type Hour float64
 
+// frontmatterKeyOrder はフロントマターに出力するキーの順序です
+// ここにないキー (カスタムフィールド) はこの後に名前順で出力します。
+var frontmatterKeyOrder = []string{
+	"key", "tkt", "source", "title", "type", "type_id", "parentKey", "epic", "status", "status_category", "resolution", "assignee", "reporter",
+	"creator", "sprint", "team", "original_estimate", "remaining_estimate", "time_spent",
+	"priority", "labels", "components", "fix_versions", "links", "references", "flagged", "environment",
+	"url", "created_at", "updated_at",
+}
+
+// knownFrontmatterKeys はTicketの各フィールドに対応するフロントマターのキーです
+// これ以外のキーで値が数値またはnullのものはCustomFieldsとして読み込みます。
+var knownFrontmatterKeys = map[string]bool{
+	"key": true, "title": true, "type": true, "type_id": true, "parentKey": true, "epic": true, "status": true,
+	"status_category": true, "resolution": true, "assignee": true, "reporter": true, "creator": true, "created_at": true,
+	"updated_at": true, "original_estimate": true, "remaining_estimate": true,
+	"time_spent": true, "url": true, "sprint": true, "team": true,
+	"priority": true, "labels": true, "components": true, "fix_versions": true,
+	"links": true, "references": true, "flagged": true, "environment": true,
+	"tkt": true, "source": true,
+}
+
 func NewHour(d time.Duration) Hour {
 	return Hour(d) / Hour(time.Hour)
 }
@@ -58,7 +142,7 @@ func FromIssue(issue *jiralib.Issue) *Ticket {
 	}
 
 	ticket := &Ticket{
-		Key:       issue.Key,
+		Key:       CanonicalKey(issue.Key),
 		Type:      issueType,
 		Status:    status,
 		CreatedAt: time.Time(issue.Fields.Created),
@@ -82,9 +166,36 @@ func FromIssue(issue *jiralib.Issue) *Ticket {
 		ticket.Reporter = issue.Fields.Reporter.DisplayName
 	}
 
+	// 作成者がある場合は設定
+	if issue.Fields.Creator != nil {
+		ticket.Creator = issue.Fields.Creator.DisplayName
+	}
+
 	return ticket
 }
 
+// SprintBacklog はフロントマターでスプリントから外す (バックログに戻す) ことを明示する場合の値です
+// sprintの行を消したり空にしたりした場合と同じく、push時にスプリントを外します。
+const SprintBacklog = "backlog"
+
+// InBacklog はスプリントが指定されていない (空または backlog) かどうかを返します
+func (t *Ticket) InBacklog() bool {
+	return t.SprintName == "" || strings.EqualFold(t.SprintName, SprintBacklog)
+}
+
+// sprintValue は差分の比較に使うスプリント名です (backlog は空とみなします)
+func (t *Ticket) sprintValue() string {
+	if t.InBacklog() {
+		return ""
+	}
+	return t.SprintName
+}
+
+// IsFlagged はフラグが付いているかどうかを返します
+func (t *Ticket) IsFlagged() bool {
+	return t.Flagged != nil && *t.Flagged
+}
+
 // ToMarkdown はチケットをマークダウン形式に変換します
 func (t *Ticket) ToMarkdown() string {
 	// フロントマターを作成
@@ -94,6 +205,12 @@ func (t *Ticket) ToMarkdown() string {
 	if t.Key != "" {
 		frontMatterData["key"] = t.Key
 	}
+	if t.Marker {
+		frontMatterData["tkt"] = true
+	}
+	if t.Source != "" {
+		frontMatterData["source"] = t.Source
+	}
 
 	// 必須項目
 	frontMatterData["title"] = t.Title
@@ -103,17 +220,32 @@ func (t *Ticket) ToMarkdown() string {
 	if t.ParentKey != "" {
 		frontMatterData["parentKey"] = t.ParentKey
 	}
+	if t.Epic != "" {
+		frontMatterData["epic"] = t.Epic
+	}
 
 	// readonly項目は値がある場合のみ追加
+	if t.TypeID != "" {
+		frontMatterData["type_id"] = t.TypeID
+	}
 	if t.Status != "" {
 		frontMatterData["status"] = t.Status
 	}
+	if t.StatusCategory != "" {
+		frontMatterData["status_category"] = t.StatusCategory
+	}
+	if t.Resolution != "" {
+		frontMatterData["resolution"] = t.Resolution
+	}
 	if t.Assignee != "" {
 		frontMatterData["assignee"] = t.Assignee
 	}
 	if t.Reporter != "" {
 		frontMatterData["reporter"] = t.Reporter
 	}
+	if t.Creator != "" {
+		frontMatterData["creator"] = t.Creator
+	}
 	if !t.CreatedAt.IsZero() {
 		frontMatterData["created_at"] = t.CreatedAt
 	}
@@ -123,43 +255,118 @@ func (t *Ticket) ToMarkdown() string {
 	if t.OriginalEstimate != 0 {
 		frontMatterData["original_estimate"] = t.OriginalEstimate
 	}
+	if t.RemainingEstimate != 0 {
+		frontMatterData["remaining_estimate"] = t.RemainingEstimate
+	}
+	if t.TimeSpent != 0 {
+		frontMatterData["time_spent"] = t.TimeSpent
+	}
 	if t.URL != "" {
 		frontMatterData["url"] = t.URL
 	}
 	if t.SprintName != "" {
 		frontMatterData["sprint"] = t.SprintName
 	}
+	if t.Team != "" {
+		frontMatterData["team"] = t.Team
+	}
+	if t.Priority != "" {
+		frontMatterData["priority"] = t.Priority
+	}
+	if len(t.Labels) > 0 {
+		frontMatterData["labels"] = t.Labels
+	}
+	if len(t.Components) > 0 {
+		frontMatterData["components"] = t.Components
+	}
+	if len(t.FixVersions) > 0 {
+		frontMatterData["fix_versions"] = t.FixVersions
+	}
+	if len(t.Links) > 0 {
+		frontMatterData["links"] = t.Links
+	}
+	if len(t.References) > 0 {
+		frontMatterData["references"] = t.References
+	}
+	if t.Flagged != nil {
+		frontMatterData["flagged"] = *t.Flagged
+	}
+	if t.Environment != nil {
+		frontMatterData["environment"] = *t.Environment
+	}
+	t.addCustomFields(frontMatterData)
 
-	frontMatter := markdown.CreateFrontMatter(frontMatterData)
+	frontMatter := markdown.CreateFrontMatter(frontMatterData, frontmatterKeyOrder...)
 
 	// マークダウン本文を作成
-	return frontMatter + t.Body
+	// コメントは本文の後ろに空行を挟んで追加する
+	comments := renderComments(t.Comments)
+	if comments == "" {
+		return frontMatter + t.Body
+	}
+	body := t.Body
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	return frontMatter + body + "\n" + comments
 }
 
 // SaveToFile はチケットをファイルに保存します
-func (t *Ticket) SaveToFile(dir string) (string, error) {
+// 同じディレクトリの既存のファイル (t.FilePath) があれば、WithFilenameTemplate を指定しない限りその名前を維持します。
+// テンプレートの指定やタイトルの変更でファイル名が変わる場合は、保存に成功してから古いファイルを削除します。
+func (t *Ticket) SaveToFile(dir string, opts ...SaveOption) (string, error) {
+	var o saveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// ディレクトリが存在しない場合は作成
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", fmt.Errorf("ディレクトリの作成に失敗しました: %v", err)
 	}
 
+	// 同じディレクトリにあるこのチケットの既存のファイル
+	var existingPath string
+	if t.FilePath != "" && filepath.Clean(filepath.Dir(t.FilePath)) == filepath.Clean(dir) && t.ownsFileName(filepath.Base(t.FilePath)) {
+		existingPath = t.FilePath
+	}
+
 	// ファイル名を決定
-	fileName := t.Key + ".md"
-	if t.Key == "" {
-		// キーがない場合はタイムスタンプからファイル名を生成
-		timestamp := time.Now().Format("20060102-150405")
-		fileName = fmt.Sprintf("TMP-%s.md", timestamp)
+	var fileName string
+	switch {
+	case t.Key == "" && existingPath == "":
+		// キーがない場合はタイムスタンプとランダムな文字列からファイル名を生成
+		fileName = draftFileName(dir)
+	case t.Key == "" || (o.filenameTemplate == nil && existingPath != ""):
+		fileName = filepath.Base(existingPath)
+	default:
+		var tmpl string
+		if o.filenameTemplate != nil {
+			tmpl = *o.filenameTemplate
+		}
+		name, err := t.FileName(tmpl)
+		if err != nil {
+			return "", err
+		}
+		fileName = name
 	}
 	filePath := filepath.Join(dir, fileName)
 
 	// マークダウンに変換
 	content := t.ToMarkdown()
 
-	// ファイルに書き込み
-	if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+	// ファイルに書き込み (中断されても途中までの内容のファイルが残らないよう、一時ファイルからリネームする)
+	if err := utils.WriteFileAtomic(filePath, []byte(content), 0644); err != nil {
 		return "", fmt.Errorf("ファイルの書き込みに失敗しました: %v", err)
 	}
 
+	// ファイル名が変わった場合は古いファイルを削除 (削除後も同じチケットのファイルが1つだけになるようにする)
+	if existingPath != "" && filepath.Base(existingPath) != fileName {
+		if err := os.Remove(existingPath); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("古いファイル %s の削除に失敗しました: %v", existingPath, err)
+		}
+	}
+
 	t.FilePath = filePath
 	return filePath, nil
 }
@@ -185,7 +392,7 @@ func FromFile(filePath string) (*Ticket, error) {
 
 	// フロントマターからフィールドを設定
 	if key, ok := frontMatter["key"].(string); ok {
-		ticket.Key = key
+		ticket.Key = CanonicalKey(key)
 	}
 	if title, ok := frontMatter["title"].(string); ok {
 		ticket.Title = title
@@ -193,45 +400,104 @@ func FromFile(filePath string) (*Ticket, error) {
 	if parentKey, ok := frontMatter["parentKey"].(string); ok {
 		ticket.ParentKey = parentKey
 	}
+	if epic, ok := frontMatter["epic"].(string); ok {
+		ticket.Epic = epic
+	}
 	if typ, ok := frontMatter["type"].(string); ok {
 		ticket.Type = typ
 	}
+	// type_idは数値として書かれていても読み込む
+	switch typeID := frontMatter["type_id"].(type) {
+	case string:
+		ticket.TypeID = typeID
+	case int:
+		ticket.TypeID = strconv.Itoa(typeID)
+	}
 	if status, ok := frontMatter["status"].(string); ok {
 		ticket.Status = status
 	}
+	if statusCategory, ok := frontMatter["status_category"].(string); ok {
+		ticket.StatusCategory = statusCategory
+	}
+	if resolution, ok := frontMatter["resolution"].(string); ok {
+		ticket.Resolution = resolution
+	}
+	if source, ok := frontMatter["source"].(string); ok {
+		ticket.Source = source
+	}
 	if assignee, ok := frontMatter["assignee"].(string); ok {
 		ticket.Assignee = assignee
 	}
 	if reporter, ok := frontMatter["reporter"].(string); ok {
 		ticket.Reporter = reporter
 	}
+	if creator, ok := frontMatter["creator"].(string); ok {
+		ticket.Creator = creator
+	}
 	if createdAt, ok := frontMatter["created_at"].(time.Time); ok {
 		ticket.CreatedAt = createdAt
 	}
 	if updatedAt, ok := frontMatter["updated_at"].(time.Time); ok {
 		ticket.UpdatedAt = updatedAt
 	}
-	if originalEstimate, ok := frontMatter["original_estimate"].(float64); ok {
-		ticket.OriginalEstimate = NewHour(time.Duration(originalEstimate * float64(time.Hour)))
-	} else if originalEstimate, ok := frontMatter["original_estimate"].(int); ok {
-		ticket.OriginalEstimate = NewHour(time.Duration(originalEstimate * int(time.Hour)))
-	}
+	ticket.OriginalEstimate = hourValue(frontMatter["original_estimate"])
+	ticket.RemainingEstimate = hourValue(frontMatter["remaining_estimate"])
+	ticket.TimeSpent = hourValue(frontMatter["time_spent"])
 	if url, ok := frontMatter["url"].(string); ok {
 		ticket.URL = url
 	}
 	if sprintName, ok := frontMatter["sprint"].(string); ok {
 		ticket.SprintName = sprintName
 	}
+	if team, ok := frontMatter["team"].(string); ok {
+		ticket.Team = team
+	}
+	if priority, ok := frontMatter["priority"].(string); ok {
+		ticket.Priority = priority
+	}
+	ticket.Labels = stringList(frontMatter["labels"])
+	ticket.Components = stringList(frontMatter["components"])
+	ticket.FixVersions = stringList(frontMatter["fix_versions"])
+	ticket.Links = parseLinks(frontMatter["links"])
+	ticket.References = parseReferences(frontMatter["references"])
+	if flagged, ok := frontMatter["flagged"].(bool); ok {
+		ticket.Flagged = &flagged
+	}
+	if marker, ok := frontMatter["tkt"].(bool); ok {
+		ticket.Marker = marker
+	}
+	// environmentはキーがあれば値が空 (null) でも設定し、push時に消去する
+	if value, ok := frontMatter["environment"]; ok {
+		environment, _ := value.(string)
+		ticket.Environment = &environment
+	}
+	for key, value := range frontMatter {
+		if knownFrontmatterKeys[key] {
+			continue
+		}
+		switch v := value.(type) {
+		case nil:
+			ticket.setCustomField(key, nil)
+		case int:
+			f := float64(v)
+			ticket.setCustomField(key, &f)
+		case float64:
+			ticket.setCustomField(key, &v)
+		}
+	}
 
 	// 本文をそのまま設定
-	ticket.Body = body
+	// ただし CreateFrontMatter がフロントマターの後に挿入する空行は除去し、
+	// 読み込みと保存を繰り返しても空行が増えないようにします。
+	// コメントセクションはreadonlyなので本文から分離します。
+	ticket.Body, ticket.Comments = splitComments(strings.TrimPrefix(body, "\n"))
 
 	return ticket, nil
 }
 
 // ToMarkdownWithoutReadonly はreadonly項目を除外したマークダウン形式を返します
 func (t *Ticket) ToMarkdownWithoutReadonly() string {
-	// readonly項目（key, assignee, reporter, created_at, updated_at）を除外したフロントマターを作成
+	// readonly項目（key, epic, reporter, creator, created_at, updated_at）を除外したフロントマターを作成
 	// titleはwritableなのでフロントマターに含める
 	// original_estimateとstatusも差分対象に含める
 	frontMatterData := map[string]interface{}{
@@ -240,27 +506,129 @@ func (t *Ticket) ToMarkdownWithoutReadonly() string {
 		"type":      t.Type,
 	}
 
+	// assigneeは表示名で比較し、push時にaccountIdに解決する
+	if t.Assignee != "" {
+		frontMatterData["assignee"] = t.Assignee
+	}
+
 	// original_estimateが設定されている場合は含める
 	if t.OriginalEstimate != 0 {
 		frontMatterData["original_estimate"] = t.OriginalEstimate
 	}
 
+	// remaining_estimateも同様に含める (time_spentはreadonlyなので含めない)
+	if t.RemainingEstimate != 0 {
+		frontMatterData["remaining_estimate"] = t.RemainingEstimate
+	}
+
 	// statusが設定されている場合は含める
 	if t.Status != "" {
 		frontMatterData["status"] = t.Status
 	}
 
-	// sprintが設定されている場合は含める
-	if t.SprintName != "" {
-		frontMatterData["sprint"] = t.SprintName
+	// sprintが設定されている場合は含める (backlog は未設定と同じ扱いのため含めない)
+	if sprint := t.sprintValue(); sprint != "" {
+		frontMatterData["sprint"] = sprint
+	}
+	if t.Team != "" {
+		frontMatterData["team"] = t.Team
 	}
 
-	frontMatter := markdown.CreateFrontMatter(frontMatterData)
+	// priorityが設定されている場合は含める
+	if t.Priority != "" {
+		frontMatterData["priority"] = t.Priority
+	}
+
+	// labelsが設定されている場合は含める
+	// 空の場合は含めないので、labelsの行を削除するとラベルを外す差分になる
+	if len(t.Labels) > 0 {
+		frontMatterData["labels"] = t.Labels
+	}
+
+	// componentsとfix_versionsもlabelsと同様に、空の場合は含めない
+	if len(t.Components) > 0 {
+		frontMatterData["components"] = t.Components
+	}
+	if len(t.FixVersions) > 0 {
+		frontMatterData["fix_versions"] = t.FixVersions
+	}
+
+	// linksも空の場合は含めない (行を削除するとリンクを外す差分になる)
+	if len(t.Links) > 0 {
+		frontMatterData["links"] = t.Links
+	}
+
+	// flaggedはJIRAにFlaggedフィールドがある場合のみ含める
+	if t.Flagged != nil {
+		frontMatterData["flagged"] = *t.Flagged
+	}
+
+	// environmentは書かれている場合のみ含める
+	if t.Environment != nil {
+		frontMatterData["environment"] = *t.Environment
+	}
+
+	// カスタムフィールドも差分対象に含める
+	t.addCustomFields(frontMatterData)
+
+	frontMatter := markdown.CreateFrontMatter(frontMatterData, frontmatterKeyOrder...)
 
 	// フロントマターとbodyを結合
 	return frontMatter + t.Body
 }
 
+// hourValue はフロントマターの値を時間として取り出します (解釈できない場合は0)
+// 数値に加えて、手で書いた "1h30m" や "90m" のような文字列も受け付けます。
+func hourValue(value interface{}) Hour {
+	switch v := value.(type) {
+	case float64:
+		return NewHour(time.Duration(v * float64(time.Hour)))
+	case int:
+		return NewHour(time.Duration(v * int(time.Hour)))
+	case string:
+		h, err := ParseHour(v)
+		if err != nil {
+			return 0
+		}
+		return h
+	}
+	return 0
+}
+
+// stringList はフロントマターの文字列のリストを取り出します (空文字は除きます)
+func stringList(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// setCustomField はカスタムフィールドの値を設定します
+func (t *Ticket) setCustomField(key string, value *float64) {
+	if t.CustomFields == nil {
+		t.CustomFields = make(map[string]*float64)
+	}
+	t.CustomFields[key] = value
+}
+
+// addCustomFields はカスタムフィールドをフロントマターのデータに追加します
+func (t *Ticket) addCustomFields(frontMatterData map[string]interface{}) {
+	for key, value := range t.CustomFields {
+		if value == nil {
+			frontMatterData[key] = nil
+			continue
+		}
+		frontMatterData[key] = *value
+	}
+}
+
 // HasNonReadonlyDiff はreadonly項目以外に差分があるかチェックします
 func (t *Ticket) HasNonReadonlyDiff(other *Ticket) bool {
 	return t.ToMarkdownWithoutReadonly() != other.ToMarkdownWithoutReadonly()
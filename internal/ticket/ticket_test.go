@@ -0,0 +1,578 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveToFileRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		title string
+	}{
+		{name: "通常のタイトル", title: "ログイン画面の改善"},
+		{name: "コロンを含む", title: "fix: handle nil pointer"},
+		{name: "先頭が#", title: "#123 の対応"},
+		{name: "クォートを含む", title: `"quoted" and 'single'`},
+		{name: "先頭がハイフン", title: "- not a list"},
+		{name: "括弧と記号を含む", title: "[WIP] {draft} *important*"},
+		{name: "YAMLの真偽値", title: "yes"},
+		{name: "数値のみ", title: "123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{
+				Key:      "PRJ-1",
+				Title:    tt.title,
+				Type:     "task",
+				Priority: "High",
+				Body:     "本文\n",
+			}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.title, loaded.Title)
+			assert.Equal(t, original.Priority, loaded.Priority)
+			assert.Equal(t, original.Body, loaded.Body)
+
+			// 読み込みと保存を繰り返しても内容が変わらない
+			_, err = loaded.SaveToFile(dir)
+			assert.NoError(t, err)
+			reloaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, loaded.ToMarkdown(), reloaded.ToMarkdown())
+		})
+	}
+}
+
+func TestCommentsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	jst := time.FixedZone("JST", 9*60*60)
+	comments := []Comment{
+		{Author: "山田太郎", CreatedAt: time.Date(2025, 1, 2, 15, 4, 5, 0, jst), Body: "確認しました。\n\n- 修正済み"},
+		{Author: "Suzuki (QA)", CreatedAt: time.Date(2025, 1, 3, 9, 0, 0, 0, jst), Body: "## 再現手順\n\n1. ログインする"},
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "本文あり", body: "本文\n"},
+		{name: "本文なし", body: ""},
+		{name: "本文に同名の見出しを含む", body: "## Comments\n\nこれは本文です\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: tt.body, Comments: comments}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, loaded.Body)
+			assert.True(t, sameComments(comments, loaded.Comments))
+			assert.Equal(t, original.ToMarkdown(), loaded.ToMarkdown())
+
+			// コメントはreadonlyなので差分にならない
+			withoutComments := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: tt.body}
+			assert.False(t, loaded.HasNonReadonlyDiff(withoutComments))
+		})
+	}
+}
+
+func TestFlaggedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	flagged := true
+	notFlagged := false
+	tests := []struct {
+		name    string
+		flagged *bool
+	}{
+		{name: "Flaggedフィールドがない", flagged: nil},
+		{name: "フラグなし", flagged: &notFlagged},
+		{name: "フラグあり", flagged: &flagged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", Flagged: tt.flagged}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.flagged, loaded.Flagged)
+			assert.False(t, loaded.HasNonReadonlyDiff(original))
+		})
+	}
+}
+
+func TestLabelsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		labels []string
+		want   []string
+	}{
+		{name: "ラベルなし", labels: nil, want: nil},
+		{name: "空のラベル", labels: []string{}, want: nil},
+		{name: "複数のラベル", labels: []string{"backend", "tech-debt"}, want: []string{"backend", "tech-debt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", Labels: tt.labels}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, loaded.Labels)
+			assert.False(t, loaded.HasNonReadonlyDiff(original))
+		})
+	}
+
+	// ラベルを外すと差分になる
+	withLabels := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Labels: []string{"backend"}}
+	withoutLabels := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task"}
+	assert.True(t, withoutLabels.HasNonReadonlyDiff(withLabels))
+}
+
+func TestComponentsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", Components: []string{"Backend", "API"}}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Backend", "API"}, loaded.Components)
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// コンポーネントを外すと差分になる
+	withoutComponents := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n"}
+	assert.True(t, withoutComponents.HasNonReadonlyDiff(loaded))
+}
+
+func TestFixVersionsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", FixVersions: []string{"1.0.0", "1.1.0"}}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, loaded.FixVersions)
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// 修正バージョンを変更すると差分になる
+	changed := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", FixVersions: []string{"1.0.0"}}
+	assert.True(t, changed.HasNonReadonlyDiff(loaded))
+}
+
+func TestCustomFieldsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	three := 3.0
+	half := 0.5
+	tests := []struct {
+		name   string
+		custom map[string]*float64
+	}{
+		{name: "カスタムフィールドなし", custom: nil},
+		{name: "未設定", custom: map[string]*float64{"story_points": nil}},
+		{name: "整数", custom: map[string]*float64{"story_points": &three}},
+		{name: "小数", custom: map[string]*float64{"story_points": &half, "business_value": &three}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", CustomFields: tt.custom}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.custom, loaded.CustomFields)
+			assert.False(t, loaded.HasNonReadonlyDiff(original))
+		})
+	}
+
+	// 値を変更すると差分になる
+	five := 5.0
+	before := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", CustomFields: map[string]*float64{"story_points": &three}}
+	after := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", CustomFields: map[string]*float64{"story_points": &five}}
+	assert.True(t, after.HasNonReadonlyDiff(before))
+}
+
+func TestLinksRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", Links: []Link{
+		{Type: "blocks", Key: "PRJ-45"},
+		{Type: "is blocked by", Key: "PRJ-3"},
+	}}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, original.ToMarkdown(), "- {type: blocks, key: PRJ-45}\n")
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original.Links, loaded.Links)
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// 並び順が違うだけなら差分にならない
+	reordered := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", Links: parseLinks([]interface{}{
+		map[string]interface{}{"type": "is blocked by", "key": "PRJ-3"},
+		map[string]interface{}{"type": "blocks", "key": "PRJ-45"},
+	})}
+	assert.False(t, reordered.HasNonReadonlyDiff(loaded))
+
+	// リンクを外すと差分になる
+	withoutLinks := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n"}
+	assert.True(t, withoutLinks.HasNonReadonlyDiff(loaded))
+}
+
+func TestEnvironmentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	environment := "iOS 17.5\nSafari"
+	empty := ""
+	tests := []struct {
+		name        string
+		environment *string
+	}{
+		{name: "環境なし", environment: nil},
+		{name: "空文字", environment: &empty},
+		{name: "複数行", environment: &environment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "bug", Body: "本文\n", Environment: tt.environment}
+			path, err := original.SaveToFile(dir)
+			assert.NoError(t, err)
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.environment, loaded.Environment)
+			assert.False(t, loaded.HasNonReadonlyDiff(original))
+		})
+	}
+}
+
+func TestTimeTrackingRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{
+		Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n",
+		OriginalEstimate: 8, RemainingEstimate: 5.5, TimeSpent: 2.5,
+	}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Hour(5.5), loaded.RemainingEstimate)
+	assert.Equal(t, Hour(2.5), loaded.TimeSpent)
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// time_spentはreadonlyなので差分にならない
+	moreSpent := *loaded
+	moreSpent.TimeSpent = 4
+	assert.False(t, moreSpent.HasNonReadonlyDiff(loaded))
+
+	// remaining_estimateを変更すると差分になる
+	lessRemaining := *loaded
+	lessRemaining.RemainingEstimate = 3
+	assert.True(t, lessRemaining.HasNonReadonlyDiff(loaded))
+}
+
+func TestAssigneeDiff(t *testing.T) {
+	t.Parallel()
+
+	cached := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Assignee: "Taro Yamada"}
+	same := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Assignee: "Taro Yamada"}
+	assert.False(t, same.HasNonReadonlyDiff(cached))
+
+	// 担当者の変更と解除は差分になる
+	changed := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Assignee: "me"}
+	assert.True(t, changed.HasNonReadonlyDiff(cached))
+	assert.Equal(t, []string{"assignee"}, changedFields(changed, cached))
+	unassigned := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task"}
+	assert.True(t, unassigned.HasNonReadonlyDiff(cached))
+}
+
+func TestCreatorRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Reporter: "Taro Yamada", Creator: "automation", Body: "本文\n"}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "automation", loaded.Creator)
+	assert.Equal(t, "Taro Yamada", loaded.Reporter)
+
+	// creatorはreadonlyなので差分にならず、差分の比較対象にも含めない
+	changed := *loaded
+	changed.Creator = "someone"
+	assert.False(t, changed.HasNonReadonlyDiff(loaded))
+	assert.NotContains(t, changed.ToMarkdownWithoutReadonly(), "creator")
+
+	// creatorのない古いファイルも読み込める
+	old := filepath.Join(dir, "PRJ-2.md")
+	assert.NoError(t, os.WriteFile(old, []byte("---\nkey: PRJ-2\ntitle: 古いチケット\ntype: task\nreporter: Taro Yamada\n---\n本文\n"), 0644))
+	loaded, err = FromFile(old)
+	assert.NoError(t, err)
+	assert.Empty(t, loaded.Creator)
+	assert.Nil(t, loaded.CustomFields["creator"])
+}
+
+func TestEpicRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", ParentKey: "PRJ-100", Epic: "決済基盤刷新", Body: "本文\n"}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "決済基盤刷新", loaded.Epic)
+	assert.Nil(t, loaded.CustomFields["epic"])
+
+	// epicはreadonlyなので差分にならない (親を変える場合はparentKeyを編集する)
+	changed := *loaded
+	changed.Epic = "別のエピック"
+	assert.False(t, changed.HasNonReadonlyDiff(loaded))
+	assert.NotContains(t, changed.ToMarkdownWithoutReadonly(), "epic")
+}
+
+func TestSprintRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", SprintName: "Sprint 42"}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, original.ToMarkdown(), "sprint: Sprint 42\n")
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sprint 42", loaded.SprintName)
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// スプリントを変更すると差分になる
+	moved := *loaded
+	moved.SprintName = "Sprint 43"
+	assert.True(t, moved.HasNonReadonlyDiff(loaded))
+	assert.Equal(t, []string{"sprint"}, changedFields(&moved, loaded))
+
+	// 空または backlog にするとスプリントから外す差分になる
+	for _, sprint := range []string{"", "backlog"} {
+		moved.SprintName = sprint
+		assert.Equal(t, []string{"sprint"}, changedFields(&moved, loaded))
+	}
+
+	// スプリントのないチケットを backlog にしても差分にならない
+	backlog := *loaded
+	backlog.SprintName = ""
+	explicit := backlog
+	explicit.SprintName = "Backlog"
+	assert.Empty(t, changedFields(&explicit, &backlog))
+}
+
+func TestTeamRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", SprintName: "Sprint 42", Team: "Platform"}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, original.ToMarkdown(), "sprint: Sprint 42\nteam: Platform\n")
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Platform", loaded.Team)
+	assert.Nil(t, loaded.CustomFields["team"])
+	assert.False(t, loaded.HasNonReadonlyDiff(original))
+
+	// チームを変更・削除すると差分になる
+	moved := *loaded
+	moved.Team = "Mobile"
+	assert.Equal(t, []string{"team"}, changedFields(&moved, loaded))
+	moved.Team = ""
+	assert.Equal(t, []string{"team"}, changedFields(&moved, loaded))
+	assert.NotContains(t, moved.ToMarkdown(), "team:")
+}
+
+func TestTypeIDRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", TypeID: "10001", Body: "本文\n"}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, original.ToMarkdown(), "type: task\ntype_id: \"10001\"\n")
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "10001", loaded.TypeID)
+
+	// readonlyなので差分にならない
+	edited := *loaded
+	edited.TypeID = ""
+	assert.False(t, edited.HasNonReadonlyDiff(loaded))
+
+	// 数値で書かれていても読み込む
+	assert.NoError(t, os.WriteFile(path, []byte("---\nkey: PRJ-1\ntitle: タイトル\ntype: task\ntype_id: 10001\n---\n"), 0644))
+	loaded, err = FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "10001", loaded.TypeID)
+}
+
+func TestOriginalEstimateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  Hour
+	}{
+		{name: "整数", value: "8", want: 8},
+		{name: "小数", value: "2.5", want: 2.5},
+		{name: "単位つきの文字列", value: "1h30m", want: 1.5},
+		{name: "クォートした数値", value: `"3"`, want: 3},
+		{name: "解釈できない文字列", value: "someday", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "PRJ-1.md")
+			content := "---\nkey: PRJ-1\ntitle: タイトル\ntype: task\noriginal_estimate: " + tt.value + "\n---\n本文\n"
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			loaded, err := FromFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, loaded.OriginalEstimate)
+		})
+	}
+
+	// fetch→保存→読み込みで差分にならず、見積もりを変えると差分になる
+	dir := t.TempDir()
+	fetched := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", OriginalEstimate: 2.5}
+	path, err := fetched.SaveToFile(dir)
+	assert.NoError(t, err)
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Hour(2.5), loaded.OriginalEstimate)
+	assert.False(t, loaded.HasNonReadonlyDiff(fetched))
+
+	loaded.OriginalEstimate = 4
+	assert.True(t, loaded.HasNonReadonlyDiff(fetched))
+	assert.Equal(t, []string{"original_estimate"}, changedFields(loaded, fetched))
+}
+
+func TestReferencesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	original := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n", References: []Reference{
+		{Title: "設計書", URL: "https://example.atlassian.net/wiki/spaces/DEV/pages/123/Design"},
+		{URL: "https://example.atlassian.net/wiki/x/AbCd"},
+	}}
+	path, err := original.SaveToFile(dir)
+	assert.NoError(t, err)
+	assert.Contains(t, original.ToMarkdown(), "- {url: 'https://example.atlassian.net/wiki/x/AbCd'}\n")
+
+	loaded, err := FromFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original.References, loaded.References)
+
+	// referencesはreadonlyなので差分にならない
+	withoutReferences := &Ticket{Key: "PRJ-1", Title: "タイトル", Type: "task", Body: "本文\n"}
+	assert.False(t, loaded.HasNonReadonlyDiff(withoutReferences))
+}
+
+func TestFrontmatterKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	points := 3.0
+	tkt := &Ticket{
+		Key: "PRJ-1", Title: "タイトル", Type: "task", ParentKey: "PRJ-9", Epic: "エピック", Status: "In Progress",
+		Assignee: "Taro Yamada", Reporter: "Hanako Suzuki", Creator: "automation", SprintName: "Sprint 42", Team: "Platform", OriginalEstimate: 2,
+		Priority: "High", Labels: []string{"backend"}, URL: "https://example.atlassian.net/browse/PRJ-1",
+		CreatedAt:    time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:    time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+		CustomFields: map[string]*float64{"story_points": &points, "business_value": nil},
+		Body:         "本文\n",
+	}
+
+	// 同じチケットは何度保存しても同じ内容になる
+	dir := t.TempDir()
+	path, err := tkt.SaveToFile(dir)
+	assert.NoError(t, err)
+	first, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	_, err = tkt.SaveToFile(dir)
+	assert.NoError(t, err)
+	second, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+
+	// 決められた順序で、カスタムフィールドは最後に名前順で出力する
+	var keys []string
+	for _, line := range strings.Split(string(first), "\n") {
+		if key, _, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			keys = append(keys, key)
+		}
+	}
+	assert.Equal(t, []string{
+		"key", "title", "type", "parentKey", "epic", "status", "assignee", "reporter", "creator", "sprint", "team",
+		"original_estimate", "priority", "labels", "url", "created_at", "updated_at", "business_value", "story_points",
+	}, keys)
+}
@@ -0,0 +1,54 @@
+package ticket
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreFileName はワークスペースで無視するファイルを指定するファイルの名前です
+// ワークスペースのルートに置き、.gitignore と同じ書式でパターンを書きます。
+const IgnoreFileName = ".tktignore"
+
+// Ignore は .tktignore のパターンです
+type Ignore struct {
+	matcher gitignore.Matcher
+}
+
+// LoadIgnore はディレクトリの .tktignore を読み込みます
+// ファイルがない場合は何も無視しない Ignore を返します。
+func LoadIgnore(dir string) (*Ignore, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ParseIgnore(""), nil
+		}
+		return nil, fmt.Errorf("%s の読み込みに失敗しました: %v", IgnoreFileName, err)
+	}
+	return ParseIgnore(string(data)), nil
+}
+
+// ParseIgnore は .tktignore の内容からパターンを作成します
+// 空行と # で始まる行は無視します。
+func ParseIgnore(content string) *Ignore {
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return &Ignore{matcher: gitignore.NewMatcher(patterns)}
+}
+
+// Match はワークスペースのルートからの相対パスが無視するパターンに一致するかどうかを返します
+func (i *Ignore) Match(rel string, isDir bool) bool {
+	if i == nil || rel == "" || rel == "." {
+		return false
+	}
+	return i.matcher.Match(strings.Split(filepath.ToSlash(rel), "/"), isDir)
+}
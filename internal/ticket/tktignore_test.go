@@ -0,0 +1,58 @@
+package ticket
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	t.Parallel()
+
+	ignore := ParseIgnore("# メモは無視する\n*.draft.md\nnotes/**\n\nREADME.md\n!keep.draft.md\n")
+	tests := []struct {
+		name  string
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{name: "ルートの下書き", path: "idea.draft.md", want: true},
+		{name: "サブディレクトリの下書き", path: "bugs/idea.draft.md", want: true},
+		{name: "否定パターン", path: "keep.draft.md", want: false},
+		{name: "notes以下のファイル", path: "notes/2025/meeting.md", want: true},
+		{name: "notes直下のファイル", path: "notes/meeting.md", want: true},
+		{name: "README", path: "README.md", want: true},
+		{name: "チケット", path: "PRJ-1.md", want: false},
+		{name: "notesという名前のチケット", path: "notes.md", want: false},
+		{name: "ルート", path: ".", isDir: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ignore.Match(tt.path, tt.isDir))
+		})
+	}
+}
+
+func TestWalkFilesIgnore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("*.draft.md\nnotes/**\nREADME.md\n"), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "notes", "2025"), 0755))
+	for _, name := range []string{"PRJ-1.md", "README.md", "idea.draft.md", ".PRJ-2.md", ".old.draft.md", "notes/2025/meeting.md"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("---\ntitle: x\n---\n"), 0644))
+	}
+
+	files, deleted, err := WalkFiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "PRJ-1.md")}, files)
+	assert.Equal(t, []string{filepath.Join(dir, ".PRJ-2.md")}, deleted)
+
+	// .tktignore がなければ何も無視しない
+	ignore, err := LoadIgnore(t.TempDir())
+	assert.NoError(t, err)
+	assert.False(t, ignore.Match("README.md", false))
+}
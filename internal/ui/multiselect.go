@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type multiSelectDelegate struct {
+	checked map[int]bool
+}
+
+func (d multiSelectDelegate) Height() int                             { return 1 }
+func (d multiSelectDelegate) Spacing() int                            { return 0 }
+func (d multiSelectDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d multiSelectDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(item)
+	if !ok {
+		return
+	}
+
+	mark := "[ ]"
+	if d.checked[index] {
+		mark = "[x]"
+	}
+	str := fmt.Sprintf("%s %s", mark, i.title)
+
+	fn := itemStyle.Render
+	if index == m.Index() {
+		fn = func(s ...string) string {
+			return selectedItemStyle.Render("> " + strings.Join(s, " "))
+		}
+	}
+
+	fmt.Fprint(w, fn(str))
+}
+
+type multiSelectModel struct {
+	list     list.Model
+	checked  map[int]bool
+	done     bool
+	quitting bool
+	err      error
+}
+
+func (m multiSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m multiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetWidth(msg.Width)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch keypress := msg.String(); keypress {
+		case "ctrl+c", "esc", "q":
+			m.quitting = true
+			m.err = fmt.Errorf("選択がキャンセルされました")
+			return m, tea.Quit
+
+		case "enter":
+			m.done = true
+			return m, tea.Quit
+
+		case " ", "x":
+			m.checked[m.list.Index()] = !m.checked[m.list.Index()]
+			return m, nil
+
+		case "a":
+			// すべて選択済みなら全解除、そうでなければ全選択
+			all := true
+			for idx := range m.list.Items() {
+				if !m.checked[idx] {
+					all = false
+					break
+				}
+			}
+			for idx := range m.list.Items() {
+				m.checked[idx] = !all
+			}
+			return m, nil
+
+		case "ctrl+n":
+			m.list.CursorDown()
+			return m, nil
+
+		case "ctrl+p":
+			m.list.CursorUp()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m multiSelectModel) View() string {
+	if m.quitting {
+		return quitTextStyle.Render("選択がキャンセルされました。")
+	}
+	if m.done {
+		return ""
+	}
+	return "\n" + m.list.View()
+}
+
+// MultiSelect displays a list with checkboxes and returns the checked values in list order
+// space/x toggles the current item, a toggles all items, enter confirms.
+func MultiSelect(title string, options []SelectorOption) ([]interface{}, error) {
+	items := make([]list.Item, len(options))
+	for i, opt := range options {
+		items[i] = item{
+			title: opt.Title,
+			desc:  opt.Description,
+			value: opt.Value,
+		}
+	}
+
+	const defaultWidth = 80
+	const listHeight = 14
+
+	checked := make(map[int]bool)
+	l := list.New(items, multiSelectDelegate{checked: checked}, defaultWidth, listHeight)
+	l.Title = title
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = titleStyle
+	l.Styles.PaginationStyle = paginationStyle
+	l.Styles.HelpStyle = helpStyle
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "選択/解除")),
+			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "すべて選択/解除")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "決定")),
+		}
+	}
+
+	m := multiSelectModel{list: l, checked: checked}
+
+	p := tea.NewProgram(m)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	result := finalModel.(multiSelectModel)
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	var values []interface{}
+	for idx, opt := range options {
+		if result.checked[idx] {
+			values = append(values, opt.Value)
+		}
+	}
+	return values, nil
+}
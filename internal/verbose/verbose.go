@@ -2,24 +2,133 @@ package verbose
 
 import (
 	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 )
 
-var Enabled bool
+// Domain はデバッグ出力の分類です
+// --debug で指定した分類のメッセージのみを表示できます。
+type Domain string
 
-func Printf(format string, args ...any) {
-	if Enabled {
-		fmt.Printf(format, args...)
+const (
+	// General はどの分類にも当てはまらないコマンドの進行状況です
+	General Domain = "general"
+	// API はJIRAやConfluenceへのリクエストとその結果です
+	API Domain = "api"
+	// Cache はキャッシュの読み書きとバックグラウンドの更新です
+	Cache Domain = "cache"
+	// Diff はローカルとキャッシュの差分の検出とマージです
+	Diff Domain = "diff"
+)
+
+// Domains は --debug で指定できる分類の一覧です
+var Domains = []Domain{General, API, Cache, Diff}
+
+// Level は -v の回数です
+// 1 (-v) で進行状況を、2 (-vv) でAPIのリクエストとレスポンスのボディまで表示します。
+var Level int
+
+// enabledDomains は --debug で指定された分類です (空の場合は全ての分類)
+var enabledDomains []Domain
+
+// SetDomains は --debug の値 (api,diff のようなカンマ区切りも可) から表示する分類を設定します
+func SetDomains(values []string) error {
+	var domains []Domain
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(strings.ToLower(name))
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(Domains, Domain(name)) {
+				names := make([]string, len(Domains))
+				for i, d := range Domains {
+					names[i] = string(d)
+				}
+				return fmt.Errorf("不明なデバッグの分類です: %s (指定できる分類: %s)", name, strings.Join(names, ", "))
+			}
+			domains = append(domains, Domain(name))
+		}
+	}
+	enabledDomains = domains
+	return nil
+}
+
+// Enabled は分類のメッセージを表示するかどうかを返します
+// --debug で分類を指定した場合は -v がなくてもその分類を表示します。
+func Enabled(d Domain) bool {
+	if len(enabledDomains) > 0 {
+		return slices.Contains(enabledDomains, d)
+	}
+	return Level >= 1
+}
+
+// TraceEnabled はレスポンスのボディのような詳細 (-vv) を表示するかどうかを返します
+func TraceEnabled(d Domain) bool {
+	return Level >= 2 && Enabled(d)
+}
+
+func Printf(d Domain, format string, args ...any) {
+	if Enabled(d) {
+		fmt.Print(Redact(fmt.Sprintf(format, args...)))
+	}
+}
+
+func Println(d Domain, args ...any) {
+	if Enabled(d) {
+		fmt.Print(Redact(fmt.Sprintln(args...)))
+	}
+}
+
+func Print(d Domain, args ...any) {
+	if Enabled(d) {
+		fmt.Print(Redact(fmt.Sprint(args...)))
 	}
 }
 
-func Println(args ...any) {
-	if Enabled {
-		fmt.Println(args...)
+// Tracef は -vv の場合のみ表示します
+// チケットの内容を含むリクエストやレスポンスのボディはこちらで出力し、-v のログを共有しても漏れないようにします。
+func Tracef(d Domain, format string, args ...any) {
+	if TraceEnabled(d) {
+		fmt.Print(Redact(fmt.Sprintf(format, args...)))
 	}
 }
 
-func Print(args ...any) {
-	if Enabled {
-		fmt.Print(args...)
+// sensitiveHeaders はログに値を出さないHTTPヘッダーです
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// authorizationPattern はメッセージに紛れ込んだ認証情報です
+// (ヘッダーの形、Goのmapの出力、JSONのいずれでも、値とBasic/Bearerの後ろの長い文字列を伏せます)
+var authorizationPattern = regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*\[?["']?)(?:(?:basic|bearer)\s+)?[^\s"'\],]+|\b(basic|bearer)\s+[A-Za-z0-9+/._~-]{20,}=*`)
+
+// Redact はメッセージに含まれる Authorization ヘッダーの値とBasic/Bearerの認証情報を伏せます
+func Redact(s string) string {
+	return authorizationPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := authorizationPattern.FindStringSubmatch(m)
+		if sub[1] != "" {
+			return sub[1] + "[REDACTED]"
+		}
+		return sub[2] + " [REDACTED]"
+	})
+}
+
+// Headers はHTTPヘッダーを認証情報を伏せて1行ずつの文字列にします
+func Headers(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		value := strings.Join(h[k], ", ")
+		if slices.Contains(sensitiveHeaders, http.CanonicalHeaderKey(k)) {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", k, value)
 	}
+	return b.String()
 }